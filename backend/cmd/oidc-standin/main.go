@@ -0,0 +1,56 @@
+// Command oidc-standin serves an in-memory OpenID Connect provider for
+// trying OIDC sign-in locally. Every sign-in is approved at once as the
+// user given by the flags.
+//
+//	oidc-standin [-addr :9000] [-client-id devoptics] [-client-secret secret] \
+//	    [-email dev@example.com] [-groups admins,analysts]
+//
+// Point the server at it with oidc.issuer http://localhost:9000 and the
+// same client id and secret.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	issuer := flag.String("issuer", "", "issuer URL (default http://localhost<addr>)")
+	clientID := flag.String("client-id", "devoptics", "client id the server uses")
+	clientSecret := flag.String("client-secret", "secret", "client secret the server uses")
+	subject := flag.String("sub", "dev-user", "subject of the signed-in user")
+	email := flag.String("email", "dev@example.com", "email of the signed-in user")
+	unverified := flag.Bool("unverified", false, "mark the email unverified")
+	name := flag.String("name", "Dev User", "name of the signed-in user")
+	groups := flag.String("groups", "", "comma-separated groups of the signed-in user")
+	flag.Parse()
+
+	if *issuer == "" {
+		*issuer = "http://localhost" + *addr
+	}
+	var groupList []string
+	if *groups != "" {
+		groupList = strings.Split(*groups, ",")
+	}
+
+	standIn := &testutil.OIDCStandIn{
+		Issuer:       *issuer,
+		ClientID:     *clientID,
+		ClientSecret: *clientSecret,
+		User: testutil.OIDCUser{
+			Subject:       *subject,
+			Email:         *email,
+			EmailVerified: !*unverified,
+			Name:          *name,
+			Groups:        groupList,
+		},
+	}
+	fmt.Printf("OIDC stand-in for %s serving %s on %s\n", *clientID, *issuer, *addr)
+	log.Fatal(http.ListenAndServe(*addr, standIn))
+}
@@ -0,0 +1,153 @@
+// Command rules checks, imports and exports Prometheus alerting rule files
+// against the backend's rule store, so rules kept in a Git repository stay
+// in sync with the ones edited through the API.
+//
+//	rules check  -source cluster alerts.yaml
+//	rules import -source cluster [-prune] [-dry-run] alerts.yaml
+//	rules export [-group name] [-source cluster] [-o alerts.yaml]
+//
+// import and export use the database settings of the server's config file
+// and environment; the server must have created the schema. check needs
+// no database.
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/lib/pq"
+
+	"github.com/fbisdevoptics/backend/internal/config"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/services"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "check":
+		err = check(os.Args[2:])
+	case "import":
+		err = importRules(os.Args[2:])
+	case "export":
+		err = exportRules(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "rules:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: rules check|import|export [flags] [file]")
+	os.Exit(2)
+}
+
+func check(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	source := flags.String("source", "", "source of the rules: cluster (default) or prometheus")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("check takes one rule file")
+	}
+
+	content, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	rules, skipped, err := services.ParseRuleFile(content, *source)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d alerting rules OK", len(rules))
+	if len(skipped) > 0 {
+		fmt.Printf(", %d recording rules skipped", len(skipped))
+	}
+	fmt.Println()
+	return nil
+}
+
+func importRules(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	source := flags.String("source", "", "source of the rules: cluster (default) or prometheus")
+	prune := flags.Bool("prune", false, "delete stored rules in the file's groups that the file does not contain")
+	dryRun := flags.Bool("dry-run", false, "validate and report changes without storing them")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("import takes one rule file")
+	}
+
+	content, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	service, db, err := ruleService()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := service.ImportRules(content, services.ImportOptions{Source: *source, Prune: *prune, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func exportRules(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	group := flags.String("group", "", "only export rules of this group")
+	source := flags.String("source", "", "only export rules of this source; needed when the rules selected have different sources")
+	output := flags.String("o", "", "write to this file instead of standard output")
+	flags.Parse(args)
+
+	service, db, err := ruleService()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	content, err := service.ExportRules(*group, *source)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(content)
+		return err
+	}
+	return os.WriteFile(*output, content, 0o644)
+}
+
+func ruleService() (services.RuleService, *sql.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := sql.Open("postgres", fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Database.Host,
+		cfg.Database.Port,
+		cfg.Database.User,
+		cfg.Database.Password,
+		cfg.Database.Database,
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return services.NewRuleService(repositories.NewRuleRepository(db)), db, nil
+}
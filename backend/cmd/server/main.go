@@ -20,16 +20,46 @@ import (
 
 	"github.com/fbisdevoptics/backend/internal/config"
 	"github.com/fbisdevoptics/backend/internal/handlers"
+	alertingevaluator "github.com/fbisdevoptics/backend/internal/modules/alerting/evaluator"
+	alertinghandlers "github.com/fbisdevoptics/backend/internal/modules/alerting/handlers"
+	alertingrepositories "github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+	alertingservices "github.com/fbisdevoptics/backend/internal/modules/alerting/services"
+	apimonitoringhandlers "github.com/fbisdevoptics/backend/internal/modules/apimonitoring/handlers"
+	apimonitoringrepositories "github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+	apimonitoringservices "github.com/fbisdevoptics/backend/internal/modules/apimonitoring/services"
 	authhandlers "github.com/fbisdevoptics/backend/internal/modules/auth/handlers"
+	authoidc "github.com/fbisdevoptics/backend/internal/modules/auth/oidc"
 	authrepositories "github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
 	authservices "github.com/fbisdevoptics/backend/internal/modules/auth/services"
+	k8smonitoringcollectors "github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	k8smonitoringgrafana "github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/grafana"
 	k8smonitoringhandlers "github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/handlers"
+	k8smonitoringprometheus "github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/prometheus"
+	k8smonitoringrepositories "github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
 	k8smonitoringservices "github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
 	metricshandlers "github.com/fbisdevoptics/backend/internal/modules/metrics/handlers"
+	metricsservices "github.com/fbisdevoptics/backend/internal/modules/metrics/services"
+	notificationshandlers "github.com/fbisdevoptics/backend/internal/modules/notifications/handlers"
+	notificationsmodels "github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+	notificationsrepositories "github.com/fbisdevoptics/backend/internal/modules/notifications/repositories"
+	notificationsservices "github.com/fbisdevoptics/backend/internal/modules/notifications/services"
+	pipelineshandlers "github.com/fbisdevoptics/backend/internal/modules/pipelines/handlers"
+	pipelinesrepositories "github.com/fbisdevoptics/backend/internal/modules/pipelines/repositories"
+	pipelinesservices "github.com/fbisdevoptics/backend/internal/modules/pipelines/services"
+	serverhandlers "github.com/fbisdevoptics/backend/internal/modules/servers/handlers"
+	serverrepositories "github.com/fbisdevoptics/backend/internal/modules/servers/repositories"
+	serverservices "github.com/fbisdevoptics/backend/internal/modules/servers/services"
 	"github.com/fbisdevoptics/backend/internal/repositories"
 	"github.com/fbisdevoptics/backend/internal/services"
 )
 
+// Postgres advisory lock keys electing the replica that runs each scheduler.
+const (
+	checkSchedulerLockKey int64 = 0x6170690001
+	runSchedulerLockKey   int64 = 0x6170690002
+	alertEngineLockKey    int64 = 0x616c650001
+)
+
 func main() {
 	startedAt := time.Now()
 	// Initialize logger
@@ -56,20 +86,23 @@ func main() {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
 
+	requestMetrics := metricshandlers.NewRequestMetrics("/metrics", "/health")
+
 	// Middleware
 	router.Use(corsMiddleware())
 	router.Use(loggingMiddleware(logger))
+	router.Use(requestMetrics.Middleware())
 
 	// Health check endpoint
 	router.GET("/health", healthCheckHandler)
 
+	// Prometheus scrape endpoint
+	router.GET("/metrics", requestMetrics.Handler())
+
 	// Initialize repositories and services
 	userRepo := repositories.NewUserRepository()
 	userService := services.NewUserService(userRepo)
 	userHandler := handlers.NewUserHandler(userService)
-	k8sHealthService := k8smonitoringservices.NewHealthService()
-	k8sHealthHandler := k8smonitoringhandlers.NewHealthHandler(k8sHealthService)
-	metricsHandler := metricshandlers.NewSummaryHandler(startedAt)
 	db, err := initDB(cfg)
 	if err != nil {
 		sugar.Fatalf("Failed to initialize database: %v", err)
@@ -80,12 +113,227 @@ func main() {
 		sugar.Fatalf("Failed to ensure auth schema: %v", err)
 	}
 
+	if err := ensureK8sSchema(db); err != nil {
+		sugar.Fatalf("Failed to ensure k8s schema: %v", err)
+	}
+
+	if err := ensureAPIMonitoringSchema(db); err != nil {
+		sugar.Fatalf("Failed to ensure API monitoring schema: %v", err)
+	}
+
+	if err := ensurePipelinesSchema(db); err != nil {
+		sugar.Fatalf("Failed to ensure pipelines schema: %v", err)
+	}
+
+	if err := ensureServersSchema(db); err != nil {
+		sugar.Fatalf("Failed to ensure servers schema: %v", err)
+	}
+
+	if err := ensureAlertingSchema(db); err != nil {
+		sugar.Fatalf("Failed to ensure alerting schema: %v", err)
+	}
+
+	if err := ensureNotificationsSchema(db); err != nil {
+		sugar.Fatalf("Failed to ensure notifications schema: %v", err)
+	}
+
 	authRepo := authrepositories.NewUserRepository(db)
-	authService := authservices.NewAuthService(authRepo, cfg.Auth.JWTSecret)
+	refreshTokenRepo := authrepositories.NewRefreshTokenRepository(db)
+	var authKeys authservices.Keys
+	var keyManager *authservices.KeyManager
+	if cfg.Auth.SigningAlgorithm == "HS256" {
+		authKeys = authservices.NewSecretKeys(cfg.Auth.JWTSecret)
+	} else {
+		keyManager, err = authservices.NewKeyManager(authrepositories.NewSigningKeyRepository(db), authservices.KeyOptions{
+			Algorithm:        cfg.Auth.SigningAlgorithm,
+			RotationInterval: cfg.Auth.KeyRotationInterval,
+			Overlap:          cfg.Auth.KeyOverlap,
+		}, logger)
+		if err != nil {
+			sugar.Fatalf("Failed to initialize signing keys: %v", err)
+		}
+		if err := keyManager.Start(); err != nil {
+			sugar.Fatalf("Failed to load signing keys: %v", err)
+		}
+		authKeys = keyManager
+	}
+	authService := authservices.NewAuthService(authRepo, refreshTokenRepo, authKeys, authservices.TokenOptions{
+		Issuer:          cfg.Auth.Issuer,
+		Audience:        cfg.Auth.Audience,
+		TTL:             cfg.Auth.TokenTTL,
+		RefreshTTL:      cfg.Auth.RefreshTokenTTL,
+		VersionCacheTTL: cfg.Auth.SessionCacheTTL,
+	})
+	refreshTokenPruner := authservices.NewRefreshTokenPruner(refreshTokenRepo, logger)
 	authHandler := authhandlers.NewAuthHandler(authService)
 	authMiddleware := authhandlers.NewAuthMiddleware(authService)
+	var oidcClient authoidc.Client
+	if cfg.OIDC.Issuer != "" {
+		oidcClient, err = authoidc.NewClient(authoidc.Options{
+			Issuer:       cfg.OIDC.Issuer,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+			Scopes:       cfg.OIDC.Scopes,
+			Timeout:      cfg.OIDC.Timeout,
+		})
+		if err != nil {
+			sugar.Fatalf("Failed to initialize OIDC client: %v", err)
+		}
+	}
+	roleMappings := make([]authservices.RoleMapping, 0, len(cfg.OIDC.RoleMappings))
+	for _, mapping := range cfg.OIDC.RoleMappings {
+		roleMappings = append(roleMappings, authservices.RoleMapping{Group: mapping.Group, Role: mapping.Role})
+	}
+	oidcService, err := authservices.NewOIDCService(oidcClient, authrepositories.NewOIDCLoginRepository(db), authService, authservices.OIDCOptions{
+		GroupsClaim:  cfg.OIDC.GroupsClaim,
+		RoleMappings: roleMappings,
+		DefaultRole:  cfg.OIDC.DefaultRole,
+	})
+	if err != nil {
+		sugar.Fatalf("Failed to initialize OIDC sign-in: %v", err)
+	}
+	oidcHandler := authhandlers.NewOIDCHandler(oidcService)
+	jwksHandler := authhandlers.NewJWKSHandler(authKeys)
+
+	k8sClusterRepo := k8smonitoringrepositories.NewClusterRepository(db)
+	k8sClients := k8smonitoringcollectors.NewCredentialsProvider(cfg.Kubernetes.Kubeconfig)
+	k8sClusterService := k8smonitoringservices.NewClusterService(k8sClusterRepo, k8sClients)
+	k8sClusterHandler := k8smonitoringhandlers.NewClusterHandler(k8sClusterService)
+	var promClient k8smonitoringprometheus.Client
+	if cfg.Prometheus.URL != "" {
+		promClient, err = k8smonitoringprometheus.NewClient(k8smonitoringprometheus.Options{
+			URL:         cfg.Prometheus.URL,
+			BearerToken: cfg.Prometheus.BearerToken,
+			Timeout:     cfg.Prometheus.Timeout,
+		})
+		if err != nil {
+			sugar.Fatalf("Failed to configure Prometheus client: %v", err)
+		}
+	}
+	k8sHealthService := k8smonitoringservices.NewHealthService(k8sClusterRepo, k8sClients, cfg.Kubernetes.ClusterTimeout, promClient, cfg.Prometheus.ClusterLabel)
+	k8sHealthHandler := k8smonitoringhandlers.NewHealthHandler(k8sHealthService)
+	k8sNodeService := k8smonitoringservices.NewNodeService(k8sClusterRepo, k8sClients)
+	k8sNodeHandler := k8smonitoringhandlers.NewNodeHandler(k8sNodeService)
+	k8sPodService := k8smonitoringservices.NewPodService(k8sClusterRepo, k8sClients)
+	k8sPodHandler := k8smonitoringhandlers.NewPodHandler(k8sPodService)
+	k8sWorkloadService := k8smonitoringservices.NewWorkloadService(k8sClusterRepo, k8sClients)
+	k8sWorkloadHandler := k8smonitoringhandlers.NewWorkloadHandler(k8sWorkloadService)
+	k8sBatchService := k8smonitoringservices.NewBatchService(k8sClusterRepo, k8sClients)
+	k8sBatchHandler := k8smonitoringhandlers.NewBatchHandler(k8sBatchService)
+	k8sNamespaceService := k8smonitoringservices.NewNamespaceService(k8sClusterRepo, k8sClients)
+	k8sNamespaceHandler := k8smonitoringhandlers.NewNamespaceHandler(k8sNamespaceService)
+	var grafanaClient k8smonitoringgrafana.Client
+	if cfg.Grafana.URL != "" {
+		grafanaClient, err = k8smonitoringgrafana.NewClient(k8smonitoringgrafana.Options{
+			URL:      cfg.Grafana.URL,
+			APIToken: cfg.Grafana.APIToken,
+			Timeout:  cfg.Grafana.Timeout,
+		})
+		if err != nil {
+			sugar.Fatalf("Failed to configure Grafana client: %v", err)
+		}
+	}
+	k8sDashboardService := k8smonitoringservices.NewDashboardService(k8sClusterRepo, cfg.Prometheus.ClusterLabel, grafanaClient, cfg.Grafana.FolderUID)
+	k8sDashboardHandler := k8smonitoringhandlers.NewDashboardHandler(k8sDashboardService)
+
+	checkRepo := apimonitoringrepositories.NewCheckRepository(db)
+	checkService := apimonitoringservices.NewCheckService(checkRepo)
+	checkHandler := apimonitoringhandlers.NewCheckHandler(checkService)
+	checkScheduler := apimonitoringservices.NewScheduler(checkRepo, apimonitoringservices.SchedulerOptions{
+		Workers:   cfg.APIMonitoring.Workers,
+		Retention: cfg.APIMonitoring.Retention,
+		Lock:      apimonitoringrepositories.NewAdvisoryLock(db, checkSchedulerLockKey),
+	}, logger)
+	scheduleRepo := apimonitoringrepositories.NewScheduleRepository(db)
+	runClient := apimonitoringservices.NewRunClient()
+	scheduleService := apimonitoringservices.NewScheduleService(scheduleRepo, runClient)
+	scheduleHandler := apimonitoringhandlers.NewScheduleHandler(scheduleService)
+	runScheduler := apimonitoringservices.NewRunScheduler(scheduleRepo, apimonitoringservices.SchedulerOptions{
+		Client:    runClient,
+		Workers:   cfg.APIMonitoring.Workers,
+		Retention: cfg.APIMonitoring.Retention,
+		Lock:      apimonitoringrepositories.NewAdvisoryLock(db, runSchedulerLockKey),
+	}, logger)
+
+	pipelineRepo := pipelinesrepositories.NewRunRepository(db)
+	pipelineService := pipelinesservices.NewPipelineService(pipelineRepo)
+	pipelineHandler := pipelineshandlers.NewPipelineHandler(pipelineService, cfg.Pipelines.WebhookSecret)
+	if cfg.Pipelines.WebhookSecret == "" {
+		sugar.Warn("pipelines.webhook_secret is not set; pipeline webhooks will be rejected")
+	}
+
+	serverRepo := serverrepositories.NewServerRepository(db)
+	serverService := serverservices.NewServerService(serverRepo, cfg.Servers.MissedHeartbeats)
+	serverHandler := serverhandlers.NewServerHandler(serverService)
+	heartbeatPruner := serverservices.NewHeartbeatPruner(serverRepo, cfg.Servers.HeartbeatRetention, logger)
+
+	var notificationConfig notificationsmodels.Config
+	if cfg.Notifications.ConfigFile != "" {
+		notificationConfig, err = notificationsservices.LoadConfig(cfg.Notifications.ConfigFile)
+		if err != nil {
+			sugar.Fatalf("Failed to load notification config: %v", err)
+		}
+	} else {
+		sugar.Warn("notifications.config_file is not set; alerts will not be sent anywhere")
+	}
+	notifier, err := notificationsservices.NewNotifier(notificationConfig, notificationsrepositories.NewDeliveryRepository(db), notificationsservices.NotifierOptions{
+		MaxAttempts: cfg.Notifications.MaxAttempts,
+		Timeout:     cfg.Notifications.Timeout,
+		Retention:   cfg.Notifications.DeliveryRetention,
+	}, logger)
+	if err != nil {
+		sugar.Fatalf("Failed to configure notifications: %v", err)
+	}
+	notificationHandler := notificationshandlers.NewNotificationHandler(notifier)
+
+	alertRuleRepo := alertingrepositories.NewRuleRepository(db)
+	alertRepo := alertingrepositories.NewAlertRepository(db)
+	alertRuleHandler := alertinghandlers.NewRuleHandler(alertingservices.NewRuleService(alertRuleRepo))
+	alertSilenceRepo := alertingrepositories.NewSilenceRepository(db)
+	alertHandler := alertinghandlers.NewAlertHandler(alertingservices.NewAlertService(alertRepo, alertSilenceRepo))
+	alertSilenceHandler := alertinghandlers.NewSilenceHandler(alertingservices.NewSilenceService(alertSilenceRepo))
+	var promEvaluator alertingevaluator.Evaluator
+	if promClient != nil {
+		promEvaluator = alertingevaluator.NewPrometheus(promClient)
+	}
+	k8sSeriesService := k8smonitoringservices.NewSeriesService(k8sClusterRepo, k8sClients, cfg.Kubernetes.ClusterTimeout, cfg.Prometheus.ClusterLabel)
+	alertEngine := alertingservices.NewEngine(alertRuleRepo, alertRepo, k8sSeriesService, promEvaluator, alertingservices.EngineOptions{
+		Interval:          cfg.Alerting.EvaluationInterval,
+		ResolvedRetention: cfg.Alerting.ResolvedRetention,
+		RepeatInterval:    cfg.Alerting.RepeatInterval,
+		Lock:              apimonitoringrepositories.NewAdvisoryLock(db, alertEngineLockKey),
+		Notifier:          notifier,
+		Silences:          alertSilenceRepo,
+	}, logger)
+
+	// Each module reports its own stats type; the summary takes them as
+	// functions so none of them depends on the metrics module.
+	metricsService := metricsservices.NewSummaryService(startedAt, metricsservices.Sources{
+		Endpoints: func(ctx context.Context, since time.Time) (metricsservices.EndpointStats, error) {
+			stats, err := checkService.EndpointStats(ctx, since)
+			return metricsservices.EndpointStats(stats), err
+		},
+		Latency: requestMetrics.LatencyStats,
+		Runs: func(ctx context.Context, since time.Time) (metricsservices.RunStats, error) {
+			stats, err := scheduleService.RunStats(ctx, since)
+			return metricsservices.RunStats(stats), err
+		},
+		Pipelines: func(ctx context.Context, since time.Time) (metricsservices.PipelineStats, error) {
+			stats, err := pipelineService.PipelineStats(ctx, since)
+			return metricsservices.PipelineStats(stats), err
+		},
+		Servers: func(ctx context.Context, since time.Time) (metricsservices.ServerStats, error) {
+			stats, err := serverService.ServerStats(ctx, since)
+			return metricsservices.ServerStats(stats), err
+		},
+	}, logger)
+	metricsHandler := metricshandlers.NewSummaryHandler(metricsService)
 
 	// Register routes
+	// Public keys for services verifying our access tokens.
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	apiV1 := router.Group("/api/v1")
 	{
 		users := apiV1.Group("/users")
@@ -99,6 +347,10 @@ func main() {
 		{
 			auth.POST("/signup", authHandler.SignUp)
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.Refresh)
+			auth.POST("/logout", authHandler.Logout)
+			auth.GET("/oidc/login", oidcHandler.BeginLogin)
+			auth.POST("/oidc/callback", oidcHandler.CompleteLogin)
 		}
 
 		metrics := apiV1.Group("/metrics")
@@ -106,12 +358,91 @@ func main() {
 			metrics.GET("/summary", metricsHandler.GetSummary)
 		}
 
+		// Webhooks authenticate with an HMAC signature instead of a JWT.
+		pipelineWebhooks := apiV1.Group("/pipelines/webhooks")
+		{
+			pipelineWebhooks.POST("/github", pipelineHandler.GitHubWebhook)
+			pipelineWebhooks.POST("/generic", pipelineHandler.GenericWebhook)
+		}
+
+		// Agents authenticate with their own bearer token instead of a JWT.
+		apiV1.POST("/servers/heartbeat", serverHandler.Heartbeat)
+
 		protected := apiV1.Group("/")
 		protected.Use(authMiddleware.RequireAuth())
 		{
 			k8sProtected := protected.Group("/k8s")
 			{
+				k8sProtected.GET("/health", k8sHealthHandler.GetFleetHealth)
 				k8sProtected.GET("/health/:cluster", k8sHealthHandler.GetClusterHealth)
+				k8sProtected.GET("/clusters", k8sClusterHandler.ListClusters)
+				k8sProtected.GET("/clusters/:cluster", k8sClusterHandler.GetCluster)
+				k8sProtected.GET("/clusters/:cluster/nodes", k8sNodeHandler.ListNodes)
+				k8sProtected.GET("/clusters/:cluster/namespaces", k8sNamespaceHandler.ListNamespaces)
+				k8sProtected.GET("/clusters/:cluster/namespaces/:namespace", k8sNamespaceHandler.GetNamespace)
+				k8sProtected.GET("/clusters/:cluster/pods", k8sPodHandler.ListPods)
+				k8sProtected.GET("/clusters/:cluster/namespaces/:namespace/pods", k8sPodHandler.ListPods)
+				k8sProtected.GET("/clusters/:cluster/workloads", k8sWorkloadHandler.ListRollouts)
+				k8sProtected.GET("/clusters/:cluster/namespaces/:namespace/workloads", k8sWorkloadHandler.ListRollouts)
+				k8sProtected.GET("/clusters/:cluster/jobs", k8sBatchHandler.ListJobs)
+				k8sProtected.GET("/clusters/:cluster/namespaces/:namespace/jobs", k8sBatchHandler.ListJobs)
+				k8sProtected.GET("/clusters/:cluster/cronjobs", k8sBatchHandler.ListCronJobs)
+				k8sProtected.GET("/clusters/:cluster/namespaces/:namespace/cronjobs", k8sBatchHandler.ListCronJobs)
+				k8sProtected.GET("/dashboards", k8sDashboardHandler.ListDashboards)
+				k8sProtected.GET("/dashboards/:category", k8sDashboardHandler.DownloadDashboard)
+			}
+
+			checks := protected.Group("/checks")
+			{
+				checks.GET("", checkHandler.ListChecks)
+				checks.GET("/:id", checkHandler.GetCheck)
+				checks.GET("/:id/results", checkHandler.ListResults)
+			}
+
+			schedules := protected.Group("/schedules")
+			{
+				schedules.GET("", scheduleHandler.ListSchedules)
+				schedules.GET("/:id", scheduleHandler.GetSchedule)
+				schedules.GET("/:id/runs", scheduleHandler.ListRuns)
+			}
+
+			pipelines := protected.Group("/pipelines")
+			{
+				pipelines.GET("/runs", pipelineHandler.ListRuns)
+				pipelines.GET("/stats", pipelineHandler.GetStats)
+				pipelines.GET("/branches", pipelineHandler.ListBranches)
+			}
+
+			servers := protected.Group("/servers")
+			{
+				servers.GET("", serverHandler.ListServers)
+				servers.GET("/:id", serverHandler.GetServer)
+			}
+
+			alerts := protected.Group("/alerts")
+			{
+				alerts.GET("", alertHandler.ListAlerts)
+				alerts.GET("/groups", alertHandler.GroupAlerts)
+				alerts.GET("/:id", alertHandler.GetAlert)
+			}
+
+			silences := protected.Group("/silences")
+			{
+				silences.GET("", alertSilenceHandler.ListSilences)
+				silences.GET("/:id", alertSilenceHandler.GetSilence)
+			}
+
+			alertRules := protected.Group("/alert-rules")
+			{
+				alertRules.GET("", alertRuleHandler.ListRules)
+				alertRules.GET("/export", alertRuleHandler.ExportRules)
+				alertRules.GET("/:id", alertRuleHandler.GetRule)
+			}
+
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("/receivers", notificationHandler.ListReceivers)
+				notifications.GET("/deliveries", notificationHandler.ListDeliveries)
 			}
 
 			admin := protected.Group("/admin")
@@ -123,6 +454,32 @@ func main() {
 				admin.GET("/users", authHandler.ListUsers)
 				admin.POST("/users", authHandler.SignUp)
 				admin.PUT("/users/:id/role", authHandler.UpdateRole)
+				admin.PUT("/users/:id/password", authHandler.ResetPassword)
+				admin.POST("/users/:id/logout", authHandler.RevokeSessions)
+				admin.POST("/k8s/clusters", k8sClusterHandler.CreateCluster)
+				admin.PUT("/k8s/clusters/:cluster", k8sClusterHandler.UpdateCluster)
+				admin.DELETE("/k8s/clusters/:cluster", k8sClusterHandler.DeleteCluster)
+				admin.POST("/k8s/dashboards/push", k8sDashboardHandler.PushDashboards)
+				admin.POST("/checks", checkHandler.CreateCheck)
+				admin.PUT("/checks/:id", checkHandler.UpdateCheck)
+				admin.DELETE("/checks/:id", checkHandler.DeleteCheck)
+				admin.POST("/schedules", scheduleHandler.CreateSchedule)
+				admin.PUT("/schedules/:id", scheduleHandler.UpdateSchedule)
+				admin.DELETE("/schedules/:id", scheduleHandler.DeleteSchedule)
+				admin.POST("/schedules/:id/run", scheduleHandler.TriggerRun)
+				admin.POST("/schedules/:id/pause", scheduleHandler.PauseSchedule)
+				admin.POST("/schedules/:id/resume", scheduleHandler.ResumeSchedule)
+				admin.POST("/servers", serverHandler.RegisterServer)
+				admin.PUT("/servers/:id", serverHandler.UpdateServer)
+				admin.DELETE("/servers/:id", serverHandler.DeleteServer)
+				admin.POST("/servers/:id/token", serverHandler.RotateToken)
+				admin.POST("/alert-rules", alertRuleHandler.CreateRule)
+				admin.POST("/alert-rules/import", alertRuleHandler.ImportRules)
+				admin.PUT("/alert-rules/:id", alertRuleHandler.UpdateRule)
+				admin.DELETE("/alert-rules/:id", alertRuleHandler.DeleteRule)
+				admin.POST("/silences", alertSilenceHandler.CreateSilence)
+				admin.DELETE("/silences/:id", alertSilenceHandler.ExpireSilence)
+				admin.POST("/notifications/receivers/:name/test", notificationHandler.TestReceiver)
 			}
 		}
 	}
@@ -136,6 +493,53 @@ func main() {
 	var wg sync.WaitGroup
 	serverErrors := make(chan error, 1)
 
+	schedulerCtx, stopSchedulers := context.WithCancel(context.Background())
+	defer stopSchedulers()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		checkScheduler.Run(schedulerCtx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runScheduler.Run(schedulerCtx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		heartbeatPruner.Run(schedulerCtx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		refreshTokenPruner.Run(schedulerCtx)
+	}()
+
+	if keyManager != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keyManager.Run(schedulerCtx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		alertEngine.Run(schedulerCtx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		notifier.Run(schedulerCtx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -172,13 +576,16 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 		httpServer.Shutdown(ctx)
+		stopSchedulers()
 	case err := <-serverErrors:
 		if err != nil && err != http.ErrServerClosed {
 			sugar.Errorw("Server error", "error", err)
 		}
+		stopSchedulers()
 	}
 
 	wg.Wait()
+	notifier.Wait()
 	sugar.Info("Server stopped")
 }
 
@@ -246,8 +653,288 @@ CREATE TABLE IF NOT EXISTS auth_users (
   email TEXT UNIQUE NOT NULL,
   password_hash TEXT NOT NULL,
   role TEXT NOT NULL,
+  token_version INTEGER NOT NULL DEFAULT 0,
   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
+
+-- Tables created before sessions could be revoked lack the column.
+ALTER TABLE auth_users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS auth_refresh_tokens (
+  id TEXT PRIMARY KEY,
+  user_id TEXT NOT NULL REFERENCES auth_users(id) ON DELETE CASCADE,
+  family_id TEXT NOT NULL,
+  token_hash TEXT UNIQUE NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS auth_refresh_tokens_family_idx ON auth_refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS auth_refresh_tokens_user_idx ON auth_refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS auth_refresh_tokens_expires_idx ON auth_refresh_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS auth_identities (
+  issuer TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  user_id TEXT NOT NULL REFERENCES auth_users(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS auth_identities_user_idx ON auth_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS auth_oidc_logins (
+  state TEXT PRIMARY KEY,
+  nonce TEXT NOT NULL,
+  code_verifier TEXT NOT NULL,
+  binding TEXT NOT NULL DEFAULT '',
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+-- Sign-ins started before they were bound to a browser cannot complete.
+ALTER TABLE auth_oidc_logins ADD COLUMN IF NOT EXISTS binding TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS auth_oidc_logins_expires_idx ON auth_oidc_logins (expires_at);
+
+CREATE TABLE IF NOT EXISTS auth_signing_keys (
+  kid TEXT PRIMARY KEY,
+  algorithm TEXT NOT NULL,
+  private_key BYTEA NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  activates_at TIMESTAMPTZ NOT NULL
+);
+`)
+	return err
+}
+
+func ensureK8sSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS k8s_clusters (
+  name TEXT PRIMARY KEY,
+  api_endpoint TEXT NOT NULL DEFAULT '',
+  credentials_ref TEXT NOT NULL,
+  labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+  environment TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+`)
+	return err
+}
+
+func ensureAPIMonitoringSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS api_checks (
+  id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  method TEXT NOT NULL DEFAULT 'GET',
+  headers JSONB NOT NULL DEFAULT '{}'::jsonb,
+  body TEXT NOT NULL DEFAULT '',
+  expected_status INTEGER NOT NULL DEFAULT 200,
+  assertions JSONB NOT NULL DEFAULT '[]'::jsonb,
+  interval_seconds INTEGER NOT NULL DEFAULT 60,
+  timeout_seconds INTEGER NOT NULL DEFAULT 10,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS api_check_results (
+  id BIGSERIAL PRIMARY KEY,
+  check_id TEXT NOT NULL REFERENCES api_checks(id) ON DELETE CASCADE,
+  started_at TIMESTAMPTZ NOT NULL,
+  duration_ms BIGINT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 0,
+  success BOOLEAN NOT NULL,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS api_check_results_check_started_idx
+  ON api_check_results (check_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS api_check_results_started_idx
+  ON api_check_results (started_at);
+
+CREATE TABLE IF NOT EXISTS api_schedules (
+  id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  method TEXT NOT NULL DEFAULT 'GET',
+  headers JSONB NOT NULL DEFAULT '{}'::jsonb,
+  body TEXT NOT NULL DEFAULT '',
+  schedule TEXT NOT NULL,
+  timeout_seconds INTEGER NOT NULL DEFAULT 30,
+  paused BOOLEAN NOT NULL DEFAULT FALSE,
+  next_run_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS api_schedules_next_run_idx
+  ON api_schedules (next_run_at) WHERE NOT paused;
+
+CREATE TABLE IF NOT EXISTS api_schedule_runs (
+  id BIGSERIAL PRIMARY KEY,
+  schedule_id TEXT NOT NULL REFERENCES api_schedules(id) ON DELETE CASCADE,
+  trigger TEXT NOT NULL,
+  started_at TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ NOT NULL,
+  outcome TEXT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 0,
+  response_body TEXT NOT NULL DEFAULT '',
+  truncated BOOLEAN NOT NULL DEFAULT FALSE,
+  error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS api_schedule_runs_schedule_started_idx
+  ON api_schedule_runs (schedule_id, started_at DESC);
+CREATE INDEX IF NOT EXISTS api_schedule_runs_started_idx
+  ON api_schedule_runs (started_at);
+`)
+	return err
+}
+
+func ensurePipelinesSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS pipeline_runs (
+  id BIGSERIAL PRIMARY KEY,
+  provider TEXT NOT NULL,
+  external_id TEXT NOT NULL,
+  repository TEXT NOT NULL,
+  branch TEXT NOT NULL DEFAULT '',
+  workflow TEXT NOT NULL,
+  commit_sha TEXT NOT NULL DEFAULT '',
+  attempt INTEGER NOT NULL DEFAULT 1,
+  status TEXT NOT NULL,
+  url TEXT NOT NULL DEFAULT '',
+  started_at TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ,
+  received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (provider, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS pipeline_runs_repo_branch_idx
+  ON pipeline_runs (repository, branch, started_at DESC);
+CREATE INDEX IF NOT EXISTS pipeline_runs_finished_idx
+  ON pipeline_runs (finished_at);
+`)
+	return err
+}
+
+func ensureServersSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS servers (
+  id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  name TEXT NOT NULL,
+  labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+  heartbeat_interval_seconds INTEGER NOT NULL DEFAULT 30,
+  agent_token_hash TEXT UNIQUE NOT NULL,
+  hostname TEXT NOT NULL DEFAULT '',
+  os TEXT NOT NULL DEFAULT '',
+  load JSONB,
+  disks JSONB,
+  last_heartbeat_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS server_heartbeats (
+  server_id TEXT NOT NULL REFERENCES servers(id) ON DELETE CASCADE,
+  received_at TIMESTAMPTZ NOT NULL,
+  load1 DOUBLE PRECISION NOT NULL DEFAULT 0,
+  PRIMARY KEY (server_id, received_at)
+);
+
+CREATE INDEX IF NOT EXISTS server_heartbeats_received_idx
+  ON server_heartbeats (received_at);
+`)
+	return err
+}
+
+func ensureAlertingSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  name TEXT NOT NULL,
+  group_name TEXT NOT NULL DEFAULT 'default',
+  source TEXT NOT NULL DEFAULT 'cluster',
+  expr TEXT NOT NULL,
+  for_duration TEXT NOT NULL DEFAULT '0s',
+  labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+  annotations JSONB NOT NULL DEFAULT '{}'::jsonb,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  health TEXT NOT NULL DEFAULT 'unknown',
+  last_error TEXT NOT NULL DEFAULT '',
+  last_evaluated_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (group_name, name)
+);
+
+CREATE TABLE IF NOT EXISTS alerts (
+  id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  rule_id TEXT NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+  fingerprint TEXT NOT NULL,
+  state TEXT NOT NULL,
+  labels JSONB NOT NULL DEFAULT '{}'::jsonb,
+  annotations JSONB NOT NULL DEFAULT '{}'::jsonb,
+  value DOUBLE PRECISION NOT NULL DEFAULT 0,
+  active_at TIMESTAMPTZ NOT NULL,
+  fired_at TIMESTAMPTZ,
+  resolved_at TIMESTAMPTZ,
+  last_evaluated_at TIMESTAMPTZ NOT NULL,
+  last_notified_at TIMESTAMPTZ
+);
+
+-- Tables created before notifications repeated lack the column. Alerts
+-- already firing count as notified at the upgrade rather than paging again.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS last_notified_at TIMESTAMPTZ DEFAULT NOW();
+ALTER TABLE alerts ALTER COLUMN last_notified_at DROP DEFAULT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS alerts_active_fingerprint_idx
+  ON alerts (rule_id, fingerprint) WHERE state <> 'resolved';
+
+CREATE INDEX IF NOT EXISTS alerts_state_active_idx
+  ON alerts (state, active_at DESC);
+
+CREATE TABLE IF NOT EXISTS alert_silences (
+  id TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+  matchers JSONB NOT NULL,
+  starts_at TIMESTAMPTZ NOT NULL,
+  ends_at TIMESTAMPTZ NOT NULL,
+  created_by TEXT NOT NULL,
+  comment TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS alert_silences_ends_idx
+  ON alert_silences (ends_at);
+`)
+	return err
+}
+
+func ensureNotificationsSchema(db *sql.DB) error {
+	_, err := db.Exec(`
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+  id BIGSERIAL PRIMARY KEY,
+  receiver TEXT NOT NULL,
+  channel TEXT NOT NULL,
+  target TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL,
+  alert_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+  outcome TEXT NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  error TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS notification_deliveries_created_idx
+  ON notification_deliveries (created_at DESC);
 `)
 	return err
 }
@@ -2,16 +2,26 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Environment string
-	Server      ServerConfig
-	Database    DatabaseConfig
-	GRPC        GRPCConfig
-	Auth        AuthConfig
+	Environment   string
+	Server        ServerConfig
+	Database      DatabaseConfig
+	GRPC          GRPCConfig
+	Auth          AuthConfig
+	OIDC          OIDCConfig
+	Kubernetes    KubernetesConfig
+	Prometheus    PrometheusConfig
+	Grafana       GrafanaConfig
+	APIMonitoring APIMonitoringConfig
+	Pipelines     PipelinesConfig
+	Servers       ServersConfig
+	Alerting      AlertingConfig
+	Notifications NotificationsConfig
 }
 
 type ServerConfig struct {
@@ -33,7 +43,83 @@ type GRPCConfig struct {
 }
 
 type AuthConfig struct {
-	JWTSecret string
+	// SigningAlgorithm is EdDSA or RS256 for rotating keys published at
+	// /.well-known/jwks.json, or HS256 to sign with JWTSecret.
+	SigningAlgorithm    string
+	JWTSecret           string
+	KeyRotationInterval time.Duration
+	KeyOverlap          time.Duration
+	Issuer              string
+	Audience            string
+	TokenTTL            time.Duration
+	RefreshTokenTTL     time.Duration
+	SessionCacheTTL     time.Duration
+}
+
+// OIDCConfig enables sign-in through an OpenID Connect provider when
+// Issuer is set. RedirectURL is the frontend page the provider returns to.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	GroupsClaim  string
+	RoleMappings []OIDCRoleMapping
+	DefaultRole  string
+	Timeout      time.Duration
+}
+
+// OIDCRoleMapping grants Role to members of the provider group Group.
+type OIDCRoleMapping struct {
+	Group string
+	Role  string
+}
+
+type KubernetesConfig struct {
+	Kubeconfig     string
+	ClusterTimeout time.Duration
+}
+
+type PrometheusConfig struct {
+	URL          string
+	BearerToken  string
+	Timeout      time.Duration
+	ClusterLabel string
+}
+
+type GrafanaConfig struct {
+	URL       string
+	APIToken  string
+	FolderUID string
+	Timeout   time.Duration
+}
+
+type APIMonitoringConfig struct {
+	Workers   int
+	Retention time.Duration
+}
+
+type PipelinesConfig struct {
+	WebhookSecret string
+}
+
+type ServersConfig struct {
+	MissedHeartbeats   int
+	HeartbeatRetention time.Duration
+}
+
+type AlertingConfig struct {
+	EvaluationInterval time.Duration
+	ResolvedRetention  time.Duration
+	RepeatInterval     time.Duration
+}
+
+type NotificationsConfig struct {
+	ConfigFile        string
+	MaxAttempts       int
+	Timeout           time.Duration
+	DeliveryRetention time.Duration
 }
 
 func Load() (*Config, error) {
@@ -54,7 +140,45 @@ func Load() (*Config, error) {
 	viper.SetDefault("database.database", "myapp")
 	viper.SetDefault("grpc.enabled", false)
 	viper.SetDefault("grpc.port", 50051)
-	viper.SetDefault("auth.jwt_secret", "your-secret-key-change-this")
+	viper.SetDefault("auth.signing_algorithm", "EdDSA")
+	viper.SetDefault("auth.jwt_secret", "")
+	viper.SetDefault("auth.key_rotation_interval", "720h")
+	viper.SetDefault("auth.key_overlap", "1h")
+	viper.SetDefault("auth.issuer", "devoptics")
+	viper.SetDefault("auth.audience", "devoptics-api")
+	viper.SetDefault("auth.token_ttl", "15m")
+	viper.SetDefault("auth.refresh_token_ttl", "720h")
+	viper.SetDefault("auth.session_cache_ttl", "10s")
+	viper.SetDefault("oidc.issuer", "")
+	viper.SetDefault("oidc.client_id", "")
+	viper.SetDefault("oidc.client_secret", "")
+	viper.SetDefault("oidc.redirect_url", "http://localhost:3000/auth/oidc/callback")
+	viper.SetDefault("oidc.scopes", []string{"openid", "email", "profile"})
+	viper.SetDefault("oidc.groups_claim", "groups")
+	viper.SetDefault("oidc.default_role", "")
+	viper.SetDefault("oidc.timeout", "10s")
+	viper.SetDefault("kubernetes.kubeconfig", "")
+	viper.SetDefault("kubernetes.cluster_timeout", "10s")
+	viper.SetDefault("prometheus.url", "")
+	viper.SetDefault("prometheus.bearer_token", "")
+	viper.SetDefault("prometheus.timeout", "10s")
+	viper.SetDefault("prometheus.cluster_label", "cluster_name")
+	viper.SetDefault("grafana.url", "")
+	viper.SetDefault("grafana.api_token", "")
+	viper.SetDefault("grafana.folder_uid", "")
+	viper.SetDefault("grafana.timeout", "10s")
+	viper.SetDefault("api_monitoring.workers", 10)
+	viper.SetDefault("api_monitoring.retention", "720h")
+	viper.SetDefault("pipelines.webhook_secret", "")
+	viper.SetDefault("servers.missed_heartbeats", 3)
+	viper.SetDefault("servers.heartbeat_retention", "720h")
+	viper.SetDefault("alerting.evaluation_interval", "30s")
+	viper.SetDefault("alerting.resolved_retention", "720h")
+	viper.SetDefault("alerting.repeat_interval", "4h")
+	viper.SetDefault("notifications.config_file", "")
+	viper.SetDefault("notifications.max_attempts", 5)
+	viper.SetDefault("notifications.timeout", "10s")
+	viper.SetDefault("notifications.delivery_retention", "720h")
 
 	// Read environment variables
 	viper.BindEnv("environment", "APP_ENV")
@@ -64,6 +188,19 @@ func Load() (*Config, error) {
 	viper.BindEnv("database.user", "DB_USER")
 	viper.BindEnv("database.password", "DB_PASSWORD")
 	viper.BindEnv("database.database", "DB_NAME")
+	viper.BindEnv("auth.jwt_secret", "JWT_SECRET")
+	viper.BindEnv("oidc.issuer", "OIDC_ISSUER")
+	viper.BindEnv("oidc.client_id", "OIDC_CLIENT_ID")
+	viper.BindEnv("oidc.client_secret", "OIDC_CLIENT_SECRET")
+	viper.BindEnv("oidc.redirect_url", "OIDC_REDIRECT_URL")
+	viper.BindEnv("oidc.default_role", "OIDC_DEFAULT_ROLE")
+	viper.BindEnv("kubernetes.kubeconfig", "KUBECONFIG")
+	viper.BindEnv("prometheus.url", "PROMETHEUS_URL")
+	viper.BindEnv("prometheus.bearer_token", "PROMETHEUS_BEARER_TOKEN")
+	viper.BindEnv("grafana.url", "GRAFANA_URL")
+	viper.BindEnv("grafana.api_token", "GRAFANA_API_TOKEN")
+	viper.BindEnv("pipelines.webhook_secret", "PIPELINES_WEBHOOK_SECRET")
+	viper.BindEnv("notifications.config_file", "NOTIFICATIONS_CONFIG_FILE")
 
 	// Try to read config file, but don't fail if it doesn't exist
 	if err := viper.ReadInConfig(); err != nil {
@@ -91,9 +228,106 @@ func Load() (*Config, error) {
 			Port:    viper.GetInt("grpc.port"),
 		},
 		Auth: AuthConfig{
-			JWTSecret: viper.GetString("auth.jwt_secret"),
+			SigningAlgorithm:    viper.GetString("auth.signing_algorithm"),
+			JWTSecret:           viper.GetString("auth.jwt_secret"),
+			KeyRotationInterval: viper.GetDuration("auth.key_rotation_interval"),
+			KeyOverlap:          viper.GetDuration("auth.key_overlap"),
+			Issuer:              viper.GetString("auth.issuer"),
+			Audience:            viper.GetString("auth.audience"),
+			TokenTTL:            viper.GetDuration("auth.token_ttl"),
+			RefreshTokenTTL:     viper.GetDuration("auth.refresh_token_ttl"),
+			SessionCacheTTL:     viper.GetDuration("auth.session_cache_ttl"),
+		},
+		OIDC: OIDCConfig{
+			Issuer:       viper.GetString("oidc.issuer"),
+			ClientID:     viper.GetString("oidc.client_id"),
+			ClientSecret: viper.GetString("oidc.client_secret"),
+			RedirectURL:  viper.GetString("oidc.redirect_url"),
+			Scopes:       viper.GetStringSlice("oidc.scopes"),
+			GroupsClaim:  viper.GetString("oidc.groups_claim"),
+			DefaultRole:  viper.GetString("oidc.default_role"),
+			Timeout:      viper.GetDuration("oidc.timeout"),
+		},
+		Kubernetes: KubernetesConfig{
+			Kubeconfig:     viper.GetString("kubernetes.kubeconfig"),
+			ClusterTimeout: viper.GetDuration("kubernetes.cluster_timeout"),
+		},
+		Prometheus: PrometheusConfig{
+			URL:          viper.GetString("prometheus.url"),
+			BearerToken:  viper.GetString("prometheus.bearer_token"),
+			Timeout:      viper.GetDuration("prometheus.timeout"),
+			ClusterLabel: viper.GetString("prometheus.cluster_label"),
+		},
+		Grafana: GrafanaConfig{
+			URL:       viper.GetString("grafana.url"),
+			APIToken:  viper.GetString("grafana.api_token"),
+			FolderUID: viper.GetString("grafana.folder_uid"),
+			Timeout:   viper.GetDuration("grafana.timeout"),
+		},
+		APIMonitoring: APIMonitoringConfig{
+			Workers:   viper.GetInt("api_monitoring.workers"),
+			Retention: viper.GetDuration("api_monitoring.retention"),
+		},
+		Pipelines: PipelinesConfig{
+			WebhookSecret: viper.GetString("pipelines.webhook_secret"),
+		},
+		Servers: ServersConfig{
+			MissedHeartbeats:   viper.GetInt("servers.missed_heartbeats"),
+			HeartbeatRetention: viper.GetDuration("servers.heartbeat_retention"),
+		},
+		Alerting: AlertingConfig{
+			EvaluationInterval: viper.GetDuration("alerting.evaluation_interval"),
+			ResolvedRetention:  viper.GetDuration("alerting.resolved_retention"),
+			RepeatInterval:     viper.GetDuration("alerting.repeat_interval"),
+		},
+		Notifications: NotificationsConfig{
+			ConfigFile:        viper.GetString("notifications.config_file"),
+			MaxAttempts:       viper.GetInt("notifications.max_attempts"),
+			Timeout:           viper.GetDuration("notifications.timeout"),
+			DeliveryRetention: viper.GetDuration("notifications.delivery_retention"),
 		},
 	}
 
+	// Group names are case-sensitive, and viper lower-cases map keys, so
+	// mappings are a list rather than a map.
+	if err := viper.UnmarshalKey("oidc.role_mappings", &cfg.OIDC.RoleMappings); err != nil {
+		return nil, fmt.Errorf("error reading oidc.role_mappings: %w", err)
+	}
+
+	if err := validateAuth(cfg.Environment, cfg.Auth); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+// placeholderSecrets are JWT secrets that have shipped as defaults or in
+// the example config, and so must be assumed public.
+var placeholderSecrets = map[string]bool{
+	"your-secret-key-change-this":               true,
+	"your-secret-key-change-this-in-production": true,
+}
+
+func validateAuth(environment string, auth AuthConfig) error {
+	switch auth.SigningAlgorithm {
+	case "EdDSA", "RS256":
+		if auth.KeyRotationInterval <= 0 {
+			return fmt.Errorf("auth.key_rotation_interval must be positive")
+		}
+		// Tokens signed just before a key retires must verify until they
+		// expire.
+		if auth.KeyOverlap < auth.TokenTTL {
+			return fmt.Errorf("auth.key_overlap (%s) must be at least auth.token_ttl (%s)", auth.KeyOverlap, auth.TokenTTL)
+		}
+	case "HS256":
+		if auth.JWTSecret == "" {
+			return fmt.Errorf("auth.jwt_secret is required with auth.signing_algorithm HS256")
+		}
+		if environment != "development" && placeholderSecrets[auth.JWTSecret] {
+			return fmt.Errorf("auth.jwt_secret is a placeholder; set a secret of your own outside development")
+		}
+	default:
+		return fmt.Errorf("unsupported auth.signing_algorithm %q", auth.SigningAlgorithm)
+	}
+	return nil
+}
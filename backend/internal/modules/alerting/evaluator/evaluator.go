@@ -0,0 +1,112 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/prometheus"
+)
+
+// MetricNameLabel holds a series' metric name.
+const MetricNameLabel = "__name__"
+
+var (
+	ErrInvalidExpr = errors.New("invalid rule expression")
+	ErrUnsupported = errors.New("unsupported in cluster rules")
+)
+
+// Sample is one element of a rule's result: a labelled value for which an
+// alert is active.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Evaluator runs a rule expression and returns the samples it produces.
+type Evaluator interface {
+	Evaluate(ctx context.Context, expr string) ([]Sample, error)
+}
+
+// Parse checks that expr is valid PromQL returning an instant vector or a
+// scalar, the only results an alert can be raised from.
+func Parse(expr string) (parser.Expr, error) {
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidExpr, err)
+	}
+	switch parsed.Type() {
+	case parser.ValueTypeVector, parser.ValueTypeScalar:
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("%w: expression must return a vector or scalar, got %s", ErrInvalidExpr, parsed.Type())
+	}
+}
+
+type prometheusEvaluator struct {
+	client prometheus.Client
+}
+
+// NewPrometheus returns an Evaluator that runs expressions as instant
+// queries against Prometheus.
+func NewPrometheus(client prometheus.Client) Evaluator {
+	return &prometheusEvaluator{client: client}
+}
+
+func (e *prometheusEvaluator) Evaluate(ctx context.Context, expr string) ([]Sample, error) {
+	result, err := e.client.Query(ctx, expr, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	switch result.Type {
+	case prometheus.ResultVector:
+		samples := make([]Sample, 0, len(result.Vector))
+		for _, sample := range result.Vector {
+			samples = append(samples, Sample{Labels: sample.Metric, Value: sample.Value.Value})
+		}
+		return samples, nil
+	case prometheus.ResultScalar:
+		if result.Scalar == nil {
+			return nil, nil
+		}
+		return []Sample{{Labels: map[string]string{}, Value: result.Scalar.Value}}, nil
+	default:
+		return nil, fmt.Errorf("%w: query returned a %s", ErrInvalidExpr, result.Type)
+	}
+}
+
+// signature identifies a label set, optionally restricted to or excluding
+// the given names.
+func signature(labels map[string]string, on bool, names []string) string {
+	named := make(map[string]bool, len(names))
+	for _, name := range names {
+		named[name] = true
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		if on != named[key] {
+			continue
+		}
+		if !on && key == MetricNameLabel {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte(0xfe)
+		b.WriteString(labels[key])
+		b.WriteByte(0xff)
+	}
+	return b.String()
+}
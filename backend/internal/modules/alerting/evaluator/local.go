@@ -0,0 +1,418 @@
+package evaluator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// value is an intermediate result: a scalar or an instant vector.
+type value struct {
+	scalar   float64
+	isScalar bool
+	vector   []Sample
+}
+
+type localEvaluator struct {
+	series []Sample
+}
+
+// NewLocal returns an Evaluator over a snapshot of series, such as those
+// collected from cluster APIs. It supports the subset of PromQL that needs
+// no history: selectors, arithmetic, comparisons, and/or/unless and the
+// sum, min, max, avg and count aggregations. Functions, range selectors,
+// offsets and group_left/group_right are rejected.
+func NewLocal(series []Sample) Evaluator {
+	return &localEvaluator{series: series}
+}
+
+// CheckLocal reports whether expr can be evaluated by a local evaluator.
+func CheckLocal(expr string) error {
+	_, err := NewLocal(nil).Evaluate(context.Background(), expr)
+	return err
+}
+
+func (e *localEvaluator) Evaluate(ctx context.Context, expr string) ([]Sample, error) {
+	parsed, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	result, err := e.eval(parsed)
+	if err != nil {
+		return nil, err
+	}
+	if result.isScalar {
+		return []Sample{{Labels: map[string]string{}, Value: result.scalar}}, nil
+	}
+	return result.vector, nil
+}
+
+func (e *localEvaluator) eval(node parser.Expr) (value, error) {
+	switch n := node.(type) {
+	case *parser.NumberLiteral:
+		return value{scalar: n.Val, isScalar: true}, nil
+	case *parser.ParenExpr:
+		return e.eval(n.Expr)
+	case *parser.UnaryExpr:
+		return e.evalUnary(n)
+	case *parser.VectorSelector:
+		return e.evalSelector(n)
+	case *parser.AggregateExpr:
+		return e.evalAggregate(n)
+	case *parser.BinaryExpr:
+		return e.evalBinary(n)
+	case *parser.Call:
+		return value{}, fmt.Errorf("%w: function %s()", ErrUnsupported, n.Func.Name)
+	case *parser.MatrixSelector, *parser.SubqueryExpr:
+		return value{}, fmt.Errorf("%w: range selectors need history", ErrUnsupported)
+	default:
+		return value{}, fmt.Errorf("%w: %s", ErrUnsupported, node.String())
+	}
+}
+
+func (e *localEvaluator) evalUnary(n *parser.UnaryExpr) (value, error) {
+	operand, err := e.eval(n.Expr)
+	if err != nil || n.Op != parser.SUB {
+		return operand, err
+	}
+	if operand.isScalar {
+		operand.scalar = -operand.scalar
+		return operand, nil
+	}
+	for i := range operand.vector {
+		operand.vector[i] = Sample{Labels: dropName(operand.vector[i].Labels), Value: -operand.vector[i].Value}
+	}
+	return operand, nil
+}
+
+func (e *localEvaluator) evalSelector(n *parser.VectorSelector) (value, error) {
+	if n.OriginalOffset != 0 || n.Timestamp != nil || n.StartOrEnd != 0 {
+		return value{}, fmt.Errorf("%w: offset and @ modifiers need history", ErrUnsupported)
+	}
+
+	vector := []Sample{}
+	for _, series := range e.series {
+		matched := true
+		for _, matcher := range n.LabelMatchers {
+			if !matcher.Matches(series.Labels[matcher.Name]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			vector = append(vector, Sample{Labels: copyLabels(series.Labels), Value: series.Value})
+		}
+	}
+	return value{vector: vector}, nil
+}
+
+func (e *localEvaluator) evalAggregate(n *parser.AggregateExpr) (value, error) {
+	switch n.Op {
+	case parser.SUM, parser.MIN, parser.MAX, parser.AVG, parser.COUNT:
+	default:
+		return value{}, fmt.Errorf("%w: aggregation %s", ErrUnsupported, n.Op)
+	}
+	operand, err := e.eval(n.Expr)
+	if err != nil {
+		return value{}, err
+	}
+
+	type group struct {
+		labels map[string]string
+		values []float64
+	}
+	groups := map[string]*group{}
+	for _, sample := range operand.vector {
+		labels := groupLabels(sample.Labels, n.Grouping, n.Without)
+		key := signature(labels, false, nil)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{labels: labels}
+			groups[key] = g
+		}
+		g.values = append(g.values, sample.Value)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	vector := make([]Sample, 0, len(keys))
+	for _, key := range keys {
+		g := groups[key]
+		vector = append(vector, Sample{Labels: g.labels, Value: aggregate(n.Op, g.values)})
+	}
+	return value{vector: vector}, nil
+}
+
+func (e *localEvaluator) evalBinary(n *parser.BinaryExpr) (value, error) {
+	lhs, err := e.eval(n.LHS)
+	if err != nil {
+		return value{}, err
+	}
+	rhs, err := e.eval(n.RHS)
+	if err != nil {
+		return value{}, err
+	}
+	if n.Op == parser.ATAN2 {
+		return value{}, fmt.Errorf("%w: operator atan2", ErrUnsupported)
+	}
+
+	switch {
+	case lhs.isScalar && rhs.isScalar:
+		result, _ := binop(n.Op, lhs.scalar, rhs.scalar, n.ReturnBool)
+		return value{scalar: result, isScalar: true}, nil
+	case lhs.isScalar:
+		return value{vector: vectorScalar(n, rhs.vector, lhs.scalar, true)}, nil
+	case rhs.isScalar:
+		return value{vector: vectorScalar(n, lhs.vector, rhs.scalar, false)}, nil
+	}
+
+	matching := n.VectorMatching
+	if matching == nil {
+		matching = &parser.VectorMatching{}
+	}
+	if n.Op.IsSetOperator() {
+		return value{vector: setOperation(n.Op, lhs.vector, rhs.vector, matching)}, nil
+	}
+	if matching.Card != parser.CardOneToOne {
+		return value{}, fmt.Errorf("%w: group_left and group_right", ErrUnsupported)
+	}
+	vector, err := vectorVector(n, lhs.vector, rhs.vector, matching)
+	return value{vector: vector}, err
+}
+
+// vectorScalar applies op between each sample and a scalar. A comparison
+// keeps the sample's own value whichever side the scalar is on.
+func vectorScalar(n *parser.BinaryExpr, vector []Sample, scalar float64, scalarOnLeft bool) []Sample {
+	result := []Sample{}
+	for _, sample := range vector {
+		lv, rv := sample.Value, scalar
+		if scalarOnLeft {
+			lv, rv = rv, lv
+		}
+		v, keep := binop(n.Op, lv, rv, n.ReturnBool)
+		if !keep {
+			continue
+		}
+		labels := sample.Labels
+		if n.Op.IsComparisonOperator() && !n.ReturnBool {
+			v = sample.Value
+		} else {
+			labels = dropName(labels)
+		}
+		result = append(result, Sample{Labels: labels, Value: v})
+	}
+	return result
+}
+
+func vectorVector(n *parser.BinaryExpr, lhs, rhs []Sample, matching *parser.VectorMatching) ([]Sample, error) {
+	right := make(map[string]Sample, len(rhs))
+	for _, sample := range rhs {
+		sig := signature(sample.Labels, matching.On, matching.MatchingLabels)
+		if _, dup := right[sig]; dup {
+			return nil, fmt.Errorf("%w: many-to-many matching on the right-hand side", ErrInvalidExpr)
+		}
+		right[sig] = sample
+	}
+
+	matched := map[string]bool{}
+	result := []Sample{}
+	for _, sample := range lhs {
+		sig := signature(sample.Labels, matching.On, matching.MatchingLabels)
+		other, ok := right[sig]
+		if !ok {
+			continue
+		}
+		if matched[sig] {
+			return nil, fmt.Errorf("%w: multiple matches on the left-hand side", ErrInvalidExpr)
+		}
+		matched[sig] = true
+
+		v, keep := binop(n.Op, sample.Value, other.Value, n.ReturnBool)
+		if !keep {
+			continue
+		}
+		labels := sample.Labels
+		if !n.Op.IsComparisonOperator() || n.ReturnBool {
+			labels = dropName(labels)
+		}
+		result = append(result, Sample{Labels: matchedLabels(labels, matching), Value: v})
+	}
+	return result, nil
+}
+
+func setOperation(op parser.ItemType, lhs, rhs []Sample, matching *parser.VectorMatching) []Sample {
+	sigs := func(vector []Sample) map[string]bool {
+		set := make(map[string]bool, len(vector))
+		for _, sample := range vector {
+			set[signature(sample.Labels, matching.On, matching.MatchingLabels)] = true
+		}
+		return set
+	}
+
+	result := []Sample{}
+	switch op {
+	case parser.LAND:
+		right := sigs(rhs)
+		for _, sample := range lhs {
+			if right[signature(sample.Labels, matching.On, matching.MatchingLabels)] {
+				result = append(result, sample)
+			}
+		}
+	case parser.LOR:
+		left := sigs(lhs)
+		result = append(result, lhs...)
+		for _, sample := range rhs {
+			if !left[signature(sample.Labels, matching.On, matching.MatchingLabels)] {
+				result = append(result, sample)
+			}
+		}
+	case parser.LUNLESS:
+		right := sigs(rhs)
+		for _, sample := range lhs {
+			if !right[signature(sample.Labels, matching.On, matching.MatchingLabels)] {
+				result = append(result, sample)
+			}
+		}
+	}
+	return result
+}
+
+// binop applies an arithmetic or comparison operator. keep is false when a
+// comparison without bool filters the element out.
+func binop(op parser.ItemType, lhs, rhs float64, returnBool bool) (result float64, keep bool) {
+	if op.IsComparisonOperator() {
+		var ok bool
+		switch op {
+		case parser.EQLC:
+			ok = lhs == rhs
+		case parser.NEQ:
+			ok = lhs != rhs
+		case parser.GTR:
+			ok = lhs > rhs
+		case parser.LSS:
+			ok = lhs < rhs
+		case parser.GTE:
+			ok = lhs >= rhs
+		case parser.LTE:
+			ok = lhs <= rhs
+		}
+		if returnBool {
+			if ok {
+				return 1, true
+			}
+			return 0, true
+		}
+		return lhs, ok
+	}
+
+	switch op {
+	case parser.ADD:
+		return lhs + rhs, true
+	case parser.SUB:
+		return lhs - rhs, true
+	case parser.MUL:
+		return lhs * rhs, true
+	case parser.DIV:
+		return lhs / rhs, true
+	case parser.MOD:
+		return math.Mod(lhs, rhs), true
+	case parser.POW:
+		return math.Pow(lhs, rhs), true
+	}
+	return 0, false
+}
+
+func aggregate(op parser.ItemType, values []float64) float64 {
+	switch op {
+	case parser.COUNT:
+		return float64(len(values))
+	case parser.MIN:
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+		return result
+	case parser.MAX:
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Max(result, v)
+		}
+		return result
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	if op == parser.AVG {
+		return sum / float64(len(values))
+	}
+	return sum
+}
+
+// groupLabels returns the labels an aggregation keeps: only the grouping
+// labels with by, or all but them and the metric name with without.
+func groupLabels(labels map[string]string, grouping []string, without bool) map[string]string {
+	result := map[string]string{}
+	if without {
+		drop := map[string]bool{MetricNameLabel: true}
+		for _, name := range grouping {
+			drop[name] = true
+		}
+		for name, v := range labels {
+			if !drop[name] {
+				result[name] = v
+			}
+		}
+		return result
+	}
+	for _, name := range grouping {
+		if v, ok := labels[name]; ok {
+			result[name] = v
+		}
+	}
+	return result
+}
+
+// matchedLabels applies one-to-one matching to a result's labels: on keeps
+// only the matching labels, ignoring drops them.
+func matchedLabels(labels map[string]string, matching *parser.VectorMatching) map[string]string {
+	if len(matching.MatchingLabels) == 0 && !matching.On {
+		return labels
+	}
+	named := make(map[string]bool, len(matching.MatchingLabels))
+	for _, name := range matching.MatchingLabels {
+		named[name] = true
+	}
+	result := map[string]string{}
+	for name, v := range labels {
+		if named[name] == matching.On {
+			result[name] = v
+		}
+	}
+	return result
+}
+
+func dropName(labels map[string]string) map[string]string {
+	if _, ok := labels[MetricNameLabel]; !ok {
+		return labels
+	}
+	result := copyLabels(labels)
+	delete(result, MetricNameLabel)
+	return result
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for name, v := range labels {
+		result[name] = v
+	}
+	return result
+}
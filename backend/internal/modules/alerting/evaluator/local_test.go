@@ -0,0 +1,259 @@
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// testSeries is the snapshot every local evaluator test runs against.
+var testSeries = []Sample{
+	{Labels: map[string]string{MetricNameLabel: "restarts", "namespace": "a", "pod": "p1"}, Value: 3},
+	{Labels: map[string]string{MetricNameLabel: "restarts", "namespace": "a", "pod": "p2"}, Value: 7},
+	{Labels: map[string]string{MetricNameLabel: "restarts", "namespace": "b", "pod": "p3"}, Value: 0},
+	{Labels: map[string]string{MetricNameLabel: "ready", "namespace": "a", "pod": "p1"}, Value: 1},
+	{Labels: map[string]string{MetricNameLabel: "ready", "namespace": "a", "pod": "p2"}, Value: 0},
+	{Labels: map[string]string{MetricNameLabel: "ready", "namespace": "b", "pod": "p3"}, Value: 1},
+	{Labels: map[string]string{MetricNameLabel: "quota", "namespace": "a"}, Value: 10},
+	{Labels: map[string]string{MetricNameLabel: "quota", "namespace": "b"}, Value: 4},
+}
+
+// formatSamples renders samples as sorted `{name=value,...} value` lines so
+// results compare independently of order.
+func formatSamples(samples []Sample) []string {
+	lines := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		names := make([]string, 0, len(sample.Labels))
+		for name := range sample.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		pairs := make([]string, 0, len(names))
+		for _, name := range names {
+			pairs = append(pairs, name+"="+sample.Labels[name])
+		}
+		lines = append(lines, fmt.Sprintf("{%s} %g", strings.Join(pairs, ","), sample.Value))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+type localCase struct {
+	expr    string
+	want    []string
+	wantErr error
+}
+
+func runLocalCases(t *testing.T, tests []localCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := NewLocal(testSeries).Evaluate(context.Background(), tt.expr)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := append([]string{}, tt.want...)
+			sort.Strings(want)
+			if lines := formatSamples(got); !reflect.DeepEqual(lines, want) {
+				t.Errorf("got  %q\nwant %q", lines, want)
+			}
+		})
+	}
+}
+
+func TestLocalSelectors(t *testing.T) {
+	runLocalCases(t, []localCase{
+		{expr: `restarts`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p1} 3",
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+			"{__name__=restarts,namespace=b,pod=p3} 0",
+		}},
+		{expr: `restarts{namespace="a"}`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p1} 3",
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+		}},
+		{expr: `restarts{namespace!="a"}`, want: []string{
+			"{__name__=restarts,namespace=b,pod=p3} 0",
+		}},
+		{expr: `restarts{pod=~"p[23]"}`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+			"{__name__=restarts,namespace=b,pod=p3} 0",
+		}},
+		{expr: `{__name__=~"quota|ready", namespace="b", pod!~"p.*"}`, want: []string{
+			"{__name__=quota,namespace=b} 4",
+		}},
+		{expr: `missing`, want: []string{}},
+		{expr: `-restarts{pod="p1"}`, want: []string{"{namespace=a,pod=p1} -3"}},
+		{expr: `(1 + 2) * 3`, want: []string{"{} 9"}},
+	})
+}
+
+func TestLocalVectorScalar(t *testing.T) {
+	runLocalCases(t, []localCase{
+		// Arithmetic drops the metric name, whichever side the scalar is on.
+		{expr: `restarts * 2`, want: []string{
+			"{namespace=a,pod=p1} 6",
+			"{namespace=a,pod=p2} 14",
+			"{namespace=b,pod=p3} 0",
+		}},
+		{expr: `10 - restarts`, want: []string{
+			"{namespace=a,pod=p1} 7",
+			"{namespace=a,pod=p2} 3",
+			"{namespace=b,pod=p3} 10",
+		}},
+		// Comparisons filter and keep the sample, name and value.
+		{expr: `restarts > 2`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p1} 3",
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+		}},
+		{expr: `5 < restarts`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+		}},
+		{expr: `restarts == 100`, want: []string{}},
+	})
+}
+
+func TestLocalVectorVector(t *testing.T) {
+	runLocalCases(t, []localCase{
+		// Without a matching clause all labels but the name must match.
+		{expr: `restarts + ready`, want: []string{
+			"{namespace=a,pod=p1} 4",
+			"{namespace=a,pod=p2} 7",
+			"{namespace=b,pod=p3} 1",
+		}},
+		{expr: `restarts > ready`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p1} 3",
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+		}},
+		// Samples without a partner are dropped.
+		{expr: `restarts - quota`, want: []string{}},
+		{expr: `sum by (namespace) (restarts) / on(namespace) quota`, want: []string{
+			"{namespace=a} 1",
+			"{namespace=b} 0",
+		}},
+		{expr: `restarts{pod="p1"} - ignoring(pod) quota`, want: []string{
+			"{namespace=a} -7",
+		}},
+		{expr: `restarts / on(namespace) quota`, wantErr: ErrInvalidExpr},
+		{expr: `quota / ignoring(pod) restarts`, wantErr: ErrInvalidExpr},
+	})
+}
+
+func TestLocalBool(t *testing.T) {
+	runLocalCases(t, []localCase{
+		{expr: `restarts > bool 2`, want: []string{
+			"{namespace=a,pod=p1} 1",
+			"{namespace=a,pod=p2} 1",
+			"{namespace=b,pod=p3} 0",
+		}},
+		{expr: `2 >= bool restarts`, want: []string{
+			"{namespace=a,pod=p1} 0",
+			"{namespace=a,pod=p2} 0",
+			"{namespace=b,pod=p3} 1",
+		}},
+		{expr: `restarts > bool ready`, want: []string{
+			"{namespace=a,pod=p1} 1",
+			"{namespace=a,pod=p2} 1",
+			"{namespace=b,pod=p3} 0",
+		}},
+		{expr: `1 > bool 2`, want: []string{"{} 0"}},
+		{expr: `2 == bool 2`, want: []string{"{} 1"}},
+	})
+}
+
+func TestLocalSetOperators(t *testing.T) {
+	runLocalCases(t, []localCase{
+		{expr: `restarts and ready == 1`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p1} 3",
+			"{__name__=restarts,namespace=b,pod=p3} 0",
+		}},
+		{expr: `restarts unless ready == 1`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+		}},
+		{expr: `restarts and on(namespace) quota > 5`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p1} 3",
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+		}},
+		// or keeps every left sample and the right ones it has no match for.
+		{expr: `restarts > 5 or ready == 0`, want: []string{
+			"{__name__=restarts,namespace=a,pod=p2} 7",
+		}},
+		{expr: `quota or restarts{namespace="b"}`, want: []string{
+			"{__name__=quota,namespace=a} 10",
+			"{__name__=quota,namespace=b} 4",
+			"{__name__=restarts,namespace=b,pod=p3} 0",
+		}},
+		{expr: `quota or on(namespace) restarts{namespace="b"}`, want: []string{
+			"{__name__=quota,namespace=a} 10",
+			"{__name__=quota,namespace=b} 4",
+		}},
+	})
+}
+
+func TestLocalAggregations(t *testing.T) {
+	runLocalCases(t, []localCase{
+		{expr: `sum(restarts)`, want: []string{"{} 10"}},
+		{expr: `sum by (namespace) (restarts)`, want: []string{
+			"{namespace=a} 10",
+			"{namespace=b} 0",
+		}},
+		{expr: `min by (namespace) (restarts)`, want: []string{
+			"{namespace=a} 3",
+			"{namespace=b} 0",
+		}},
+		{expr: `max without (pod) (restarts)`, want: []string{
+			"{namespace=a} 7",
+			"{namespace=b} 0",
+		}},
+		{expr: `avg by (namespace) (restarts)`, want: []string{
+			"{namespace=a} 5",
+			"{namespace=b} 0",
+		}},
+		{expr: `count(ready == 1)`, want: []string{"{} 2"}},
+		// without drops the metric name, so series of different metrics
+		// with the same labels aggregate together.
+		{expr: `count without () ({__name__=~"restarts|ready"})`, want: []string{
+			"{namespace=a,pod=p1} 2",
+			"{namespace=a,pod=p2} 2",
+			"{namespace=b,pod=p3} 2",
+		}},
+		{expr: `sum by (namespace) (missing)`, want: []string{}},
+	})
+}
+
+func TestLocalRejects(t *testing.T) {
+	runLocalCases(t, []localCase{
+		{expr: `rate(restarts[5m])`, wantErr: ErrUnsupported},
+		{expr: `absent(restarts)`, wantErr: ErrUnsupported},
+		{expr: `sum(increase(restarts[1h]))`, wantErr: ErrUnsupported},
+		{expr: `restarts[5m]`, wantErr: ErrInvalidExpr},
+		{expr: `restarts[5m:1m]`, wantErr: ErrInvalidExpr},
+		{expr: `restarts offset 5m`, wantErr: ErrUnsupported},
+		{expr: `restarts @ 100`, wantErr: ErrUnsupported},
+		{expr: `restarts * on(namespace) group_left quota`, wantErr: ErrUnsupported},
+		{expr: `quota * on(namespace) group_right restarts`, wantErr: ErrUnsupported},
+		{expr: `topk(1, restarts)`, wantErr: ErrUnsupported},
+		{expr: `restarts atan2 ready`, wantErr: ErrUnsupported},
+		{expr: `restarts +`, wantErr: ErrInvalidExpr},
+		{expr: `"text"`, wantErr: ErrInvalidExpr},
+	})
+}
+
+func TestCheckLocal(t *testing.T) {
+	if err := CheckLocal(`sum by (namespace) (restarts) > 5`); err != nil {
+		t.Errorf("supported expression: %v", err)
+	}
+	if err := CheckLocal(`rate(restarts[5m]) > 0`); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("function: err = %v, want ErrUnsupported", err)
+	}
+}
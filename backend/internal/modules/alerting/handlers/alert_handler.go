@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/services"
+)
+
+type AlertHandler struct {
+	service services.AlertService
+}
+
+func NewAlertHandler(service services.AlertService) *AlertHandler {
+	return &AlertHandler{service: service}
+}
+
+// ListAlerts returns alerts newest first, optionally filtered by state
+// (pending, firing or resolved) and rule.
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	filter := repositories.AlertFilter{State: c.Query("state"), RuleID: c.Query("rule")}
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	alerts, err := h.service.ListAlerts(filter)
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts})
+}
+
+// GetAlert returns a single alert.
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	alert, err := h.service.GetAlert(c.Param("id"))
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// GroupAlerts returns pending and firing alerts grouped by the comma
+// separated labels in by (all alerts in one group if empty), one alert per
+// fingerprint, optionally filtered by state.
+func (h *AlertHandler) GroupAlerts(c *gin.Context) {
+	var by []string
+	for _, name := range strings.Split(c.Query("by"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			by = append(by, name)
+		}
+	}
+
+	groups, err := h.service.GroupAlerts(by, c.Query("state"))
+	if err != nil {
+		writeAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groups})
+}
+
+func writeAlertError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrAlertNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidFilter):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
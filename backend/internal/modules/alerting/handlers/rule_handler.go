@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/services"
+)
+
+type RuleHandler struct {
+	service services.RuleService
+}
+
+func NewRuleHandler(service services.RuleService) *RuleHandler {
+	return &RuleHandler{service: service}
+}
+
+type ruleRequest struct {
+	Name        string            `json:"name" binding:"required"`
+	Group       string            `json:"group"`
+	Source      string            `json:"source"`
+	Expr        string            `json:"expr" binding:"required"`
+	For         string            `json:"for"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Enabled     *bool             `json:"enabled"`
+}
+
+// ListRules returns every alerting rule with its last evaluation status.
+func (h *RuleHandler) ListRules(c *gin.Context) {
+	rules, err := h.service.ListRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// GetRule returns a single alerting rule.
+func (h *RuleHandler) GetRule(c *gin.Context) {
+	rule, err := h.service.GetRule(c.Param("id"))
+	if err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// CreateRule adds an alerting rule. Rules are enabled unless the request
+// says otherwise.
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	var req ruleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.CreateRule(req.toModel(""))
+	if err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule replaces an alerting rule's definition.
+func (h *RuleHandler) UpdateRule(c *gin.Context) {
+	var req ruleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.UpdateRule(req.toModel(c.Param("id")))
+	if err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule removes an alerting rule and its alerts.
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	if err := h.service.DeleteRule(c.Param("id")); err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// maxRuleFileBytes bounds an imported rule file.
+const maxRuleFileBytes = 1 << 20
+
+// ImportRules stores the alerting rules of a Prometheus rule file sent as
+// the request body. Query parameters: source (cluster or prometheus),
+// prune to delete other rules in the file's groups, and dryRun to only
+// validate.
+func (h *RuleHandler) ImportRules(c *gin.Context) {
+	opts := services.ImportOptions{Source: c.Query("source")}
+	for name, target := range map[string]*bool{"prune": &opts.Prune, "dryRun": &opts.DryRun} {
+		if raw := c.Query(name); raw != "" {
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be true or false", name)})
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	content, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRuleFileBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(content) > maxRuleFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "rule file is larger than 1 MiB"})
+		return
+	}
+
+	result, err := h.service.ImportRules(content, opts)
+	if err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportRules returns the stored rules as a Prometheus rule file,
+// optionally only those of one group or source.
+func (h *RuleHandler) ExportRules(c *gin.Context) {
+	content, err := h.service.ExportRules(c.Query("group"), c.Query("source"))
+	if err != nil {
+		writeRuleError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/yaml; charset=utf-8", content)
+}
+
+func (r ruleRequest) toModel(id string) models.Rule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return models.Rule{
+		ID:          id,
+		Name:        r.Name,
+		Group:       r.Group,
+		Source:      r.Source,
+		Expr:        r.Expr,
+		For:         r.For,
+		Labels:      r.Labels,
+		Annotations: r.Annotations,
+		Enabled:     enabled,
+	}
+}
+
+func writeRuleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrRuleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repositories.ErrRuleExists):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrInvalidRule):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/services"
+)
+
+type SilenceHandler struct {
+	service services.SilenceService
+}
+
+func NewSilenceHandler(service services.SilenceService) *SilenceHandler {
+	return &SilenceHandler{service: service}
+}
+
+type silenceRequest struct {
+	Matchers []matcherRequest `json:"matchers" binding:"required"`
+	StartsAt *time.Time       `json:"startsAt"`
+	EndsAt   time.Time        `json:"endsAt" binding:"required"`
+	Comment  string           `json:"comment" binding:"required"`
+}
+
+type matcherRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual *bool  `json:"isEqual"`
+}
+
+// ListSilences returns silences newest first, optionally filtered by state
+// (pending, active or expired).
+func (h *SilenceHandler) ListSilences(c *gin.Context) {
+	silences, err := h.service.ListSilences(c.Query("state"))
+	if err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"silences": silences})
+}
+
+// GetSilence returns a single silence.
+func (h *SilenceHandler) GetSilence(c *gin.Context) {
+	silence, err := h.service.GetSilence(c.Param("id"))
+	if err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, silence)
+}
+
+// CreateSilence adds a silence on behalf of the signed-in user. Matchers
+// are equality matchers unless isEqual is false.
+func (h *SilenceHandler) CreateSilence(c *gin.Context) {
+	var req silenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	silence := models.Silence{
+		Matchers:  make([]models.Matcher, 0, len(req.Matchers)),
+		EndsAt:    req.EndsAt,
+		CreatedBy: c.GetString("auth.sub"),
+		Comment:   req.Comment,
+	}
+	if req.StartsAt != nil {
+		silence.StartsAt = *req.StartsAt
+	}
+	for _, matcher := range req.Matchers {
+		isEqual := true
+		if matcher.IsEqual != nil {
+			isEqual = *matcher.IsEqual
+		}
+		silence.Matchers = append(silence.Matchers, models.Matcher{
+			Name:    matcher.Name,
+			Value:   matcher.Value,
+			IsRegex: matcher.IsRegex,
+			IsEqual: isEqual,
+		})
+	}
+
+	created, err := h.service.CreateSilence(silence)
+	if err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ExpireSilence ends a silence now. Expired silences are kept for the
+// alert retention period.
+func (h *SilenceHandler) ExpireSilence(c *gin.Context) {
+	silence, err := h.service.ExpireSilence(c.Param("id"))
+	if err != nil {
+		writeSilenceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, silence)
+}
+
+func writeSilenceError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrSilenceNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidSilence), errors.Is(err, services.ErrInvalidFilter):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Alert states. An alert is pending until its rule's For duration has
+// elapsed, then firing until the rule stops returning it.
+const (
+	StatePending  = "pending"
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// AlertNameLabel holds the name of the rule that raised an alert.
+const AlertNameLabel = "alertname"
+
+// Alert is one instance of a rule, identified by its label set.
+type Alert struct {
+	ID              string            `json:"id"`
+	RuleID          string            `json:"ruleId"`
+	RuleName        string            `json:"ruleName"`
+	Fingerprint     string            `json:"fingerprint"`
+	State           string            `json:"state"`
+	Severity        string            `json:"severity,omitempty"`
+	Priority        string            `json:"priority,omitempty"`
+	Labels          map[string]string `json:"labels"`
+	Annotations     map[string]string `json:"annotations"`
+	Value           float64           `json:"value"`
+	ActiveAt        time.Time         `json:"activeAt"`
+	FiredAt         *time.Time        `json:"firedAt,omitempty"`
+	ResolvedAt      *time.Time        `json:"resolvedAt,omitempty"`
+	LastEvaluatedAt time.Time         `json:"lastEvaluatedAt"`
+	// LastNotifiedAt is when a firing alert was last handed to the
+	// notifier; nil until then.
+	LastNotifiedAt *time.Time `json:"lastNotifiedAt,omitempty"`
+	// SilencedBy lists the active silences matching the alert. It is
+	// computed when alerts are read, not stored.
+	SilencedBy []string `json:"silencedBy,omitempty"`
+}
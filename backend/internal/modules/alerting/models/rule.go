@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Rule sources. Cluster rules are evaluated against series collected from
+// the cluster APIs; Prometheus rules are run as instant queries.
+const (
+	SourceCluster    = "cluster"
+	SourcePrometheus = "prometheus"
+)
+
+// Rule health after the most recent evaluation.
+const (
+	HealthUnknown = "unknown"
+	HealthOK      = "ok"
+	HealthError   = "err"
+)
+
+// Severities, set through a rule's severity label. Each maps to a priority.
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// SeverityLabel is the label that carries an alert's severity.
+const SeverityLabel = "severity"
+
+// Priority returns the P1-P3 priority for a severity, or "" if it has none.
+func Priority(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return "P1"
+	case SeverityWarning:
+		return "P2"
+	case SeverityInfo:
+		return "P3"
+	}
+	return ""
+}
+
+// Rule is an alerting rule. Every sample its expression returns is an
+// active alert, which fires once it has been active for the For duration.
+type Rule struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	Group           string            `json:"group"`
+	Source          string            `json:"source"`
+	Expr            string            `json:"expr"`
+	For             string            `json:"for"`
+	Labels          map[string]string `json:"labels"`
+	Annotations     map[string]string `json:"annotations"`
+	Enabled         bool              `json:"enabled"`
+	Health          string            `json:"health"`
+	LastError       string            `json:"lastError,omitempty"`
+	LastEvaluatedAt *time.Time        `json:"lastEvaluatedAt,omitempty"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+// ImportResult summarises a rule file import. Updated counts every rule
+// that already existed, whether or not it changed.
+type ImportResult struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Deleted int      `json:"deleted"`
+	Skipped []string `json:"skipped,omitempty"`
+	DryRun  bool     `json:"dryRun"`
+}
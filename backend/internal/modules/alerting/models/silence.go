@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Silence states, derived from the silence's time window.
+const (
+	SilenceStatePending = "pending"
+	SilenceStateActive  = "active"
+	SilenceStateExpired = "expired"
+)
+
+// Matcher selects alerts by one label. IsEqual false negates the match;
+// IsRegex matches Value as an anchored regular expression.
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Silence suppresses notifications for alerts matching all of its matchers
+// between StartsAt and EndsAt. Silenced alerts are still evaluated and
+// listed.
+type Silence struct {
+	ID        string    `json:"id"`
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+	State     string    `json:"state"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// StateAt returns the silence's state at now.
+func (s Silence) StateAt(now time.Time) string {
+	switch {
+	case now.Before(s.StartsAt):
+		return SilenceStatePending
+	case now.Before(s.EndsAt):
+		return SilenceStateActive
+	default:
+		return SilenceStateExpired
+	}
+}
+
+// AlertGroup is a set of alerts sharing the values of the grouping labels.
+type AlertGroup struct {
+	Labels map[string]string `json:"labels"`
+	Alerts []Alert           `json:"alerts"`
+}
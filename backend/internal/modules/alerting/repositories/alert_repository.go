@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+)
+
+var ErrAlertNotFound = errors.New("alert not found")
+
+// AlertFilter narrows an alert listing. Empty fields match every alert.
+type AlertFilter struct {
+	State  string
+	RuleID string
+	Limit  int
+}
+
+// AlertRepository persists alert instances.
+type AlertRepository interface {
+	Create(alert models.Alert) (models.Alert, error)
+	GetByID(id string) (models.Alert, error)
+	List(filter AlertFilter) ([]models.Alert, error)
+	// ListActive returns every pending and firing alert.
+	ListActive() ([]models.Alert, error)
+	// Update stores an alert's state, labels, annotations, value and
+	// timestamps, including when it was last notified.
+	Update(alert models.Alert) error
+	Delete(id string) error
+	DeleteResolvedBefore(before time.Time) (int64, error)
+}
+
+type alertRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRepository(db *sql.DB) AlertRepository {
+	return &alertRepository{db: db}
+}
+
+const alertColumns = `a.id, a.rule_id, r.name, a.fingerprint, a.state, a.labels, a.annotations, a.value,
+	a.active_at, a.fired_at, a.resolved_at, a.last_evaluated_at, a.last_notified_at`
+
+const alertFrom = ` FROM alerts a JOIN alert_rules r ON r.id = a.rule_id`
+
+func (r *alertRepository) Create(alert models.Alert) (models.Alert, error) {
+	labels, annotations, err := marshalAlertJSON(alert)
+	if err != nil {
+		return models.Alert{}, err
+	}
+
+	var id string
+	err = r.db.QueryRow(
+		`INSERT INTO alerts (rule_id, fingerprint, state, labels, annotations, value, active_at,
+		   fired_at, last_evaluated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING id`,
+		alert.RuleID, alert.Fingerprint, alert.State, labels, annotations, alert.Value, alert.ActiveAt,
+		alert.FiredAt, alert.LastEvaluatedAt,
+	).Scan(&id)
+	if err != nil {
+		return models.Alert{}, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *alertRepository) GetByID(id string) (models.Alert, error) {
+	row := r.db.QueryRow(`SELECT `+alertColumns+alertFrom+` WHERE a.id = $1`, id)
+	return scanAlert(row)
+}
+
+// List returns alerts newest first.
+func (r *alertRepository) List(filter AlertFilter) ([]models.Alert, error) {
+	return r.query(
+		`SELECT `+alertColumns+alertFrom+`
+		 WHERE ($1 = '' OR a.state = $1) AND ($2 = '' OR a.rule_id = $2)
+		 ORDER BY a.active_at DESC
+		 LIMIT $3`,
+		filter.State, filter.RuleID, filter.Limit,
+	)
+}
+
+func (r *alertRepository) ListActive() ([]models.Alert, error) {
+	return r.query(`SELECT ` + alertColumns + alertFrom + ` WHERE a.state <> 'resolved'`)
+}
+
+func (r *alertRepository) query(query string, args ...interface{}) ([]models.Alert, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []models.Alert{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func (r *alertRepository) Update(alert models.Alert) error {
+	labels, annotations, err := marshalAlertJSON(alert)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db.Exec(
+		`UPDATE alerts
+		 SET state = $2, labels = $3, annotations = $4, value = $5, fired_at = $6, resolved_at = $7,
+		     last_evaluated_at = $8, last_notified_at = $9
+		 WHERE id = $1`,
+		alert.ID, alert.State, labels, annotations, alert.Value, alert.FiredAt, alert.ResolvedAt,
+		alert.LastEvaluatedAt, alert.LastNotifiedAt,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrAlertNotFound
+	}
+	return nil
+}
+
+func (r *alertRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM alerts WHERE id = $1`, id)
+	return err
+}
+
+func (r *alertRepository) DeleteResolvedBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM alerts WHERE state = 'resolved' AND resolved_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func marshalAlertJSON(alert models.Alert) (labels, annotations []byte, err error) {
+	labels, err = json.Marshal(alert.Labels)
+	if err != nil {
+		return nil, nil, err
+	}
+	annotations, err = json.Marshal(alert.Annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return labels, annotations, nil
+}
+
+func scanAlert(row rowScanner) (models.Alert, error) {
+	var alert models.Alert
+	var labels, annotations []byte
+	var firedAt, resolvedAt, notifiedAt sql.NullTime
+	if err := row.Scan(
+		&alert.ID, &alert.RuleID, &alert.RuleName, &alert.Fingerprint, &alert.State, &labels, &annotations,
+		&alert.Value, &alert.ActiveAt, &firedAt, &resolvedAt, &alert.LastEvaluatedAt, &notifiedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Alert{}, ErrAlertNotFound
+		}
+		return models.Alert{}, err
+	}
+	if firedAt.Valid {
+		alert.FiredAt = &firedAt.Time
+	}
+	if resolvedAt.Valid {
+		alert.ResolvedAt = &resolvedAt.Time
+	}
+	if notifiedAt.Valid {
+		alert.LastNotifiedAt = &notifiedAt.Time
+	}
+	if err := json.Unmarshal(labels, &alert.Labels); err != nil {
+		return models.Alert{}, err
+	}
+	if err := json.Unmarshal(annotations, &alert.Annotations); err != nil {
+		return models.Alert{}, err
+	}
+	alert.Severity = alert.Labels[models.SeverityLabel]
+	alert.Priority = models.Priority(alert.Severity)
+	return alert, nil
+}
@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+)
+
+var (
+	ErrRuleNotFound = errors.New("alert rule not found")
+	ErrRuleExists   = errors.New("an alert rule with this name already exists in the group")
+)
+
+// uniqueViolation is the Postgres error code for a unique constraint
+// violation.
+const uniqueViolation = "23505"
+
+// RuleRepository persists alerting rules and their evaluation status.
+type RuleRepository interface {
+	Create(rule models.Rule) (models.Rule, error)
+	GetByID(id string) (models.Rule, error)
+	List() ([]models.Rule, error)
+	ListEnabled() ([]models.Rule, error)
+	Update(rule models.Rule) (models.Rule, error)
+	Delete(id string) error
+
+	// RecordEvaluation stores the outcome of an evaluation; an empty
+	// evalErr marks the rule healthy.
+	RecordEvaluation(id string, at time.Time, evalErr string) error
+
+	// Import creates or replaces rules by group and name in one
+	// transaction, keeping each existing rule's enabled flag. With prune,
+	// other rules in the imported groups are deleted.
+	Import(rules []models.Rule, prune bool) (models.ImportResult, error)
+}
+
+type ruleRepository struct {
+	db *sql.DB
+}
+
+func NewRuleRepository(db *sql.DB) RuleRepository {
+	return &ruleRepository{db: db}
+}
+
+const ruleColumns = `id, name, group_name, source, expr, for_duration, labels, annotations, enabled,
+	health, last_error, last_evaluated_at, created_at, updated_at`
+
+func (r *ruleRepository) Create(rule models.Rule) (models.Rule, error) {
+	labels, annotations, err := marshalRuleJSON(rule)
+	if err != nil {
+		return models.Rule{}, err
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO alert_rules (name, group_name, source, expr, for_duration, labels, annotations, enabled)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING `+ruleColumns,
+		rule.Name, rule.Group, rule.Source, rule.Expr, rule.For, labels, annotations, rule.Enabled,
+	)
+	return scanRule(row)
+}
+
+func (r *ruleRepository) GetByID(id string) (models.Rule, error) {
+	row := r.db.QueryRow(`SELECT `+ruleColumns+` FROM alert_rules WHERE id = $1`, id)
+	return scanRule(row)
+}
+
+func (r *ruleRepository) List() ([]models.Rule, error) {
+	return r.query(`SELECT ` + ruleColumns + ` FROM alert_rules ORDER BY group_name, name`)
+}
+
+func (r *ruleRepository) ListEnabled() ([]models.Rule, error) {
+	return r.query(`SELECT ` + ruleColumns + ` FROM alert_rules WHERE enabled ORDER BY group_name, name`)
+}
+
+func (r *ruleRepository) query(query string, args ...interface{}) ([]models.Rule, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.Rule
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// Update replaces a rule's definition. Its health is reset until the next
+// evaluation.
+func (r *ruleRepository) Update(rule models.Rule) (models.Rule, error) {
+	labels, annotations, err := marshalRuleJSON(rule)
+	if err != nil {
+		return models.Rule{}, err
+	}
+
+	row := r.db.QueryRow(
+		`UPDATE alert_rules
+		 SET name = $2, group_name = $3, source = $4, expr = $5, for_duration = $6, labels = $7,
+		     annotations = $8, enabled = $9, health = 'unknown', last_error = '', updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+ruleColumns,
+		rule.ID, rule.Name, rule.Group, rule.Source, rule.Expr, rule.For, labels, annotations, rule.Enabled,
+	)
+	return scanRule(row)
+}
+
+func (r *ruleRepository) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func (r *ruleRepository) RecordEvaluation(id string, at time.Time, evalErr string) error {
+	health := models.HealthOK
+	if evalErr != "" {
+		health = models.HealthError
+	}
+	_, err := r.db.Exec(
+		`UPDATE alert_rules SET health = $2, last_error = $3, last_evaluated_at = $4 WHERE id = $1`,
+		id, health, evalErr, at,
+	)
+	return err
+}
+
+func (r *ruleRepository) Import(rules []models.Rule, prune bool) (models.ImportResult, error) {
+	var result models.ImportResult
+	tx, err := r.db.Begin()
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	imported := map[string][]string{}
+	for _, rule := range rules {
+		labels, annotations, err := marshalRuleJSON(rule)
+		if err != nil {
+			return result, err
+		}
+		// xmax is zero only for freshly inserted rows.
+		var inserted bool
+		if err := tx.QueryRow(
+			`INSERT INTO alert_rules (name, group_name, source, expr, for_duration, labels, annotations, enabled)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 ON CONFLICT (group_name, name) DO UPDATE
+			 SET source = EXCLUDED.source, expr = EXCLUDED.expr, for_duration = EXCLUDED.for_duration,
+			     labels = EXCLUDED.labels, annotations = EXCLUDED.annotations, health = 'unknown',
+			     last_error = '', updated_at = NOW()
+			 RETURNING xmax = 0`,
+			rule.Name, rule.Group, rule.Source, rule.Expr, rule.For, labels, annotations, rule.Enabled,
+		).Scan(&inserted); err != nil {
+			return result, err
+		}
+		if inserted {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		imported[rule.Group] = append(imported[rule.Group], rule.Name)
+	}
+
+	if prune {
+		for group, names := range imported {
+			res, err := tx.Exec(
+				`DELETE FROM alert_rules WHERE group_name = $1 AND NOT (name = ANY($2))`,
+				group, pq.Array(names),
+			)
+			if err != nil {
+				return result, err
+			}
+			deleted, err := res.RowsAffected()
+			if err != nil {
+				return result, err
+			}
+			result.Deleted += int(deleted)
+		}
+	}
+	return result, tx.Commit()
+}
+
+func marshalRuleJSON(rule models.Rule) (labels, annotations []byte, err error) {
+	labels, err = json.Marshal(rule.Labels)
+	if err != nil {
+		return nil, nil, err
+	}
+	annotations, err = json.Marshal(rule.Annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return labels, annotations, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRule(row rowScanner) (models.Rule, error) {
+	var rule models.Rule
+	var labels, annotations []byte
+	var lastEvaluated sql.NullTime
+	if err := row.Scan(
+		&rule.ID, &rule.Name, &rule.Group, &rule.Source, &rule.Expr, &rule.For, &labels, &annotations,
+		&rule.Enabled, &rule.Health, &rule.LastError, &lastEvaluated, &rule.CreatedAt, &rule.UpdatedAt,
+	); err != nil {
+		var pqErr *pq.Error
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.Rule{}, ErrRuleNotFound
+		case errors.As(err, &pqErr) && pqErr.Code == uniqueViolation:
+			return models.Rule{}, ErrRuleExists
+		}
+		return models.Rule{}, err
+	}
+	if lastEvaluated.Valid {
+		rule.LastEvaluatedAt = &lastEvaluated.Time
+	}
+	if err := json.Unmarshal(labels, &rule.Labels); err != nil {
+		return models.Rule{}, err
+	}
+	if err := json.Unmarshal(annotations, &rule.Annotations); err != nil {
+		return models.Rule{}, err
+	}
+	return rule, nil
+}
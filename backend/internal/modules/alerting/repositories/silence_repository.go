@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+)
+
+var ErrSilenceNotFound = errors.New("silence not found")
+
+// SilenceRepository persists silences.
+type SilenceRepository interface {
+	Create(silence models.Silence) (models.Silence, error)
+	GetByID(id string) (models.Silence, error)
+	// List returns silences newest first.
+	List() ([]models.Silence, error)
+	// ListActive returns the silences whose window contains now.
+	ListActive(now time.Time) ([]models.Silence, error)
+	// Expire ends a silence at now; a silence that has not started yet
+	// ends before it begins.
+	Expire(id string, now time.Time) (models.Silence, error)
+	DeleteExpiredBefore(before time.Time) (int64, error)
+}
+
+type silenceRepository struct {
+	db *sql.DB
+}
+
+func NewSilenceRepository(db *sql.DB) SilenceRepository {
+	return &silenceRepository{db: db}
+}
+
+const silenceColumns = `id, matchers, starts_at, ends_at, created_by, comment, created_at, updated_at`
+
+func (r *silenceRepository) Create(silence models.Silence) (models.Silence, error) {
+	matchers, err := json.Marshal(silence.Matchers)
+	if err != nil {
+		return models.Silence{}, err
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO alert_silences (matchers, starts_at, ends_at, created_by, comment)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+silenceColumns,
+		matchers, silence.StartsAt, silence.EndsAt, silence.CreatedBy, silence.Comment,
+	)
+	return scanSilence(row)
+}
+
+func (r *silenceRepository) GetByID(id string) (models.Silence, error) {
+	row := r.db.QueryRow(`SELECT `+silenceColumns+` FROM alert_silences WHERE id = $1`, id)
+	return scanSilence(row)
+}
+
+func (r *silenceRepository) List() ([]models.Silence, error) {
+	return r.query(`SELECT ` + silenceColumns + ` FROM alert_silences ORDER BY created_at DESC`)
+}
+
+func (r *silenceRepository) ListActive(now time.Time) ([]models.Silence, error) {
+	return r.query(
+		`SELECT `+silenceColumns+` FROM alert_silences WHERE starts_at <= $1 AND ends_at > $1`,
+		now,
+	)
+}
+
+func (r *silenceRepository) query(query string, args ...interface{}) ([]models.Silence, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	silences := []models.Silence{}
+	for rows.Next() {
+		silence, err := scanSilence(rows)
+		if err != nil {
+			return nil, err
+		}
+		silences = append(silences, silence)
+	}
+	return silences, rows.Err()
+}
+
+func (r *silenceRepository) Expire(id string, now time.Time) (models.Silence, error) {
+	row := r.db.QueryRow(
+		`UPDATE alert_silences
+		 SET ends_at = $2, starts_at = LEAST(starts_at, $2), updated_at = NOW()
+		 WHERE id = $1 AND ends_at > $2
+		 RETURNING `+silenceColumns,
+		id, now,
+	)
+	silence, err := scanSilence(row)
+	if errors.Is(err, ErrSilenceNotFound) {
+		// Already expired silences are returned unchanged.
+		return r.GetByID(id)
+	}
+	return silence, err
+}
+
+func (r *silenceRepository) DeleteExpiredBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM alert_silences WHERE ends_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func scanSilence(row rowScanner) (models.Silence, error) {
+	var silence models.Silence
+	var matchers []byte
+	if err := row.Scan(
+		&silence.ID, &matchers, &silence.StartsAt, &silence.EndsAt, &silence.CreatedBy, &silence.Comment,
+		&silence.CreatedAt, &silence.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Silence{}, ErrSilenceNotFound
+		}
+		return models.Silence{}, err
+	}
+	if err := json.Unmarshal(matchers, &silence.Matchers); err != nil {
+		return models.Silence{}, err
+	}
+	return silence, nil
+}
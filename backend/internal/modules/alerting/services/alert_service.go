@@ -0,0 +1,176 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+)
+
+const (
+	DefaultAlertLimit = 100
+	MaxAlertLimit     = 1000
+)
+
+// AlertService reads alert instances raised by the engine.
+type AlertService interface {
+	ListAlerts(filter repositories.AlertFilter) ([]models.Alert, error)
+	GetAlert(id string) (models.Alert, error)
+	// GroupAlerts returns the pending and firing alerts, optionally only
+	// those in state, grouped by the values of the labels in by, with one
+	// alert per fingerprint.
+	GroupAlerts(by []string, state string) ([]models.AlertGroup, error)
+}
+
+type alertService struct {
+	repo     repositories.AlertRepository
+	silences repositories.SilenceRepository
+}
+
+// NewAlertService returns an AlertService backed by repo. Alerts matched
+// by an active silence in silences carry its ID.
+func NewAlertService(repo repositories.AlertRepository, silences repositories.SilenceRepository) AlertService {
+	return &alertService{repo: repo, silences: silences}
+}
+
+func (s *alertService) ListAlerts(filter repositories.AlertFilter) ([]models.Alert, error) {
+	switch filter.State {
+	case "", models.StatePending, models.StateFiring, models.StateResolved:
+	default:
+		return nil, fmt.Errorf("%w: unknown alert state %q", ErrInvalidFilter, filter.State)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultAlertLimit
+	}
+	if filter.Limit > MaxAlertLimit {
+		filter.Limit = MaxAlertLimit
+	}
+
+	alerts, err := s.repo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.markSilenced(alerts)
+}
+
+func (s *alertService) GetAlert(id string) (models.Alert, error) {
+	alert, err := s.repo.GetByID(id)
+	if err != nil {
+		return models.Alert{}, err
+	}
+	marked, err := s.markSilenced([]models.Alert{alert})
+	if err != nil {
+		return models.Alert{}, err
+	}
+	return marked[0], nil
+}
+
+func (s *alertService) GroupAlerts(by []string, state string) ([]models.AlertGroup, error) {
+	switch state {
+	case "", models.StatePending, models.StateFiring:
+	default:
+		return nil, fmt.Errorf("%w: alert groups only hold pending and firing alerts", ErrInvalidFilter)
+	}
+	for _, name := range by {
+		if name == "..." && len(by) == 1 {
+			continue
+		}
+		if !labelNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%w: invalid label name %q", ErrInvalidFilter, name)
+		}
+	}
+
+	active, err := s.repo.ListActive()
+	if err != nil {
+		return nil, err
+	}
+	active, err = s.markSilenced(active)
+	if err != nil {
+		return nil, err
+	}
+
+	// The same labels can be raised by rules of the same name in different
+	// groups; the most recently activated alert stands for the fingerprint.
+	byFingerprint := map[string]models.Alert{}
+	for _, alert := range active {
+		if state != "" && alert.State != state {
+			continue
+		}
+		if kept, ok := byFingerprint[alert.Fingerprint]; !ok || alert.ActiveAt.After(kept.ActiveAt) {
+			byFingerprint[alert.Fingerprint] = alert
+		}
+	}
+
+	groups := map[string]*models.AlertGroup{}
+	for _, alert := range byFingerprint {
+		labels := GroupLabels(alert.Labels, by)
+		key := groupKey(labels)
+		group, ok := groups[key]
+		if !ok {
+			group = &models.AlertGroup{Labels: labels}
+			groups[key] = group
+		}
+		group.Alerts = append(group.Alerts, alert)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]models.AlertGroup, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		sort.Slice(group.Alerts, func(i, j int) bool {
+			return group.Alerts[i].ActiveAt.After(group.Alerts[j].ActiveAt)
+		})
+		result = append(result, *group)
+	}
+	return result, nil
+}
+
+func (s *alertService) markSilenced(alerts []models.Alert) ([]models.Alert, error) {
+	active, err := loadSilences(s.silences, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range alerts {
+		alerts[i].SilencedBy = active.match(alerts[i].Labels)
+	}
+	return alerts, nil
+}
+
+// GroupLabels returns the values of the labels in by; labels an alert does
+// not have are omitted. The single name "..." groups by every label.
+func GroupLabels(labels map[string]string, by []string) map[string]string {
+	group := map[string]string{}
+	if len(by) == 1 && by[0] == "..." {
+		for name, value := range labels {
+			group[name] = value
+		}
+		return group
+	}
+	for _, name := range by {
+		if value, ok := labels[name]; ok {
+			group[name] = value
+		}
+	}
+	return group
+}
+
+func groupKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s=%q,", name, labels[name])
+	}
+	return b.String()
+}
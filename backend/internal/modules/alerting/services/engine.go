@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/evaluator"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+	k8sservices "github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+var errNoPrometheus = errors.New("prometheus is not configured")
+
+// Locker elects a single replica to evaluate rules.
+type Locker interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release()
+}
+
+// Notifier is told about alerts that started firing or resolved in an
+// evaluation round, and about alerts still firing when they are due to be
+// repeated.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []models.Alert)
+}
+
+// EngineOptions tunes the rule engine. Zero values fall back to defaults.
+type EngineOptions struct {
+	// Interval is how often every enabled rule is evaluated.
+	Interval time.Duration
+	// ResolvedRetention is how long resolved alerts are kept; zero keeps
+	// them forever.
+	ResolvedRetention time.Duration
+	// Lock, when set, limits evaluation to whichever replica holds it.
+	// Without it every replica evaluates every rule.
+	Lock Locker
+	// Notifier, when set, receives the alerts due a notification after
+	// every round.
+	Notifier Notifier
+	// Silences, when set, keeps alerts matched by an active silence from
+	// the notifier.
+	Silences repositories.SilenceRepository
+	// RepeatInterval is how long after a firing alert was notified it is
+	// notified again; zero notifies it once.
+	RepeatInterval time.Duration
+}
+
+// Engine evaluates alerting rules on an interval and moves their alerts
+// through pending, firing and resolved.
+type Engine struct {
+	rules      repositories.RuleRepository
+	alerts     repositories.AlertRepository
+	series     k8sservices.SeriesService
+	prometheus evaluator.Evaluator
+	opts       EngineOptions
+	logger     *zap.Logger
+}
+
+// NewEngine returns an Engine. Cluster rules are evaluated against series
+// from series; Prometheus rules against prometheus, which may be nil when
+// no Prometheus server is configured.
+func NewEngine(rules repositories.RuleRepository, alerts repositories.AlertRepository, series k8sservices.SeriesService, prometheus evaluator.Evaluator, opts EngineOptions, logger *zap.Logger) *Engine {
+	if opts.Interval <= 0 {
+		opts.Interval = 30 * time.Second
+	}
+	return &Engine{
+		rules:      rules,
+		alerts:     alerts,
+		series:     series,
+		prometheus: prometheus,
+		opts:       opts,
+		logger:     logger,
+	}
+}
+
+// Run evaluates rules every interval until ctx is cancelled.
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+	defer func() {
+		if e.opts.Lock != nil {
+			e.opts.Lock.Release()
+		}
+	}()
+
+	lastPrune := time.Time{}
+	for {
+		now := time.Now()
+		if e.isLeader(ctx) {
+			e.Evaluate(ctx, now)
+			if e.opts.ResolvedRetention > 0 && now.Sub(lastPrune) >= time.Hour {
+				lastPrune = now
+				e.prune(now)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Engine) isLeader(ctx context.Context) bool {
+	if e.opts.Lock == nil {
+		return true
+	}
+	leader, err := e.opts.Lock.TryAcquire(ctx)
+	if err != nil {
+		e.logger.Warn("failed to acquire alert engine lock", zap.Error(err))
+		return false
+	}
+	return leader
+}
+
+// Evaluate runs every enabled rule once. Alerts of rules that have been
+// disabled are resolved. Alerts that resolved, and firing alerts that are
+// due, are passed to the notifier unless they are silenced.
+func (e *Engine) Evaluate(ctx context.Context, now time.Time) {
+	rules, err := e.rules.ListEnabled()
+	if err != nil {
+		e.logger.Warn("failed to load alert rules", zap.Error(err))
+		return
+	}
+	active, err := e.alerts.ListActive()
+	if err != nil {
+		e.logger.Warn("failed to load active alerts", zap.Error(err))
+		return
+	}
+	byRule := map[string][]models.Alert{}
+	for _, alert := range active {
+		byRule[alert.RuleID] = append(byRule[alert.RuleID], alert)
+	}
+
+	var reported []models.Alert
+	defer func() {
+		e.notify(ctx, reported, now)
+	}()
+
+	// The cluster snapshot is shared by every cluster rule in this round
+	// and only collected if one is enabled.
+	var cluster evaluator.Evaluator
+	var clusterErr error
+	collected := false
+	for _, rule := range rules {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var source evaluator.Evaluator
+		var sourceErr error
+		switch rule.Source {
+		case models.SourcePrometheus:
+			source = e.prometheus
+			if source == nil {
+				sourceErr = errNoPrometheus
+			}
+		default:
+			if !collected {
+				cluster, clusterErr = e.clusterEvaluator(ctx)
+				collected = true
+			}
+			source, sourceErr = cluster, clusterErr
+		}
+
+		evalErr := sourceErr
+		if evalErr == nil {
+			var alerts []models.Alert
+			alerts, evalErr = e.evaluateRule(ctx, rule, source, byRule[rule.ID], now)
+			reported = append(reported, alerts...)
+		}
+		delete(byRule, rule.ID)
+
+		message := ""
+		if evalErr != nil {
+			message = evalErr.Error()
+		}
+		if err := e.rules.RecordEvaluation(rule.ID, now, message); err != nil {
+			e.logger.Warn("failed to record rule evaluation", zap.String("rule", rule.ID), zap.Error(err))
+		}
+	}
+
+	for _, alerts := range byRule {
+		for _, alert := range alerts {
+			if resolved, ok := e.retire(alert, now); ok {
+				reported = append(reported, resolved)
+			}
+		}
+	}
+}
+
+func (e *Engine) clusterEvaluator(ctx context.Context) (evaluator.Evaluator, error) {
+	series, err := e.series.FleetSeries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("collect cluster series: %w", err)
+	}
+	samples := make([]evaluator.Sample, 0, len(series))
+	for _, item := range series {
+		samples = append(samples, evaluator.Sample{Labels: item.Labels, Value: item.Value})
+	}
+	return evaluator.NewLocal(samples), nil
+}
+
+// evaluateRule reconciles a rule's alerts with the samples its expression
+// returns, and returns the alerts that are firing or just resolved. On error
+// the existing alerts are left as they are, so a failed query neither
+// raises nor resolves anything.
+func (e *Engine) evaluateRule(ctx context.Context, rule models.Rule, source evaluator.Evaluator, existing []models.Alert, now time.Time) ([]models.Alert, error) {
+	forDuration, err := model.ParseDuration(rule.For)
+	if err != nil {
+		return nil, fmt.Errorf("for: %w", err)
+	}
+	samples, err := source.Evaluate(ctx, rule.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	type instance struct {
+		labels map[string]string
+		value  float64
+	}
+	current := map[string]instance{}
+	for _, sample := range samples {
+		labels := alertLabels(rule, sample.Labels)
+		fingerprint := Fingerprint(labels)
+		if _, dup := current[fingerprint]; dup {
+			return nil, errors.New("expression returned samples with the same labels once alert labels were applied")
+		}
+		current[fingerprint] = instance{labels: labels, value: sample.Value}
+	}
+
+	var reported []models.Alert
+	for _, alert := range existing {
+		found, ok := current[alert.Fingerprint]
+		if !ok {
+			if resolved, ok := e.retire(alert, now); ok {
+				reported = append(reported, resolved)
+			}
+			continue
+		}
+		delete(current, alert.Fingerprint)
+
+		alert.Labels = found.labels
+		alert.Value = found.value
+		alert.Annotations = expandAnnotations(rule.Annotations, found.labels, found.value)
+		alert.LastEvaluatedAt = now
+		alert.Severity = found.labels[models.SeverityLabel]
+		alert.Priority = models.Priority(alert.Severity)
+		if alert.State == models.StatePending && now.Sub(alert.ActiveAt) >= time.Duration(forDuration) {
+			alert.State = models.StateFiring
+			alert.FiredAt = &now
+			// Pending rows that predate notification tracking carry the
+			// time the column was added.
+			alert.LastNotifiedAt = nil
+		}
+		if err := e.alerts.Update(alert); err != nil {
+			e.logger.Warn("failed to update alert", zap.String("alert", alert.ID), zap.Error(err))
+			continue
+		}
+		if alert.State == models.StateFiring {
+			reported = append(reported, alert)
+		}
+	}
+
+	for fingerprint, found := range current {
+		alert := models.Alert{
+			RuleID:          rule.ID,
+			Fingerprint:     fingerprint,
+			State:           models.StatePending,
+			Labels:          found.labels,
+			Annotations:     expandAnnotations(rule.Annotations, found.labels, found.value),
+			Value:           found.value,
+			ActiveAt:        now,
+			LastEvaluatedAt: now,
+		}
+		if forDuration == 0 {
+			alert.State = models.StateFiring
+			alert.FiredAt = &now
+		}
+		created, err := e.alerts.Create(alert)
+		if err != nil {
+			e.logger.Warn("failed to create alert", zap.String("rule", rule.ID), zap.Error(err))
+			continue
+		}
+		if created.State == models.StateFiring {
+			reported = append(reported, created)
+		}
+	}
+	return reported, nil
+}
+
+// notify passes the notifier the resolved alerts and the firing alerts that
+// are due: never notified, or last notified RepeatInterval ago. Alerts an
+// active silence matches are held back; a firing one is notified once no
+// silence matches it any more. If silences cannot be loaded the alerts are
+// sent anyway; a missed page is worse than an unwanted one.
+func (e *Engine) notify(ctx context.Context, alerts []models.Alert, now time.Time) {
+	if e.opts.Notifier == nil || len(alerts) == 0 {
+		return
+	}
+	var active silences
+	if e.opts.Silences != nil {
+		var err error
+		if active, err = loadSilences(e.opts.Silences, now); err != nil {
+			e.logger.Warn("failed to load silences", zap.Error(err))
+		}
+	}
+
+	var due []models.Alert
+	for _, alert := range alerts {
+		if alert.State == models.StateFiring && !e.repeatDue(alert, now) {
+			continue
+		}
+		if ids := active.match(alert.Labels); len(ids) > 0 {
+			e.logger.Debug("alert notification silenced", zap.String("alert", alert.ID), zap.Strings("silences", ids))
+			continue
+		}
+		due = append(due, alert)
+	}
+	if len(due) == 0 {
+		return
+	}
+	e.opts.Notifier.Notify(ctx, due)
+
+	for _, alert := range due {
+		if alert.State != models.StateFiring {
+			continue
+		}
+		alert.LastNotifiedAt = &now
+		if err := e.alerts.Update(alert); err != nil {
+			e.logger.Warn("failed to record alert notification", zap.String("alert", alert.ID), zap.Error(err))
+		}
+	}
+}
+
+func (e *Engine) repeatDue(alert models.Alert, now time.Time) bool {
+	if alert.LastNotifiedAt == nil {
+		return true
+	}
+	return e.opts.RepeatInterval > 0 && now.Sub(*alert.LastNotifiedAt) >= e.opts.RepeatInterval
+}
+
+// retire ends an alert that is no longer active: a firing alert is
+// resolved and returned, a pending one never fired and is dropped.
+func (e *Engine) retire(alert models.Alert, now time.Time) (models.Alert, bool) {
+	if alert.State == models.StatePending {
+		if err := e.alerts.Delete(alert.ID); err != nil {
+			e.logger.Warn("failed to delete pending alert", zap.String("alert", alert.ID), zap.Error(err))
+		}
+		return models.Alert{}, false
+	}
+
+	alert.State = models.StateResolved
+	alert.ResolvedAt = &now
+	alert.LastEvaluatedAt = now
+	if err := e.alerts.Update(alert); err != nil {
+		e.logger.Warn("failed to resolve alert", zap.String("alert", alert.ID), zap.Error(err))
+		return models.Alert{}, false
+	}
+	return alert, true
+}
+
+func (e *Engine) prune(now time.Time) {
+	removed, err := e.alerts.DeleteResolvedBefore(now.Add(-e.opts.ResolvedRetention))
+	if err != nil {
+		e.logger.Warn("failed to prune resolved alerts", zap.Error(err))
+		return
+	}
+	if removed > 0 {
+		e.logger.Info("pruned resolved alerts", zap.Int64("removed", removed))
+	}
+
+	if e.opts.Silences == nil {
+		return
+	}
+	removed, err = e.opts.Silences.DeleteExpiredBefore(now.Add(-e.opts.ResolvedRetention))
+	if err != nil {
+		e.logger.Warn("failed to prune expired silences", zap.Error(err))
+		return
+	}
+	if removed > 0 {
+		e.logger.Info("pruned expired silences", zap.Int64("removed", removed))
+	}
+}
+
+// alertLabels builds an alert's labels: the sample's labels without the
+// metric name, overridden by the rule's labels and its alertname.
+func alertLabels(rule models.Rule, sampleLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(sampleLabels)+len(rule.Labels)+1)
+	for name, value := range sampleLabels {
+		if name != evaluator.MetricNameLabel {
+			labels[name] = value
+		}
+	}
+	for name, value := range rule.Labels {
+		labels[name] = value
+	}
+	labels[models.AlertNameLabel] = rule.Name
+	return labels
+}
+
+// Fingerprint identifies an alert by its label set.
+func Fingerprint(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		hash.Write([]byte(name))
+		hash.Write([]byte{0xfe})
+		hash.Write([]byte(labels[name]))
+		hash.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -0,0 +1,520 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/evaluator"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+)
+
+// memRules is an in-memory RuleRepository.
+type memRules struct {
+	rules []models.Rule
+}
+
+func (r *memRules) Create(rule models.Rule) (models.Rule, error) {
+	r.rules = append(r.rules, rule)
+	return rule, nil
+}
+
+func (r *memRules) GetByID(id string) (models.Rule, error) {
+	for _, rule := range r.rules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return models.Rule{}, repositories.ErrRuleNotFound
+}
+
+func (r *memRules) List() ([]models.Rule, error) {
+	return append([]models.Rule(nil), r.rules...), nil
+}
+
+func (r *memRules) ListEnabled() ([]models.Rule, error) {
+	var enabled []models.Rule
+	for _, rule := range r.rules {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	return enabled, nil
+}
+
+func (r *memRules) Update(rule models.Rule) (models.Rule, error) {
+	for i := range r.rules {
+		if r.rules[i].ID == rule.ID {
+			r.rules[i] = rule
+			return rule, nil
+		}
+	}
+	return models.Rule{}, repositories.ErrRuleNotFound
+}
+
+func (r *memRules) Delete(id string) error {
+	for i := range r.rules {
+		if r.rules[i].ID == id {
+			r.rules = append(r.rules[:i], r.rules[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrRuleNotFound
+}
+
+func (r *memRules) RecordEvaluation(id string, at time.Time, evalErr string) error {
+	for i := range r.rules {
+		if r.rules[i].ID == id {
+			r.rules[i].LastEvaluatedAt = &at
+			r.rules[i].LastError = evalErr
+			return nil
+		}
+	}
+	return repositories.ErrRuleNotFound
+}
+
+func (r *memRules) Import(rules []models.Rule, prune bool) (models.ImportResult, error) {
+	return models.ImportResult{}, fmt.Errorf("not supported")
+}
+
+// memAlerts is an in-memory AlertRepository.
+type memAlerts struct {
+	alerts []models.Alert
+}
+
+func (r *memAlerts) Create(alert models.Alert) (models.Alert, error) {
+	alert.ID = fmt.Sprintf("alert-%d", len(r.alerts)+1)
+	r.alerts = append(r.alerts, alert)
+	return alert, nil
+}
+
+func (r *memAlerts) GetByID(id string) (models.Alert, error) {
+	for _, alert := range r.alerts {
+		if alert.ID == id {
+			return alert, nil
+		}
+	}
+	return models.Alert{}, repositories.ErrAlertNotFound
+}
+
+func (r *memAlerts) List(filter repositories.AlertFilter) ([]models.Alert, error) {
+	var list []models.Alert
+	for _, alert := range r.alerts {
+		if (filter.State == "" || alert.State == filter.State) && (filter.RuleID == "" || alert.RuleID == filter.RuleID) {
+			list = append(list, alert)
+		}
+	}
+	return list, nil
+}
+
+func (r *memAlerts) ListActive() ([]models.Alert, error) {
+	var active []models.Alert
+	for _, alert := range r.alerts {
+		if alert.State != models.StateResolved {
+			active = append(active, alert)
+		}
+	}
+	return active, nil
+}
+
+func (r *memAlerts) Update(alert models.Alert) error {
+	for i := range r.alerts {
+		if r.alerts[i].ID == alert.ID {
+			r.alerts[i] = alert
+			return nil
+		}
+	}
+	return repositories.ErrAlertNotFound
+}
+
+func (r *memAlerts) Delete(id string) error {
+	for i := range r.alerts {
+		if r.alerts[i].ID == id {
+			r.alerts = append(r.alerts[:i], r.alerts[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *memAlerts) DeleteResolvedBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// memSilences is an in-memory SilenceRepository.
+type memSilences struct {
+	silences []models.Silence
+}
+
+func (r *memSilences) Create(silence models.Silence) (models.Silence, error) {
+	silence.ID = fmt.Sprintf("silence-%d", len(r.silences)+1)
+	r.silences = append(r.silences, silence)
+	return silence, nil
+}
+
+func (r *memSilences) GetByID(id string) (models.Silence, error) {
+	for _, silence := range r.silences {
+		if silence.ID == id {
+			return silence, nil
+		}
+	}
+	return models.Silence{}, repositories.ErrSilenceNotFound
+}
+
+func (r *memSilences) List() ([]models.Silence, error) {
+	return append([]models.Silence(nil), r.silences...), nil
+}
+
+func (r *memSilences) ListActive(now time.Time) ([]models.Silence, error) {
+	var active []models.Silence
+	for _, silence := range r.silences {
+		if silence.StateAt(now) == models.SilenceStateActive {
+			active = append(active, silence)
+		}
+	}
+	return active, nil
+}
+
+func (r *memSilences) Expire(id string, now time.Time) (models.Silence, error) {
+	for i := range r.silences {
+		if r.silences[i].ID == id && r.silences[i].EndsAt.After(now) {
+			r.silences[i].EndsAt = now
+			if r.silences[i].StartsAt.After(now) {
+				r.silences[i].StartsAt = now
+			}
+			return r.silences[i], nil
+		}
+	}
+	return models.Silence{}, repositories.ErrSilenceNotFound
+}
+
+func (r *memSilences) DeleteExpiredBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// staticSamples is an Evaluator that returns the same samples for every
+// expression.
+type staticSamples []evaluator.Sample
+
+func (s *staticSamples) Evaluate(ctx context.Context, expr string) ([]evaluator.Sample, error) {
+	return *s, nil
+}
+
+// notifications records the alerts passed to the notifier, one batch per
+// round.
+type notifications struct {
+	mu      sync.Mutex
+	batches [][]models.Alert
+}
+
+func (n *notifications) Notify(ctx context.Context, alerts []models.Alert) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.batches = append(n.batches, append([]models.Alert(nil), alerts...))
+}
+
+// take returns the alerts notified since the last call as "id:state".
+func (n *notifications) take() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var taken []string
+	for _, batch := range n.batches {
+		for _, alert := range batch {
+			taken = append(taken, alert.ID+":"+alert.State)
+		}
+	}
+	n.batches = nil
+	return taken
+}
+
+type engineFixture struct {
+	engine   *Engine
+	rules    *memRules
+	alerts   *memAlerts
+	silences *memSilences
+	samples  *staticSamples
+	notified *notifications
+}
+
+func newEngineFixture(forDuration string, repeat time.Duration) *engineFixture {
+	f := &engineFixture{
+		alerts:   &memAlerts{},
+		silences: &memSilences{},
+		samples:  &staticSamples{{Labels: map[string]string{"instance": "db-1"}, Value: 1}},
+		notified: &notifications{},
+	}
+	f.rules = &memRules{rules: []models.Rule{{
+		ID:      "r1",
+		Name:    "InstanceDown",
+		Source:  models.SourcePrometheus,
+		Expr:    "up == 0",
+		For:     forDuration,
+		Enabled: true,
+	}}}
+	f.engine = NewEngine(f.rules, f.alerts, nil, f.samples, EngineOptions{
+		Notifier:       f.notified,
+		Silences:       f.silences,
+		RepeatInterval: repeat,
+	}, zap.NewNop())
+	return f
+}
+
+// round evaluates at now and returns what was notified.
+func (f *engineFixture) round(now time.Time) string {
+	f.engine.Evaluate(context.Background(), now)
+	return fmt.Sprint(f.notified.take())
+}
+
+func (f *engineFixture) silence(startsAt, endsAt time.Time) models.Silence {
+	silence, _ := f.silences.Create(models.Silence{
+		Matchers: []models.Matcher{{Name: models.AlertNameLabel, Value: "InstanceDown", IsEqual: true}},
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	})
+	return silence
+}
+
+func TestEngineNotifiesOnceSilenceEnds(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	f := newEngineFixture("0s", 0)
+	f.silence(start, start.Add(10*time.Minute))
+
+	steps := []struct {
+		at   time.Duration
+		want string
+	}{
+		{at: 0, want: "[]"},
+		{at: 5 * time.Minute, want: "[]"},
+		// The silence ended, so the alert that fired under it is notified.
+		{at: 10 * time.Minute, want: "[alert-1:firing]"},
+		{at: 15 * time.Minute, want: "[]"},
+	}
+	for _, step := range steps {
+		if got := f.round(start.Add(step.at)); got != step.want {
+			t.Fatalf("at +%s notified %s, want %s", step.at, got, step.want)
+		}
+	}
+	if notified := f.alerts.alerts[0].LastNotifiedAt; notified == nil || !notified.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("LastNotifiedAt = %v", notified)
+	}
+}
+
+func TestEngineNotifiesWhenSilenceExpired(t *testing.T) {
+	f := newEngineFixture("0s", 0)
+	service := NewSilenceService(f.silences)
+	silence, err := service.CreateSilence(models.Silence{
+		Matchers:  []models.Matcher{{Name: "instance", Value: "db-.*", IsRegex: true, IsEqual: true}},
+		EndsAt:    time.Now().Add(time.Hour),
+		CreatedBy: "ops",
+		Comment:   "maintenance",
+	})
+	if err != nil {
+		t.Fatalf("CreateSilence: %v", err)
+	}
+
+	if got := f.round(time.Now()); got != "[]" {
+		t.Fatalf("notified %s under an active silence", got)
+	}
+	if _, err := service.ExpireSilence(silence.ID); err != nil {
+		t.Fatalf("ExpireSilence: %v", err)
+	}
+	if got := f.round(time.Now().Add(time.Second)); got != "[alert-1:firing]" {
+		t.Fatalf("after expiring the silence notified %s, want the firing alert", got)
+	}
+}
+
+func TestEngineRepeatInterval(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		repeat time.Duration
+		want   []string
+	}{
+		{name: "hourly", repeat: time.Hour, want: []string{"[alert-1:firing]", "[]", "[alert-1:firing]", "[]", "[alert-1:resolved]"}},
+		{name: "never", want: []string{"[alert-1:firing]", "[]", "[]", "[]", "[alert-1:resolved]"}},
+	}
+	rounds := []time.Duration{0, 30 * time.Minute, time.Hour, 90 * time.Minute, 100 * time.Minute}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEngineFixture("0s", tt.repeat)
+			for i, at := range rounds {
+				if at == 100*time.Minute {
+					*f.samples = nil
+				}
+				if got := f.round(start.Add(at)); got != tt.want[i] {
+					t.Fatalf("at +%s notified %s, want %s", at, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEngineRepeatHeldBySilence(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	f := newEngineFixture("0s", time.Hour)
+	if got := f.round(start); got != "[alert-1:firing]" {
+		t.Fatalf("first round notified %s", got)
+	}
+
+	// A silence during the repeat holds it back until the silence ends,
+	// and its resolution while silenced is dropped.
+	f.silence(start.Add(30*time.Minute), start.Add(2*time.Hour))
+	if got := f.round(start.Add(time.Hour)); got != "[]" {
+		t.Fatalf("silenced repeat notified %s", got)
+	}
+	if got := f.round(start.Add(2 * time.Hour)); got != "[alert-1:firing]" {
+		t.Fatalf("after the silence notified %s, want the repeat", got)
+	}
+	f.silence(start.Add(2*time.Hour), start.Add(3*time.Hour))
+	*f.samples = nil
+	if got := f.round(start.Add(150 * time.Minute)); got != "[]" {
+		t.Fatalf("silenced resolution notified %s", got)
+	}
+}
+
+func TestEnginePendingNotNotified(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	f := newEngineFixture("5m", time.Hour)
+	if got := f.round(start); got != "[]" {
+		t.Fatalf("pending alert notified %s", got)
+	}
+	// Rows that predate notification tracking were given a notified time.
+	placeholder := start
+	f.alerts.alerts[0].LastNotifiedAt = &placeholder
+	if got := f.round(start.Add(5 * time.Minute)); got != "[alert-1:firing]" {
+		t.Fatalf("alert that started firing notified %s", got)
+	}
+}
+
+// states returns the stored alerts as "id:state".
+func (f *engineFixture) states() string {
+	var states []string
+	for _, alert := range f.alerts.alerts {
+		states = append(states, alert.ID+":"+alert.State)
+	}
+	return fmt.Sprint(states)
+}
+
+func TestEngineAlertLifecycle(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	firing := staticSamples{{Labels: map[string]string{"instance": "db-1"}, Value: 1}}
+
+	type step struct {
+		at time.Duration
+		// clear makes the expression return nothing from this round on;
+		// fire makes it return the sample again.
+		clear, fire bool
+		// disable turns the rule off before the round.
+		disable      bool
+		wantAlerts   string
+		wantNotified string
+	}
+	tests := []struct {
+		name        string
+		forDuration string
+		steps       []step
+	}{
+		{
+			name:        "pending fires after for, then resolves",
+			forDuration: "5m",
+			steps: []step{
+				{at: 0, wantAlerts: "[alert-1:pending]", wantNotified: "[]"},
+				{at: 4 * time.Minute, wantAlerts: "[alert-1:pending]", wantNotified: "[]"},
+				{at: 5 * time.Minute, wantAlerts: "[alert-1:firing]", wantNotified: "[alert-1:firing]"},
+				{at: 6 * time.Minute, wantAlerts: "[alert-1:firing]", wantNotified: "[]"},
+				{at: 7 * time.Minute, clear: true, wantAlerts: "[alert-1:resolved]", wantNotified: "[alert-1:resolved]"},
+				{at: 8 * time.Minute, wantAlerts: "[alert-1:resolved]", wantNotified: "[]"},
+			},
+		},
+		{
+			name:        "without for fires at once",
+			forDuration: "0s",
+			steps: []step{
+				{at: 0, wantAlerts: "[alert-1:firing]", wantNotified: "[alert-1:firing]"},
+				{at: time.Minute, clear: true, wantAlerts: "[alert-1:resolved]", wantNotified: "[alert-1:resolved]"},
+				// A new occurrence is a new alert.
+				{at: 2 * time.Minute, fire: true, wantAlerts: "[alert-1:resolved alert-2:firing]", wantNotified: "[alert-2:firing]"},
+			},
+		},
+		{
+			name:        "pending alert that clears is dropped",
+			forDuration: "5m",
+			steps: []step{
+				{at: 0, wantAlerts: "[alert-1:pending]", wantNotified: "[]"},
+				{at: 3 * time.Minute, clear: true, wantAlerts: "[]", wantNotified: "[]"},
+				// Returning starts the for clock again.
+				{at: 4 * time.Minute, fire: true, wantAlerts: "[alert-1:pending]", wantNotified: "[]"},
+				{at: 8 * time.Minute, wantAlerts: "[alert-1:pending]", wantNotified: "[]"},
+				{at: 9 * time.Minute, wantAlerts: "[alert-1:firing]", wantNotified: "[alert-1:firing]"},
+			},
+		},
+		{
+			name:        "firing alert of a disabled rule resolves",
+			forDuration: "0s",
+			steps: []step{
+				{at: 0, wantAlerts: "[alert-1:firing]", wantNotified: "[alert-1:firing]"},
+				{at: time.Minute, disable: true, wantAlerts: "[alert-1:resolved]", wantNotified: "[alert-1:resolved]"},
+				{at: 2 * time.Minute, wantAlerts: "[alert-1:resolved]", wantNotified: "[]"},
+			},
+		},
+		{
+			name:        "pending alert of a disabled rule is dropped",
+			forDuration: "5m",
+			steps: []step{
+				{at: 0, wantAlerts: "[alert-1:pending]", wantNotified: "[]"},
+				{at: time.Minute, disable: true, wantAlerts: "[]", wantNotified: "[]"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEngineFixture(tt.forDuration, 0)
+			for _, step := range tt.steps {
+				switch {
+				case step.clear:
+					*f.samples = nil
+				case step.fire:
+					*f.samples = firing
+				}
+				if step.disable {
+					f.rules.rules[0].Enabled = false
+				}
+				notified := f.round(start.Add(step.at))
+				if got := f.states(); got != step.wantAlerts {
+					t.Fatalf("at +%s alerts %s, want %s", step.at, got, step.wantAlerts)
+				}
+				if notified != step.wantNotified {
+					t.Fatalf("at +%s notified %s, want %s", step.at, notified, step.wantNotified)
+				}
+			}
+		})
+	}
+}
+
+func TestEngineAlertTimestamps(t *testing.T) {
+	start := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	f := newEngineFixture("5m", 0)
+	f.round(start)
+	f.round(start.Add(5 * time.Minute))
+	*f.samples = nil
+	f.round(start.Add(7 * time.Minute))
+
+	alert := f.alerts.alerts[0]
+	if !alert.ActiveAt.Equal(start) {
+		t.Errorf("ActiveAt = %s, want the first round", alert.ActiveAt)
+	}
+	if alert.FiredAt == nil || !alert.FiredAt.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("FiredAt = %v, want once for had passed", alert.FiredAt)
+	}
+	if alert.ResolvedAt == nil || !alert.ResolvedAt.Equal(start.Add(7*time.Minute)) || !alert.LastEvaluatedAt.Equal(*alert.ResolvedAt) {
+		t.Errorf("ResolvedAt = %v, LastEvaluatedAt = %s", alert.ResolvedAt, alert.LastEvaluatedAt)
+	}
+	if alert.Labels[models.AlertNameLabel] != "InstanceDown" || alert.Labels["instance"] != "db-1" {
+		t.Errorf("labels = %v", alert.Labels)
+	}
+}
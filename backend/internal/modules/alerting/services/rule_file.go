@@ -0,0 +1,140 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+)
+
+// ParseRuleFile reads a Prometheus rule file (groups of alerting and
+// recording rules) into rules of the given source, validating the file as
+// Prometheus would and each rule as NormalizeRule does. Recording rules
+// are not supported; their names are returned as skipped.
+func ParseRuleFile(content []byte, source string) ([]models.Rule, []string, error) {
+	groups, errs := rulefmt.Parse(content)
+	if len(errs) > 0 {
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidRule, strings.Join(messages, "; "))
+	}
+
+	var rules []models.Rule
+	var skipped []string
+	for _, group := range groups.Groups {
+		seen := map[string]bool{}
+		for _, node := range group.Rules {
+			if node.Record.Value != "" {
+				skipped = append(skipped, group.Name+"/"+node.Record.Value)
+				continue
+			}
+			if seen[node.Alert.Value] {
+				return nil, nil, fmt.Errorf("%w: %s/%s: alert names must be unique within a group",
+					ErrInvalidRule, group.Name, node.Alert.Value)
+			}
+			seen[node.Alert.Value] = true
+			if node.KeepFiringFor != 0 {
+				return nil, nil, fmt.Errorf("%w: %s/%s: keep_firing_for is not supported",
+					ErrInvalidRule, group.Name, node.Alert.Value)
+			}
+
+			rule, err := NormalizeRule(models.Rule{
+				Name:        node.Alert.Value,
+				Group:       group.Name,
+				Source:      source,
+				Expr:        node.Expr.Value,
+				For:         node.For.String(),
+				Labels:      node.Labels,
+				Annotations: node.Annotations,
+				Enabled:     true,
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s/%s: %w", group.Name, node.Alert.Value, err)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, skipped, nil
+}
+
+type ruleFile struct {
+	Groups []ruleFileGroup `yaml:"groups"`
+}
+
+type ruleFileGroup struct {
+	Name  string          `yaml:"name"`
+	Rules []ruleFileEntry `yaml:"rules"`
+}
+
+type ruleFileEntry struct {
+	Alert       string            `yaml:"alert"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// FormatRuleFile writes rules as a Prometheus rule file, one group per rule
+// group, groups and rules sorted by name. The output is parsed back with
+// the Prometheus rule file parser before it is returned. A rule file has
+// no place for a rule's source, so rules of different sources are refused
+// rather than written into a file that would import them all as one.
+func FormatRuleFile(rules []models.Rule) ([]byte, error) {
+	byGroup := map[string][]models.Rule{}
+	sources := map[string]bool{}
+	for _, rule := range rules {
+		byGroup[rule.Group] = append(byGroup[rule.Group], rule)
+		sources[rule.Source] = true
+	}
+	if len(sources) > 1 {
+		names := make([]string, 0, len(sources))
+		for source := range sources {
+			names = append(names, source)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("%w: rules of sources %s cannot share a rule file; export one source at a time",
+			ErrInvalidRule, strings.Join(names, " and "))
+	}
+	names := make([]string, 0, len(byGroup))
+	for name := range byGroup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	file := ruleFile{Groups: make([]ruleFileGroup, 0, len(names))}
+	for _, name := range names {
+		grouped := byGroup[name]
+		sort.Slice(grouped, func(i, j int) bool { return grouped[i].Name < grouped[j].Name })
+
+		group := ruleFileGroup{Name: name, Rules: make([]ruleFileEntry, 0, len(grouped))}
+		for _, rule := range grouped {
+			entry := ruleFileEntry{
+				Alert:       rule.Name,
+				Expr:        rule.Expr,
+				Labels:      rule.Labels,
+				Annotations: rule.Annotations,
+			}
+			if forDuration, err := model.ParseDuration(rule.For); err == nil && forDuration != 0 {
+				entry.For = forDuration.String()
+			}
+			group.Rules = append(group.Rules, entry)
+		}
+		file.Groups = append(file.Groups, group)
+	}
+
+	content, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, errs := rulefmt.Parse(content); len(errs) > 0 {
+		return nil, fmt.Errorf("exported rule file does not parse: %v", errs[0])
+	}
+	return content, nil
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+)
+
+const kubernetesRules = `
+groups:
+  - name: kubernetes.rules
+    rules:
+      - alert: PodNotReady
+        expr: kube_pod_status_ready == 0
+        for: 10m
+        labels:
+          severity: warning
+        annotations:
+          summary: "{{ $labels.pod }} in {{ $labels.namespace }} is not ready"
+      - alert: NodeNotReady
+        expr: kube_node_status_condition{condition="Ready"} == 0
+  - name: capacity.rules
+    rules:
+      - alert: QuotaNearLimit
+        expr: namespace_quota_used_percent > 90
+        for: 1h
+        labels:
+          severity: info
+`
+
+const apiRules = `
+groups:
+  - name: api.rules
+    rules:
+      - record: job:http_errors:rate5m
+        expr: sum by (job) (rate(http_requests_total{code=~"5.."}[5m]))
+      - alert: HighErrorRate
+        expr: sum by (job) (rate(http_requests_total{code=~"5.."}[5m])) / sum by (job) (rate(http_requests_total[5m])) > 0.05
+        for: 5m
+        labels:
+          severity: critical
+`
+
+func parseRules(t *testing.T, content, source string) []models.Rule {
+	t.Helper()
+	rules, _, err := ParseRuleFile([]byte(content), source)
+	if err != nil {
+		t.Fatalf("ParseRuleFile: %v", err)
+	}
+	return rules
+}
+
+func TestRuleFileRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		source  string
+		content string
+	}{
+		{source: models.SourceCluster, content: kubernetesRules},
+		{source: models.SourcePrometheus, content: apiRules},
+	} {
+		t.Run(tt.source, func(t *testing.T) {
+			rules := parseRules(t, tt.content, tt.source)
+			exported, err := FormatRuleFile(rules)
+			if err != nil {
+				t.Fatalf("FormatRuleFile: %v", err)
+			}
+			reimported := parseRules(t, string(exported), tt.source)
+			if len(reimported) != len(rules) {
+				t.Fatalf("reimported %d rules, want %d:\n%s", len(reimported), len(rules), exported)
+			}
+			byKey := map[string]models.Rule{}
+			for _, rule := range rules {
+				byKey[rule.Group+"/"+rule.Name] = rule
+			}
+			for _, rule := range reimported {
+				if want := byKey[rule.Group+"/"+rule.Name]; !reflect.DeepEqual(rule, want) {
+					t.Errorf("reimported %+v\nwant       %+v", rule, want)
+				}
+			}
+
+			// Exporting is stable, so a file kept in Git does not churn.
+			again, err := FormatRuleFile(reimported)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(exported) {
+				t.Errorf("second export differs:\n%s\nfirst:\n%s", again, exported)
+			}
+		})
+	}
+}
+
+func TestFormatRuleFileOrder(t *testing.T) {
+	exported, err := FormatRuleFile(parseRules(t, kubernetesRules, models.SourceCluster))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(exported)
+	order := []string{"capacity.rules", "kubernetes.rules", "NodeNotReady", "PodNotReady"}
+	last := -1
+	for _, name := range order {
+		at := strings.Index(content, name)
+		if at <= last {
+			t.Fatalf("%s out of order in\n%s", name, content)
+		}
+		last = at
+	}
+	// A zero for is left out, as Prometheus would write it.
+	if strings.Count(content, "for:") != 2 {
+		t.Errorf("want for only on the rules that have one:\n%s", content)
+	}
+}
+
+func TestExportRulesSources(t *testing.T) {
+	var stored []models.Rule
+	stored = append(stored, parseRules(t, kubernetesRules, models.SourceCluster)...)
+	stored = append(stored, parseRules(t, apiRules, models.SourcePrometheus)...)
+	service := NewRuleService(&memRules{rules: stored})
+
+	if _, err := service.ExportRules("", ""); !errors.Is(err, ErrInvalidRule) {
+		t.Fatalf("exporting both sources: err = %v, want ErrInvalidRule", err)
+	}
+	// A group of one source exports without naming it.
+	if _, err := service.ExportRules("api.rules", ""); err != nil {
+		t.Errorf("exporting one group: %v", err)
+	}
+
+	exported, err := service.ExportRules("", models.SourcePrometheus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := parseRules(t, string(exported), models.SourcePrometheus)
+	if len(rules) != 1 || rules[0].Name != "HighErrorRate" || rules[0].Source != models.SourcePrometheus {
+		t.Errorf("prometheus export reimported as %+v", rules)
+	}
+	// The rule needs Prometheus; importing it as a cluster rule fails
+	// rather than storing a rule that can never evaluate.
+	if _, _, err := ParseRuleFile(exported, models.SourceCluster); err == nil {
+		t.Error("prometheus rules imported as cluster rules")
+	}
+}
+
+func TestParseRuleFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		content     string
+		wantRules   []string
+		wantSkipped []string
+		wantErr     error
+	}{
+		{
+			name:      "default source is cluster",
+			content:   kubernetesRules,
+			wantRules: []string{"kubernetes.rules/PodNotReady cluster", "kubernetes.rules/NodeNotReady cluster", "capacity.rules/QuotaNearLimit cluster"},
+		},
+		{
+			name:        "recording rules are skipped",
+			source:      models.SourcePrometheus,
+			content:     apiRules,
+			wantRules:   []string{"api.rules/HighErrorRate prometheus"},
+			wantSkipped: []string{"api.rules/job:http_errors:rate5m"},
+		},
+		{
+			name:   "invalid PromQL",
+			source: models.SourcePrometheus,
+			content: `
+groups:
+  - name: g
+    rules:
+      - alert: Broken
+        expr: sum by (job (up)
+`,
+			wantErr: ErrInvalidRule,
+		},
+		{
+			name: "function in a cluster rule",
+			content: `
+groups:
+  - name: g
+    rules:
+      - alert: Restarting
+        expr: rate(kube_pod_container_status_restarts_total[5m]) > 0
+`,
+			wantErr: ErrInvalidRule,
+		},
+		{
+			name:   "duplicate alert in a group",
+			source: models.SourcePrometheus,
+			content: `
+groups:
+  - name: g
+    rules:
+      - alert: Down
+        expr: up == 0
+      - alert: Down
+        expr: up < 1
+`,
+			wantErr: ErrInvalidRule,
+		},
+		{
+			name:   "same alert in two groups",
+			source: models.SourcePrometheus,
+			content: `
+groups:
+  - name: a
+    rules:
+      - alert: Down
+        expr: up == 0
+  - name: b
+    rules:
+      - alert: Down
+        expr: up == 0
+`,
+			wantRules: []string{"a/Down prometheus", "b/Down prometheus"},
+		},
+		{
+			name:   "keep_firing_for",
+			source: models.SourcePrometheus,
+			content: `
+groups:
+  - name: g
+    rules:
+      - alert: Down
+        expr: up == 0
+        keep_firing_for: 5m
+`,
+			wantErr: ErrInvalidRule,
+		},
+		{
+			name:   "unknown field",
+			source: models.SourcePrometheus,
+			content: `
+groups:
+  - name: g
+    source: prometheus
+    rules:
+      - alert: Down
+        expr: up == 0
+`,
+			wantErr: ErrInvalidRule,
+		},
+		{
+			name:   "bad template",
+			source: models.SourcePrometheus,
+			content: `
+groups:
+  - name: g
+    rules:
+      - alert: Down
+        expr: up == 0
+        annotations:
+          summary: "{{ .Labels.instance"
+`,
+			wantErr: ErrInvalidRule,
+		},
+		{
+			name:    "unknown source",
+			source:  "graphite",
+			content: kubernetesRules,
+			wantErr: ErrInvalidRule,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, skipped, err := ParseRuleFile([]byte(tt.content), tt.source)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, rule := range rules {
+				if !rule.Enabled {
+					t.Errorf("%s was imported disabled", rule.Name)
+				}
+				got = append(got, rule.Group+"/"+rule.Name+" "+rule.Source)
+			}
+			if !reflect.DeepEqual(got, tt.wantRules) {
+				t.Errorf("rules = %q, want %q", got, tt.wantRules)
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("skipped = %q, want %q", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/evaluator"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+)
+
+var (
+	ErrInvalidRule   = errors.New("invalid alert rule")
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// DefaultGroup is the group of rules created without one.
+const DefaultGroup = "default"
+
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// RuleService manages alerting rules.
+type RuleService interface {
+	ListRules() ([]models.Rule, error)
+	GetRule(id string) (models.Rule, error)
+	CreateRule(rule models.Rule) (models.Rule, error)
+	UpdateRule(rule models.Rule) (models.Rule, error)
+	DeleteRule(id string) error
+	// ImportRules stores the alerting rules of a Prometheus rule file.
+	ImportRules(content []byte, opts ImportOptions) (models.ImportResult, error)
+	// ExportRules writes stored rules as a Prometheus rule file, optionally
+	// only those of one group or source. The selected rules must share a
+	// source.
+	ExportRules(group, source string) ([]byte, error)
+}
+
+// ImportOptions controls a rule file import.
+type ImportOptions struct {
+	// Source is the source given to every imported rule; it defaults to
+	// cluster, as for rules created through the API.
+	Source string
+	// Prune deletes stored rules in the file's groups that the file does
+	// not contain.
+	Prune bool
+	// DryRun validates the file without storing anything.
+	DryRun bool
+}
+
+type ruleService struct {
+	repo repositories.RuleRepository
+}
+
+// NewRuleService returns a RuleService backed by repo.
+func NewRuleService(repo repositories.RuleRepository) RuleService {
+	return &ruleService{repo: repo}
+}
+
+func (s *ruleService) ListRules() ([]models.Rule, error) {
+	rules, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.Rule{}
+	}
+	return rules, nil
+}
+
+func (s *ruleService) GetRule(id string) (models.Rule, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *ruleService) CreateRule(rule models.Rule) (models.Rule, error) {
+	rule, err := NormalizeRule(rule)
+	if err != nil {
+		return models.Rule{}, err
+	}
+	return s.repo.Create(rule)
+}
+
+func (s *ruleService) UpdateRule(rule models.Rule) (models.Rule, error) {
+	rule, err := NormalizeRule(rule)
+	if err != nil {
+		return models.Rule{}, err
+	}
+	return s.repo.Update(rule)
+}
+
+// DeleteRule removes a rule together with its alerts.
+func (s *ruleService) DeleteRule(id string) error {
+	return s.repo.Delete(id)
+}
+
+func (s *ruleService) ImportRules(content []byte, opts ImportOptions) (models.ImportResult, error) {
+	rules, skipped, err := ParseRuleFile(content, opts.Source)
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+
+	var result models.ImportResult
+	if opts.DryRun {
+		result, err = s.planImport(rules, opts.Prune)
+	} else {
+		result, err = s.repo.Import(rules, opts.Prune)
+	}
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+	result.Skipped = skipped
+	result.DryRun = opts.DryRun
+	return result, nil
+}
+
+// planImport counts what importing rules would create, update and delete.
+func (s *ruleService) planImport(rules []models.Rule, prune bool) (models.ImportResult, error) {
+	stored, err := s.repo.List()
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+	existing := map[string]bool{}
+	for _, rule := range stored {
+		existing[rule.Group+"/"+rule.Name] = true
+	}
+
+	var result models.ImportResult
+	imported := map[string]bool{}
+	groups := map[string]bool{}
+	for _, rule := range rules {
+		key := rule.Group + "/" + rule.Name
+		imported[key] = true
+		groups[rule.Group] = true
+		if existing[key] {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+	}
+	if prune {
+		for _, rule := range stored {
+			if groups[rule.Group] && !imported[rule.Group+"/"+rule.Name] {
+				result.Deleted++
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *ruleService) ExportRules(group, source string) ([]byte, error) {
+	rules, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]models.Rule, 0, len(rules))
+	for _, rule := range rules {
+		if (group == "" || rule.Group == group) && (source == "" || rule.Source == source) {
+			selected = append(selected, rule)
+		}
+	}
+	return FormatRuleFile(selected)
+}
+
+// NormalizeRule applies defaults and validates a rule: its name must be a
+// valid alertname, its expression must parse (and for cluster rules use
+// only what can be evaluated without history), and its annotations must be
+// valid templates.
+func NormalizeRule(rule models.Rule) (models.Rule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.Group = strings.TrimSpace(rule.Group)
+	rule.Expr = strings.TrimSpace(rule.Expr)
+	if rule.Group == "" {
+		rule.Group = DefaultGroup
+	}
+	if rule.Source == "" {
+		rule.Source = models.SourceCluster
+	}
+	if rule.Labels == nil {
+		rule.Labels = map[string]string{}
+	}
+	if rule.Annotations == nil {
+		rule.Annotations = map[string]string{}
+	}
+
+	if !labelNamePattern.MatchString(rule.Name) {
+		return models.Rule{}, fmt.Errorf("%w: name must match %s", ErrInvalidRule, labelNamePattern)
+	}
+	if rule.Expr == "" {
+		return models.Rule{}, fmt.Errorf("%w: expr is required", ErrInvalidRule)
+	}
+
+	switch rule.Source {
+	case models.SourceCluster:
+		if err := evaluator.CheckLocal(rule.Expr); err != nil {
+			return models.Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	case models.SourcePrometheus:
+		if _, err := evaluator.Parse(rule.Expr); err != nil {
+			return models.Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	default:
+		return models.Rule{}, fmt.Errorf("%w: source must be %s or %s",
+			ErrInvalidRule, models.SourceCluster, models.SourcePrometheus)
+	}
+
+	forDuration := model.Duration(0)
+	if rule.For != "" {
+		var err error
+		forDuration, err = model.ParseDuration(rule.For)
+		if err != nil {
+			return models.Rule{}, fmt.Errorf("%w: for: %v", ErrInvalidRule, err)
+		}
+	}
+	rule.For = forDuration.String()
+
+	for name := range rule.Labels {
+		if !labelNamePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+			return models.Rule{}, fmt.Errorf("%w: invalid label name %q", ErrInvalidRule, name)
+		}
+		if name == models.AlertNameLabel {
+			return models.Rule{}, fmt.Errorf("%w: the %s label is set from the rule name", ErrInvalidRule, name)
+		}
+	}
+	if severity, ok := rule.Labels[models.SeverityLabel]; ok && models.Priority(severity) == "" {
+		return models.Rule{}, fmt.Errorf("%w: severity must be %s, %s or %s", ErrInvalidRule,
+			models.SeverityCritical, models.SeverityWarning, models.SeverityInfo)
+	}
+	for name, text := range rule.Annotations {
+		if _, err := parseTemplate(name, text); err != nil {
+			return models.Rule{}, fmt.Errorf("%w: annotation %s: %v", ErrInvalidRule, name, err)
+		}
+	}
+	return rule, nil
+}
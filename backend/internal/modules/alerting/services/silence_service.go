@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/alerting/repositories"
+)
+
+var ErrInvalidSilence = errors.New("invalid silence")
+
+// SilenceService manages silences.
+type SilenceService interface {
+	// ListSilences returns silences newest first, optionally only those in
+	// state (pending, active or expired).
+	ListSilences(state string) ([]models.Silence, error)
+	GetSilence(id string) (models.Silence, error)
+	CreateSilence(silence models.Silence) (models.Silence, error)
+	ExpireSilence(id string) (models.Silence, error)
+}
+
+type silenceService struct {
+	repo repositories.SilenceRepository
+}
+
+// NewSilenceService returns a SilenceService backed by repo.
+func NewSilenceService(repo repositories.SilenceRepository) SilenceService {
+	return &silenceService{repo: repo}
+}
+
+func (s *silenceService) ListSilences(state string) ([]models.Silence, error) {
+	switch state {
+	case "", models.SilenceStatePending, models.SilenceStateActive, models.SilenceStateExpired:
+	default:
+		return nil, fmt.Errorf("%w: unknown silence state %q", ErrInvalidFilter, state)
+	}
+
+	silences, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	filtered := make([]models.Silence, 0, len(silences))
+	for _, silence := range silences {
+		silence.State = silence.StateAt(now)
+		if state == "" || silence.State == state {
+			filtered = append(filtered, silence)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *silenceService) GetSilence(id string) (models.Silence, error) {
+	silence, err := s.repo.GetByID(id)
+	if err != nil {
+		return models.Silence{}, err
+	}
+	silence.State = silence.StateAt(time.Now())
+	return silence, nil
+}
+
+// CreateSilence stores a silence. StartsAt defaults to now.
+func (s *silenceService) CreateSilence(silence models.Silence) (models.Silence, error) {
+	now := time.Now()
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if err := validateSilence(silence, now); err != nil {
+		return models.Silence{}, err
+	}
+
+	created, err := s.repo.Create(silence)
+	if err != nil {
+		return models.Silence{}, err
+	}
+	created.State = created.StateAt(now)
+	return created, nil
+}
+
+func (s *silenceService) ExpireSilence(id string) (models.Silence, error) {
+	now := time.Now()
+	silence, err := s.repo.Expire(id, now)
+	if err != nil {
+		return models.Silence{}, err
+	}
+	silence.State = silence.StateAt(now)
+	return silence, nil
+}
+
+func validateSilence(silence models.Silence, now time.Time) error {
+	if strings.TrimSpace(silence.Comment) == "" {
+		return fmt.Errorf("%w: comment is required", ErrInvalidSilence)
+	}
+	if strings.TrimSpace(silence.CreatedBy) == "" {
+		return fmt.Errorf("%w: createdBy is required", ErrInvalidSilence)
+	}
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return fmt.Errorf("%w: endsAt must be after startsAt", ErrInvalidSilence)
+	}
+	if !silence.EndsAt.After(now) {
+		return fmt.Errorf("%w: endsAt must be in the future", ErrInvalidSilence)
+	}
+	if len(silence.Matchers) == 0 {
+		return fmt.Errorf("%w: at least one matcher is required", ErrInvalidSilence)
+	}
+
+	// As in Alertmanager, a silence must not match alerts that lack every
+	// one of its labels, or it would silence everything.
+	selective := false
+	for _, matcher := range silence.Matchers {
+		if !labelNamePattern.MatchString(matcher.Name) {
+			return fmt.Errorf("%w: invalid label name %q", ErrInvalidSilence, matcher.Name)
+		}
+		compiled, err := compileMatcher(matcher)
+		if err != nil {
+			return fmt.Errorf("%w: matcher %s: %v", ErrInvalidSilence, matcher.Name, err)
+		}
+		if !compiled.matches(map[string]string{}) {
+			selective = true
+		}
+	}
+	if !selective {
+		return fmt.Errorf("%w: at least one matcher must not match an empty value", ErrInvalidSilence)
+	}
+	return nil
+}
+
+type compiledMatcher struct {
+	models.Matcher
+	pattern *regexp.Regexp
+}
+
+func compileMatcher(matcher models.Matcher) (compiledMatcher, error) {
+	compiled := compiledMatcher{Matcher: matcher}
+	if matcher.IsRegex {
+		pattern, err := regexp.Compile("^(?:" + matcher.Value + ")$")
+		if err != nil {
+			return compiledMatcher{}, err
+		}
+		compiled.pattern = pattern
+	}
+	return compiled, nil
+}
+
+func (m compiledMatcher) matches(labels map[string]string) bool {
+	value := labels[m.Name]
+	var matched bool
+	if m.pattern != nil {
+		matched = m.pattern.MatchString(value)
+	} else {
+		matched = value == m.Value
+	}
+	return matched == m.IsEqual
+}
+
+// silences is the set of silences active at one moment, compiled for
+// matching.
+type silences []compiledSilence
+
+type compiledSilence struct {
+	id       string
+	matchers []compiledMatcher
+}
+
+// loadSilences compiles the silences active at now. A stored matcher that
+// no longer compiles is skipped rather than failing every read.
+func loadSilences(repo repositories.SilenceRepository, now time.Time) (silences, error) {
+	active, err := repo.ListActive(now)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := make(silences, 0, len(active))
+	for _, silence := range active {
+		entry := compiledSilence{id: silence.ID}
+		ok := true
+		for _, matcher := range silence.Matchers {
+			m, err := compileMatcher(matcher)
+			if err != nil {
+				ok = false
+				break
+			}
+			entry.matchers = append(entry.matchers, m)
+		}
+		if ok && len(entry.matchers) > 0 {
+			compiled = append(compiled, entry)
+		}
+	}
+	return compiled, nil
+}
+
+// match returns the IDs of the silences matching labels.
+func (s silences) match(labels map[string]string) []string {
+	var ids []string
+	for _, silence := range s {
+		matched := true
+		for _, matcher := range silence.matchers {
+			if !matcher.matches(labels) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			ids = append(ids, silence.id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// templatePrelude gives annotation templates the $labels and $value
+// variables used in Prometheus rule files.
+const templatePrelude = "{{$labels := .Labels}}{{$value := .Value}}"
+
+type templateData struct {
+	Labels map[string]string
+	Value  float64
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=zero").Parse(templatePrelude + text)
+}
+
+// expandAnnotations renders each annotation for an alert. An annotation
+// that fails to render is replaced by the error, so a bad template cannot
+// hide the alert.
+func expandAnnotations(annotations, labels map[string]string, value float64) map[string]string {
+	data := templateData{Labels: labels, Value: value}
+	expanded := make(map[string]string, len(annotations))
+	for name, text := range annotations {
+		tmpl, err := parseTemplate(name, text)
+		if err != nil {
+			expanded[name] = fmt.Sprintf("error expanding template: %v", err)
+			continue
+		}
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, data); err != nil {
+			expanded[name] = fmt.Sprintf("error expanding template: %v", err)
+			continue
+		}
+		expanded[name] = b.String()
+	}
+	return expanded
+}
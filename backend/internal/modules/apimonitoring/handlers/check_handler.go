@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/services"
+)
+
+type CheckHandler struct {
+	service services.CheckService
+}
+
+func NewCheckHandler(service services.CheckService) *CheckHandler {
+	return &CheckHandler{service: service}
+}
+
+type checkRequest struct {
+	Name            string                 `json:"name" binding:"required"`
+	URL             string                 `json:"url" binding:"required"`
+	Method          string                 `json:"method"`
+	Headers         map[string]string      `json:"headers"`
+	Body            string                 `json:"body"`
+	ExpectedStatus  int                    `json:"expectedStatus"`
+	Assertions      []models.BodyAssertion `json:"assertions"`
+	IntervalSeconds int                    `json:"intervalSeconds"`
+	TimeoutSeconds  int                    `json:"timeoutSeconds"`
+	Enabled         *bool                  `json:"enabled"`
+}
+
+// ListChecks returns every configured endpoint check.
+func (h *CheckHandler) ListChecks(c *gin.Context) {
+	checks, err := h.service.ListChecks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"checks": checks})
+}
+
+// GetCheck returns a single endpoint check.
+func (h *CheckHandler) GetCheck(c *gin.Context) {
+	check, err := h.service.GetCheck(c.Param("id"))
+	if err != nil {
+		writeCheckError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// CreateCheck adds a new endpoint check. Checks are enabled unless the
+// request says otherwise.
+func (h *CheckHandler) CreateCheck(c *gin.Context) {
+	var req checkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	check, err := h.service.CreateCheck(req.toModel(""))
+	if err != nil {
+		writeCheckError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, check)
+}
+
+// UpdateCheck replaces an endpoint check's definition.
+func (h *CheckHandler) UpdateCheck(c *gin.Context) {
+	var req checkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	check, err := h.service.UpdateCheck(req.toModel(c.Param("id")))
+	if err != nil {
+		writeCheckError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// DeleteCheck removes an endpoint check and its results.
+func (h *CheckHandler) DeleteCheck(c *gin.Context) {
+	if err := h.service.DeleteCheck(c.Param("id")); err != nil {
+		writeCheckError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// ListResults returns the most recent results for a check, newest first.
+func (h *CheckHandler) ListResults(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := h.service.ListResults(c.Param("id"), limit)
+	if err != nil {
+		writeCheckError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+func (r checkRequest) toModel(id string) models.EndpointCheck {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return models.EndpointCheck{
+		ID:              id,
+		Name:            r.Name,
+		URL:             r.URL,
+		Method:          r.Method,
+		Headers:         r.Headers,
+		Body:            r.Body,
+		ExpectedStatus:  r.ExpectedStatus,
+		Assertions:      r.Assertions,
+		IntervalSeconds: r.IntervalSeconds,
+		TimeoutSeconds:  r.TimeoutSeconds,
+		Enabled:         enabled,
+	}
+}
+
+func writeCheckError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrCheckNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidCheck):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
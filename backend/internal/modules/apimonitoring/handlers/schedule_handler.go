@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/services"
+)
+
+type ScheduleHandler struct {
+	service services.ScheduleService
+}
+
+func NewScheduleHandler(service services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+type scheduleRequest struct {
+	Name           string            `json:"name" binding:"required"`
+	URL            string            `json:"url" binding:"required"`
+	Method         string            `json:"method"`
+	Headers        map[string]string `json:"headers"`
+	Body           string            `json:"body"`
+	Schedule       string            `json:"schedule" binding:"required"`
+	TimeoutSeconds int               `json:"timeoutSeconds"`
+	Paused         bool              `json:"paused"`
+}
+
+// ListSchedules returns every scheduled API call.
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.service.ListSchedules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+// GetSchedule returns a single scheduled API call.
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	schedule, err := h.service.GetSchedule(c.Param("id"))
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateSchedule registers a new scheduled API call.
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req scheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.service.CreateSchedule(req.toModel(""))
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateSchedule replaces a scheduled API call's definition. Pausing is
+// done through PauseSchedule and ResumeSchedule, so paused is ignored here.
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	var req scheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.service.UpdateSchedule(req.toModel(c.Param("id")))
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule removes a scheduled API call and its run history.
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	if err := h.service.DeleteSchedule(c.Param("id")); err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// PauseSchedule stops a schedule from running until it is resumed.
+func (h *ScheduleHandler) PauseSchedule(c *gin.Context) {
+	schedule, err := h.service.PauseSchedule(c.Param("id"))
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// ResumeSchedule restarts a paused schedule from its next cron slot.
+func (h *ScheduleHandler) ResumeSchedule(c *gin.Context) {
+	schedule, err := h.service.ResumeSchedule(c.Param("id"))
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// TriggerRun runs a schedule immediately and returns the recorded run. It
+// works on paused schedules too.
+func (h *ScheduleHandler) TriggerRun(c *gin.Context) {
+	run, err := h.service.TriggerRun(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, run)
+}
+
+// ListRuns returns the most recent runs of a schedule, newest first.
+func (h *ScheduleHandler) ListRuns(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.service.ListRuns(c.Param("id"), limit)
+	if err != nil {
+		writeScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+func (r scheduleRequest) toModel(id string) models.ScheduledAPI {
+	return models.ScheduledAPI{
+		ID:             id,
+		Name:           r.Name,
+		URL:            r.URL,
+		Method:         r.Method,
+		Headers:        r.Headers,
+		Body:           r.Body,
+		Schedule:       r.Schedule,
+		TimeoutSeconds: r.TimeoutSeconds,
+		Paused:         r.Paused,
+	}
+}
+
+func writeScheduleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrScheduleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidSchedule):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
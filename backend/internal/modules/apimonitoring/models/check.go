@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Body assertion types.
+const (
+	AssertContains    = "contains"
+	AssertNotContains = "not_contains"
+	AssertRegex       = "regex"
+	AssertJSONEquals  = "json_equals"
+)
+
+// EndpointCheck is a synthetic HTTP probe run on a fixed interval.
+type EndpointCheck struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	URL             string            `json:"url"`
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body,omitempty"`
+	ExpectedStatus  int               `json:"expectedStatus"`
+	Assertions      []BodyAssertion   `json:"assertions"`
+	IntervalSeconds int               `json:"intervalSeconds"`
+	TimeoutSeconds  int               `json:"timeoutSeconds"`
+	Enabled         bool              `json:"enabled"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
+}
+
+// BodyAssertion checks the response body. Path is a dot-separated JSON path
+// and is only used by json_equals.
+type BodyAssertion struct {
+	Type  string `json:"type"`
+	Path  string `json:"path,omitempty"`
+	Value string `json:"value"`
+}
+
+// EndpointStats counts enabled checks and the probes run since a point in
+// time.
+type EndpointStats struct {
+	Total    int
+	Probes   int
+	UpProbes int
+}
+
+// CheckResult is the outcome of one probe.
+type CheckResult struct {
+	ID         int64     `json:"id"`
+	CheckID    string    `json:"checkId"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs"`
+	StatusCode int       `json:"statusCode"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+}
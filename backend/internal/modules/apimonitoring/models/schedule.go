@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Run triggers and outcomes.
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeError   = "error"
+)
+
+// ScheduledAPI is an HTTP call made on a cron schedule.
+type ScheduledAPI struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	URL            string            `json:"url"`
+	Method         string            `json:"method"`
+	Headers        map[string]string `json:"headers"`
+	Body           string            `json:"body,omitempty"`
+	Schedule       string            `json:"schedule"`
+	TimeoutSeconds int               `json:"timeoutSeconds"`
+	Paused         bool              `json:"paused"`
+	NextRunAt      *time.Time        `json:"nextRunAt,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+}
+
+// Run is one execution of a ScheduledAPI. Outcome is success for a 2xx
+// response, failure for any other response and error when no response was
+// received.
+type Run struct {
+	ID           int64     `json:"id"`
+	ScheduleID   string    `json:"scheduleId"`
+	Trigger      string    `json:"trigger"`
+	StartedAt    time.Time `json:"startedAt"`
+	FinishedAt   time.Time `json:"finishedAt"`
+	Outcome      string    `json:"outcome"`
+	StatusCode   int       `json:"statusCode"`
+	ResponseBody string    `json:"responseBody"`
+	Truncated    bool      `json:"truncated"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// RunStats counts active schedules and the runs started since a point in
+// time.
+type RunStats struct {
+	ScheduledAPIs int
+	Total         int
+	Succeeded     int
+	Scheduled     int
+}
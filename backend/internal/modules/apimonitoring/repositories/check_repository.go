@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+)
+
+var ErrCheckNotFound = errors.New("check not found")
+
+// CheckRepository persists endpoint checks and their results.
+type CheckRepository interface {
+	Create(check models.EndpointCheck) (models.EndpointCheck, error)
+	GetByID(id string) (models.EndpointCheck, error)
+	List() ([]models.EndpointCheck, error)
+	ListEnabled() ([]models.EndpointCheck, error)
+	Update(check models.EndpointCheck) (models.EndpointCheck, error)
+	Delete(id string) error
+
+	AddResult(result models.CheckResult) (models.CheckResult, error)
+	ListResults(checkID string, limit int) ([]models.CheckResult, error)
+	CountResultsSince(since time.Time) (total, succeeded int, err error)
+	DeleteResultsBefore(before time.Time) (int64, error)
+}
+
+type checkRepository struct {
+	db *sql.DB
+}
+
+func NewCheckRepository(db *sql.DB) CheckRepository {
+	return &checkRepository{db: db}
+}
+
+const checkColumns = `id, name, url, method, headers, body, expected_status, assertions,
+	interval_seconds, timeout_seconds, enabled, created_at, updated_at`
+
+func (r *checkRepository) Create(check models.EndpointCheck) (models.EndpointCheck, error) {
+	headers, assertions, err := marshalCheckJSON(check)
+	if err != nil {
+		return models.EndpointCheck{}, err
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO api_checks (name, url, method, headers, body, expected_status, assertions,
+		   interval_seconds, timeout_seconds, enabled)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 RETURNING `+checkColumns,
+		check.Name, check.URL, check.Method, headers, check.Body, check.ExpectedStatus, assertions,
+		check.IntervalSeconds, check.TimeoutSeconds, check.Enabled,
+	)
+	return scanCheck(row)
+}
+
+func (r *checkRepository) GetByID(id string) (models.EndpointCheck, error) {
+	row := r.db.QueryRow(`SELECT `+checkColumns+` FROM api_checks WHERE id = $1`, id)
+	return scanCheck(row)
+}
+
+func (r *checkRepository) List() ([]models.EndpointCheck, error) {
+	return r.query(`SELECT ` + checkColumns + ` FROM api_checks ORDER BY name`)
+}
+
+func (r *checkRepository) ListEnabled() ([]models.EndpointCheck, error) {
+	return r.query(`SELECT ` + checkColumns + ` FROM api_checks WHERE enabled ORDER BY name`)
+}
+
+func (r *checkRepository) query(query string, args ...interface{}) ([]models.EndpointCheck, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []models.EndpointCheck
+	for rows.Next() {
+		check, err := scanCheck(rows)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
+
+func (r *checkRepository) Update(check models.EndpointCheck) (models.EndpointCheck, error) {
+	headers, assertions, err := marshalCheckJSON(check)
+	if err != nil {
+		return models.EndpointCheck{}, err
+	}
+
+	row := r.db.QueryRow(
+		`UPDATE api_checks
+		 SET name = $2, url = $3, method = $4, headers = $5, body = $6, expected_status = $7,
+		     assertions = $8, interval_seconds = $9, timeout_seconds = $10, enabled = $11,
+		     updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+checkColumns,
+		check.ID, check.Name, check.URL, check.Method, headers, check.Body, check.ExpectedStatus,
+		assertions, check.IntervalSeconds, check.TimeoutSeconds, check.Enabled,
+	)
+	return scanCheck(row)
+}
+
+func (r *checkRepository) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM api_checks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCheckNotFound
+	}
+	return nil
+}
+
+func (r *checkRepository) AddResult(result models.CheckResult) (models.CheckResult, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO api_check_results (check_id, started_at, duration_ms, status_code, success, error)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id`,
+		result.CheckID, result.StartedAt, result.DurationMs, result.StatusCode, result.Success, result.Error,
+	).Scan(&result.ID)
+	return result, err
+}
+
+func (r *checkRepository) ListResults(checkID string, limit int) ([]models.CheckResult, error) {
+	rows, err := r.db.Query(
+		`SELECT id, check_id, started_at, duration_ms, status_code, success, error
+		 FROM api_check_results WHERE check_id = $1
+		 ORDER BY started_at DESC LIMIT $2`,
+		checkID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.CheckResult
+	for rows.Next() {
+		var result models.CheckResult
+		if err := rows.Scan(&result.ID, &result.CheckID, &result.StartedAt, &result.DurationMs,
+			&result.StatusCode, &result.Success, &result.Error); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+func (r *checkRepository) CountResultsSince(since time.Time) (int, int, error) {
+	var total, succeeded int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE success)
+		 FROM api_check_results WHERE started_at >= $1`,
+		since,
+	).Scan(&total, &succeeded)
+	return total, succeeded, err
+}
+
+func (r *checkRepository) DeleteResultsBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM api_check_results WHERE started_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func marshalCheckJSON(check models.EndpointCheck) ([]byte, []byte, error) {
+	headers, err := json.Marshal(check.Headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	assertions, err := json.Marshal(check.Assertions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return headers, assertions, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCheck(row rowScanner) (models.EndpointCheck, error) {
+	var check models.EndpointCheck
+	var headers, assertions []byte
+	if err := row.Scan(
+		&check.ID, &check.Name, &check.URL, &check.Method, &headers, &check.Body,
+		&check.ExpectedStatus, &assertions, &check.IntervalSeconds, &check.TimeoutSeconds,
+		&check.Enabled, &check.CreatedAt, &check.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EndpointCheck{}, ErrCheckNotFound
+		}
+		return models.EndpointCheck{}, err
+	}
+	if err := json.Unmarshal(headers, &check.Headers); err != nil {
+		return models.EndpointCheck{}, err
+	}
+	if err := json.Unmarshal(assertions, &check.Assertions); err != nil {
+		return models.EndpointCheck{}, err
+	}
+	return check, nil
+}
@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+)
+
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// RunCounts aggregates runs started within a window.
+type RunCounts struct {
+	Total     int
+	Succeeded int
+	Scheduled int
+}
+
+// ScheduleRepository persists scheduled API calls and their run history.
+type ScheduleRepository interface {
+	Create(schedule models.ScheduledAPI) (models.ScheduledAPI, error)
+	GetByID(id string) (models.ScheduledAPI, error)
+	List() ([]models.ScheduledAPI, error)
+	ListDue(now time.Time) ([]models.ScheduledAPI, error)
+	CountActive() (int, error)
+	Update(schedule models.ScheduledAPI) (models.ScheduledAPI, error)
+	SetPaused(id string, paused bool, nextRunAt *time.Time) (models.ScheduledAPI, error)
+	// ClaimRun moves next_run_at from due to next, reporting false if
+	// another scheduler already moved it.
+	ClaimRun(id string, due, next time.Time) (bool, error)
+	Delete(id string) error
+
+	AddRun(run models.Run) (models.Run, error)
+	ListRuns(scheduleID string, limit int) ([]models.Run, error)
+	CountRunsSince(since time.Time) (RunCounts, error)
+	DeleteRunsBefore(before time.Time) (int64, error)
+}
+
+type scheduleRepository struct {
+	db *sql.DB
+}
+
+func NewScheduleRepository(db *sql.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+const scheduleColumns = `id, name, url, method, headers, body, schedule, timeout_seconds,
+	paused, next_run_at, created_at, updated_at`
+
+func (r *scheduleRepository) Create(schedule models.ScheduledAPI) (models.ScheduledAPI, error) {
+	headers, err := json.Marshal(schedule.Headers)
+	if err != nil {
+		return models.ScheduledAPI{}, err
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO api_schedules (name, url, method, headers, body, schedule, timeout_seconds,
+		   paused, next_run_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING `+scheduleColumns,
+		schedule.Name, schedule.URL, schedule.Method, headers, schedule.Body, schedule.Schedule,
+		schedule.TimeoutSeconds, schedule.Paused, schedule.NextRunAt,
+	)
+	return scanSchedule(row)
+}
+
+func (r *scheduleRepository) GetByID(id string) (models.ScheduledAPI, error) {
+	row := r.db.QueryRow(`SELECT `+scheduleColumns+` FROM api_schedules WHERE id = $1`, id)
+	return scanSchedule(row)
+}
+
+func (r *scheduleRepository) List() ([]models.ScheduledAPI, error) {
+	return r.query(`SELECT ` + scheduleColumns + ` FROM api_schedules ORDER BY name`)
+}
+
+func (r *scheduleRepository) ListDue(now time.Time) ([]models.ScheduledAPI, error) {
+	return r.query(
+		`SELECT `+scheduleColumns+` FROM api_schedules
+		 WHERE NOT paused AND next_run_at IS NOT NULL AND next_run_at <= $1
+		 ORDER BY next_run_at`,
+		now,
+	)
+}
+
+func (r *scheduleRepository) query(query string, args ...interface{}) ([]models.ScheduledAPI, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schedules []models.ScheduledAPI
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+func (r *scheduleRepository) CountActive() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM api_schedules WHERE NOT paused`).Scan(&count)
+	return count, err
+}
+
+func (r *scheduleRepository) Update(schedule models.ScheduledAPI) (models.ScheduledAPI, error) {
+	headers, err := json.Marshal(schedule.Headers)
+	if err != nil {
+		return models.ScheduledAPI{}, err
+	}
+
+	row := r.db.QueryRow(
+		`UPDATE api_schedules
+		 SET name = $2, url = $3, method = $4, headers = $5, body = $6, schedule = $7,
+		     timeout_seconds = $8, next_run_at = CASE WHEN paused THEN NULL ELSE $9::timestamptz END,
+		     updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+scheduleColumns,
+		schedule.ID, schedule.Name, schedule.URL, schedule.Method, headers, schedule.Body,
+		schedule.Schedule, schedule.TimeoutSeconds, schedule.NextRunAt,
+	)
+	return scanSchedule(row)
+}
+
+func (r *scheduleRepository) SetPaused(id string, paused bool, nextRunAt *time.Time) (models.ScheduledAPI, error) {
+	row := r.db.QueryRow(
+		`UPDATE api_schedules SET paused = $2, next_run_at = $3, updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+scheduleColumns,
+		id, paused, nextRunAt,
+	)
+	return scanSchedule(row)
+}
+
+func (r *scheduleRepository) ClaimRun(id string, due, next time.Time) (bool, error) {
+	res, err := r.db.Exec(
+		`UPDATE api_schedules SET next_run_at = $3
+		 WHERE id = $1 AND next_run_at = $2 AND NOT paused`,
+		id, due, next,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *scheduleRepository) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM api_schedules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *scheduleRepository) AddRun(run models.Run) (models.Run, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO api_schedule_runs (schedule_id, trigger, started_at, finished_at, outcome,
+		   status_code, response_body, truncated, error)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING id`,
+		run.ScheduleID, run.Trigger, run.StartedAt, run.FinishedAt, run.Outcome, run.StatusCode,
+		run.ResponseBody, run.Truncated, run.Error,
+	).Scan(&run.ID)
+	return run, err
+}
+
+func (r *scheduleRepository) ListRuns(scheduleID string, limit int) ([]models.Run, error) {
+	rows, err := r.db.Query(
+		`SELECT id, schedule_id, trigger, started_at, finished_at, outcome, status_code,
+		   response_body, truncated, error
+		 FROM api_schedule_runs WHERE schedule_id = $1
+		 ORDER BY started_at DESC LIMIT $2`,
+		scheduleID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.Run
+	for rows.Next() {
+		var run models.Run
+		if err := rows.Scan(&run.ID, &run.ScheduleID, &run.Trigger, &run.StartedAt, &run.FinishedAt,
+			&run.Outcome, &run.StatusCode, &run.ResponseBody, &run.Truncated, &run.Error); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *scheduleRepository) CountRunsSince(since time.Time) (RunCounts, error) {
+	var counts RunCounts
+	err := r.db.QueryRow(
+		`SELECT COUNT(*),
+		        COUNT(*) FILTER (WHERE outcome = 'success'),
+		        COUNT(*) FILTER (WHERE trigger = 'schedule')
+		 FROM api_schedule_runs WHERE started_at >= $1`,
+		since,
+	).Scan(&counts.Total, &counts.Succeeded, &counts.Scheduled)
+	return counts, err
+}
+
+func (r *scheduleRepository) DeleteRunsBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM api_schedule_runs WHERE started_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func scanSchedule(row rowScanner) (models.ScheduledAPI, error) {
+	var schedule models.ScheduledAPI
+	var headers []byte
+	var nextRunAt sql.NullTime
+	if err := row.Scan(
+		&schedule.ID, &schedule.Name, &schedule.URL, &schedule.Method, &headers, &schedule.Body,
+		&schedule.Schedule, &schedule.TimeoutSeconds, &schedule.Paused, &nextRunAt,
+		&schedule.CreatedAt, &schedule.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ScheduledAPI{}, ErrScheduleNotFound
+		}
+		return models.ScheduledAPI{}, err
+	}
+	if nextRunAt.Valid {
+		schedule.NextRunAt = &nextRunAt.Time
+	}
+	if err := json.Unmarshal(headers, &schedule.Headers); err != nil {
+		return models.ScheduledAPI{}, err
+	}
+	return schedule, nil
+}
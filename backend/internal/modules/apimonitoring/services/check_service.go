@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+)
+
+var ErrInvalidCheck = errors.New("invalid check")
+
+const (
+	DefaultIntervalSeconds = 60
+	DefaultTimeoutSeconds  = 10
+	MinIntervalSeconds     = 10
+
+	DefaultResultLimit = 100
+	MaxResultLimit     = 1000
+)
+
+var allowedMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// CheckService manages synthetic endpoint checks and their results.
+type CheckService interface {
+	ListChecks() ([]models.EndpointCheck, error)
+	GetCheck(id string) (models.EndpointCheck, error)
+	CreateCheck(check models.EndpointCheck) (models.EndpointCheck, error)
+	UpdateCheck(check models.EndpointCheck) (models.EndpointCheck, error)
+	DeleteCheck(id string) error
+	ListResults(checkID string, limit int) ([]models.CheckResult, error)
+
+	// EndpointStats feeds the dashboard summary.
+	EndpointStats(ctx context.Context, since time.Time) (models.EndpointStats, error)
+}
+
+type checkService struct {
+	repo repositories.CheckRepository
+}
+
+// NewCheckService returns a CheckService backed by repo.
+func NewCheckService(repo repositories.CheckRepository) CheckService {
+	return &checkService{repo: repo}
+}
+
+func (s *checkService) ListChecks() ([]models.EndpointCheck, error) {
+	checks, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if checks == nil {
+		checks = []models.EndpointCheck{}
+	}
+	return checks, nil
+}
+
+func (s *checkService) GetCheck(id string) (models.EndpointCheck, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *checkService) CreateCheck(check models.EndpointCheck) (models.EndpointCheck, error) {
+	check = normalizeCheck(check)
+	if err := validateCheck(check); err != nil {
+		return models.EndpointCheck{}, err
+	}
+	return s.repo.Create(check)
+}
+
+func (s *checkService) UpdateCheck(check models.EndpointCheck) (models.EndpointCheck, error) {
+	check = normalizeCheck(check)
+	if err := validateCheck(check); err != nil {
+		return models.EndpointCheck{}, err
+	}
+	return s.repo.Update(check)
+}
+
+func (s *checkService) DeleteCheck(id string) error {
+	return s.repo.Delete(id)
+}
+
+func (s *checkService) ListResults(checkID string, limit int) ([]models.CheckResult, error) {
+	if _, err := s.repo.GetByID(checkID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultResultLimit
+	}
+	if limit > MaxResultLimit {
+		limit = MaxResultLimit
+	}
+
+	results, err := s.repo.ListResults(checkID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []models.CheckResult{}
+	}
+	return results, nil
+}
+
+func (s *checkService) EndpointStats(ctx context.Context, since time.Time) (models.EndpointStats, error) {
+	checks, err := s.repo.ListEnabled()
+	if err != nil {
+		return models.EndpointStats{}, err
+	}
+	total, succeeded, err := s.repo.CountResultsSince(since)
+	if err != nil {
+		return models.EndpointStats{}, err
+	}
+	return models.EndpointStats{
+		Total:    len(checks),
+		Probes:   total,
+		UpProbes: succeeded,
+	}, nil
+}
+
+func normalizeCheck(check models.EndpointCheck) models.EndpointCheck {
+	check.Name = strings.TrimSpace(check.Name)
+	check.Method = strings.ToUpper(strings.TrimSpace(check.Method))
+	if check.Method == "" {
+		check.Method = http.MethodGet
+	}
+	if check.ExpectedStatus == 0 {
+		check.ExpectedStatus = http.StatusOK
+	}
+	if check.IntervalSeconds == 0 {
+		check.IntervalSeconds = DefaultIntervalSeconds
+	}
+	if check.TimeoutSeconds == 0 {
+		check.TimeoutSeconds = DefaultTimeoutSeconds
+	}
+	if check.Headers == nil {
+		check.Headers = map[string]string{}
+	}
+	if check.Assertions == nil {
+		check.Assertions = []models.BodyAssertion{}
+	}
+	return check
+}
+
+func validateCheck(check models.EndpointCheck) error {
+	if check.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCheck)
+	}
+	u, err := url.Parse(check.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an http(s) URL", ErrInvalidCheck)
+	}
+	if !allowedMethods[check.Method] {
+		return fmt.Errorf("%w: unsupported method %q", ErrInvalidCheck, check.Method)
+	}
+	if check.ExpectedStatus < 100 || check.ExpectedStatus > 599 {
+		return fmt.Errorf("%w: expectedStatus must be between 100 and 599", ErrInvalidCheck)
+	}
+	if check.IntervalSeconds < MinIntervalSeconds {
+		return fmt.Errorf("%w: intervalSeconds must be at least %d", ErrInvalidCheck, MinIntervalSeconds)
+	}
+	if check.TimeoutSeconds < 1 || check.TimeoutSeconds > check.IntervalSeconds {
+		return fmt.Errorf("%w: timeoutSeconds must be between 1 and intervalSeconds", ErrInvalidCheck)
+	}
+	for _, assertion := range check.Assertions {
+		switch assertion.Type {
+		case models.AssertContains, models.AssertNotContains:
+		case models.AssertRegex:
+			if _, err := regexp.Compile(assertion.Value); err != nil {
+				return fmt.Errorf("%w: invalid regex %q", ErrInvalidCheck, assertion.Value)
+			}
+		case models.AssertJSONEquals:
+			if assertion.Path == "" {
+				return fmt.Errorf("%w: json_equals assertions need a path", ErrInvalidCheck)
+			}
+		default:
+			return fmt.Errorf("%w: unknown assertion type %q", ErrInvalidCheck, assertion.Type)
+		}
+	}
+	return nil
+}
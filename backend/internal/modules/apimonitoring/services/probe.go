@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+)
+
+// maxBodyBytes caps how much of a response body is read for assertions.
+const maxBodyBytes = 1 << 20
+
+// Probe runs check once and reports the outcome. Transport and assertion
+// failures are recorded on the result rather than returned.
+func Probe(ctx context.Context, client *http.Client, check models.EndpointCheck) models.CheckResult {
+	result := models.CheckResult{CheckID: check.ID, StartedAt: time.Now().UTC()}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(check.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	var body io.Reader
+	if check.Body != "" {
+		body = strings.NewReader(check.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, check.Method, check.URL, body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for name, value := range check.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.DurationMs = time.Since(result.StartedAt).Milliseconds()
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	result.DurationMs = time.Since(result.StartedAt).Milliseconds()
+	result.StatusCode = resp.StatusCode
+	if err != nil {
+		result.Error = fmt.Sprintf("read body: %v", err)
+		return result
+	}
+
+	if resp.StatusCode != check.ExpectedStatus {
+		result.Error = fmt.Sprintf("expected status %d, got %d", check.ExpectedStatus, resp.StatusCode)
+		return result
+	}
+	for _, assertion := range check.Assertions {
+		if err := evaluateAssertion(assertion, respBody); err != nil {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	result.Success = true
+	return result
+}
+
+func evaluateAssertion(assertion models.BodyAssertion, body []byte) error {
+	switch assertion.Type {
+	case models.AssertContains:
+		if !bytes.Contains(body, []byte(assertion.Value)) {
+			return fmt.Errorf("body does not contain %q", assertion.Value)
+		}
+	case models.AssertNotContains:
+		if bytes.Contains(body, []byte(assertion.Value)) {
+			return fmt.Errorf("body contains %q", assertion.Value)
+		}
+	case models.AssertRegex:
+		re, err := regexp.Compile(assertion.Value)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %v", assertion.Value, err)
+		}
+		if !re.Match(body) {
+			return fmt.Errorf("body does not match %q", assertion.Value)
+		}
+	case models.AssertJSONEquals:
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Errorf("body is not JSON: %v", err)
+		}
+		got, ok := lookupJSONPath(doc, assertion.Path)
+		if !ok {
+			return fmt.Errorf("json path %q not found", assertion.Path)
+		}
+		if got != assertion.Value {
+			return fmt.Errorf("json path %q is %q, want %q", assertion.Path, got, assertion.Value)
+		}
+	default:
+		return fmt.Errorf("unknown assertion type %q", assertion.Type)
+	}
+	return nil
+}
+
+// lookupJSONPath resolves a dot-separated path such as "data.items.0.status"
+// and renders the value as a string for comparison.
+func lookupJSONPath(doc interface{}, path string) (string, bool) {
+	current := doc
+	if path != "" {
+		for _, segment := range strings.Split(path, ".") {
+			switch node := current.(type) {
+			case map[string]interface{}:
+				next, ok := node[segment]
+				if !ok {
+					return "", false
+				}
+				current = next
+			case []interface{}:
+				index, err := strconv.Atoi(segment)
+				if err != nil || index < 0 || index >= len(node) {
+					return "", false
+				}
+				current = node[index]
+			default:
+				return "", false
+			}
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, true
+	case nil:
+		return "null", true
+	case float64, bool:
+		return fmt.Sprint(value), true
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
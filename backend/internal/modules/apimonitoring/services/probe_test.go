@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+)
+
+func newEndpoint(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"status":"ok","version":2,"ready":true,"items":[{"name":"db","up":null}]}`)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, r.Method+" "+r.Header.Get("X-Probe")+" "+string(body))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/health", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProbe(t *testing.T) {
+	server := newEndpoint(t)
+	check := func(path string, assertions ...models.BodyAssertion) models.EndpointCheck {
+		return normalizeCheck(models.EndpointCheck{ID: "c1", Name: "probe", URL: server.URL + path, Assertions: assertions})
+	}
+
+	tests := []struct {
+		name       string
+		check      models.EndpointCheck
+		wantStatus int
+		wantErr    string
+	}{
+		{name: "up", check: check("/health"), wantStatus: 200},
+		{name: "redirect followed", check: check("/moved"), wantStatus: 200},
+		{name: "unexpected status", check: check("/missing"), wantStatus: 404, wantErr: "expected status 200, got 404"},
+		{name: "expected 404", check: func() models.EndpointCheck { c := check("/missing"); c.ExpectedStatus = 404; return c }(), wantStatus: 404},
+		{name: "contains", check: check("/health", models.BodyAssertion{Type: models.AssertContains, Value: `"ok"`}), wantStatus: 200},
+		{name: "contains fails", check: check("/health", models.BodyAssertion{Type: models.AssertContains, Value: "degraded"}), wantStatus: 200, wantErr: `body does not contain "degraded"`},
+		{name: "not contains fails", check: check("/health", models.BodyAssertion{Type: models.AssertNotContains, Value: "ok"}), wantStatus: 200, wantErr: `body contains "ok"`},
+		{name: "regex", check: check("/health", models.BodyAssertion{Type: models.AssertRegex, Value: `"version":\d+`}), wantStatus: 200},
+		{name: "regex fails", check: check("/health", models.BodyAssertion{Type: models.AssertRegex, Value: `^<html`}), wantStatus: 200, wantErr: "body does not match"},
+		{name: "json string", check: check("/health", models.BodyAssertion{Type: models.AssertJSONEquals, Path: "status", Value: "ok"}), wantStatus: 200},
+		{name: "json number", check: check("/health", models.BodyAssertion{Type: models.AssertJSONEquals, Path: "version", Value: "2"}), wantStatus: 200},
+		{name: "json bool", check: check("/health", models.BodyAssertion{Type: models.AssertJSONEquals, Path: "ready", Value: "true"}), wantStatus: 200},
+		{name: "json array index", check: check("/health", models.BodyAssertion{Type: models.AssertJSONEquals, Path: "items.0.up", Value: "null"}), wantStatus: 200},
+		{name: "json mismatch", check: check("/health", models.BodyAssertion{Type: models.AssertJSONEquals, Path: "status", Value: "down"}), wantStatus: 200, wantErr: `json path "status" is "ok", want "down"`},
+		{name: "json path missing", check: check("/health", models.BodyAssertion{Type: models.AssertJSONEquals, Path: "items.3.name", Value: "db"}), wantStatus: 200, wantErr: `json path "items.3.name" not found`},
+		{name: "json on non-json body", check: check("/missing", models.BodyAssertion{Type: models.AssertJSONEquals, Path: "a", Value: "b"}), wantStatus: 404, wantErr: "expected status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Probe(context.Background(), server.Client(), tt.check)
+			if result.CheckID != "c1" || result.StartedAt.IsZero() {
+				t.Errorf("result = %+v, want check id and start time", result)
+			}
+			if result.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", result.StatusCode, tt.wantStatus)
+			}
+			if result.Success != (tt.wantErr == "") {
+				t.Errorf("Success = %v, error %q", result.Success, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantErr) {
+				t.Errorf("Error = %q, want %q", result.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProbeSendsRequest(t *testing.T) {
+	server := newEndpoint(t)
+	check := normalizeCheck(models.EndpointCheck{
+		ID:         "c1",
+		Name:       "echo",
+		URL:        server.URL + "/echo",
+		Method:     http.MethodPost,
+		Headers:    map[string]string{"X-Probe": "synthetic"},
+		Body:       `{"ping":true}`,
+		Assertions: []models.BodyAssertion{{Type: models.AssertContains, Value: `POST synthetic {"ping":true}`}},
+	})
+	if result := Probe(context.Background(), server.Client(), check); !result.Success {
+		t.Fatalf("probe failed: %s", result.Error)
+	}
+}
+
+func TestProbeTransportFailures(t *testing.T) {
+	blocked := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-blocked:
+		}
+	}))
+	defer slow.Close()
+	defer close(blocked)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	t.Run("timeout", func(t *testing.T) {
+		check := normalizeCheck(models.EndpointCheck{ID: "c1", Name: "slow", URL: slow.URL, TimeoutSeconds: 1})
+		start := time.Now()
+		result := Probe(context.Background(), slow.Client(), check)
+		if result.Success || result.StatusCode != 0 || !strings.Contains(result.Error, "deadline exceeded") {
+			t.Errorf("result = %+v, want a deadline error", result)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("probe took %s despite a 1s timeout", elapsed)
+		}
+		if result.DurationMs < 900 {
+			t.Errorf("DurationMs = %d, want about the timeout", result.DurationMs)
+		}
+	})
+	t.Run("connection refused", func(t *testing.T) {
+		check := normalizeCheck(models.EndpointCheck{ID: "c1", Name: "down", URL: closedURL})
+		result := Probe(context.Background(), http.DefaultClient, check)
+		if result.Success || result.Error == "" {
+			t.Errorf("result = %+v, want a transport error", result)
+		}
+	})
+}
+
+func TestValidateCheck(t *testing.T) {
+	valid := models.EndpointCheck{Name: "api", URL: "https://api.example.com/health"}
+	tests := []struct {
+		name    string
+		change  func(*models.EndpointCheck)
+		wantErr string
+	}{
+		{name: "defaults", change: func(*models.EndpointCheck) {}},
+		{name: "no name", change: func(c *models.EndpointCheck) { c.Name = "  " }, wantErr: "name is required"},
+		{name: "ftp url", change: func(c *models.EndpointCheck) { c.URL = "ftp://example.com" }, wantErr: "url must be"},
+		{name: "relative url", change: func(c *models.EndpointCheck) { c.URL = "/health" }, wantErr: "url must be"},
+		{name: "method", change: func(c *models.EndpointCheck) { c.Method = "TRACE" }, wantErr: "unsupported method"},
+		{name: "status", change: func(c *models.EndpointCheck) { c.ExpectedStatus = 600 }, wantErr: "expectedStatus"},
+		{name: "interval", change: func(c *models.EndpointCheck) { c.IntervalSeconds = 5 }, wantErr: "intervalSeconds"},
+		{name: "timeout above interval", change: func(c *models.EndpointCheck) { c.IntervalSeconds = 10; c.TimeoutSeconds = 11 }, wantErr: "timeoutSeconds"},
+		{name: "bad regex", change: func(c *models.EndpointCheck) {
+			c.Assertions = []models.BodyAssertion{{Type: models.AssertRegex, Value: "("}}
+		}, wantErr: "invalid regex"},
+		{name: "json without path", change: func(c *models.EndpointCheck) {
+			c.Assertions = []models.BodyAssertion{{Type: models.AssertJSONEquals, Value: "ok"}}
+		}, wantErr: "need a path"},
+		{name: "unknown assertion", change: func(c *models.EndpointCheck) {
+			c.Assertions = []models.BodyAssertion{{Type: "equals", Value: "ok"}}
+		}, wantErr: "unknown assertion type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := valid
+			tt.change(&check)
+			err := validateCheck(normalizeCheck(check))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateCheck: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateCheck error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
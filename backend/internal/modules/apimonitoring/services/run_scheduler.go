@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+)
+
+// RunScheduler executes scheduled API calls when their cron slot comes due
+// and records each run.
+type RunScheduler struct {
+	repo   repositories.ScheduleRepository
+	opts   SchedulerOptions
+	logger *zap.Logger
+}
+
+// NewRunScheduler returns a RunScheduler over the schedules in repo. Runs
+// use opts.Client, or a NewRunClient when nil.
+func NewRunScheduler(repo repositories.ScheduleRepository, opts SchedulerOptions, logger *zap.Logger) *RunScheduler {
+	if opts.Client == nil {
+		opts.Client = NewRunClient()
+	}
+	return &RunScheduler{repo: repo, opts: opts.withDefaults(), logger: logger}
+}
+
+// Run starts due schedules every tick until ctx is cancelled, then waits for
+// in-flight runs to finish. A schedule that fell behind, for example while
+// no replica held the lock, runs once and then resumes from its next slot.
+// Slots claimed but not started when ctx is cancelled are handed back, so
+// they run after a restart instead of being lost.
+func (s *RunScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.Tick)
+	defer ticker.Stop()
+	defer s.opts.release()
+
+	sem := make(chan struct{}, s.opts.Workers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lastPrune := time.Time{}
+	for {
+		now := time.Now()
+		if s.opts.isLeader(ctx, s.logger) {
+			claims := s.claimDue(now)
+			for i, claim := range claims {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					s.unclaim(claims[i:])
+					return
+				}
+				wg.Add(1)
+				go func(claim runClaim) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					s.execute(ctx, claim)
+				}(claim)
+			}
+
+			if s.opts.Retention > 0 && now.Sub(lastPrune) >= time.Hour {
+				lastPrune = now
+				s.prune(now)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runClaim is a schedule whose next_run_at was moved from due to next.
+type runClaim struct {
+	schedule  models.ScheduledAPI
+	due, next time.Time
+}
+
+// claimDue advances next_run_at on every due schedule before running it, so
+// a run is never started twice for the same slot.
+func (s *RunScheduler) claimDue(now time.Time) []runClaim {
+	due, err := s.repo.ListDue(now)
+	if err != nil {
+		s.logger.Warn("failed to load due schedules", zap.Error(err))
+		return nil
+	}
+
+	var claimed []runClaim
+	for _, schedule := range due {
+		sched, err := parseSchedule(schedule.Schedule)
+		if err != nil {
+			s.logger.Warn("skipping schedule with invalid cron expression",
+				zap.String("schedule", schedule.ID), zap.Error(err))
+			continue
+		}
+		next := sched.Next(now)
+		if next.IsZero() {
+			// Never advance to the zero time: the slot would stay due forever.
+			s.logger.Warn("skipping schedule with no next run", zap.String("schedule", schedule.ID))
+			continue
+		}
+		ok, err := s.repo.ClaimRun(schedule.ID, *schedule.NextRunAt, next)
+		if err != nil {
+			s.logger.Warn("failed to claim scheduled run", zap.String("schedule", schedule.ID), zap.Error(err))
+			continue
+		}
+		if ok {
+			claimed = append(claimed, runClaim{schedule: schedule, due: *schedule.NextRunAt, next: next})
+		}
+	}
+	return claimed
+}
+
+// unclaim moves next_run_at back to the due slot of runs that never started.
+// A schedule edited or paused in the meantime keeps its new slot.
+func (s *RunScheduler) unclaim(claims []runClaim) {
+	for _, claim := range claims {
+		if _, err := s.repo.ClaimRun(claim.schedule.ID, claim.next, claim.due); err != nil {
+			s.logger.Warn("failed to release scheduled run", zap.String("schedule", claim.schedule.ID), zap.Error(err))
+		}
+	}
+}
+
+func (s *RunScheduler) execute(ctx context.Context, claim runClaim) {
+	if ctx.Err() != nil {
+		s.unclaim([]runClaim{claim})
+		return
+	}
+	run := executeRun(ctx, s.opts.Client, claim.schedule, models.TriggerSchedule)
+	if ctx.Err() != nil && run.Outcome == models.OutcomeError {
+		// The call may or may not have reached the API, so it is recorded
+		// rather than retried.
+		run.Error = "interrupted by shutdown: " + run.Error
+	}
+	if _, err := s.repo.AddRun(run); err != nil {
+		s.logger.Warn("failed to store scheduled run", zap.String("schedule", claim.schedule.ID), zap.Error(err))
+	}
+}
+
+func (s *RunScheduler) prune(now time.Time) {
+	removed, err := s.repo.DeleteRunsBefore(now.Add(-s.opts.Retention))
+	if err != nil {
+		s.logger.Warn("failed to prune scheduled runs", zap.Error(err))
+		return
+	}
+	if removed > 0 {
+		s.logger.Info("pruned scheduled runs", zap.Int64("removed", removed))
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+)
+
+func dueSchedule(id, url, expr string, due time.Time) models.ScheduledAPI {
+	schedule := normalizeSchedule(models.ScheduledAPI{ID: id, Name: id, URL: url, Schedule: expr})
+	schedule.NextRunAt = &due
+	return schedule
+}
+
+func TestClaimDue(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 30, 0, time.Local)
+	due := now.Add(-time.Minute)
+	repo := newMemSchedules(
+		dueSchedule("hourly", "https://example.com", "@hourly", due),
+		// Stored before never-firing expressions were rejected.
+		dueSchedule("never", "https://example.com", "0 0 30 2 *", due),
+		dueSchedule("broken", "https://example.com", "not cron", due),
+		dueSchedule("later", "https://example.com", "@hourly", now.Add(time.Minute)),
+	)
+	scheduler := NewRunScheduler(repo, SchedulerOptions{}, zap.NewNop())
+
+	claims := scheduler.claimDue(now)
+	if len(claims) != 1 || claims[0].schedule.ID != "hourly" {
+		t.Fatalf("claimed %+v, want hourly only", claims)
+	}
+	if want := time.Date(2026, 10, 17, 13, 0, 0, 0, time.Local); !claims[0].next.Equal(want) || !repo.get("hourly").NextRunAt.Equal(want) {
+		t.Errorf("next run = %v, want %v", repo.get("hourly").NextRunAt, want)
+	}
+	for _, id := range []string{"never", "broken"} {
+		if next := repo.get(id).NextRunAt; next == nil || !next.Equal(due) {
+			t.Errorf("%s next run moved to %v", id, next)
+		}
+	}
+
+	// The slot is taken, so a second scheduler cannot claim it again.
+	if again := scheduler.claimDue(now); len(again) != 0 {
+		t.Errorf("slot claimed twice: %+v", again)
+	}
+
+	// Handing the claim back makes the slot due again.
+	scheduler.unclaim(claims)
+	if next := repo.get("hourly").NextRunAt; !next.Equal(due) {
+		t.Errorf("after unclaim next run = %v, want %v", next, due)
+	}
+}
+
+func TestRunSchedulerRecordsRuns(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer api.Close()
+
+	repo := newMemSchedules(dueSchedule("s1", api.URL, "@hourly", time.Now().Add(-time.Second)))
+	scheduler := NewRunScheduler(repo, SchedulerOptions{Tick: 10 * time.Millisecond, Client: api.Client()}, zap.NewNop())
+	if scheduler.opts.Client != api.Client() {
+		t.Fatal("scheduler does not use the configured client")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if runs, _ := repo.ListRuns("s1", 10); len(runs) > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	runs, _ := repo.ListRuns("s1", 10)
+	if len(runs) != 1 || runs[0].Trigger != models.TriggerSchedule || runs[0].Outcome != models.OutcomeSuccess {
+		t.Fatalf("runs = %+v, want one successful scheduled run", runs)
+	}
+	if next := repo.get("s1").NextRunAt; !next.After(time.Now()) {
+		t.Errorf("next run %v not advanced", next)
+	}
+}
+
+func TestRunSchedulerShutdown(t *testing.T) {
+	t.Run("claimed but not started", func(t *testing.T) {
+		due := time.Now().Add(-time.Second)
+		repo := newMemSchedules(dueSchedule("s1", "http://127.0.0.1:1", "@hourly", due))
+		scheduler := NewRunScheduler(repo, SchedulerOptions{}, zap.NewNop())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		scheduler.Run(ctx)
+
+		// One update claims the slot and a second hands it back.
+		if repo.claims != 2 {
+			t.Fatalf("made %d claim updates, want 2", repo.claims)
+		}
+		if next := repo.get("s1").NextRunAt; !next.Equal(due) {
+			t.Errorf("next run = %v, want the unstarted slot %v back", next, due)
+		}
+		if runs, _ := repo.ListRuns("s1", 10); len(runs) != 0 {
+			t.Errorf("recorded %+v for a run that never started", runs)
+		}
+	})
+
+	t.Run("interrupted mid-call", func(t *testing.T) {
+		received := make(chan struct{})
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(received)
+			<-r.Context().Done()
+		}))
+		defer api.Close()
+
+		repo := newMemSchedules(dueSchedule("s1", api.URL, "@hourly", time.Now().Add(-time.Second)))
+		scheduler := NewRunScheduler(repo, SchedulerOptions{Client: api.Client()}, zap.NewNop())
+		claims := scheduler.claimDue(time.Now())
+		if len(claims) != 1 {
+			t.Fatalf("claimed %d schedules, want 1", len(claims))
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-received
+			cancel()
+		}()
+		scheduler.execute(ctx, claims[0])
+
+		runs, _ := repo.ListRuns("s1", 10)
+		if len(runs) != 1 || runs[0].Outcome != models.OutcomeError || !strings.HasPrefix(runs[0].Error, "interrupted by shutdown") {
+			t.Fatalf("runs = %+v, want one interrupted run", runs)
+		}
+		if next := repo.get("s1").NextRunAt; !next.Equal(claims[0].next) {
+			t.Errorf("next run = %v, want the claimed %v kept", next, claims[0].next)
+		}
+	})
+}
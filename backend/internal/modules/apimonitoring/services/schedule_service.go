@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+)
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+const (
+	DefaultRunTimeoutSeconds = 30
+	MaxRunTimeoutSeconds     = 300
+
+	// maxStoredResponseBytes is how much of each response body is kept in
+	// run history.
+	maxStoredResponseBytes = 4 << 10
+)
+
+// ScheduleService manages scheduled API calls and their run history.
+type ScheduleService interface {
+	ListSchedules() ([]models.ScheduledAPI, error)
+	GetSchedule(id string) (models.ScheduledAPI, error)
+	CreateSchedule(schedule models.ScheduledAPI) (models.ScheduledAPI, error)
+	UpdateSchedule(schedule models.ScheduledAPI) (models.ScheduledAPI, error)
+	DeleteSchedule(id string) error
+	PauseSchedule(id string) (models.ScheduledAPI, error)
+	ResumeSchedule(id string) (models.ScheduledAPI, error)
+
+	// TriggerRun runs the schedule now, outside its cron timing, and
+	// returns the recorded run.
+	TriggerRun(ctx context.Context, id string) (models.Run, error)
+	ListRuns(scheduleID string, limit int) ([]models.Run, error)
+
+	// RunStats feeds the dashboard summary.
+	RunStats(ctx context.Context, since time.Time) (models.RunStats, error)
+}
+
+type scheduleService struct {
+	repo   repositories.ScheduleRepository
+	client *http.Client
+}
+
+// NewScheduleService returns a ScheduleService backed by repo. Manual runs
+// use client, or a NewRunClient when nil.
+func NewScheduleService(repo repositories.ScheduleRepository, client *http.Client) ScheduleService {
+	if client == nil {
+		client = NewRunClient()
+	}
+	return &scheduleService{repo: repo, client: client}
+}
+
+// NewRunClient returns the HTTP client for scheduled API calls. Each run is
+// also bounded by its own timeoutSeconds; the client timeout is a backstop
+// that no run outlives.
+func NewRunClient() *http.Client {
+	return &http.Client{Timeout: MaxRunTimeoutSeconds * time.Second}
+}
+
+func (s *scheduleService) ListSchedules() ([]models.ScheduledAPI, error) {
+	schedules, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if schedules == nil {
+		schedules = []models.ScheduledAPI{}
+	}
+	return schedules, nil
+}
+
+func (s *scheduleService) GetSchedule(id string) (models.ScheduledAPI, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *scheduleService) CreateSchedule(schedule models.ScheduledAPI) (models.ScheduledAPI, error) {
+	schedule = normalizeSchedule(schedule)
+	sched, err := validateSchedule(schedule)
+	if err != nil {
+		return models.ScheduledAPI{}, err
+	}
+	if !schedule.Paused {
+		next := sched.Next(time.Now())
+		schedule.NextRunAt = &next
+	}
+	return s.repo.Create(schedule)
+}
+
+func (s *scheduleService) UpdateSchedule(schedule models.ScheduledAPI) (models.ScheduledAPI, error) {
+	schedule = normalizeSchedule(schedule)
+	sched, err := validateSchedule(schedule)
+	if err != nil {
+		return models.ScheduledAPI{}, err
+	}
+	// The repository keeps next_run_at empty while the schedule is paused.
+	next := sched.Next(time.Now())
+	schedule.NextRunAt = &next
+	return s.repo.Update(schedule)
+}
+
+func (s *scheduleService) DeleteSchedule(id string) error {
+	return s.repo.Delete(id)
+}
+
+func (s *scheduleService) PauseSchedule(id string) (models.ScheduledAPI, error) {
+	return s.repo.SetPaused(id, true, nil)
+}
+
+// ResumeSchedule re-enables a schedule from the next cron slot; runs missed
+// while paused are not caught up.
+func (s *scheduleService) ResumeSchedule(id string) (models.ScheduledAPI, error) {
+	schedule, err := s.repo.GetByID(id)
+	if err != nil {
+		return models.ScheduledAPI{}, err
+	}
+	sched, err := parseSchedule(schedule.Schedule)
+	if err != nil {
+		return models.ScheduledAPI{}, err
+	}
+	next := sched.Next(time.Now())
+	return s.repo.SetPaused(id, false, &next)
+}
+
+func (s *scheduleService) TriggerRun(ctx context.Context, id string) (models.Run, error) {
+	schedule, err := s.repo.GetByID(id)
+	if err != nil {
+		return models.Run{}, err
+	}
+	run := executeRun(ctx, s.client, schedule, models.TriggerManual)
+	return s.repo.AddRun(run)
+}
+
+func (s *scheduleService) ListRuns(scheduleID string, limit int) ([]models.Run, error) {
+	if _, err := s.repo.GetByID(scheduleID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultResultLimit
+	}
+	if limit > MaxResultLimit {
+		limit = MaxResultLimit
+	}
+
+	runs, err := s.repo.ListRuns(scheduleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		runs = []models.Run{}
+	}
+	return runs, nil
+}
+
+func (s *scheduleService) RunStats(ctx context.Context, since time.Time) (models.RunStats, error) {
+	active, err := s.repo.CountActive()
+	if err != nil {
+		return models.RunStats{}, err
+	}
+	counts, err := s.repo.CountRunsSince(since)
+	if err != nil {
+		return models.RunStats{}, err
+	}
+	return models.RunStats{
+		ScheduledAPIs: active,
+		Total:         counts.Total,
+		Succeeded:     counts.Succeeded,
+		Scheduled:     counts.Scheduled,
+	}, nil
+}
+
+// executeRun calls the scheduled API once. Transport failures are recorded on
+// the run rather than returned.
+func executeRun(ctx context.Context, client *http.Client, schedule models.ScheduledAPI, trigger string) models.Run {
+	run := models.Run{ScheduleID: schedule.ID, Trigger: trigger, StartedAt: time.Now().UTC()}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(schedule.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	var body io.Reader
+	if schedule.Body != "" {
+		body = strings.NewReader(schedule.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, schedule.Method, schedule.URL, body)
+	if err != nil {
+		run.Outcome = models.OutcomeError
+		run.Error = err.Error()
+		run.FinishedAt = time.Now().UTC()
+		return run
+	}
+	for name, value := range schedule.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		run.Outcome = models.OutcomeError
+		run.Error = err.Error()
+		run.FinishedAt = time.Now().UTC()
+		return run
+	}
+	defer resp.Body.Close()
+
+	run.StatusCode = resp.StatusCode
+	run.Outcome = models.OutcomeFailure
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		run.Outcome = models.OutcomeSuccess
+	}
+
+	// Read one byte past the limit to tell whether the body was cut.
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxStoredResponseBytes+1))
+	if err != nil {
+		run.Error = fmt.Sprintf("read body: %v", err)
+	}
+	if len(respBody) > maxStoredResponseBytes {
+		respBody = respBody[:maxStoredResponseBytes]
+		run.Truncated = true
+	}
+	run.ResponseBody = storableText(respBody)
+	run.FinishedAt = time.Now().UTC()
+	return run
+}
+
+// storableText makes a possibly truncated body safe to store in a Postgres
+// TEXT column, which rejects invalid UTF-8 and NUL bytes.
+func storableText(body []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(body), "\uFFFD"), "\x00", "")
+}
+
+// parseSchedule accepts standard five-field cron expressions, descriptors
+// such as @hourly, and an optional CRON_TZ= prefix. Expressions that parse
+// but never fire, such as "0 0 30 2 *", are rejected: cron reports their
+// next run as the zero time.
+func parseSchedule(expr string) (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+	}
+	if sched.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("%w: %q never fires", ErrInvalidSchedule, expr)
+	}
+	return sched, nil
+}
+
+func normalizeSchedule(schedule models.ScheduledAPI) models.ScheduledAPI {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	schedule.Schedule = strings.TrimSpace(schedule.Schedule)
+	schedule.Method = strings.ToUpper(strings.TrimSpace(schedule.Method))
+	if schedule.Method == "" {
+		schedule.Method = http.MethodGet
+	}
+	if schedule.TimeoutSeconds == 0 {
+		schedule.TimeoutSeconds = DefaultRunTimeoutSeconds
+	}
+	if schedule.Headers == nil {
+		schedule.Headers = map[string]string{}
+	}
+	return schedule
+}
+
+func validateSchedule(schedule models.ScheduledAPI) (cron.Schedule, error) {
+	if schedule.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+	u, err := url.Parse(schedule.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an http(s) URL", ErrInvalidSchedule)
+	}
+	if !allowedMethods[schedule.Method] {
+		return nil, fmt.Errorf("%w: unsupported method %q", ErrInvalidSchedule, schedule.Method)
+	}
+	if schedule.TimeoutSeconds < 1 || schedule.TimeoutSeconds > MaxRunTimeoutSeconds {
+		return nil, fmt.Errorf("%w: timeoutSeconds must be between 1 and %d", ErrInvalidSchedule, MaxRunTimeoutSeconds)
+	}
+	return parseSchedule(schedule.Schedule)
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+)
+
+// memSchedules is an in-memory ScheduleRepository.
+type memSchedules struct {
+	mu        sync.Mutex
+	schedules map[string]models.ScheduledAPI
+	runs      []models.Run
+	claims    int
+}
+
+func newMemSchedules(schedules ...models.ScheduledAPI) *memSchedules {
+	repo := &memSchedules{schedules: map[string]models.ScheduledAPI{}}
+	for _, schedule := range schedules {
+		repo.schedules[schedule.ID] = schedule
+	}
+	return repo
+}
+
+func (r *memSchedules) Create(schedule models.ScheduledAPI) (models.ScheduledAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if schedule.ID == "" {
+		schedule.ID = fmt.Sprintf("s%d", len(r.schedules)+1)
+	}
+	r.schedules[schedule.ID] = schedule
+	return schedule, nil
+}
+
+func (r *memSchedules) GetByID(id string) (models.ScheduledAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schedule, ok := r.schedules[id]
+	if !ok {
+		return models.ScheduledAPI{}, repositories.ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+func (r *memSchedules) List() ([]models.ScheduledAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var schedules []models.ScheduledAPI
+	for _, schedule := range r.schedules {
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func (r *memSchedules) ListDue(now time.Time) ([]models.ScheduledAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []models.ScheduledAPI
+	for _, schedule := range r.schedules {
+		if !schedule.Paused && schedule.NextRunAt != nil && !schedule.NextRunAt.After(now) {
+			due = append(due, schedule)
+		}
+	}
+	return due, nil
+}
+
+func (r *memSchedules) CountActive() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	active := 0
+	for _, schedule := range r.schedules {
+		if !schedule.Paused {
+			active++
+		}
+	}
+	return active, nil
+}
+
+func (r *memSchedules) Update(schedule models.ScheduledAPI) (models.ScheduledAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.schedules[schedule.ID]; !ok {
+		return models.ScheduledAPI{}, repositories.ErrScheduleNotFound
+	}
+	r.schedules[schedule.ID] = schedule
+	return schedule, nil
+}
+
+func (r *memSchedules) SetPaused(id string, paused bool, nextRunAt *time.Time) (models.ScheduledAPI, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schedule, ok := r.schedules[id]
+	if !ok {
+		return models.ScheduledAPI{}, repositories.ErrScheduleNotFound
+	}
+	schedule.Paused, schedule.NextRunAt = paused, nextRunAt
+	r.schedules[id] = schedule
+	return schedule, nil
+}
+
+func (r *memSchedules) ClaimRun(id string, due, next time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schedule, ok := r.schedules[id]
+	if !ok || schedule.Paused || schedule.NextRunAt == nil || !schedule.NextRunAt.Equal(due) {
+		return false, nil
+	}
+	r.claims++
+	schedule.NextRunAt = &next
+	r.schedules[id] = schedule
+	return true, nil
+}
+
+func (r *memSchedules) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.schedules, id)
+	return nil
+}
+
+func (r *memSchedules) AddRun(run models.Run) (models.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run.ID = int64(len(r.runs) + 1)
+	r.runs = append(r.runs, run)
+	return run, nil
+}
+
+func (r *memSchedules) ListRuns(scheduleID string, limit int) ([]models.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var runs []models.Run
+	for i := len(r.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if r.runs[i].ScheduleID == scheduleID {
+			runs = append(runs, r.runs[i])
+		}
+	}
+	return runs, nil
+}
+
+func (r *memSchedules) CountRunsSince(since time.Time) (repositories.RunCounts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var counts repositories.RunCounts
+	for _, run := range r.runs {
+		if run.StartedAt.Before(since) {
+			continue
+		}
+		counts.Total++
+		if run.Outcome == models.OutcomeSuccess {
+			counts.Succeeded++
+		}
+		if run.Trigger == models.TriggerSchedule {
+			counts.Scheduled++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memSchedules) DeleteRunsBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memSchedules) get(id string) models.ScheduledAPI {
+	schedule, _ := r.GetByID(id)
+	return schedule
+}
+
+func TestValidateSchedule(t *testing.T) {
+	valid := models.ScheduledAPI{Name: "report", URL: "https://api.example.com/report", Schedule: "*/5 * * * *"}
+	tests := []struct {
+		name    string
+		change  func(*models.ScheduledAPI)
+		wantErr string
+	}{
+		{name: "valid", change: func(*models.ScheduledAPI) {}},
+		{name: "descriptor", change: func(s *models.ScheduledAPI) { s.Schedule = "@hourly" }},
+		{name: "time zone", change: func(s *models.ScheduledAPI) { s.Schedule = "CRON_TZ=Europe/Paris 0 9 * * 1-5" }},
+		{name: "leap day", change: func(s *models.ScheduledAPI) { s.Schedule = "0 0 29 2 *" }},
+		{name: "never fires", change: func(s *models.ScheduledAPI) { s.Schedule = "0 0 30 2 *" }, wantErr: "never fires"},
+		{name: "april 31", change: func(s *models.ScheduledAPI) { s.Schedule = "0 12 31 4 *" }, wantErr: "never fires"},
+		{name: "garbage", change: func(s *models.ScheduledAPI) { s.Schedule = "every monday" }, wantErr: "invalid schedule"},
+		{name: "no name", change: func(s *models.ScheduledAPI) { s.Name = "" }, wantErr: "name is required"},
+		{name: "bad url", change: func(s *models.ScheduledAPI) { s.URL = "mailto:ops@example.com" }, wantErr: "url must be"},
+		{name: "timeout", change: func(s *models.ScheduledAPI) { s.TimeoutSeconds = MaxRunTimeoutSeconds + 1 }, wantErr: "timeoutSeconds"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := valid
+			tt.change(&schedule)
+			_, err := validateSchedule(normalizeSchedule(schedule))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateSchedule: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidSchedule) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateSchedule error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleLifecycle(t *testing.T) {
+	repo := newMemSchedules()
+	service := NewScheduleService(repo, nil)
+
+	if _, err := service.CreateSchedule(models.ScheduledAPI{Name: "x", URL: "https://example.com", Schedule: "0 0 30 2 *"}); !errors.Is(err, ErrInvalidSchedule) {
+		t.Fatalf("never-firing schedule: err = %v, want ErrInvalidSchedule", err)
+	}
+
+	created, err := service.CreateSchedule(models.ScheduledAPI{Name: "report", URL: "https://example.com", Schedule: "@hourly"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.NextRunAt == nil || !created.NextRunAt.After(time.Now()) || created.NextRunAt.Minute() != 0 {
+		t.Fatalf("NextRunAt = %v, want the next hour", created.NextRunAt)
+	}
+
+	created.Schedule = "0 0 31 2 *"
+	if _, err := service.UpdateSchedule(created); !errors.Is(err, ErrInvalidSchedule) {
+		t.Fatalf("update to never-firing schedule: err = %v, want ErrInvalidSchedule", err)
+	}
+
+	if paused, err := service.PauseSchedule(created.ID); err != nil || !paused.Paused || paused.NextRunAt != nil {
+		t.Fatalf("PauseSchedule = %+v, %v", paused, err)
+	}
+	if resumed, err := service.ResumeSchedule(created.ID); err != nil || resumed.Paused || resumed.NextRunAt == nil {
+		t.Fatalf("ResumeSchedule = %+v, %v", resumed, err)
+	}
+}
+
+// countingTransport records requests made through a client.
+type countingTransport struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests++
+	t.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTriggerRunUsesServiceClient(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "done")
+	}))
+	defer api.Close()
+
+	repo := newMemSchedules(normalizeSchedule(models.ScheduledAPI{ID: "s1", Name: "report", URL: api.URL, Schedule: "@daily", Paused: true}))
+	transport := &countingTransport{}
+	service := NewScheduleService(repo, &http.Client{Transport: transport})
+
+	run, err := service.TriggerRun(context.Background(), "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Trigger != models.TriggerManual || run.Outcome != models.OutcomeSuccess || run.ResponseBody != "done" {
+		t.Errorf("run = %+v", run)
+	}
+	if transport.requests != 1 {
+		t.Errorf("service client made %d requests, want 1", transport.requests)
+	}
+	if _, err := service.TriggerRun(context.Background(), "missing"); !errors.Is(err, repositories.ErrScheduleNotFound) {
+		t.Errorf("unknown schedule: err = %v", err)
+	}
+
+	if client := NewScheduleService(repo, nil).(*scheduleService).client; client == http.DefaultClient || client.Timeout <= 0 {
+		t.Errorf("default client has no timeout: %+v", client)
+	}
+}
+
+func TestExecuteRun(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusAccepted)
+		case "/fail":
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, "boom\x00")
+		case "/large":
+			io.WriteString(w, strings.Repeat("a", maxStoredResponseBytes+10))
+		}
+	}))
+	defer api.Close()
+
+	tests := []struct {
+		path          string
+		wantOutcome   string
+		wantBody      string
+		wantTruncated bool
+	}{
+		{path: "/ok", wantOutcome: models.OutcomeSuccess},
+		{path: "/fail", wantOutcome: models.OutcomeFailure, wantBody: "boom"},
+		{path: "/large", wantOutcome: models.OutcomeSuccess, wantBody: strings.Repeat("a", maxStoredResponseBytes), wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			schedule := normalizeSchedule(models.ScheduledAPI{ID: "s1", URL: api.URL + tt.path})
+			run := executeRun(context.Background(), api.Client(), schedule, models.TriggerSchedule)
+			if run.Outcome != tt.wantOutcome || run.ResponseBody != tt.wantBody || run.Truncated != tt.wantTruncated {
+				t.Errorf("run = outcome %q, %d body bytes, truncated %v", run.Outcome, len(run.ResponseBody), run.Truncated)
+			}
+			if run.FinishedAt.Before(run.StartedAt) {
+				t.Errorf("finished %v before starting %v", run.FinishedAt, run.StartedAt)
+			}
+		})
+	}
+
+	schedule := normalizeSchedule(models.ScheduledAPI{ID: "s1", URL: "http://127.0.0.1:1/unreachable"})
+	if run := executeRun(context.Background(), api.Client(), schedule, models.TriggerSchedule); run.Outcome != models.OutcomeError || run.Error == "" {
+		t.Errorf("unreachable run = %+v, want an error outcome", run)
+	}
+}
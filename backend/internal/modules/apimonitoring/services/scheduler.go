@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+)
+
+// Locker elects a single replica to run a scheduler.
+// repositories.AdvisoryLock implements it.
+type Locker interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release()
+}
+
+// SchedulerOptions tunes the probe scheduler. Zero values fall back to
+// defaults.
+type SchedulerOptions struct {
+	// Tick is how often the check list is reloaded and due checks started.
+	Tick time.Duration
+	// Workers bounds the number of probes in flight.
+	Workers int
+	// Retention is how long results are kept; zero keeps them forever.
+	Retention time.Duration
+	// Client is the HTTP client used for outgoing requests.
+	Client *http.Client
+	// Lock, when set, limits the scheduler to whichever replica holds it.
+	// Without it every replica runs every check.
+	Lock Locker
+}
+
+func (o SchedulerOptions) withDefaults() SchedulerOptions {
+	if o.Tick <= 0 {
+		o.Tick = 5 * time.Second
+	}
+	if o.Workers <= 0 {
+		o.Workers = 10
+	}
+	if o.Client == nil {
+		// Redirects are followed so statuses describe the final response,
+		// matching what a browser or API client would see.
+		o.Client = &http.Client{}
+	}
+	return o
+}
+
+// isLeader reports whether this replica should run scheduled work this tick.
+func (o SchedulerOptions) isLeader(ctx context.Context, logger *zap.Logger) bool {
+	if o.Lock == nil {
+		return true
+	}
+	leader, err := o.Lock.TryAcquire(ctx)
+	if err != nil {
+		logger.Warn("failed to acquire scheduler lock", zap.Error(err))
+		return false
+	}
+	return leader
+}
+
+func (o SchedulerOptions) release() {
+	if o.Lock != nil {
+		o.Lock.Release()
+	}
+}
+
+// Scheduler runs enabled checks on their configured interval and stores
+// every result.
+type Scheduler struct {
+	repo   repositories.CheckRepository
+	opts   SchedulerOptions
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	lastRun  map[string]time.Time
+	inFlight map[string]bool
+}
+
+// NewScheduler returns a Scheduler that reads checks from and writes results
+// to repo.
+func NewScheduler(repo repositories.CheckRepository, opts SchedulerOptions, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		opts:     opts.withDefaults(),
+		logger:   logger,
+		lastRun:  map[string]time.Time{},
+		inFlight: map[string]bool{},
+	}
+}
+
+// Run starts due checks every tick until ctx is cancelled, then waits for
+// in-flight probes to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.Tick)
+	defer ticker.Stop()
+	defer s.opts.release()
+
+	sem := make(chan struct{}, s.opts.Workers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lastPrune := time.Time{}
+	for {
+		now := time.Now()
+		if s.opts.isLeader(ctx, s.logger) {
+			for _, check := range s.dueChecks(now) {
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				wg.Add(1)
+				go func(check models.EndpointCheck) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					s.runCheck(ctx, check)
+				}(check)
+			}
+
+			if s.opts.Retention > 0 && now.Sub(lastPrune) >= time.Hour {
+				lastPrune = now
+				s.prune(now)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) dueChecks(now time.Time) []models.EndpointCheck {
+	checks, err := s.repo.ListEnabled()
+	if err != nil {
+		s.logger.Warn("failed to load endpoint checks", zap.Error(err))
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active := make(map[string]bool, len(checks))
+	var due []models.EndpointCheck
+	for _, check := range checks {
+		active[check.ID] = true
+		if s.inFlight[check.ID] {
+			continue
+		}
+		interval := time.Duration(check.IntervalSeconds) * time.Second
+		if last, ok := s.lastRun[check.ID]; ok && now.Sub(last) < interval {
+			continue
+		}
+		s.lastRun[check.ID] = now
+		s.inFlight[check.ID] = true
+		due = append(due, check)
+	}
+
+	// Forget deleted or disabled checks so they run immediately if re-enabled.
+	for id := range s.lastRun {
+		if !active[id] && !s.inFlight[id] {
+			delete(s.lastRun, id)
+		}
+	}
+	return due
+}
+
+func (s *Scheduler) runCheck(ctx context.Context, check models.EndpointCheck) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.inFlight, check.ID)
+		s.mu.Unlock()
+	}()
+
+	result := Probe(ctx, s.opts.Client, check)
+	if ctx.Err() != nil {
+		// Shutting down; a cancelled probe says nothing about the endpoint.
+		return
+	}
+	if _, err := s.repo.AddResult(result); err != nil {
+		s.logger.Warn("failed to store check result", zap.String("check", check.ID), zap.Error(err))
+	}
+}
+
+func (s *Scheduler) prune(now time.Time) {
+	removed, err := s.repo.DeleteResultsBefore(now.Add(-s.opts.Retention))
+	if err != nil {
+		s.logger.Warn("failed to prune check results", zap.Error(err))
+		return
+	}
+	if removed > 0 {
+		s.logger.Info("pruned check results", zap.Int64("removed", removed))
+	}
+}
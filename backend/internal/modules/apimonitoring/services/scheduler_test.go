@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/apimonitoring/repositories"
+)
+
+// memChecks is an in-memory CheckRepository.
+type memChecks struct {
+	mu      sync.Mutex
+	checks  []models.EndpointCheck
+	results []models.CheckResult
+}
+
+func (r *memChecks) Create(check models.EndpointCheck) (models.EndpointCheck, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check)
+	return check, nil
+}
+
+func (r *memChecks) GetByID(id string) (models.EndpointCheck, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, check := range r.checks {
+		if check.ID == id {
+			return check, nil
+		}
+	}
+	return models.EndpointCheck{}, repositories.ErrCheckNotFound
+}
+
+func (r *memChecks) List() ([]models.EndpointCheck, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.EndpointCheck(nil), r.checks...), nil
+}
+
+func (r *memChecks) ListEnabled() ([]models.EndpointCheck, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var enabled []models.EndpointCheck
+	for _, check := range r.checks {
+		if check.Enabled {
+			enabled = append(enabled, check)
+		}
+	}
+	return enabled, nil
+}
+
+func (r *memChecks) Update(check models.EndpointCheck) (models.EndpointCheck, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.checks {
+		if r.checks[i].ID == check.ID {
+			r.checks[i] = check
+			return check, nil
+		}
+	}
+	return models.EndpointCheck{}, repositories.ErrCheckNotFound
+}
+
+func (r *memChecks) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.checks {
+		if r.checks[i].ID == id {
+			r.checks = append(r.checks[:i], r.checks[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrCheckNotFound
+}
+
+func (r *memChecks) AddResult(result models.CheckResult) (models.CheckResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result.ID = int64(len(r.results) + 1)
+	r.results = append(r.results, result)
+	return result, nil
+}
+
+func (r *memChecks) ListResults(checkID string, limit int) ([]models.CheckResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []models.CheckResult
+	for i := len(r.results) - 1; i >= 0 && len(results) < limit; i-- {
+		if r.results[i].CheckID == checkID {
+			results = append(results, r.results[i])
+		}
+	}
+	return results, nil
+}
+
+func (r *memChecks) CountResultsSince(since time.Time) (int, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var total, succeeded int
+	for _, result := range r.results {
+		if !result.StartedAt.Before(since) {
+			total++
+			if result.Success {
+				succeeded++
+			}
+		}
+	}
+	return total, succeeded, nil
+}
+
+func (r *memChecks) DeleteResultsBefore(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.results[:0]
+	for _, result := range r.results {
+		if !result.StartedAt.Before(before) {
+			kept = append(kept, result)
+		}
+	}
+	removed := int64(len(r.results) - len(kept))
+	r.results = kept
+	return removed, nil
+}
+
+func TestSchedulerDueChecks(t *testing.T) {
+	repo := &memChecks{checks: []models.EndpointCheck{
+		{ID: "fast", Enabled: true, IntervalSeconds: 10},
+		{ID: "slow", Enabled: true, IntervalSeconds: 60},
+		{ID: "off", Enabled: false, IntervalSeconds: 10},
+	}}
+	scheduler := NewScheduler(repo, SchedulerOptions{}, zap.NewNop())
+	ids := func(checks []models.EndpointCheck) map[string]bool {
+		set := map[string]bool{}
+		for _, check := range checks {
+			set[check.ID] = true
+		}
+		return set
+	}
+	finish := func(id string) {
+		scheduler.mu.Lock()
+		delete(scheduler.inFlight, id)
+		scheduler.mu.Unlock()
+	}
+
+	start := time.Now()
+	if due := ids(scheduler.dueChecks(start)); len(due) != 2 || !due["fast"] || !due["slow"] {
+		t.Fatalf("first tick due = %v, want fast and slow", due)
+	}
+	// Both are still in flight, so nothing starts twice.
+	if due := scheduler.dueChecks(start.Add(20 * time.Second)); len(due) != 0 {
+		t.Fatalf("in-flight checks started again: %v", ids(due))
+	}
+	finish("fast")
+	finish("slow")
+	if due := ids(scheduler.dueChecks(start.Add(20 * time.Second))); len(due) != 1 || !due["fast"] {
+		t.Fatalf("after 20s due = %v, want fast only", due)
+	}
+	finish("fast")
+
+	// A deleted check is forgotten and runs at once if it comes back.
+	repo.Delete("slow")
+	scheduler.dueChecks(start.Add(25 * time.Second))
+	repo.Create(models.EndpointCheck{ID: "slow", Enabled: true, IntervalSeconds: 60})
+	if due := ids(scheduler.dueChecks(start.Add(26 * time.Second))); !due["slow"] {
+		t.Fatalf("re-created check not due: %v", due)
+	}
+}
+
+func TestSchedulerRunStoresResults(t *testing.T) {
+	var hits atomic.Int32
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer endpoint.Close()
+
+	repo := &memChecks{checks: []models.EndpointCheck{
+		normalizeCheck(models.EndpointCheck{ID: "c1", Name: "api", URL: endpoint.URL, Enabled: true}),
+	}}
+	scheduler := NewScheduler(repo, SchedulerOptions{Tick: 10 * time.Millisecond, Client: endpoint.Client()}, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if results, _ := repo.ListResults("c1", 10); len(results) > 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	results, _ := repo.ListResults("c1", 10)
+	// The check runs every 60s, so many ticks still produce one probe.
+	if len(results) != 1 || hits.Load() != 1 {
+		t.Fatalf("got %d results from %d requests, want 1", len(results), hits.Load())
+	}
+	if result := results[0]; result.Success || result.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("result = %+v, want a failed 503 probe", result)
+	}
+
+	service := NewCheckService(repo)
+	stats, err := service.EndpointStats(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (models.EndpointStats{Total: 1, Probes: 1, UpProbes: 0}) {
+		t.Errorf("EndpointStats = %+v", stats)
+	}
+}
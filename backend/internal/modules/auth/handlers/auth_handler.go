@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
 	"github.com/fbisdevoptics/backend/internal/modules/auth/services"
 )
 
@@ -16,12 +19,24 @@ func NewAuthHandler(service services.AuthService) *AuthHandler {
 	return &AuthHandler{service: service}
 }
 
+// authResponse is a user with a token pair; the pair's fields sit next to
+// user, so the access token is still returned as token.
+type authResponse struct {
+	User models.User `json:"user"`
+	services.TokenPair
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// SignUp creates a viewer account. A role in the request is ignored, so
+// the public endpoint cannot grant itself more.
 func (h *AuthHandler) SignUp(c *gin.Context) {
 	var req struct {
 		FullName string `json:"fullName" binding:"required"`
 		Email    string `json:"email" binding:"required,email"`
 		Password string `json:"password" binding:"required,min=8"`
-		Role     string `json:"role"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -29,17 +44,17 @@ func (h *AuthHandler) SignUp(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.service.SignUp(req.FullName, req.Email, req.Password, req.Role)
+	user, tokens, err := h.service.SignUp(req.FullName, req.Email, req.Password)
 	if err != nil {
 		status := http.StatusInternalServerError
-		if err == services.ErrEmailExists {
+		if errors.Is(err, services.ErrEmailExists) {
 			status = http.StatusConflict
 		}
 		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"user": user, "token": token})
+	c.JSON(http.StatusCreated, authResponse{User: user, TokenPair: tokens})
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -53,13 +68,47 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := h.service.Login(req.Email, req.Password)
+	user, tokens, err := h.service.Login(req.Email, req.Password)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"user": user, "token": token})
+	c.JSON(http.StatusOK, authResponse{User: user, TokenPair: tokens})
+}
+
+// Refresh rotates a refresh token into a new token pair.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.service.Refresh(req.RefreshToken)
+	if err != nil {
+		writeRefreshError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout revokes the refresh token's family, ending the session it belongs
+// to. Access tokens already issued stay valid until they expire.
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req refreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Logout(req.RefreshToken); err != nil {
+		writeRefreshError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "logged out"})
 }
 
 func (h *AuthHandler) ListUsers(c *gin.Context) {
@@ -82,9 +131,58 @@ func (h *AuthHandler) UpdateRole(c *gin.Context) {
 	}
 
 	if err := h.service.UpdateRole(c.Param("id"), req.Role); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "updated"})
+}
+
+// ResetPassword sets a user's password and signs them out everywhere.
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req struct {
+		Password string `json:"password" binding:"required,min=8"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if err := h.service.ResetPassword(c.Param("id"), req.Password); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"status": "updated"})
 }
+
+// RevokeSessions signs a user out everywhere: their access tokens stop
+// working and their refresh tokens are revoked.
+func (h *AuthHandler) RevokeSessions(c *gin.Context) {
+	if err := h.service.RevokeSessions(c.Param("id")); err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
+
+func writeUserError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidRole):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func writeRefreshError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+		status = http.StatusUnauthorized
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/services"
+)
+
+// memUsers is an in-memory UserRepository keyed by user ID.
+type memUsers map[string]models.User
+
+func (r memUsers) Create(user models.User) (models.User, error) {
+	r[user.ID] = user
+	return user, nil
+}
+
+func (r memUsers) GetByEmail(email string) (models.User, error) {
+	for _, user := range r {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, repositories.ErrUserNotFound
+}
+
+func (r memUsers) GetByID(id string) (models.User, error) {
+	user, ok := r[id]
+	if !ok {
+		return models.User{}, repositories.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r memUsers) List() ([]models.User, error) {
+	users := make([]models.User, 0, len(r))
+	for _, user := range r {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r memUsers) UpdateRole(id, role string) error             { return nil }
+func (r memUsers) UpdatePassword(id, passwordHash string) error { return nil }
+func (r memUsers) BumpTokenVersion(id string) error             { return nil }
+
+func (r memUsers) GetTokenVersion(id string) (int, error) {
+	user, err := r.GetByID(id)
+	return user.TokenVersion, err
+}
+
+func (r memUsers) GetByIdentity(issuer, subject string) (models.User, error) {
+	return models.User{}, repositories.ErrUserNotFound
+}
+
+func (r memUsers) CreateWithIdentity(user models.User, identity models.Identity) (models.User, error) {
+	return r.Create(user)
+}
+
+func (r memUsers) LinkIdentity(identity models.Identity) error { return nil }
+
+// memRefreshTokens keeps issued refresh tokens; the signup tests never
+// redeem them.
+type memRefreshTokens []models.RefreshToken
+
+func (r *memRefreshTokens) Create(token models.RefreshToken) error {
+	*r = append(*r, token)
+	return nil
+}
+
+func (r *memRefreshTokens) GetByHash(hash string) (models.RefreshToken, error) {
+	return models.RefreshToken{}, repositories.ErrRefreshTokenNotFound
+}
+
+func (r *memRefreshTokens) Rotate(usedID string, next models.RefreshToken, now time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *memRefreshTokens) RevokeFamily(familyID string, now time.Time) error { return nil }
+func (r *memRefreshTokens) RevokeUser(userID string, now time.Time) error     { return nil }
+func (r *memRefreshTokens) DeleteExpiredBefore(t time.Time) (int64, error)    { return 0, nil }
+
+func TestSignUpCreatesViewers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "no role", body: `{"fullName":"Ada","email":"ada@example.com","password":"correct horse"}`},
+		{name: "admin requested", body: `{"fullName":"Ada","email":"ada@example.com","password":"correct horse","role":"admin"}`},
+		{name: "unknown role requested", body: `{"fullName":"Ada","email":"ada@example.com","password":"correct horse","role":"root"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := memUsers{}
+			service := services.NewAuthService(users, &memRefreshTokens{}, services.NewSecretKeys("secret"), services.TokenOptions{})
+			router := gin.New()
+			router.POST("/api/v1/auth/signup", NewAuthHandler(service).SignUp)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signup", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusCreated {
+				t.Fatalf("SignUp = %d %s", resp.Code, resp.Body)
+			}
+			var body authResponse
+			if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.User.Role != models.RoleViewer {
+				t.Errorf("user role = %q, want viewer", body.User.Role)
+			}
+			claims, err := service.ValidateToken(body.AccessToken)
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if claims.Role != models.RoleViewer {
+				t.Errorf("token role = %q, want viewer", claims.Role)
+			}
+			if stored, err := users.GetByEmail("ada@example.com"); err != nil || stored.Role != models.RoleViewer {
+				t.Errorf("stored user = %+v, %v", stored, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/services"
+)
+
+// jwksMaxAge is how long verifiers may cache the key set. It must stay well
+// below the key overlap so a new key is fetched before it starts signing.
+const jwksMaxAge = 300
+
+type JWKSHandler struct {
+	keys services.Keys
+}
+
+func NewJWKSHandler(keys services.Keys) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKS serves the public keys access tokens are verified with.
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", jwksMaxAge))
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -26,15 +27,25 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 			return
 		}
 
-		claims, role, err := m.service.ValidateToken(token)
-		if err != nil {
+		claims, err := m.service.ValidateToken(token)
+		switch {
+		case errors.Is(err, services.ErrTokenRevoked):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token revoked"})
+			c.Abort()
+			return
+		case errors.Is(err, services.ErrInvalidToken):
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			c.Abort()
 			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to validate token"})
+			c.Abort()
+			return
 		}
 
 		c.Set("auth.sub", claims.Subject)
-		c.Set("auth.role", role)
+		c.Set("auth.role", claims.Role)
+		c.Set("auth.email", claims.Email)
 		c.Next()
 	}
 }
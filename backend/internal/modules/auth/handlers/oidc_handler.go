@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/services"
+)
+
+// oidcBindingCookie holds the binding of the sign-in the browser started.
+const oidcBindingCookie = "oidc_binding"
+
+type OIDCHandler struct {
+	service services.OIDCService
+}
+
+func NewOIDCHandler(service services.OIDCService) *OIDCHandler {
+	return &OIDCHandler{service: service}
+}
+
+// BeginLogin returns the identity provider URL the browser should visit,
+// and sets the cookie that binds the sign-in to the browser.
+func (h *OIDCHandler) BeginLogin(c *gin.Context) {
+	authURL, binding, err := h.service.BeginLogin(c.Request.Context())
+	if err != nil {
+		writeOIDCError(c, err)
+		return
+	}
+
+	setBindingCookie(c, binding, 0)
+
+	c.JSON(http.StatusOK, gin.H{"authorizationUrl": authURL})
+}
+
+// CompleteLogin takes the code and state the provider redirected the
+// browser back with and returns the same response as Login. The browser
+// must send the cookie BeginLogin set; it is cleared either way.
+func (h *OIDCHandler) CompleteLogin(c *gin.Context) {
+	var req struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	binding, _ := c.Cookie(oidcBindingCookie)
+	setBindingCookie(c, "", -1)
+
+	user, tokens, err := h.service.CompleteLogin(c.Request.Context(), req.Code, req.State, binding)
+	if err != nil {
+		writeOIDCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authResponse{User: user, TokenPair: tokens})
+}
+
+// setBindingCookie sets or, with a negative maxAge, clears the binding
+// cookie. It is scoped to the OIDC routes, which share the parent path of
+// the request. SameSite=Lax keeps other sites from posting it to the
+// callback, and HttpOnly keeps scripts from reading it.
+func setBindingCookie(c *gin.Context, binding string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcBindingCookie, binding, maxAge, path.Dir(c.Request.URL.Path), "", secure, true)
+}
+
+func writeOIDCError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrOIDCNotConfigured):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, services.ErrInvalidOIDCState):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrOIDCRejected):
+		status = http.StatusUnauthorized
+	case errors.Is(err, services.ErrNoMappedRole), errors.Is(err, services.ErrMissingEmail):
+		status = http.StatusForbidden
+	case errors.Is(err, services.ErrIdentityConflict):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrOIDCProvider):
+		status = http.StatusBadGateway
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
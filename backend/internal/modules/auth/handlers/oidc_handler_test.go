@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/services"
+)
+
+// fakeOIDCService hands out one binding and completes sign-ins only with
+// it, recording the binding it was given.
+type fakeOIDCService struct {
+	binding    string
+	gotBinding string
+}
+
+func (s *fakeOIDCService) BeginLogin(ctx context.Context) (string, string, error) {
+	return "https://idp.example.com/authorize?state=s1", s.binding, nil
+}
+
+func (s *fakeOIDCService) CompleteLogin(ctx context.Context, code, state, binding string) (models.User, services.TokenPair, error) {
+	s.gotBinding = binding
+	if binding != s.binding {
+		return models.User{}, services.TokenPair{}, services.ErrInvalidOIDCState
+	}
+	return models.User{ID: "u1", Email: "ada@example.com", Role: models.RoleViewer}, services.TokenPair{AccessToken: "access"}, nil
+}
+
+func newOIDCRouter(service services.OIDCService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewOIDCHandler(service)
+	router.GET("/api/v1/auth/oidc/login", handler.BeginLogin)
+	router.POST("/api/v1/auth/oidc/callback", handler.CompleteLogin)
+	return router
+}
+
+func bindingCookie(t *testing.T, resp *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, cookie := range resp.Result().Cookies() {
+		if cookie.Name == oidcBindingCookie {
+			return cookie
+		}
+	}
+	t.Fatalf("no %s cookie in %v", oidcBindingCookie, resp.Header())
+	return nil
+}
+
+func TestBeginLoginSetsBindingCookie(t *testing.T) {
+	router := newOIDCRouter(&fakeOIDCService{binding: "b1"})
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		wantSecure bool
+	}{
+		{name: "plain HTTP"},
+		{name: "behind a TLS proxy", header: http.Header{"X-Forwarded-Proto": {"https"}}, wantSecure: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login", nil)
+			for name, values := range tt.header {
+				req.Header[name] = values
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusOK || !strings.Contains(resp.Body.String(), "authorizationUrl") {
+				t.Fatalf("BeginLogin = %d %s", resp.Code, resp.Body)
+			}
+			cookie := bindingCookie(t, resp)
+			if cookie.Value != "b1" || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/api/v1/auth/oidc" || cookie.Secure != tt.wantSecure {
+				t.Errorf("cookie = %+v", cookie)
+			}
+		})
+	}
+}
+
+func TestCompleteLoginRequiresBindingCookie(t *testing.T) {
+	tests := []struct {
+		name       string
+		cookie     *http.Cookie
+		wantStatus int
+	}{
+		{name: "matching cookie", cookie: &http.Cookie{Name: oidcBindingCookie, Value: "b1"}, wantStatus: http.StatusOK},
+		{name: "cookie of another sign-in", cookie: &http.Cookie{Name: oidcBindingCookie, Value: "b2"}, wantStatus: http.StatusBadRequest},
+		{name: "no cookie", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeOIDCService{binding: "b1"}
+			router := newOIDCRouter(service)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/oidc/callback", strings.NewReader(`{"code":"c1","state":"s1"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+
+			if resp.Code != tt.wantStatus {
+				t.Fatalf("CompleteLogin = %d %s, want %d", resp.Code, resp.Body, tt.wantStatus)
+			}
+			if tt.cookie != nil && service.gotBinding != tt.cookie.Value {
+				t.Errorf("service got binding %q, want %q", service.gotBinding, tt.cookie.Value)
+			}
+			// The binding is single use, so the cookie is cleared whatever
+			// the outcome.
+			if cookie := bindingCookie(t, resp); cookie.MaxAge >= 0 || cookie.Value != "" {
+				t.Errorf("cookie = %+v, want it cleared", cookie)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// OIDCLogin is a sign-in started at the OIDC provider and not yet
+// completed. State is sent to the provider and comes back with the code;
+// Nonce must come back in the ID token, and CodeVerifier redeems the code.
+// Binding is held in a cookie by the browser that started the sign-in,
+// so the state cannot complete it in any other browser.
+type OIDCLogin struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+	Binding      string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+// Identity links a user to the subject an OIDC provider knows them by.
+type Identity struct {
+	Issuer  string
+	Subject string
+	UserID  string
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RefreshToken is a stored refresh token. Only a hash of the token is kept.
+// Every token issued by refreshing belongs to the family of the login that
+// started it; UsedAt is set once the token has been rotated and RevokedAt
+// once its family has been revoked.
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
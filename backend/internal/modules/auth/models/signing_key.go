@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// SigningKey is a key access tokens are signed with. PrivateKey is the
+// PKCS #8 DER encoding of the key. A key is published as soon as it is
+// created but signs tokens only from ActivatesAt, so verifiers that cache
+// the key set learn about it before they see tokens signed with it.
+type SigningKey struct {
+	KID         string
+	Algorithm   string
+	PrivateKey  []byte
+	CreatedAt   time.Time
+	ActivatesAt time.Time
+}
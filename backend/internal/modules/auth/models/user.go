@@ -6,6 +6,7 @@ type User struct {
 	Email        string `json:"email"`
 	Role         string `json:"role"`
 	PasswordHash string `json:"-"`
+	TokenVersion int    `json:"-"`
 }
 
 const (
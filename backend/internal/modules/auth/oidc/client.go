@@ -0,0 +1,316 @@
+// Package oidc is a client for the OpenID Connect authorization code flow
+// with PKCE: provider discovery, code exchange and ID token verification.
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrNonceMismatch  = errors.New("id token nonce mismatch")
+)
+
+// idTokenMethods are the algorithms ID tokens are accepted with. HMAC is
+// left out: it would make the client secret a signing key.
+var idTokenMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Client runs the authorization code flow against one provider.
+type Client interface {
+	// AuthCodeURL returns the provider URL the user signs in at. The
+	// provider redirects back to the redirect URL with a code and state.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems code with the PKCE verifier it was requested for,
+	// and returns the verified ID token, which must carry nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*IDToken, error)
+}
+
+// Options configures a Client. Transport defaults to http.DefaultTransport
+// and can be replaced with a testutil.OIDCStandIn to run without a provider.
+type Options struct {
+	// Issuer is the provider's issuer URL; its discovery document is at
+	// Issuer + "/.well-known/openid-configuration".
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Timeout      time.Duration
+	Transport    http.RoundTripper
+}
+
+// IDToken is a verified ID token. Claims holds every claim, including
+// provider-specific ones such as groups.
+type IDToken struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Claims        jwt.MapClaims
+}
+
+// Strings returns a claim holding a string or a list of strings.
+func (t *IDToken) Strings(claim string) []string {
+	switch value := t.Claims[claim].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		out := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// ProviderError is an error response from the provider.
+type ProviderError struct {
+	StatusCode  int
+	Code        string
+	Description string
+}
+
+func (e *ProviderError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oidc provider returned %d: %s: %s", e.StatusCode, e.Code, e.Description)
+	}
+	return fmt.Sprintf("oidc provider returned %d: %s", e.StatusCode, e.Code)
+}
+
+// CodeChallenge returns the S256 PKCE challenge for verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// discovery is the part of the provider metadata the client uses.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type client struct {
+	opts   Options
+	http   *http.Client
+	parser *jwt.Parser
+	keys   *keySet
+
+	mu       sync.Mutex
+	provider *discovery
+}
+
+// NewClient returns a Client for the provider at opts.Issuer. The provider
+// is discovered on first use, so it need not be up when NewClient runs.
+func NewClient(opts Options) (Client, error) {
+	issuer, err := url.Parse(opts.Issuer)
+	if err != nil || issuer.Scheme == "" || issuer.Host == "" {
+		return nil, fmt.Errorf("invalid oidc issuer %q", opts.Issuer)
+	}
+	if opts.ClientID == "" || opts.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc client id and redirect url are required")
+	}
+
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	httpClient := &http.Client{Timeout: opts.Timeout, Transport: transport}
+	return &client{
+		opts: opts,
+		http: httpClient,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(idTokenMethods),
+			jwt.WithIssuer(opts.Issuer),
+			jwt.WithAudience(opts.ClientID),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
+		keys: newKeySet(httpClient),
+	}, nil
+}
+
+func (c *client) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	provider, err := c.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", c.opts.ClientID)
+	query.Set("redirect_uri", c.opts.RedirectURL)
+	query.Set("scope", strings.Join(c.opts.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+func (c *client) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*IDToken, error) {
+	provider, err := c.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {c.opts.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	if c.opts.ClientSecret == "" {
+		form.Set("client_id", c.opts.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.opts.ClientSecret != "" {
+		// client_secret_basic, the method providers must support.
+		req.SetBasicAuth(url.QueryEscape(c.opts.ClientID), url.QueryEscape(c.opts.ClientSecret))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := c.do(req, &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrInvalidIDToken)
+	}
+	return c.verify(ctx, provider, tokens.IDToken, nonce)
+}
+
+func (c *client) verify(ctx context.Context, provider *discovery, raw, nonce string) (*IDToken, error) {
+	claims := jwt.MapClaims{}
+	if _, err := c.parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return c.keys.get(ctx, provider.JWKSURI, kid, t.Method)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	// A token for several audiences must name this client as the party
+	// it was issued to.
+	if audience, _ := claims.GetAudience(); len(audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != c.opts.ClientID {
+			return nil, fmt.Errorf("%w: azp %q is not this client", ErrInvalidIDToken, azp)
+		}
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	token := &IDToken{Issuer: c.opts.Issuer, Subject: subject, Claims: claims}
+	token.Email, _ = claims["email"].(string)
+	token.Name, _ = claims["name"].(string)
+	// Some providers send email_verified as a string.
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		token.EmailVerified = verified
+	case string:
+		token.EmailVerified = verified == "true"
+	}
+	return token, nil
+}
+
+// discover fetches the provider metadata once and keeps it.
+func (c *client) discover(ctx context.Context) (*discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.provider != nil {
+		return c.provider, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.opts.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var provider discovery
+	if err := c.do(req, &provider); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	if provider.Issuer != c.opts.Issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer %q does not match %q", provider.Issuer, c.opts.Issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery: provider metadata lacks an endpoint")
+	}
+	c.provider = &provider
+	return c.provider, nil
+}
+
+func (c *client) do(req *http.Request, out interface{}) error {
+	return doJSON(c.http, req, out)
+}
+
+func doJSON(httpClient *http.Client, req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		providerErr := &ProviderError{StatusCode: resp.StatusCode, Code: truncate(raw, 200)}
+		if json.Unmarshal(raw, &body) == nil && body.Error != "" {
+			providerErr.Code = body.Error
+			providerErr.Description = body.ErrorDescription
+		}
+		return providerErr
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("decode oidc response: %w", err)
+	}
+	return nil
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) > n {
+		return string(b[:n]) + "..."
+	}
+	return string(b)
+}
@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+const (
+	testIssuer      = "https://idp.example.com"
+	testRedirectURL = "https://devoptics.example.com/auth/oidc/callback"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newStandIn() *testutil.OIDCStandIn {
+	return &testutil.OIDCStandIn{
+		Issuer:       testIssuer,
+		ClientID:     "devoptics",
+		ClientSecret: "secret",
+		User:         testutil.OIDCUser{Subject: "u1", Email: "ada@example.com", EmailVerified: true, Name: "Ada", Groups: []string{"admins"}},
+	}
+}
+
+func newStandInClient(t *testing.T, transport http.RoundTripper) Client {
+	t.Helper()
+	client, err := NewClient(Options{
+		Issuer:       testIssuer,
+		ClientID:     "devoptics",
+		ClientSecret: "secret",
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email"},
+		Timeout:      5 * time.Second,
+		Transport:    transport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// authorize signs in at standIn for a sign-in with state, nonce and
+// verifier, and returns the code it redirects back with.
+func authorize(t *testing.T, client Client, standIn *testutil.OIDCStandIn, state, nonce, verifier string) string {
+	t.Helper()
+	authURL, err := client.AuthCodeURL(context.Background(), state, nonce, CodeChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	location, err := standIn.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	redirect, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redirect.Query().Get("state") != state {
+		t.Fatalf("redirect %s does not carry state %q", location, state)
+	}
+	return redirect.Query().Get("code")
+}
+
+func TestExchange(t *testing.T) {
+	standIn := newStandIn()
+	client := newStandInClient(t, standIn)
+
+	code := authorize(t, client, standIn, "state", "nonce", "verifier")
+	token, err := client.Exchange(context.Background(), code, "verifier", "nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if token.Issuer != testIssuer || token.Subject != "u1" || token.Email != "ada@example.com" || !token.EmailVerified || token.Name != "Ada" {
+		t.Errorf("token = %+v", token)
+	}
+	if groups := token.Strings("groups"); len(groups) != 1 || groups[0] != "admins" {
+		t.Errorf("groups = %v", groups)
+	}
+
+	// Codes are single use.
+	var providerErr *ProviderError
+	if _, err := client.Exchange(context.Background(), code, "verifier", "nonce"); !errors.As(err, &providerErr) || providerErr.Code != "invalid_grant" {
+		t.Errorf("second Exchange error = %v, want invalid_grant", err)
+	}
+}
+
+func TestExchangeRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		// exchange redeems a code issued for state, "nonce" and "verifier",
+		// tampering with one side of the flow.
+		exchange func(t *testing.T, standIn *testutil.OIDCStandIn, client Client, code string) error
+		check    func(err error) bool
+	}{
+		{
+			name: "wrong PKCE verifier",
+			exchange: func(t *testing.T, standIn *testutil.OIDCStandIn, client Client, code string) error {
+				_, err := client.Exchange(context.Background(), code, "another-verifier", "nonce")
+				return err
+			},
+			check: func(err error) bool {
+				var providerErr *ProviderError
+				return errors.As(err, &providerErr) && providerErr.StatusCode == http.StatusBadRequest && providerErr.Code == "invalid_grant"
+			},
+		},
+		{
+			name: "nonce mismatch",
+			exchange: func(t *testing.T, standIn *testutil.OIDCStandIn, client Client, code string) error {
+				_, err := client.Exchange(context.Background(), code, "verifier", "another-nonce")
+				return err
+			},
+			check: func(err error) bool { return errors.Is(err, ErrNonceMismatch) },
+		},
+		{
+			// The client discovered the provider already; the ID token is
+			// then minted under another issuer.
+			name: "wrong issuer",
+			exchange: func(t *testing.T, standIn *testutil.OIDCStandIn, client Client, code string) error {
+				standIn.Issuer = "https://evil.example.com"
+				_, err := client.Exchange(context.Background(), code, "verifier", "nonce")
+				return err
+			},
+			check: func(err error) bool { return errors.Is(err, ErrInvalidIDToken) },
+		},
+		{
+			// The provider issues the ID token to another client, as if the
+			// token endpoint answered a different relying party.
+			name: "wrong audience",
+			exchange: func(t *testing.T, standIn *testutil.OIDCStandIn, client Client, code string) error {
+				standIn.ClientID = "another-client"
+				_, err := client.Exchange(context.Background(), code, "verifier", "nonce")
+				return err
+			},
+			check: func(err error) bool { return errors.Is(err, ErrInvalidIDToken) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := newStandIn()
+			// Token requests authenticate as whichever client the stand-in
+			// currently expects, so only the tampering under test differs.
+			client := newStandInClient(t, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path == "/token" {
+					req.SetBasicAuth(url.QueryEscape(standIn.ClientID), url.QueryEscape(standIn.ClientSecret))
+				}
+				return standIn.RoundTrip(req)
+			}))
+			code := authorize(t, client, standIn, "state", "nonce", "verifier")
+
+			if err := tt.exchange(t, standIn, client, code); !tt.check(err) {
+				t.Fatalf("Exchange error = %v", err)
+			}
+		})
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	standIn := newStandIn()
+	standIn.Issuer = "https://other.example.com"
+	client := newStandInClient(t, standIn)
+
+	if _, err := client.AuthCodeURL(context.Background(), "state", "nonce", CodeChallenge("verifier")); err == nil {
+		t.Fatal("AuthCodeURL succeeded against a provider with another issuer")
+	}
+}
@@ -0,0 +1,189 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// keySetRefetchInterval is the least time between fetches of the
+// provider's key set, which is refetched when a token names an unknown key.
+const keySetRefetchInterval = time.Minute
+
+// jwk is a JSON Web Key from the provider's key set.
+type jwk struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+type publicKey struct {
+	algorithm string
+	key       interface{}
+}
+
+// keySet caches the provider's signing keys by kid.
+type keySet struct {
+	http *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+}
+
+func newKeySet(httpClient *http.Client) *keySet {
+	return &keySet{http: httpClient}
+}
+
+// get returns the key with kid for method, fetching the key set if the key
+// is not cached. A token without a kid may use the key set's only key.
+func (s *keySet) get(ctx context.Context, uri, kid string, method jwt.SigningMethod) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.lookup(kid)
+	if !ok && time.Since(s.fetchedAt) >= keySetRefetchInterval {
+		if err := s.fetch(ctx, uri); err != nil {
+			return nil, err
+		}
+		key, ok = s.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if key.algorithm != "" && key.algorithm != method.Alg() {
+		return nil, fmt.Errorf("key %q is for %s, not %s", kid, key.algorithm, method.Alg())
+	}
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok = key.key.(*rsa.PublicKey)
+	case *jwt.SigningMethodECDSA:
+		_, ok = key.key.(*ecdsa.PublicKey)
+	case *jwt.SigningMethodEd25519:
+		_, ok = key.key.(ed25519.PublicKey)
+	default:
+		ok = false
+	}
+	if !ok {
+		return nil, fmt.Errorf("key %q cannot verify %s", kid, method.Alg())
+	}
+	return key.key, nil
+}
+
+func (s *keySet) lookup(kid string) (publicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *keySet) fetch(ctx context.Context, uri string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := doJSON(s.http, req, &set); err != nil {
+		return fmt.Errorf("fetch oidc key set: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped; tokens signed with them
+		// fail as signed by an unknown key.
+		if key, err := k.publicKey(); err == nil {
+			keys[k.KeyID] = publicKey{algorithm: k.Algorithm, key: key}
+		}
+	}
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("rsa exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("ec point is not on %s", k.Curve)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+)
+
+var ErrOIDCLoginNotFound = errors.New("oidc login not found")
+
+// OIDCLoginRepository persists OIDC sign-ins between their start and the
+// provider's callback, so any replica can complete them.
+type OIDCLoginRepository interface {
+	Create(login models.OIDCLogin) error
+	// Consume deletes and returns the login with state, so each state
+	// completes at most one sign-in.
+	Consume(state string) (models.OIDCLogin, error)
+	DeleteExpiredBefore(t time.Time) (int64, error)
+}
+
+type oidcLoginRepository struct {
+	db *sql.DB
+}
+
+func NewOIDCLoginRepository(db *sql.DB) OIDCLoginRepository {
+	return &oidcLoginRepository{db: db}
+}
+
+func (r *oidcLoginRepository) Create(login models.OIDCLogin) error {
+	_, err := r.db.Exec(
+		`INSERT INTO auth_oidc_logins (state, nonce, code_verifier, binding, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		login.State, login.Nonce, login.CodeVerifier, login.Binding, login.ExpiresAt, login.CreatedAt,
+	)
+	return err
+}
+
+func (r *oidcLoginRepository) Consume(state string) (models.OIDCLogin, error) {
+	row := r.db.QueryRow(
+		`DELETE FROM auth_oidc_logins WHERE state = $1
+		 RETURNING state, nonce, code_verifier, binding, expires_at, created_at`,
+		state,
+	)
+
+	var login models.OIDCLogin
+	if err := row.Scan(&login.State, &login.Nonce, &login.CodeVerifier, &login.Binding, &login.ExpiresAt, &login.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OIDCLogin{}, ErrOIDCLoginNotFound
+		}
+		return models.OIDCLogin{}, err
+	}
+	return login, nil
+}
+
+func (r *oidcLoginRepository) DeleteExpiredBefore(t time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM auth_oidc_logins WHERE expires_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+)
+
+var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+
+// RefreshTokenRepository persists hashed refresh tokens.
+type RefreshTokenRepository interface {
+	Create(token models.RefreshToken) error
+	GetByHash(hash string) (models.RefreshToken, error)
+	// Rotate marks the token with usedID as used and stores next in one
+	// transaction. It returns false, storing nothing, if the token was
+	// already used or revoked.
+	Rotate(usedID string, next models.RefreshToken, now time.Time) (bool, error)
+	// RevokeFamily revokes every unrevoked token of a family.
+	RevokeFamily(familyID string, now time.Time) error
+	// RevokeUser revokes every unrevoked token of a user.
+	RevokeUser(userID string, now time.Time) error
+	DeleteExpiredBefore(t time.Time) (int64, error)
+}
+
+type refreshTokenRepository struct {
+	db *sql.DB
+}
+
+func NewRefreshTokenRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(token models.RefreshToken) error {
+	return insertRefreshToken(r.db, token)
+}
+
+func (r *refreshTokenRepository) GetByHash(hash string) (models.RefreshToken, error) {
+	row := r.db.QueryRow(
+		`SELECT id, user_id, family_id, token_hash, expires_at, created_at, used_at, revoked_at
+		 FROM auth_refresh_tokens WHERE token_hash = $1`,
+		hash,
+	)
+
+	var token models.RefreshToken
+	var usedAt, revokedAt sql.NullTime
+	if err := row.Scan(&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt, &usedAt, &revokedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RefreshToken{}, ErrRefreshTokenNotFound
+		}
+		return models.RefreshToken{}, err
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
+
+func (r *refreshTokenRepository) Rotate(usedID string, next models.RefreshToken, now time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// The conditional update makes concurrent refreshes with the same
+	// token race for a single row; only one of them rotates it.
+	result, err := tx.Exec(
+		`UPDATE auth_refresh_tokens SET used_at = $2
+		 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`,
+		usedID, now,
+	)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows == 0 {
+		return false, err
+	}
+
+	if err := insertRefreshToken(tx, next); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *refreshTokenRepository) RevokeFamily(familyID string, now time.Time) error {
+	_, err := r.db.Exec(
+		`UPDATE auth_refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL`,
+		familyID, now,
+	)
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeUser(userID string, now time.Time) error {
+	_, err := r.db.Exec(
+		`UPDATE auth_refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID, now,
+	)
+	return err
+}
+
+func (r *refreshTokenRepository) DeleteExpiredBefore(t time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM auth_refresh_tokens WHERE expires_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertRefreshToken(db execer, token models.RefreshToken) error {
+	_, err := db.Exec(
+		`INSERT INTO auth_refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	return err
+}
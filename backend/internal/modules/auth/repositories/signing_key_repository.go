@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+)
+
+// signingKeyLockKey is the Postgres advisory lock key serialising key
+// creation across replicas.
+const signingKeyLockKey int64 = 0x6175740001
+
+// SigningKeyRepository persists the keys access tokens are signed with.
+type SigningKeyRepository interface {
+	// List returns every stored key, oldest activation first.
+	List() ([]models.SigningKey, error)
+	// CreateUnlessNewer stores key unless another key was created after
+	// since, and reports whether it did. Replicas that decide to rotate at
+	// the same time therefore create a single key between them.
+	CreateUnlessNewer(key models.SigningKey, since time.Time) (bool, error)
+	Delete(kid string) error
+}
+
+type signingKeyRepository struct {
+	db *sql.DB
+}
+
+func NewSigningKeyRepository(db *sql.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+func (r *signingKeyRepository) List() ([]models.SigningKey, error) {
+	rows, err := r.db.Query(
+		`SELECT kid, algorithm, private_key, created_at, activates_at
+		 FROM auth_signing_keys ORDER BY activates_at, created_at`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.SigningKey
+	for rows.Next() {
+		var key models.SigningKey
+		if err := rows.Scan(&key.KID, &key.Algorithm, &key.PrivateKey, &key.CreatedAt, &key.ActivatesAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *signingKeyRepository) CreateUnlessNewer(key models.SigningKey, since time.Time) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, signingKeyLockKey); err != nil {
+		return false, err
+	}
+
+	var newer bool
+	if err := tx.QueryRow(
+		`SELECT EXISTS (SELECT 1 FROM auth_signing_keys WHERE created_at > $1)`,
+		since,
+	).Scan(&newer); err != nil {
+		return false, err
+	}
+	if newer {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO auth_signing_keys (kid, algorithm, private_key, created_at, activates_at)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		key.KID, key.Algorithm, key.PrivateKey, key.CreatedAt, key.ActivatesAt,
+	); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *signingKeyRepository) Delete(kid string) error {
+	_, err := r.db.Exec(`DELETE FROM auth_signing_keys WHERE kid = $1`, kid)
+	return err
+}
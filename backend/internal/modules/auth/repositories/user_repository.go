@@ -15,7 +15,17 @@ type UserRepository interface {
 	GetByEmail(email string) (models.User, error)
 	GetByID(id string) (models.User, error)
 	List() ([]models.User, error)
+	// UpdateRole, UpdatePassword and BumpTokenVersion each increment the
+	// user's token version, invalidating access tokens issued before.
 	UpdateRole(id, role string) error
+	UpdatePassword(id, passwordHash string) error
+	BumpTokenVersion(id string) error
+	GetTokenVersion(id string) (int, error)
+	// GetByIdentity returns the user linked to an OIDC provider subject.
+	GetByIdentity(issuer, subject string) (models.User, error)
+	// CreateWithIdentity creates a user linked to identity.
+	CreateWithIdentity(user models.User, identity models.Identity) (models.User, error)
+	LinkIdentity(identity models.Identity) error
 }
 
 type userRepository struct {
@@ -27,22 +37,17 @@ func NewUserRepository(db *sql.DB) UserRepository {
 }
 
 func (r *userRepository) Create(user models.User) (models.User, error) {
-	_, err := r.db.Exec(
-		`INSERT INTO auth_users (id, full_name, email, password_hash, role)
-		 VALUES ($1, $2, $3, $4, $5)`,
-		user.ID, user.FullName, user.Email, user.PasswordHash, user.Role,
-	)
-	return user, err
+	return user, insertUser(r.db, user)
 }
 
 func (r *userRepository) GetByEmail(email string) (models.User, error) {
 	row := r.db.QueryRow(
-		`SELECT id, full_name, email, password_hash, role FROM auth_users WHERE email = $1`,
+		`SELECT id, full_name, email, password_hash, role, token_version FROM auth_users WHERE email = $1`,
 		email,
 	)
 
 	var user models.User
-	if err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.Role); err != nil {
+	if err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.Role, &user.TokenVersion); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, ErrUserNotFound
 		}
@@ -53,12 +58,12 @@ func (r *userRepository) GetByEmail(email string) (models.User, error) {
 
 func (r *userRepository) GetByID(id string) (models.User, error) {
 	row := r.db.QueryRow(
-		`SELECT id, full_name, email, password_hash, role FROM auth_users WHERE id = $1`,
+		`SELECT id, full_name, email, password_hash, role, token_version FROM auth_users WHERE id = $1`,
 		id,
 	)
 
 	var user models.User
-	if err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.Role); err != nil {
+	if err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.Role, &user.TokenVersion); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.User{}, ErrUserNotFound
 		}
@@ -68,7 +73,7 @@ func (r *userRepository) GetByID(id string) (models.User, error) {
 }
 
 func (r *userRepository) List() ([]models.User, error) {
-	rows, err := r.db.Query(`SELECT id, full_name, email, password_hash, role FROM auth_users ORDER BY created_at DESC`)
+	rows, err := r.db.Query(`SELECT id, full_name, email, password_hash, role, token_version FROM auth_users ORDER BY created_at DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +82,7 @@ func (r *userRepository) List() ([]models.User, error) {
 	var users []models.User
 	for rows.Next() {
 		var user models.User
-		if err := rows.Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.Role); err != nil {
+		if err := rows.Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.Role, &user.TokenVersion); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -86,6 +91,93 @@ func (r *userRepository) List() ([]models.User, error) {
 }
 
 func (r *userRepository) UpdateRole(id, role string) error {
-	_, err := r.db.Exec(`UPDATE auth_users SET role = $1 WHERE id = $2`, role, id)
+	return r.updateUser(`UPDATE auth_users SET role = $2, token_version = token_version + 1 WHERE id = $1`, id, role)
+}
+
+func (r *userRepository) UpdatePassword(id, passwordHash string) error {
+	return r.updateUser(`UPDATE auth_users SET password_hash = $2, token_version = token_version + 1 WHERE id = $1`, id, passwordHash)
+}
+
+func (r *userRepository) BumpTokenVersion(id string) error {
+	return r.updateUser(`UPDATE auth_users SET token_version = token_version + 1 WHERE id = $1`, id)
+}
+
+func (r *userRepository) GetTokenVersion(id string) (int, error) {
+	var version int
+	err := r.db.QueryRow(`SELECT token_version FROM auth_users WHERE id = $1`, id).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrUserNotFound
+	}
+	return version, err
+}
+
+func (r *userRepository) GetByIdentity(issuer, subject string) (models.User, error) {
+	row := r.db.QueryRow(
+		`SELECT u.id, u.full_name, u.email, u.password_hash, u.role, u.token_version
+		 FROM auth_users u JOIN auth_identities i ON i.user_id = u.id
+		 WHERE i.issuer = $1 AND i.subject = $2`,
+		issuer, subject,
+	)
+
+	var user models.User
+	if err := row.Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.Role, &user.TokenVersion); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.User{}, ErrUserNotFound
+		}
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+func (r *userRepository) CreateWithIdentity(user models.User, identity models.Identity) (models.User, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.User{}, err
+	}
+	defer tx.Rollback()
+
+	if err := insertUser(tx, user); err != nil {
+		return models.User{}, err
+	}
+	identity.UserID = user.ID
+	if err := insertIdentity(tx, identity); err != nil {
+		return models.User{}, err
+	}
+	return user, tx.Commit()
+}
+
+func (r *userRepository) LinkIdentity(identity models.Identity) error {
+	return insertIdentity(r.db, identity)
+}
+
+func insertUser(db execer, user models.User) error {
+	_, err := db.Exec(
+		`INSERT INTO auth_users (id, full_name, email, password_hash, role)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		user.ID, user.FullName, user.Email, user.PasswordHash, user.Role,
+	)
 	return err
 }
+
+func insertIdentity(db execer, identity models.Identity) error {
+	_, err := db.Exec(
+		`INSERT INTO auth_identities (issuer, subject, user_id) VALUES ($1, $2, $3)`,
+		identity.Issuer, identity.Subject, identity.UserID,
+	)
+	return err
+}
+
+func (r *userRepository) updateUser(query string, args ...interface{}) error {
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,42 +15,107 @@ import (
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrEmailExists        = errors.New("email already exists")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenRevoked       = errors.New("token revoked")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrIdentityConflict   = errors.New("email belongs to another account")
 )
 
+// ExternalIdentity is a user as an identity provider vouched for them,
+// with the role their groups map to.
+type ExternalIdentity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Role          string
+}
+
 type AuthService interface {
-	SignUp(fullName, email, password, role string) (models.User, string, error)
-	Login(email, password string) (models.User, string, error)
-	ValidateToken(token string) (*jwt.RegisteredClaims, string, error)
+	// SignUp creates a viewer account. Only admins grant other roles,
+	// through UpdateRole.
+	SignUp(fullName, email, password string) (models.User, TokenPair, error)
+	Login(email, password string) (models.User, TokenPair, error)
+	// SignInWithIdentity signs in the user linked to an external identity.
+	// An unlinked identity is linked to the account with its email if the
+	// provider verified the email, or gets a new account otherwise. The
+	// user's role is set to identity.Role.
+	SignInWithIdentity(identity ExternalIdentity) (models.User, TokenPair, error)
+	// Refresh exchanges a refresh token for a new token pair. Each refresh
+	// token can be used once; presenting a used one revokes its family.
+	Refresh(refreshToken string) (TokenPair, error)
+	// Logout revokes the family of a refresh token.
+	Logout(refreshToken string) error
+	// ValidateToken verifies a token's signature, algorithm, expiry, issuer
+	// and audience, and that the user's sessions were not revoked since it
+	// was issued, and returns its claims.
+	ValidateToken(token string) (*Claims, error)
 	ListUsers() ([]models.User, error)
+	// UpdateRole changes a user's role. Access tokens carrying the old role
+	// stop working; refreshing yields tokens with the new one.
 	UpdateRole(id, role string) error
+	// ResetPassword sets a user's password and ends all their sessions.
+	ResetPassword(id, password string) error
+	// RevokeSessions ends all of a user's sessions.
+	RevokeSessions(id string) error
 }
 
 type authService struct {
-	repo      repositories.UserRepository
-	jwtSecret []byte
-}
-
-func NewAuthService(repo repositories.UserRepository, jwtSecret string) AuthService {
-	return &authService{repo: repo, jwtSecret: []byte(jwtSecret)}
+	repo          repositories.UserRepository
+	refreshTokens repositories.RefreshTokenRepository
+	keys          Keys
+	tokens        TokenOptions
+	parser        *jwt.Parser
+	versions      *tokenVersions
 }
 
-func (s *authService) SignUp(fullName, email, password, role string) (models.User, string, error) {
-	if role == "" {
-		role = models.RoleViewer
+// NewAuthService returns an AuthService that signs access tokens with keys
+// and keeps refresh tokens in refreshTokens. Zero durations fall
+// back to 15 minutes for access tokens, 30 days for refresh tokens and 10
+// seconds for the token version cache; an empty issuer or audience is
+// neither set nor checked.
+func NewAuthService(repo repositories.UserRepository, refreshTokens repositories.RefreshTokenRepository, keys Keys, tokens TokenOptions) AuthService {
+	if tokens.TTL <= 0 {
+		tokens.TTL = 15 * time.Minute
 	}
-	if !IsValidRole(role) {
-		return models.User{}, "", errors.New("invalid role")
+	if tokens.RefreshTTL <= 0 {
+		tokens.RefreshTTL = 30 * 24 * time.Hour
+	}
+	if tokens.VersionCacheTTL <= 0 {
+		tokens.VersionCacheTTL = 10 * time.Second
+	}
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(keys.Methods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
 	}
+	if tokens.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(tokens.Issuer))
+	}
+	if tokens.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(tokens.Audience))
+	}
+	return &authService{
+		repo:          repo,
+		refreshTokens: refreshTokens,
+		keys:          keys,
+		tokens:        tokens,
+		parser:        jwt.NewParser(parserOptions...),
+		versions:      newTokenVersions(repo, tokens.VersionCacheTTL),
+	}
+}
 
+func (s *authService) SignUp(fullName, email, password string) (models.User, TokenPair, error) {
 	if _, err := s.repo.GetByEmail(email); err == nil {
-		return models.User{}, "", ErrEmailExists
+		return models.User{}, TokenPair{}, ErrEmailExists
 	} else if !errors.Is(err, repositories.ErrUserNotFound) {
-		return models.User{}, "", err
+		return models.User{}, TokenPair{}, err
 	}
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return models.User{}, "", err
+		return models.User{}, TokenPair{}, err
 	}
 
 	user := models.User{
@@ -57,57 +123,144 @@ func (s *authService) SignUp(fullName, email, password, role string) (models.Use
 		FullName:     fullName,
 		Email:        email,
 		PasswordHash: string(hash),
-		Role:         role,
+		Role:         models.RoleViewer,
 	}
 
 	created, err := s.repo.Create(user)
 	if err != nil {
-		return models.User{}, "", err
+		return models.User{}, TokenPair{}, err
 	}
 
-	token, err := s.issueToken(created)
+	tokens, err := s.issueTokens(created, newID())
 	if err != nil {
-		return models.User{}, "", err
+		return models.User{}, TokenPair{}, err
 	}
 
 	created.PasswordHash = ""
-	return created, token, nil
+	return created, tokens, nil
 }
 
-func (s *authService) Login(email, password string) (models.User, string, error) {
+func (s *authService) Login(email, password string) (models.User, TokenPair, error) {
 	user, err := s.repo.GetByEmail(email)
 	if err != nil {
-		return models.User{}, "", ErrInvalidCredentials
+		return models.User{}, TokenPair{}, ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return models.User{}, "", ErrInvalidCredentials
+		return models.User{}, TokenPair{}, ErrInvalidCredentials
 	}
 
-	token, err := s.issueToken(user)
+	tokens, err := s.issueTokens(user, newID())
 	if err != nil {
-		return models.User{}, "", err
+		return models.User{}, TokenPair{}, err
 	}
 
 	user.PasswordHash = ""
-	return user, token, nil
+	return user, tokens, nil
 }
 
-func (s *authService) ValidateToken(token string) (*jwt.RegisteredClaims, string, error) {
-	parsed, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, func(t *jwt.Token) (interface{}, error) {
-		return s.jwtSecret, nil
-	})
+func (s *authService) SignInWithIdentity(identity ExternalIdentity) (models.User, TokenPair, error) {
+	if !IsValidRole(identity.Role) {
+		return models.User{}, TokenPair{}, ErrInvalidRole
+	}
+
+	user, err := s.userForIdentity(identity)
 	if err != nil {
-		return nil, "", err
+		return models.User{}, TokenPair{}, err
+	}
+
+	// The provider's groups decide the role, so a change there applies at
+	// the next sign-in and revokes tokens carrying the old role.
+	if user.Role != identity.Role {
+		if err := s.UpdateRole(user.ID, identity.Role); err != nil {
+			return models.User{}, TokenPair{}, err
+		}
+		if user, err = s.repo.GetByID(user.ID); err != nil {
+			return models.User{}, TokenPair{}, err
+		}
+	}
+
+	tokens, err := s.issueTokens(user, newID())
+	if err != nil {
+		return models.User{}, TokenPair{}, err
+	}
+
+	user.PasswordHash = ""
+	return user, tokens, nil
+}
+
+func (s *authService) userForIdentity(identity ExternalIdentity) (models.User, error) {
+	user, err := s.repo.GetByIdentity(identity.Issuer, identity.Subject)
+	if err == nil || !errors.Is(err, repositories.ErrUserNotFound) {
+		return user, err
+	}
+
+	link := models.Identity{Issuer: identity.Issuer, Subject: identity.Subject}
+	user, err = s.repo.GetByEmail(identity.Email)
+	switch {
+	case err == nil:
+		// Linking on an unverified email would let anyone who can set
+		// that email at the provider take over the account.
+		if !identity.EmailVerified {
+			return models.User{}, ErrIdentityConflict
+		}
+		link.UserID = user.ID
+		return user, s.repo.LinkIdentity(link)
+	case !errors.Is(err, repositories.ErrUserNotFound):
+		return models.User{}, err
 	}
 
-	claims, ok := parsed.Claims.(*jwt.RegisteredClaims)
-	if !ok || !parsed.Valid {
-		return nil, "", ErrInvalidCredentials
+	fullName := identity.Name
+	if fullName == "" {
+		fullName = identity.Email
+	}
+	// Without a password hash the account can only sign in through the
+	// provider.
+	return s.repo.CreateWithIdentity(models.User{
+		ID:       newID(),
+		FullName: fullName,
+		Email:    identity.Email,
+		Role:     identity.Role,
+	}, link)
+}
+
+func (s *authService) ValidateToken(token string) (*Claims, error) {
+	claims := &Claims{}
+	parsed, err := s.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := s.keys.VerificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		// WithValidMethods accepts the algorithm of any key; each key
+		// verifies only its own, so a public key can never be used as an
+		// HMAC secret.
+		if t.Method != key.Method {
+			return nil, fmt.Errorf("unexpected signing method %v for key %q", t.Header["alg"], kid)
+		}
+		return key.Public, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if !parsed.Valid {
+		return nil, ErrInvalidToken
+	}
+	if claims.Subject == "" || !IsValidRole(claims.Role) {
+		return nil, fmt.Errorf("%w: missing subject or unknown role", ErrInvalidToken)
 	}
 
-	role, _ := parsed.Claims.(jwt.MapClaims)["role"].(string)
-	return claims, role, nil
+	version, err := s.versions.get(claims.Subject)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, ErrTokenRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenVersion != version {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
 }
 
 func (s *authService) ListUsers() ([]models.User, error) {
@@ -124,17 +277,59 @@ func (s *authService) ListUsers() ([]models.User, error) {
 
 func (s *authService) UpdateRole(id, role string) error {
 	if !IsValidRole(role) {
-		return errors.New("invalid role")
+		return ErrInvalidRole
+	}
+	if err := s.repo.UpdateRole(id, role); err != nil {
+		return err
 	}
-	return s.repo.UpdateRole(id, role)
+	s.versions.forget(id)
+	return nil
 }
 
-func (s *authService) issueToken(user models.User) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":   user.ID,
-		"role":  user.Role,
-		"email": user.Email,
-		"exp":   time.Now().Add(24 * time.Hour).Unix(),
+func (s *authService) ResetPassword(id, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(id, string(hash)); err != nil {
+		return err
+	}
+	s.versions.forget(id)
+	return s.refreshTokens.RevokeUser(id, time.Now())
+}
+
+func (s *authService) RevokeSessions(id string) error {
+	if err := s.repo.BumpTokenVersion(id); err != nil {
+		return err
+	}
+	s.versions.forget(id)
+	return s.refreshTokens.RevokeUser(id, time.Now())
+}
+
+func (s *authService) issueAccessToken(user models.User, now time.Time) (string, error) {
+	claims := Claims{
+		Role:         user.Role,
+		Email:        user.Email,
+		TokenVersion: user.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        newID(),
+			Subject:   user.ID,
+			Issuer:    s.tokens.Issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.tokens.TTL)),
+		},
+	}
+	if s.tokens.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.tokens.Audience}
+	}
+	key, err := s.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.KID != "" {
+		token.Header["kid"] = key.KID
 	}
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.jwtSecret)
+	return token.SignedString(key.Private)
 }
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims of an access token. Subject is the user ID and ID
+// (jti) identifies the token itself. TokenVersion is the user's token
+// version at issue; tokens from an older version are revoked.
+type Claims struct {
+	Role         string `json:"role"`
+	Email        string `json:"email"`
+	TokenVersion int    `json:"ver"`
+	jwt.RegisteredClaims
+}
+
+// TokenOptions sets the issuer and audience access tokens are issued for
+// and checked against, and how long access and refresh tokens stay valid.
+// VersionCacheTTL bounds how long a replica trusts a cached token version,
+// and so how long a revocation made through another replica takes to
+// reach it.
+type TokenOptions struct {
+	Issuer          string
+	Audience        string
+	TTL             time.Duration
+	RefreshTTL      time.Duration
+	VersionCacheTTL time.Duration
+}
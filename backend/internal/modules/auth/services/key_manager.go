@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+)
+
+// keySyncInterval is how often a KeyManager reloads, rotates and prunes
+// keys, and so how late a replica may start signing with a new key.
+const keySyncInterval = time.Minute
+
+// keyReloadBackoff is the least time between reloads caused by a token
+// whose kid the manager does not know.
+const keyReloadBackoff = 10 * time.Second
+
+// KeyOptions configures a KeyManager. A new key is created every
+// RotationInterval. It is published at once but signs only after Overlap,
+// and the key it replaces stays published for another Overlap, so Overlap
+// must cover the access token TTL and how long verifiers cache the key set.
+type KeyOptions struct {
+	Algorithm        string
+	RotationInterval time.Duration
+	Overlap          time.Duration
+}
+
+// KeyManager signs access tokens with asymmetric keys kept in Postgres and
+// rotates them on a schedule. Every replica runs one; they share the keys.
+type KeyManager struct {
+	repo   repositories.SigningKeyRepository
+	opts   KeyOptions
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	keys     []managedKey
+	loadedAt time.Time
+}
+
+// managedKey is a parsed key with its schedule.
+type managedKey struct {
+	Key
+	algorithm   string
+	createdAt   time.Time
+	activatesAt time.Time
+}
+
+func NewKeyManager(repo repositories.SigningKeyRepository, opts KeyOptions, logger *zap.Logger) (*KeyManager, error) {
+	if opts.Algorithm != AlgorithmEdDSA && opts.Algorithm != AlgorithmRS256 {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedKeyType, opts.Algorithm)
+	}
+	if opts.RotationInterval <= 0 || opts.Overlap <= 0 {
+		return nil, fmt.Errorf("key rotation interval and overlap must be positive")
+	}
+	return &KeyManager{repo: repo, opts: opts, logger: logger, now: time.Now}, nil
+}
+
+// Start loads the keys, creating the first one if there are none, so the
+// manager can sign as soon as it returns.
+func (m *KeyManager) Start() error {
+	if err := m.sync(m.now()); err != nil {
+		return err
+	}
+	_, err := m.SigningKey()
+	return err
+}
+
+// Run reloads, rotates and prunes keys until ctx is cancelled. Every
+// replica may rotate; the repository lets only one create each new key.
+func (m *KeyManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(keySyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.sync(m.now()); err != nil {
+			m.logger.Warn("failed to sync signing keys", zap.Error(err))
+		}
+	}
+}
+
+func (m *KeyManager) SigningKey() (Key, error) {
+	now := m.now()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	// Keys are ordered by activation, so the last active one is newest.
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if !now.Before(m.keys[i].activatesAt) {
+			return m.keys[i].Key, nil
+		}
+	}
+	return Key{}, ErrNoSigningKey
+}
+
+func (m *KeyManager) VerificationKey(kid string) (Key, error) {
+	if key, ok := m.lookup(kid); ok {
+		return key, nil
+	}
+	if kid == "" {
+		return Key{}, fmt.Errorf("%w: token has no kid", ErrUnknownKey)
+	}
+
+	// Another replica may have created a key this one has not loaded yet.
+	m.mu.RLock()
+	stale := m.now().Sub(m.loadedAt) >= keyReloadBackoff
+	m.mu.RUnlock()
+	if stale {
+		if err := m.reload(); err != nil {
+			return Key{}, err
+		}
+		if key, ok := m.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return Key{}, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+}
+
+func (m *KeyManager) Methods() []string {
+	return []string{AlgorithmEdDSA, AlgorithmRS256}
+}
+
+// JWKS returns every key that is about to sign, signs, or signed tokens
+// that may still be valid.
+func (m *KeyManager) JWKS() JWKS {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	set := JWKS{Keys: make([]JWK, 0, len(m.keys))}
+	for _, key := range m.keys {
+		set.Keys = append(set.Keys, publicJWK(key.Key))
+	}
+	return set
+}
+
+func (m *KeyManager) lookup(kid string) (Key, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, key := range m.keys {
+		if key.KID == kid {
+			return key.Key, true
+		}
+	}
+	return Key{}, false
+}
+
+// sync creates a key if one is due, deletes keys retired for longer than
+// the overlap, and loads the rest.
+func (m *KeyManager) sync(now time.Time) error {
+	keys, err := m.load()
+	if err != nil {
+		return err
+	}
+
+	if m.rotationDue(keys, now) {
+		if err := m.rotate(keys, now); err != nil {
+			return err
+		}
+		if keys, err = m.load(); err != nil {
+			return err
+		}
+	}
+
+	// A key retires when the next one activates.
+	kept := keys[:0]
+	for i, key := range keys {
+		if i+1 < len(keys) && keys[i+1].activatesAt.Add(m.opts.Overlap).Before(now) {
+			if err := m.repo.Delete(key.KID); err != nil {
+				return err
+			}
+			m.logger.Info("deleted retired signing key", zap.String("kid", key.KID))
+			continue
+		}
+		kept = append(kept, key)
+	}
+
+	m.store(kept)
+	return nil
+}
+
+func (m *KeyManager) reload() error {
+	keys, err := m.load()
+	if err != nil {
+		return err
+	}
+	m.store(keys)
+	return nil
+}
+
+func (m *KeyManager) store(keys []managedKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys = keys
+	m.loadedAt = m.now()
+}
+
+func (m *KeyManager) load() ([]managedKey, error) {
+	stored, err := m.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]managedKey, 0, len(stored))
+	for _, s := range stored {
+		key, err := parseKey(s.KID, s.Algorithm, s.PrivateKey)
+		if err != nil {
+			m.logger.Warn("skipping unusable signing key", zap.String("kid", s.KID), zap.Error(err))
+			continue
+		}
+		keys = append(keys, managedKey{Key: key, algorithm: s.Algorithm, createdAt: s.CreatedAt, activatesAt: s.ActivatesAt})
+	}
+	return keys, nil
+}
+
+// rotationDue reports whether a new key is needed: there is none, the
+// newest is older than the rotation interval, or the configured algorithm
+// changed.
+func (m *KeyManager) rotationDue(keys []managedKey, now time.Time) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	newest := keys[len(keys)-1]
+	return newest.algorithm != m.opts.Algorithm || now.Sub(newest.createdAt) >= m.opts.RotationInterval
+}
+
+func (m *KeyManager) rotate(keys []managedKey, now time.Time) error {
+	der, err := generatePrivateKey(m.opts.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	// Without an active key nothing can sign, so there is no one to
+	// warn ahead; the new key activates at once.
+	activatesAt := now
+	var since time.Time
+	for _, key := range keys {
+		if !now.Before(key.activatesAt) {
+			activatesAt = now.Add(m.opts.Overlap)
+		}
+		if key.createdAt.After(since) {
+			since = key.createdAt
+		}
+	}
+
+	key := models.SigningKey{
+		KID:         newID(),
+		Algorithm:   m.opts.Algorithm,
+		PrivateKey:  der,
+		CreatedAt:   now,
+		ActivatesAt: activatesAt,
+	}
+	created, err := m.repo.CreateUnlessNewer(key, since)
+	if err != nil {
+		return err
+	}
+	if created {
+		m.logger.Info("created signing key",
+			zap.String("kid", key.KID),
+			zap.String("algorithm", key.Algorithm),
+			zap.Time("activatesAt", key.ActivatesAt),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// testClock is a settable clock for KeyManager.now.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func (c *testClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestKeyManager(t *testing.T, repo *memSigningKeys, clock *testClock, algorithm string) *KeyManager {
+	t.Helper()
+	m, err := NewKeyManager(repo, KeyOptions{Algorithm: algorithm, RotationInterval: 24 * time.Hour, Overlap: time.Hour}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.now = clock.Now
+	return m
+}
+
+func jwksKIDs(m *KeyManager) []string {
+	var kids []string
+	for _, key := range m.JWKS().Keys {
+		kids = append(kids, key.KeyID)
+	}
+	return kids
+}
+
+func signingKID(t *testing.T, m *KeyManager) string {
+	t.Helper()
+	key, err := m.SigningKey()
+	if err != nil {
+		t.Fatalf("SigningKey: %v", err)
+	}
+	return key.KID
+}
+
+func TestKeyManagerRotation(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	repo := &memSigningKeys{}
+	m := newTestKeyManager(t, repo, clock, AlgorithmEdDSA)
+
+	// The first key signs at once; there is nothing to overlap with.
+	if err := m.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	first := signingKID(t, m)
+
+	// Syncing before the rotation interval leaves the key alone.
+	clock.Advance(23 * time.Hour)
+	if err := m.sync(clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if kids := jwksKIDs(m); len(kids) != 1 || kids[0] != first {
+		t.Fatalf("JWKS before rotation = %v, want only %s", kids, first)
+	}
+
+	// At the interval a new key is published but does not sign yet.
+	clock.Advance(time.Hour)
+	if err := m.sync(clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	kids := jwksKIDs(m)
+	if len(kids) != 2 || kids[0] != first {
+		t.Fatalf("JWKS after rotation = %v, want %s and a new key", kids, first)
+	}
+	second := kids[1]
+	if got := signingKID(t, m); got != first {
+		t.Errorf("signing with %s before the new key activates, want %s", got, first)
+	}
+
+	// Once the overlap passes the new key signs, and the old one still
+	// verifies tokens it signed.
+	clock.Advance(time.Hour)
+	if got := signingKID(t, m); got != second {
+		t.Errorf("signing with %s after activation, want %s", got, second)
+	}
+	if _, err := m.VerificationKey(first); err != nil {
+		t.Errorf("old key within the overlap: %v", err)
+	}
+
+	// A further overlap later the old key retires: it is deleted, dropped
+	// from the key set and no longer verifies.
+	clock.Advance(time.Hour + time.Second)
+	if err := m.sync(clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if kids := jwksKIDs(m); len(kids) != 1 || kids[0] != second {
+		t.Errorf("JWKS after retirement = %v, want only %s", kids, second)
+	}
+	if _, err := m.VerificationKey(first); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("retired key: err = %v, want ErrUnknownKey", err)
+	}
+	if stored, _ := repo.List(); len(stored) != 1 || stored[0].KID != second {
+		t.Errorf("stored keys = %+v, want only %s", stored, second)
+	}
+}
+
+func TestKeyManagerVerifiesAcrossRotation(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m := newTestKeyManager(t, &memSigningKeys{}, clock, AlgorithmEdDSA)
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func() string {
+		key, err := m.SigningKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		token := jwt.NewWithClaims(key.Method, jwt.MapClaims{"sub": "u1"})
+		token.Header["kid"] = key.KID
+		signed, err := token.SignedString(key.Private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	verify := func(raw string) error {
+		_, err := jwt.Parse(raw, func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			key, err := m.VerificationKey(kid)
+			if err != nil {
+				return nil, err
+			}
+			return key.Public, nil
+		}, jwt.WithValidMethods(m.Methods()))
+		return err
+	}
+
+	clock.Advance(24 * time.Hour)
+	if err := m.sync(clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// Signed with the old key just before the new one activates.
+	old := sign()
+
+	clock.Advance(90 * time.Minute)
+	if err := m.sync(clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(old); err != nil {
+		t.Errorf("token of the previous key within the overlap: %v", err)
+	}
+	if err := verify(sign()); err != nil {
+		t.Errorf("token of the new key: %v", err)
+	}
+
+	clock.Advance(31 * time.Minute)
+	if err := m.sync(clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(old); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("token of a retired key: err = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestKeyManagerReplicas(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	repo := &memSigningKeys{}
+	a := newTestKeyManager(t, repo, clock, AlgorithmEdDSA)
+	b := newTestKeyManager(t, repo, clock, AlgorithmEdDSA)
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.keys) != 1 || signingKID(t, a) != signingKID(t, b) {
+		t.Fatalf("replicas starting together made keys %+v", repo.keys)
+	}
+
+	// Both replicas decide to rotate from the same view of the keys; the
+	// repository lets only the first create a key.
+	clock.Advance(24 * time.Hour)
+	seen, err := a.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.rotate(seen, clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.rotate(seen, clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.keys) != 2 {
+		t.Fatalf("racing rotations stored %d keys, want 2", len(repo.keys))
+	}
+	created := repo.keys[1].KID
+
+	// A replica that has not synced yet loads the new key when it first
+	// sees a token signed with it, but not more often than the backoff.
+	b.mu.Lock()
+	b.loadedAt = clock.Now()
+	b.mu.Unlock()
+	if _, err := b.VerificationKey(created); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("unknown kid within the reload backoff: err = %v, want ErrUnknownKey", err)
+	}
+	clock.Advance(keyReloadBackoff)
+	if key, err := b.VerificationKey(created); err != nil || key.KID != created {
+		t.Errorf("unknown kid after the backoff = %+v, %v; want the new key", key, err)
+	}
+}
+
+func TestKeyManagerJWKS(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	repo := &memSigningKeys{}
+	ed := newTestKeyManager(t, repo, clock, AlgorithmEdDSA)
+	if err := ed.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing the algorithm rotates at once, whatever the interval.
+	clock.Advance(time.Minute)
+	rs := newTestKeyManager(t, repo, clock, AlgorithmRS256)
+	if err := rs.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	set := rs.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("JWKS = %+v, want the Ed25519 and the RSA key", set)
+	}
+	for i, want := range []struct{ kty, alg string }{
+		{kty: "OKP", alg: AlgorithmEdDSA},
+		{kty: "RSA", alg: AlgorithmRS256},
+	} {
+		jwk := set.Keys[i]
+		if jwk.KeyType != want.kty || jwk.Algorithm != want.alg || jwk.Use != "sig" || jwk.KeyID != repo.keys[i].KID {
+			t.Errorf("key %d = %+v, want a %s %s signing key", i, jwk, want.kty, want.alg)
+		}
+		key, err := rs.VerificationKey(jwk.KeyID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch public := key.Public.(type) {
+		case ed25519.PublicKey:
+			if jwk.Curve != "Ed25519" || jwk.X == "" || jwk.N != "" {
+				t.Errorf("Ed25519 key %+v", jwk)
+			}
+		case *rsa.PublicKey:
+			if jwk.N == "" || jwk.E != "AQAB" || jwk.X != "" || public.E != 65537 {
+				t.Errorf("RSA key %+v", jwk)
+			}
+		}
+	}
+}
+
+func TestNewKeyManagerRejects(t *testing.T) {
+	for name, opts := range map[string]KeyOptions{
+		"HMAC algorithm":   {Algorithm: "HS256", RotationInterval: time.Hour, Overlap: time.Hour},
+		"no interval":      {Algorithm: AlgorithmEdDSA, Overlap: time.Hour},
+		"negative overlap": {Algorithm: AlgorithmEdDSA, RotationInterval: time.Hour, Overlap: -time.Hour},
+	} {
+		if _, err := NewKeyManager(&memSigningKeys{}, opts, zap.NewNop()); err == nil {
+			t.Errorf("%s: NewKeyManager succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownKey         = errors.New("unknown signing key")
+	ErrNoSigningKey       = errors.New("no active signing key")
+	ErrUnsupportedKeyType = errors.New("unsupported signing algorithm")
+)
+
+// Asymmetric signing algorithms a KeyManager can rotate keys for.
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+)
+
+// rsaKeyBits is the size of generated RS256 keys.
+const rsaKeyBits = 2048
+
+// Key is a key access tokens are signed or verified with. KID is empty for
+// an HMAC secret, which is never published and signs without a kid header.
+type Key struct {
+	KID     string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+// Keys holds the keys access tokens are signed and verified with.
+type Keys interface {
+	// SigningKey returns the key new tokens are signed with.
+	SigningKey() (Key, error)
+	// VerificationKey returns the key identified by a token's kid header.
+	VerificationKey(kid string) (Key, error)
+	// Methods lists the algorithms tokens may be signed with.
+	Methods() []string
+	// JWKS returns the public keys other services verify tokens with.
+	JWKS() JWKS
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is the public half of a signing key. Ed25519 keys set Curve and X
+// (RFC 8037); RSA keys set N and E (RFC 7518).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type secretKeys struct {
+	key Key
+}
+
+// NewSecretKeys returns Keys that sign and verify HS256 tokens with a
+// single shared secret. Nothing is published in the key set, so only
+// holders of the secret can verify tokens.
+func NewSecretKeys(secret string) Keys {
+	return &secretKeys{key: Key{
+		Method:  jwt.SigningMethodHS256,
+		Private: []byte(secret),
+		Public:  []byte(secret),
+	}}
+}
+
+func (k *secretKeys) SigningKey() (Key, error) {
+	return k.key, nil
+}
+
+func (k *secretKeys) VerificationKey(kid string) (Key, error) {
+	if kid != "" {
+		return Key{}, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+	return k.key, nil
+}
+
+func (k *secretKeys) Methods() []string {
+	return []string{jwt.SigningMethodHS256.Alg()}
+}
+
+func (k *secretKeys) JWKS() JWKS {
+	return JWKS{Keys: []JWK{}}
+}
+
+// generatePrivateKey returns a new key for algorithm in PKCS #8 DER form.
+func generatePrivateKey(algorithm string) ([]byte, error) {
+	var private interface{}
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case AlgorithmRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedKeyType, algorithm)
+	}
+	return x509.MarshalPKCS8PrivateKey(private)
+}
+
+// parseKey decodes a stored PKCS #8 key and checks it suits algorithm.
+func parseKey(kid, algorithm string, der []byte) (Key, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return Key{}, err
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return Key{}, fmt.Errorf("key %q is not a signing key", kid)
+	}
+
+	switch signer.(type) {
+	case ed25519.PrivateKey:
+		if algorithm != AlgorithmEdDSA {
+			return Key{}, fmt.Errorf("key %q is an Ed25519 key, not %s", kid, algorithm)
+		}
+	case *rsa.PrivateKey:
+		if algorithm != AlgorithmRS256 {
+			return Key{}, fmt.Errorf("key %q is an RSA key, not %s", kid, algorithm)
+		}
+	default:
+		return Key{}, fmt.Errorf("%w: key %q has type %T", ErrUnsupportedKeyType, kid, signer)
+	}
+
+	return Key{
+		KID:     kid,
+		Method:  jwt.GetSigningMethod(algorithm),
+		Private: signer,
+		Public:  signer.Public(),
+	}, nil
+}
+
+// publicJWK returns the JWK of an asymmetric key.
+func publicJWK(key Key) JWK {
+	jwk := JWK{Use: "sig", Algorithm: key.Method.Alg(), KeyID: key.KID}
+	switch public := key.Public.(type) {
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	}
+	return jwk
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/oidc"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+)
+
+var (
+	ErrOIDCNotConfigured = errors.New("oidc sign-in is not configured")
+	ErrInvalidOIDCState  = errors.New("unknown or expired sign-in state")
+	ErrOIDCRejected      = errors.New("identity provider sign-in rejected")
+	ErrOIDCProvider      = errors.New("identity provider unavailable")
+	ErrNoMappedRole      = errors.New("no group grants access")
+	ErrMissingEmail      = errors.New("identity provider sent no email")
+)
+
+// OIDCService signs users in through an OpenID Connect provider with the
+// authorization code flow and PKCE.
+type OIDCService interface {
+	// BeginLogin starts a sign-in and returns the provider URL to send the
+	// user to, and the binding the user's browser must keep to complete it.
+	BeginLogin(ctx context.Context) (authURL, binding string, err error)
+	// CompleteLogin finishes the sign-in with the code and state the
+	// provider redirected back with, provisioning the user on first
+	// sign-in. binding must be the one BeginLogin returned for state, so
+	// a state lured into another browser completes nothing there.
+	CompleteLogin(ctx context.Context, code, state, binding string) (models.User, TokenPair, error)
+}
+
+// RoleMapping grants Role to members of Group.
+type RoleMapping struct {
+	Group string
+	Role  string
+}
+
+// OIDCOptions configures how provider groups become roles. GroupsClaim
+// names the ID token claim listing the user's groups. A user gets the
+// most privileged role any of their groups maps to, or DefaultRole if none
+// does; with no DefaultRole they are refused. LoginTTL bounds how long a
+// started sign-in can be completed.
+type OIDCOptions struct {
+	GroupsClaim  string
+	RoleMappings []RoleMapping
+	DefaultRole  string
+	LoginTTL     time.Duration
+}
+
+type oidcService struct {
+	client oidc.Client
+	logins repositories.OIDCLoginRepository
+	auth   AuthService
+	opts   OIDCOptions
+}
+
+// NewOIDCService returns an OIDCService that signs users in through client
+// and issues their tokens with auth. A nil client disables OIDC sign-in.
+// LoginTTL defaults to 10 minutes and GroupsClaim to "groups".
+func NewOIDCService(client oidc.Client, logins repositories.OIDCLoginRepository, auth AuthService, opts OIDCOptions) (OIDCService, error) {
+	for _, mapping := range opts.RoleMappings {
+		if mapping.Group == "" || !IsValidRole(mapping.Role) {
+			return nil, fmt.Errorf("%w: mapping %q to %q", ErrInvalidRole, mapping.Group, mapping.Role)
+		}
+	}
+	if opts.DefaultRole != "" && !IsValidRole(opts.DefaultRole) {
+		return nil, fmt.Errorf("%w: default role %q", ErrInvalidRole, opts.DefaultRole)
+	}
+	if opts.GroupsClaim == "" {
+		opts.GroupsClaim = "groups"
+	}
+	if opts.LoginTTL <= 0 {
+		opts.LoginTTL = 10 * time.Minute
+	}
+	return &oidcService{client: client, logins: logins, auth: auth, opts: opts}, nil
+}
+
+func (s *oidcService) BeginLogin(ctx context.Context) (string, string, error) {
+	if s.client == nil {
+		return "", "", ErrOIDCNotConfigured
+	}
+
+	now := time.Now()
+	// Abandoned sign-ins are cleared here rather than by a pruner; the
+	// delete is cheap and sign-ins are rare.
+	if _, err := s.logins.DeleteExpiredBefore(now); err != nil {
+		return "", "", err
+	}
+
+	login, err := newOIDCLogin(now, s.opts.LoginTTL)
+	if err != nil {
+		return "", "", err
+	}
+	authURL, err := s.client.AuthCodeURL(ctx, login.State, login.Nonce, oidc.CodeChallenge(login.CodeVerifier))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %v", ErrOIDCProvider, err)
+	}
+	if err := s.logins.Create(login); err != nil {
+		return "", "", err
+	}
+	return authURL, login.Binding, nil
+}
+
+func (s *oidcService) CompleteLogin(ctx context.Context, code, state, binding string) (models.User, TokenPair, error) {
+	if s.client == nil {
+		return models.User{}, TokenPair{}, ErrOIDCNotConfigured
+	}
+
+	login, err := s.logins.Consume(state)
+	if errors.Is(err, repositories.ErrOIDCLoginNotFound) {
+		return models.User{}, TokenPair{}, ErrInvalidOIDCState
+	}
+	if err != nil {
+		return models.User{}, TokenPair{}, err
+	}
+	if time.Now().After(login.ExpiresAt) {
+		return models.User{}, TokenPair{}, ErrInvalidOIDCState
+	}
+	// The login is consumed either way, so a state that reached the wrong
+	// browser cannot be retried from the right one.
+	if binding == "" || subtle.ConstantTimeCompare([]byte(binding), []byte(login.Binding)) != 1 {
+		return models.User{}, TokenPair{}, ErrInvalidOIDCState
+	}
+
+	idToken, err := s.client.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		var providerErr *oidc.ProviderError
+		if errors.Is(err, oidc.ErrInvalidIDToken) || errors.Is(err, oidc.ErrNonceMismatch) ||
+			(errors.As(err, &providerErr) && providerErr.StatusCode < 500) {
+			return models.User{}, TokenPair{}, fmt.Errorf("%w: %v", ErrOIDCRejected, err)
+		}
+		return models.User{}, TokenPair{}, fmt.Errorf("%w: %v", ErrOIDCProvider, err)
+	}
+	if idToken.Email == "" {
+		return models.User{}, TokenPair{}, ErrMissingEmail
+	}
+
+	role := s.role(idToken.Strings(s.opts.GroupsClaim))
+	if role == "" {
+		return models.User{}, TokenPair{}, ErrNoMappedRole
+	}
+
+	return s.auth.SignInWithIdentity(ExternalIdentity{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         idToken.Email,
+		EmailVerified: idToken.EmailVerified,
+		Name:          idToken.Name,
+		Role:          role,
+	})
+}
+
+// role returns the most privileged role groups map to, or the default.
+func (s *oidcService) role(groups []string) string {
+	role := s.opts.DefaultRole
+	for _, group := range groups {
+		for _, mapping := range s.opts.RoleMappings {
+			if mapping.Group == group && roleRank[mapping.Role] > roleRank[role] {
+				role = mapping.Role
+			}
+		}
+	}
+	return role
+}
+
+// newOIDCLogin returns a sign-in with fresh random secrets.
+func newOIDCLogin(now time.Time, ttl time.Duration) (models.OIDCLogin, error) {
+	login := models.OIDCLogin{ExpiresAt: now.Add(ttl), CreatedAt: now}
+	for _, field := range []*string{&login.State, &login.Nonce, &login.CodeVerifier, &login.Binding} {
+		token, err := randomToken()
+		if err != nil {
+			return models.OIDCLogin{}, err
+		}
+		*field = token
+	}
+	return login, nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/oidc"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+// memOIDCLogins is an in-memory OIDCLoginRepository keyed by state.
+type memOIDCLogins map[string]models.OIDCLogin
+
+func (r memOIDCLogins) Create(login models.OIDCLogin) error {
+	r[login.State] = login
+	return nil
+}
+
+func (r memOIDCLogins) Consume(state string) (models.OIDCLogin, error) {
+	login, ok := r[state]
+	if !ok {
+		return models.OIDCLogin{}, repositories.ErrOIDCLoginNotFound
+	}
+	delete(r, state)
+	return login, nil
+}
+
+func (r memOIDCLogins) DeleteExpiredBefore(t time.Time) (int64, error) {
+	var deleted int64
+	for state, login := range r {
+		if login.ExpiresAt.Before(t) {
+			delete(r, state)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// memRefreshTokens is an in-memory RefreshTokenRepository keyed by hash.
+type memRefreshTokens map[string]models.RefreshToken
+
+func (r memRefreshTokens) Create(token models.RefreshToken) error {
+	r[token.TokenHash] = token
+	return nil
+}
+
+func (r memRefreshTokens) GetByHash(hash string) (models.RefreshToken, error) {
+	token, ok := r[hash]
+	if !ok {
+		return models.RefreshToken{}, repositories.ErrRefreshTokenNotFound
+	}
+	return token, nil
+}
+
+func (r memRefreshTokens) Rotate(usedID string, next models.RefreshToken, now time.Time) (bool, error) {
+	for hash, token := range r {
+		if token.ID == usedID {
+			if token.UsedAt != nil || token.RevokedAt != nil {
+				return false, nil
+			}
+			token.UsedAt = &now
+			r[hash] = token
+			r[next.TokenHash] = next
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memRefreshTokens) RevokeFamily(familyID string, now time.Time) error {
+	return r.revoke(func(token models.RefreshToken) bool { return token.FamilyID == familyID }, now)
+}
+
+func (r memRefreshTokens) RevokeUser(userID string, now time.Time) error {
+	return r.revoke(func(token models.RefreshToken) bool { return token.UserID == userID }, now)
+}
+
+func (r memRefreshTokens) DeleteExpiredBefore(t time.Time) (int64, error) {
+	var deleted int64
+	for hash, token := range r {
+		if token.ExpiresAt.Before(t) {
+			delete(r, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r memRefreshTokens) revoke(match func(models.RefreshToken) bool, now time.Time) error {
+	for hash, token := range r {
+		if match(token) && token.RevokedAt == nil {
+			token.RevokedAt = &now
+			r[hash] = token
+		}
+	}
+	return nil
+}
+
+var testRoleMappings = []RoleMapping{
+	{Group: "admins", Role: models.RoleAdmin},
+	{Group: "sre", Role: models.RoleManager},
+	{Group: "data", Role: models.RoleAnalyst},
+}
+
+func newTestStandIn(groups ...string) *testutil.OIDCStandIn {
+	return &testutil.OIDCStandIn{
+		Issuer:       "https://idp.example.com",
+		ClientID:     "devoptics",
+		ClientSecret: "secret",
+		User:         testutil.OIDCUser{Subject: "idp-ada", Email: "ada@example.com", EmailVerified: true, Name: "Ada", Groups: groups},
+	}
+}
+
+func newTestOIDCService(t *testing.T, standIn *testutil.OIDCStandIn, opts OIDCOptions) (OIDCService, memUsers) {
+	t.Helper()
+	client, err := oidc.NewClient(oidc.Options{
+		Issuer:       standIn.Issuer,
+		ClientID:     standIn.ClientID,
+		ClientSecret: standIn.ClientSecret,
+		RedirectURL:  "https://devoptics.example.com/auth/oidc/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+		Timeout:      5 * time.Second,
+		Transport:    standIn,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := memUsers{}
+	auth := NewAuthService(users, memRefreshTokens{}, NewSecretKeys("secret"), TokenOptions{})
+	service, err := NewOIDCService(client, memOIDCLogins{}, auth, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, users
+}
+
+// oidcSignIn is a sign-in started by BeginLogin and approved at the
+// provider, ready to complete.
+type oidcSignIn struct {
+	code    string
+	state   string
+	binding string
+}
+
+func beginSignIn(t *testing.T, service OIDCService, standIn *testutil.OIDCStandIn) oidcSignIn {
+	t.Helper()
+	authURL, binding, err := service.BeginLogin(context.Background())
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	if binding == "" {
+		t.Fatal("BeginLogin returned no binding")
+	}
+	location, err := standIn.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	redirect, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return oidcSignIn{code: redirect.Query().Get("code"), state: redirect.Query().Get("state"), binding: binding}
+}
+
+func TestCompleteLoginRoles(t *testing.T) {
+	tests := []struct {
+		name        string
+		groups      []string
+		defaultRole string
+		wantRole    string
+		wantErr     error
+	}{
+		{name: "mapped group", groups: []string{"data"}, wantRole: models.RoleAnalyst},
+		{name: "most privileged group wins", groups: []string{"data", "admins", "sre"}, wantRole: models.RoleAdmin},
+		{name: "unmapped groups get the default", groups: []string{"marketing"}, defaultRole: models.RoleViewer, wantRole: models.RoleViewer},
+		{name: "mapping outranks the default", groups: []string{"sre"}, defaultRole: models.RoleViewer, wantRole: models.RoleManager},
+		{name: "no mapped group and no default", groups: []string{"marketing"}, wantErr: ErrNoMappedRole},
+		{name: "no groups", wantErr: ErrNoMappedRole},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := newTestStandIn(tt.groups...)
+			service, users := newTestOIDCService(t, standIn, OIDCOptions{RoleMappings: testRoleMappings, DefaultRole: tt.defaultRole})
+
+			signIn := beginSignIn(t, service, standIn)
+			user, tokens, err := service.CompleteLogin(context.Background(), signIn.code, signIn.state, signIn.binding)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CompleteLogin error = %v, want %v", err, tt.wantErr)
+				}
+				if len(users) != 0 {
+					t.Errorf("created %v for a refused sign-in", users)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompleteLogin: %v", err)
+			}
+			if user.Email != "ada@example.com" || user.FullName != "Ada" || user.Role != tt.wantRole {
+				t.Errorf("user = %+v, want ada@example.com as %s", user, tt.wantRole)
+			}
+			if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+				t.Errorf("tokens = %+v", tokens)
+			}
+			if stored, ok := users[user.ID]; !ok || stored.Role != tt.wantRole {
+				t.Errorf("stored user = %+v", stored)
+			}
+		})
+	}
+}
+
+func TestCompleteLoginRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		// complete finishes signIn, tampering with it, after another
+		// sign-in, other, was started in a second browser.
+		complete func(service OIDCService, signIn, other oidcSignIn) error
+		wantErr  error
+	}{
+		{
+			name: "missing binding",
+			complete: func(service OIDCService, signIn, other oidcSignIn) error {
+				_, _, err := service.CompleteLogin(context.Background(), signIn.code, signIn.state, "")
+				return err
+			},
+			wantErr: ErrInvalidOIDCState,
+		},
+		{
+			// Login CSRF: the attacker's state and code, replayed in a
+			// victim's browser that holds its own binding.
+			name: "binding of another browser",
+			complete: func(service OIDCService, signIn, other oidcSignIn) error {
+				_, _, err := service.CompleteLogin(context.Background(), signIn.code, signIn.state, other.binding)
+				return err
+			},
+			wantErr: ErrInvalidOIDCState,
+		},
+		{
+			name: "unknown state",
+			complete: func(service OIDCService, signIn, other oidcSignIn) error {
+				_, _, err := service.CompleteLogin(context.Background(), signIn.code, "forged", signIn.binding)
+				return err
+			},
+			wantErr: ErrInvalidOIDCState,
+		},
+		{
+			name: "state reused",
+			complete: func(service OIDCService, signIn, other oidcSignIn) error {
+				if _, _, err := service.CompleteLogin(context.Background(), signIn.code, signIn.state, signIn.binding); err != nil {
+					return err
+				}
+				_, _, err := service.CompleteLogin(context.Background(), signIn.code, signIn.state, signIn.binding)
+				return err
+			},
+			wantErr: ErrInvalidOIDCState,
+		},
+		{
+			// A code injected into another sign-in fails PKCE at the
+			// provider, since it was requested for the other verifier.
+			name: "code of another sign-in",
+			complete: func(service OIDCService, signIn, other oidcSignIn) error {
+				_, _, err := service.CompleteLogin(context.Background(), signIn.code, other.state, other.binding)
+				return err
+			},
+			wantErr: ErrOIDCRejected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := newTestStandIn("admins")
+			service, _ := newTestOIDCService(t, standIn, OIDCOptions{RoleMappings: testRoleMappings})
+			signIn := beginSignIn(t, service, standIn)
+			other := beginSignIn(t, service, standIn)
+
+			if err := tt.complete(service, signIn, other); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompleteLogin error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompleteLoginExpired(t *testing.T) {
+	standIn := newTestStandIn("admins")
+	service, _ := newTestOIDCService(t, standIn, OIDCOptions{RoleMappings: testRoleMappings, LoginTTL: time.Millisecond})
+
+	signIn := beginSignIn(t, service, standIn)
+	time.Sleep(5 * time.Millisecond)
+	if _, _, err := service.CompleteLogin(context.Background(), signIn.code, signIn.state, signIn.binding); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("CompleteLogin error = %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestOIDCNotConfigured(t *testing.T) {
+	service, err := NewOIDCService(nil, memOIDCLogins{}, nil, OIDCOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.BeginLogin(context.Background()); !errors.Is(err, ErrOIDCNotConfigured) {
+		t.Errorf("BeginLogin error = %v", err)
+	}
+	if _, _, err := service.CompleteLogin(context.Background(), "code", "state", "binding"); !errors.Is(err, ErrOIDCNotConfigured) {
+		t.Errorf("CompleteLogin error = %v", err)
+	}
+}
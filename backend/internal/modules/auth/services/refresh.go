@@ -0,0 +1,145 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+)
+
+// TokenPair is a short-lived access token and the opaque refresh token that
+// renews it.
+type TokenPair struct {
+	AccessToken           string    `json:"token"`
+	AccessTokenExpiresAt  time.Time `json:"expiresAt"`
+	RefreshToken          string    `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time `json:"refreshTokenExpiresAt"`
+}
+
+func (s *authService) Refresh(refreshToken string) (TokenPair, error) {
+	stored, err := s.refreshTokens.GetByHash(hashRefreshToken(refreshToken))
+	if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := time.Now()
+	if stored.RevokedAt != nil || !now.Before(stored.ExpiresAt) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if stored.UsedAt != nil {
+		return TokenPair{}, s.revokeReused(stored.FamilyID, now)
+	}
+
+	user, err := s.repo.GetByID(stored.UserID)
+	if errors.Is(err, repositories.ErrUserNotFound) {
+		return TokenPair{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	pair, next, err := s.newTokenPair(user, stored.FamilyID, now)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	rotated, err := s.refreshTokens.Rotate(stored.ID, next, now)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if !rotated {
+		// Another request rotated or revoked the token since it was read.
+		return TokenPair{}, s.revokeReused(stored.FamilyID, now)
+	}
+	return pair, nil
+}
+
+func (s *authService) Logout(refreshToken string) error {
+	stored, err := s.refreshTokens.GetByHash(hashRefreshToken(refreshToken))
+	if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+		return ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return err
+	}
+	return s.refreshTokens.RevokeFamily(stored.FamilyID, time.Now())
+}
+
+// revokeReused revokes a family whose used refresh token was presented
+// again. Either the client or someone holding a stolen token is replaying
+// it, and there is no telling which, so every session of the family ends.
+func (s *authService) revokeReused(familyID string, now time.Time) error {
+	if err := s.refreshTokens.RevokeFamily(familyID, now); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens issues a token pair and stores its refresh token in family.
+func (s *authService) issueTokens(user models.User, familyID string) (TokenPair, error) {
+	pair, stored, err := s.newTokenPair(user, familyID, time.Now())
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if err := s.refreshTokens.Create(stored); err != nil {
+		return TokenPair{}, err
+	}
+	return pair, nil
+}
+
+// newTokenPair returns a token pair and the refresh token to store for it.
+func (s *authService) newTokenPair(user models.User, familyID string, now time.Time) (TokenPair, models.RefreshToken, error) {
+	access, err := s.issueAccessToken(user, now)
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+	refresh, err := randomToken()
+	if err != nil {
+		return TokenPair{}, models.RefreshToken{}, err
+	}
+
+	stored := models.RefreshToken{
+		ID:        newID(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(refresh),
+		ExpiresAt: now.Add(s.tokens.RefreshTTL),
+		CreatedAt: now,
+	}
+	return TokenPair{
+		AccessToken:           access,
+		AccessTokenExpiresAt:  now.Add(s.tokens.TTL),
+		RefreshToken:          refresh,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
+	}, stored, nil
+}
+
+// randomToken returns 256 random bits, URL-safe encoded. It makes refresh
+// tokens and OIDC state, nonces, PKCE verifiers and browser bindings.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashRefreshToken is the stored form of a refresh token. The token has
+// full entropy, so an unsalted SHA-256 is enough to make a leaked table
+// useless.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
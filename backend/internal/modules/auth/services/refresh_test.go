@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+)
+
+// staleReads is a RefreshTokenRepository whose reads lag behind: GetByHash
+// returns tokens as they were before any rotation, as when two requests
+// race to redeem the same token.
+type staleReads struct {
+	memRefreshTokens
+	snapshot map[string]models.RefreshToken
+}
+
+func (r staleReads) GetByHash(hash string) (models.RefreshToken, error) {
+	if token, ok := r.snapshot[hash]; ok {
+		return token, nil
+	}
+	return r.memRefreshTokens.GetByHash(hash)
+}
+
+func TestRefresh(t *testing.T) {
+	user := models.User{ID: "u1", Email: "ada@example.com", Role: models.RoleAnalyst}
+
+	tests := []struct {
+		name string
+		// run exercises the service, starting from pair, a freshly issued
+		// session of user, and another session of the same user.
+		run func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error
+		// wantErr is the error of run's last call.
+		wantErr error
+	}{
+		{
+			name: "rotates into a new pair",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				next, err := s.Refresh(pair.RefreshToken)
+				if err != nil {
+					return err
+				}
+				if next.RefreshToken == pair.RefreshToken || next.AccessToken == "" {
+					t.Errorf("Refresh returned %+v", next)
+				}
+				if claims, err := s.ValidateToken(next.AccessToken); err != nil || claims.Subject != user.ID || claims.Role != user.Role {
+					t.Errorf("new access token: claims %+v, %v", claims, err)
+				}
+				used := tokens[hashRefreshToken(pair.RefreshToken)]
+				stored := tokens[hashRefreshToken(next.RefreshToken)]
+				if used.UsedAt == nil || stored.FamilyID != used.FamilyID || stored.UsedAt != nil {
+					t.Errorf("used %+v, next %+v; want the next token in the same family", used, stored)
+				}
+				// The rotated token keeps rotating.
+				_, err = s.Refresh(next.RefreshToken)
+				return err
+			},
+		},
+		{
+			name: "reuse revokes the family",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				next, err := s.Refresh(pair.RefreshToken)
+				if err != nil {
+					return err
+				}
+				if _, err := s.Refresh(pair.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+					t.Fatalf("replaying a used token: err = %v, want ErrRefreshTokenReused", err)
+				}
+				if _, err := s.Refresh(other.RefreshToken); err != nil {
+					t.Errorf("another session was revoked too: %v", err)
+				}
+				// The thief and the client hold the same family; neither
+				// gets in with the newest token either.
+				_, err = s.Refresh(next.RefreshToken)
+				return err
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "concurrent rotation counts as reuse",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				snapshot := map[string]models.RefreshToken{}
+				for hash, token := range tokens {
+					snapshot[hash] = token
+				}
+				if _, err := s.Refresh(pair.RefreshToken); err != nil {
+					return err
+				}
+				s.refreshTokens = staleReads{memRefreshTokens: tokens, snapshot: snapshot}
+				return refreshErr(s, pair.RefreshToken)
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "expired",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				hash := hashRefreshToken(pair.RefreshToken)
+				expired := tokens[hash]
+				expired.ExpiresAt = time.Now().Add(-time.Second)
+				tokens[hash] = expired
+				return refreshErr(s, pair.RefreshToken)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "unknown token",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				return refreshErr(s, "not-a-token")
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "deleted user",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				delete(users, user.ID)
+				return refreshErr(s, pair.RefreshToken)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "refreshed tokens carry a changed role",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				if err := s.UpdateRole(user.ID, models.RoleManager); err != nil {
+					return err
+				}
+				if _, err := s.ValidateToken(pair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+					t.Errorf("old access token: err = %v, want ErrTokenRevoked", err)
+				}
+				next, err := s.Refresh(pair.RefreshToken)
+				if err != nil {
+					return err
+				}
+				if claims, err := s.ValidateToken(next.AccessToken); err != nil || claims.Role != models.RoleManager {
+					t.Errorf("refreshed claims %+v, %v; want the manager role", claims, err)
+				}
+				return nil
+			},
+		},
+		{
+			name: "logout ends the session",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				next, err := s.Refresh(pair.RefreshToken)
+				if err != nil {
+					return err
+				}
+				// Logging out with any token of the family ends all of it.
+				if err := s.Logout(pair.RefreshToken); err != nil {
+					return err
+				}
+				if _, err := s.Refresh(other.RefreshToken); err != nil {
+					t.Errorf("another session was logged out too: %v", err)
+				}
+				return refreshErr(s, next.RefreshToken)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "logout with an unknown token",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				return s.Logout("not-a-token")
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "revoking sessions ends every family",
+			run: func(t *testing.T, s *authService, tokens memRefreshTokens, users memUsers, pair, other TokenPair) error {
+				if err := s.RevokeSessions(user.ID); err != nil {
+					return err
+				}
+				if _, err := s.ValidateToken(pair.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+					t.Errorf("access token: err = %v, want ErrTokenRevoked", err)
+				}
+				if _, err := s.Refresh(pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+					t.Errorf("first session: err = %v, want ErrInvalidRefreshToken", err)
+				}
+				return refreshErr(s, other.RefreshToken)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := memUsers{user.ID: user}
+			tokens := memRefreshTokens{}
+			s := NewAuthService(users, tokens, NewSecretKeys("secret"), TokenOptions{}).(*authService)
+			pair, err := s.issueTokens(user, newID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			other, err := s.issueTokens(user, newID())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.run(t, s, tokens, users, pair, other); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// refreshErr redeems refreshToken and returns only the error.
+func refreshErr(s *authService, refreshToken string) error {
+	_, err := s.Refresh(refreshToken)
+	return err
+}
+
+func TestNewTokenPairExpiry(t *testing.T) {
+	user := models.User{ID: "u1", Role: models.RoleViewer}
+	s := NewAuthService(memUsers{user.ID: user}, memRefreshTokens{}, NewSecretKeys("secret"), TokenOptions{TTL: time.Minute, RefreshTTL: time.Hour}).(*authService)
+
+	now := time.Now()
+	pair, stored, err := s.newTokenPair(user, "family", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pair.AccessTokenExpiresAt.Equal(now.Add(time.Minute)) || !pair.RefreshTokenExpiresAt.Equal(now.Add(time.Hour)) || !stored.ExpiresAt.Equal(pair.RefreshTokenExpiresAt) {
+		t.Errorf("pair %+v, stored %+v", pair, stored)
+	}
+	if stored.TokenHash == pair.RefreshToken || stored.TokenHash != hashRefreshToken(pair.RefreshToken) || stored.FamilyID != "family" {
+		t.Errorf("stored %+v, want only the hash of the token kept", stored)
+	}
+}
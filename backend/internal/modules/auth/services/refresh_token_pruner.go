@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+)
+
+// RefreshTokenPruner deletes expired refresh tokens.
+type RefreshTokenPruner struct {
+	repo   repositories.RefreshTokenRepository
+	logger *zap.Logger
+}
+
+func NewRefreshTokenPruner(repo repositories.RefreshTokenRepository, logger *zap.Logger) *RefreshTokenPruner {
+	return &RefreshTokenPruner{repo: repo, logger: logger}
+}
+
+// Run prunes hourly until ctx is cancelled. Every replica may prune; the
+// delete is idempotent.
+func (p *RefreshTokenPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		removed, err := p.repo.DeleteExpiredBefore(time.Now())
+		if err != nil {
+			p.logger.Warn("failed to prune refresh tokens", zap.Error(err))
+		} else if removed > 0 {
+			p.logger.Info("pruned refresh tokens", zap.Int64("removed", removed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -10,3 +10,11 @@ func IsValidRole(role string) bool {
 		return false
 	}
 }
+
+// roleRank orders roles from most to least privileged.
+var roleRank = map[string]int{
+	models.RoleAdmin:   4,
+	models.RoleManager: 3,
+	models.RoleAnalyst: 2,
+	models.RoleViewer:  1,
+}
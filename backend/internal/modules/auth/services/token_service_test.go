@@ -0,0 +1,339 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/models"
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+)
+
+// memUsers is an in-memory UserRepository keyed by user ID.
+type memUsers map[string]models.User
+
+func (r memUsers) Create(user models.User) (models.User, error) {
+	r[user.ID] = user
+	return user, nil
+}
+
+func (r memUsers) GetByEmail(email string) (models.User, error) {
+	for _, user := range r {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return models.User{}, repositories.ErrUserNotFound
+}
+
+func (r memUsers) GetByID(id string) (models.User, error) {
+	user, ok := r[id]
+	if !ok {
+		return models.User{}, repositories.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (r memUsers) List() ([]models.User, error) {
+	users := make([]models.User, 0, len(r))
+	for _, user := range r {
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r memUsers) UpdateRole(id, role string) error {
+	return r.update(id, func(user *models.User) { user.Role = role })
+}
+
+func (r memUsers) UpdatePassword(id, passwordHash string) error {
+	return r.update(id, func(user *models.User) { user.PasswordHash = passwordHash })
+}
+
+func (r memUsers) BumpTokenVersion(id string) error {
+	return r.update(id, func(*models.User) {})
+}
+
+func (r memUsers) GetTokenVersion(id string) (int, error) {
+	user, err := r.GetByID(id)
+	return user.TokenVersion, err
+}
+
+func (r memUsers) GetByIdentity(issuer, subject string) (models.User, error) {
+	return models.User{}, repositories.ErrUserNotFound
+}
+
+func (r memUsers) CreateWithIdentity(user models.User, identity models.Identity) (models.User, error) {
+	return r.Create(user)
+}
+
+func (r memUsers) LinkIdentity(identity models.Identity) error {
+	return nil
+}
+
+func (r memUsers) update(id string, change func(*models.User)) error {
+	user, ok := r[id]
+	if !ok {
+		return repositories.ErrUserNotFound
+	}
+	change(&user)
+	user.TokenVersion++
+	r[id] = user
+	return nil
+}
+
+// memSigningKeys is an in-memory SigningKeyRepository.
+type memSigningKeys struct {
+	keys []models.SigningKey
+}
+
+func (r *memSigningKeys) List() ([]models.SigningKey, error) {
+	return append([]models.SigningKey(nil), r.keys...), nil
+}
+
+func (r *memSigningKeys) CreateUnlessNewer(key models.SigningKey, since time.Time) (bool, error) {
+	for _, existing := range r.keys {
+		if existing.CreatedAt.After(since) {
+			return false, nil
+		}
+	}
+	r.keys = append(r.keys, key)
+	return true, nil
+}
+
+func (r *memSigningKeys) Delete(kid string) error {
+	for i, key := range r.keys {
+		if key.KID == kid {
+			r.keys = append(r.keys[:i], r.keys[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func newEdDSAKeys(t *testing.T) *KeyManager {
+	t.Helper()
+	keys, err := NewKeyManager(&memSigningKeys{}, KeyOptions{Algorithm: AlgorithmEdDSA, RotationInterval: time.Hour, Overlap: time.Hour}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.Claims, key interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestValidateToken(t *testing.T) {
+	user := models.User{ID: "u1", Email: "ada@example.com", Role: models.RoleAnalyst}
+	tokens := TokenOptions{Issuer: "https://devoptics.example.com", Audience: "devoptics-api"}
+
+	// claims returns valid claims for user as issued at now.
+	claims := func(now time.Time) Claims {
+		return Claims{
+			Role:  user.Role,
+			Email: user.Email,
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "jti",
+				Subject:   user.ID,
+				Issuer:    tokens.Issuer,
+				Audience:  jwt.ClaimStrings{tokens.Audience},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		// token returns the token to validate, given the service's signing
+		// key and users.
+		token   func(t *testing.T, s *authService, key Key, users memUsers) string
+		wantErr error
+	}{
+		{
+			name: "valid",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				token, err := s.issueAccessToken(user, time.Now())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+		},
+		{
+			name: "wrong signature",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				_, other, _ := ed25519.GenerateKey(rand.Reader)
+				return signToken(t, key.Method, key.KID, claims(time.Now()), other)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "unknown kid",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				return signToken(t, key.Method, "retired", claims(time.Now()), key.Private)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				token, err := s.issueAccessToken(user, time.Now().Add(-time.Hour))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "missing exp",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				c := claims(time.Now())
+				c.ExpiresAt = nil
+				return signToken(t, key.Method, key.KID, c, key.Private)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "alg none",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				return signToken(t, jwt.SigningMethodNone, key.KID, claims(time.Now()), jwt.UnsafeAllowNoneSignatureType)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			// An HMAC keyed with the published public key must not pass
+			// for the EdDSA key it came from.
+			name: "HS256 with the public key as secret",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				return signToken(t, jwt.SigningMethodHS256, key.KID, claims(time.Now()), []byte(key.Public.(ed25519.PublicKey)))
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "wrong issuer",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				c := claims(time.Now())
+				c.Issuer = "https://evil.example.com"
+				return signToken(t, key.Method, key.KID, c, key.Private)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "wrong audience",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				c := claims(time.Now())
+				c.Audience = jwt.ClaimStrings{"another-api"}
+				return signToken(t, key.Method, key.KID, c, key.Private)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "unknown role",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				c := claims(time.Now())
+				c.Role = "root"
+				return signToken(t, key.Method, key.KID, c, key.Private)
+			},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "stale version",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				token, err := s.issueAccessToken(user, time.Now())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := users.BumpTokenVersion(user.ID); err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+			wantErr: ErrTokenRevoked,
+		},
+		{
+			name: "deleted user",
+			token: func(t *testing.T, s *authService, key Key, users memUsers) string {
+				token, err := s.issueAccessToken(user, time.Now())
+				if err != nil {
+					t.Fatal(err)
+				}
+				delete(users, user.ID)
+				return token
+			},
+			wantErr: ErrTokenRevoked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := newEdDSAKeys(t)
+			key, err := keys.SigningKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			users := memUsers{user.ID: user}
+			s := NewAuthService(users, nil, keys, tokens).(*authService)
+
+			got, err := s.ValidateToken(tt.token(t, s, key, users))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ValidateToken error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if got.Subject != user.ID || got.Role != user.Role || got.Email != user.Email {
+				t.Errorf("claims = %+v", got)
+			}
+		})
+	}
+}
+
+// HS256 deployments sign without a kid; a kid names a key the secret
+// cannot be.
+func TestValidateTokenSecretKeys(t *testing.T) {
+	user := models.User{ID: "u1", Role: models.RoleViewer}
+	s := NewAuthService(memUsers{user.ID: user}, nil, NewSecretKeys("secret"), TokenOptions{}).(*authService)
+
+	token, err := s.issueAccessToken(user, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ValidateToken(token); err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	claims := Claims{Role: user.Role, RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   user.ID,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}}
+	for name, forged := range map[string]string{
+		"other secret": signToken(t, jwt.SigningMethodHS256, "", claims, []byte("guess")),
+		"with kid":     signToken(t, jwt.SigningMethodHS256, "k1", claims, []byte("secret")),
+	} {
+		if _, err := s.ValidateToken(forged); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: ValidateToken error = %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
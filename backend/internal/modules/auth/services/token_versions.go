@@ -0,0 +1,64 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/auth/repositories"
+)
+
+// tokenVersions caches users' token versions in front of the database, so
+// validating a token reads Postgres at most once per user and ttl. A
+// version changed through this replica is forgotten at once; other
+// replicas see the change within ttl.
+type tokenVersions struct {
+	repo repositories.UserRepository
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedVersion
+}
+
+type cachedVersion struct {
+	version int
+	expires time.Time
+}
+
+func newTokenVersions(repo repositories.UserRepository, ttl time.Duration) *tokenVersions {
+	return &tokenVersions{repo: repo, ttl: ttl, entries: map[string]cachedVersion{}}
+}
+
+func (c *tokenVersions) get(userID string) (int, error) {
+	now := time.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.version, nil
+	}
+
+	version, err := c.repo.GetTokenVersion(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Entries are only replaced, never dropped on expiry, so sweep them
+	// here to keep users who stopped signing in from piling up.
+	if !ok {
+		for id, cached := range c.entries {
+			if !now.Before(cached.expires) {
+				delete(c.entries, id)
+			}
+		}
+	}
+	c.entries[userID] = cachedVersion{version: version, expires: now.Add(c.ttl)}
+	return version, nil
+}
+
+func (c *tokenVersions) forget(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
@@ -0,0 +1,214 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+const (
+	// missedScheduleGrace is how late the CronJob controller may be before a
+	// schedule without startingDeadlineSeconds counts as missed.
+	missedScheduleGrace = time.Minute
+	maxMissedRuns       = 100
+)
+
+// JobFilter narrows a job listing. An empty Status lists every job.
+type JobFilter struct {
+	Namespace string
+	Status    string
+}
+
+// CollectJobs reports execution state, duration and failure reasons for Jobs.
+func CollectJobs(ctx context.Context, client kubernetes.Interface, filter JobFilter) ([]models.JobStatus, error) {
+	switch filter.Status {
+	case "", models.JobRunning, models.JobSucceeded, models.JobFailed, models.JobSuspended:
+	default:
+		return nil, fmt.Errorf("%w: unknown job status %q", ErrInvalidFilter, filter.Status)
+	}
+
+	jobs, err := client.BatchV1().Jobs(filter.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	result := []models.JobStatus{}
+	for _, job := range jobs.Items {
+		status := jobStatus(job)
+		if filter.Status == "" || status.Status == filter.Status {
+			result = append(result, status)
+		}
+	}
+	return result, nil
+}
+
+// CollectCronJobs reports schedule health for CronJobs: the next run, missed
+// schedules and counts of the jobs each one owns.
+func CollectCronJobs(ctx context.Context, client kubernetes.Interface, namespace string) ([]models.CronJobStatus, error) {
+	cronJobs, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list cronjobs: %w", err)
+	}
+	jobs, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+
+	owned := map[string][]models.JobStatus{}
+	for _, job := range jobs.Items {
+		if owner := metav1.GetControllerOf(&job); owner != nil && owner.Kind == "CronJob" {
+			key := job.Namespace + "/" + owner.Name
+			owned[key] = append(owned[key], jobStatus(job))
+		}
+	}
+
+	now := time.Now()
+	result := make([]models.CronJobStatus, 0, len(cronJobs.Items))
+	for _, cronJob := range cronJobs.Items {
+		result = append(result, cronJobStatus(cronJob, owned[cronJob.Namespace+"/"+cronJob.Name], now))
+	}
+	return result, nil
+}
+
+func jobStatus(job batchv1.Job) models.JobStatus {
+	status := models.JobStatus{
+		Name:      job.Name,
+		Namespace: job.Namespace,
+		Status:    models.JobRunning,
+		Active:    job.Status.Active,
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+	}
+	if owner := metav1.GetControllerOf(&job); owner != nil {
+		status.Owner = &models.WorkloadRef{Kind: owner.Kind, Name: owner.Name}
+	}
+	if job.Spec.Completions != nil {
+		status.Completions = *job.Spec.Completions
+	}
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		status.Status = models.JobSuspended
+	}
+
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			status.Status = models.JobSucceeded
+		case batchv1.JobFailed:
+			status.Status = models.JobFailed
+			status.FailureReason = cond.Reason
+			status.FailureMessage = cond.Message
+		}
+	}
+
+	if job.Status.StartTime != nil {
+		start := job.Status.StartTime.Time
+		status.StartTime = start.UTC().Format(time.RFC3339)
+		end := time.Now()
+		if job.Status.CompletionTime != nil {
+			end = job.Status.CompletionTime.Time
+			status.CompletionTime = end.UTC().Format(time.RFC3339)
+		}
+		status.DurationSeconds = end.Sub(start).Seconds()
+	}
+	return status
+}
+
+func cronJobStatus(cronJob batchv1.CronJob, jobs []models.JobStatus, now time.Time) models.CronJobStatus {
+	status := models.CronJobStatus{
+		Name:      cronJob.Name,
+		Namespace: cronJob.Namespace,
+		Schedule:  cronJob.Spec.Schedule,
+		Suspended: cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+	}
+	if cronJob.Spec.TimeZone != nil {
+		status.TimeZone = *cronJob.Spec.TimeZone
+	}
+	if cronJob.Status.LastScheduleTime != nil {
+		status.LastScheduleTime = cronJob.Status.LastScheduleTime.UTC().Format(time.RFC3339)
+	}
+	if cronJob.Status.LastSuccessfulTime != nil {
+		status.LastSuccessfulTime = cronJob.Status.LastSuccessfulTime.UTC().Format(time.RFC3339)
+	}
+
+	var totalDuration float64
+	for _, job := range jobs {
+		switch job.Status {
+		case models.JobSucceeded:
+			status.Jobs.Succeeded++
+			totalDuration += job.DurationSeconds
+		case models.JobFailed:
+			status.Jobs.Failed++
+		default:
+			status.Jobs.Active++
+		}
+	}
+	if status.Jobs.Succeeded > 0 {
+		status.AvgDurationSeconds = totalDuration / float64(status.Jobs.Succeeded)
+	}
+
+	schedule, err := parseCronSchedule(cronJob.Spec.Schedule, status.TimeZone)
+	if err != nil {
+		status.ScheduleError = err.Error()
+		return status
+	}
+	// A valid expression such as "0 0 30 2 *" may still never fire; cron
+	// then reports the zero time.
+	next := schedule.Next(now)
+	if next.IsZero() {
+		status.ScheduleError = "schedule never fires"
+		return status
+	}
+	status.NextScheduleTime = next.UTC().Format(time.RFC3339)
+
+	if status.Suspended {
+		return status
+	}
+
+	since := cronJob.CreationTimestamp.Time
+	if cronJob.Status.LastScheduleTime != nil {
+		since = cronJob.Status.LastScheduleTime.Time
+	}
+	grace := missedScheduleGrace
+	if cronJob.Spec.StartingDeadlineSeconds != nil {
+		grace = time.Duration(*cronJob.Spec.StartingDeadlineSeconds) * time.Second
+	}
+	status.MissedRuns = missedRuns(schedule, since, now, grace)
+	status.MissedSchedule = status.MissedRuns > 0
+	return status
+}
+
+// parseCronSchedule parses a standard five-field expression or descriptor
+// such as @hourly, evaluated in timeZone when set.
+func parseCronSchedule(expr, timeZone string) (cron.Schedule, error) {
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+		}
+		expr = "CRON_TZ=" + timeZone + " " + expr
+	}
+	return cron.ParseStandard(expr)
+}
+
+// missedRuns counts the scheduled times after since that should have started
+// more than grace ago, i.e. runs the controller is behind on.
+func missedRuns(schedule cron.Schedule, since, now time.Time, grace time.Duration) int {
+	missed := 0
+	for next := schedule.Next(since); !next.IsZero() && next.Add(grace).Before(now); next = schedule.Next(next) {
+		missed++
+		if missed >= maxMissedRuns {
+			break
+		}
+	}
+	return missed
+}
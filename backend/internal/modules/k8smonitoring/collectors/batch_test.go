@@ -0,0 +1,61 @@
+package collectors
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCronJobStatusSchedule(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 30, 0, 0, time.UTC)
+	lastRun := metav1.NewTime(now.Add(-3 * time.Hour).Truncate(time.Hour))
+
+	tests := []struct {
+		name            string
+		schedule        string
+		timeZone        string
+		wantNext        string
+		wantMissed      int
+		wantScheduleErr string
+	}{
+		{name: "on schedule", schedule: "0 * * * *", wantNext: "2026-10-17T13:00:00Z", wantMissed: 3},
+		{name: "time zone", schedule: "0 9 * * *", timeZone: "Europe/Paris", wantNext: "2026-10-18T07:00:00Z"},
+		{name: "never fires", schedule: "0 0 30 2 *", wantScheduleErr: "schedule never fires"},
+		{name: "invalid", schedule: "not a schedule", wantScheduleErr: "expected exactly 5 fields, found 3: [not a schedule]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cronJob := batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default", CreationTimestamp: lastRun},
+				Spec:       batchv1.CronJobSpec{Schedule: tt.schedule},
+				Status:     batchv1.CronJobStatus{LastScheduleTime: &lastRun},
+			}
+			if tt.timeZone != "" {
+				cronJob.Spec.TimeZone = &tt.timeZone
+			}
+			status := cronJobStatus(cronJob, nil, now)
+			if status.ScheduleError != tt.wantScheduleErr {
+				t.Errorf("ScheduleError = %q, want %q", status.ScheduleError, tt.wantScheduleErr)
+			}
+			if status.NextScheduleTime != tt.wantNext {
+				t.Errorf("NextScheduleTime = %q, want %q", status.NextScheduleTime, tt.wantNext)
+			}
+			if status.MissedRuns != tt.wantMissed || status.MissedSchedule != (tt.wantMissed > 0) {
+				t.Errorf("MissedRuns = %d (missedSchedule %v), want %d", status.MissedRuns, status.MissedSchedule, tt.wantMissed)
+			}
+		})
+	}
+}
+
+func TestMissedRunsStopsAtNeverFiringSchedule(t *testing.T) {
+	schedule, err := parseCronSchedule("0 0 30 2 *", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	if missed := missedRuns(schedule, now.AddDate(-1, 0, 0), now, time.Minute); missed != 0 {
+		t.Errorf("missedRuns = %d, want 0", missed)
+	}
+}
@@ -0,0 +1,157 @@
+package collectors
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+// Credential reference forms accepted in models.Cluster.CredentialsRef.
+const (
+	CredentialsInCluster  = "in-cluster"
+	CredentialsKubeconfig = "kubeconfig"
+)
+
+const requestTimeout = 15 * time.Second
+
+var (
+	ErrClusterNotConfigured = errors.New("cluster not configured")
+	ErrEndpointMismatch     = errors.New("apiEndpoint does not match the credentials' server")
+)
+
+// ClientProvider resolves a Kubernetes clientset for a registered cluster.
+type ClientProvider interface {
+	ClientFor(cluster models.Cluster) (kubernetes.Interface, error)
+	// Forget drops any client cached for a cluster, so the next ClientFor
+	// resolves its credentials again.
+	Forget(clusterName string)
+}
+
+// ValidateCredentialsRef reports whether ref is a credentials reference the
+// collectors know how to resolve.
+func ValidateCredentialsRef(ref string) error {
+	switch {
+	case ref == CredentialsInCluster, ref == CredentialsKubeconfig:
+		return nil
+	case strings.HasPrefix(ref, CredentialsKubeconfig+":") && len(ref) > len(CredentialsKubeconfig)+1:
+		return nil
+	default:
+		return fmt.Errorf("credentialsRef must be %q, %q or %q", CredentialsInCluster, CredentialsKubeconfig, CredentialsKubeconfig+":<context>")
+	}
+}
+
+type credentialsProvider struct {
+	kubeconfig string
+
+	mu      sync.Mutex
+	clients map[string]cachedClient
+}
+
+type cachedClient struct {
+	key    string
+	client kubernetes.Interface
+}
+
+// NewCredentialsProvider returns a ClientProvider that resolves each
+// cluster's CredentialsRef, reading kubeconfig references from kubeconfig.
+func NewCredentialsProvider(kubeconfig string) ClientProvider {
+	return &credentialsProvider{
+		kubeconfig: kubeconfig,
+		clients:    map[string]cachedClient{},
+	}
+}
+
+func (p *credentialsProvider) ClientFor(cluster models.Cluster) (kubernetes.Interface, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Registry edits change the key, so a stale client is rebuilt.
+	key := cluster.CredentialsRef + "|" + cluster.APIEndpoint
+	if cached, ok := p.clients[cluster.Name]; ok && cached.key == key {
+		return cached.client, nil
+	}
+
+	cfg, err := p.restConfig(cluster.CredentialsRef)
+	if err != nil {
+		return nil, err
+	}
+	// The credentials' token and CA belong to their own server; sending
+	// them to another host would leak them, so apiEndpoint only confirms
+	// which server the credentials reach.
+	if cluster.APIEndpoint != "" && !sameEndpoint(cluster.APIEndpoint, cfg.Host) {
+		return nil, fmt.Errorf("%w: %q is not %q", ErrEndpointMismatch, cluster.APIEndpoint, cfg.Host)
+	}
+	cfg.Timeout = requestTimeout
+
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[cluster.Name] = cachedClient{key: key, client: client}
+	return client, nil
+}
+
+func (p *credentialsProvider) Forget(clusterName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, clusterName)
+}
+
+// sameEndpoint reports whether two API server URLs name the same server,
+// ignoring default ports and trailing slashes.
+func sameEndpoint(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return ua.Scheme == ub.Scheme &&
+		endpointHost(ua) == endpointHost(ub) &&
+		strings.TrimSuffix(ua.Path, "/") == strings.TrimSuffix(ub.Path, "/")
+}
+
+func endpointHost(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return strings.ToLower(u.Hostname()) + ":" + port
+}
+
+func (p *credentialsProvider) restConfig(ref string) (*rest.Config, error) {
+	if err := ValidateCredentialsRef(ref); err != nil {
+		return nil, err
+	}
+	if ref == CredentialsInCluster {
+		return rest.InClusterConfig()
+	}
+	if p.kubeconfig == "" {
+		return nil, ErrClusterNotConfigured
+	}
+
+	raw, err := clientcmd.LoadFromFile(p.kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("load kubeconfig: %w", err)
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	if context := strings.TrimPrefix(ref, CredentialsKubeconfig+":"); context != ref {
+		if _, ok := raw.Contexts[context]; !ok {
+			return nil, fmt.Errorf("%w: kubeconfig has no context %q", ErrClusterNotConfigured, context)
+		}
+		overrides.CurrentContext = context
+	}
+	return clientcmd.NewDefaultClientConfig(*raw, overrides).ClientConfig()
+}
@@ -0,0 +1,79 @@
+package collectors
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: prod
+current-context: prod
+users:
+- name: prod
+  user:
+    token: secret-token
+`
+
+func TestCredentialsProviderEndpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		endpoint string
+		wantErr  error
+	}{
+		{endpoint: ""},
+		{endpoint: "https://prod.example.com:6443"},
+		{endpoint: "https://PROD.example.com:6443/"},
+		{endpoint: "https://attacker.example.com", wantErr: ErrEndpointMismatch},
+		{endpoint: "https://prod.example.com", wantErr: ErrEndpointMismatch},
+		{endpoint: "http://prod.example.com:6443", wantErr: ErrEndpointMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			provider := NewCredentialsProvider(path)
+			cluster := models.Cluster{Name: "prod", APIEndpoint: tt.endpoint, CredentialsRef: "kubeconfig:prod"}
+			_, err := provider.ClientFor(cluster)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClientFor(%q) error = %v, want %v", tt.endpoint, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCredentialsProviderForget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewCredentialsProvider(path)
+	cluster := models.Cluster{Name: "prod", CredentialsRef: CredentialsKubeconfig}
+
+	first, err := provider.ClientFor(cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := provider.ClientFor(cluster); again != first {
+		t.Fatal("client was not cached")
+	}
+
+	provider.Forget("prod")
+	if rebuilt, _ := provider.ClientFor(cluster); rebuilt == first {
+		t.Fatal("Forget kept the cached client")
+	}
+}
@@ -0,0 +1,171 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+const listPageSize = 500
+
+// CollectClusterHealth builds a health snapshot from the cluster's API server.
+// Node and pod listing errors fail the collection; control-plane status is
+// best effort because ComponentStatus is deprecated and often forbidden.
+func CollectClusterHealth(ctx context.Context, cluster string, client kubernetes.Interface) (models.ClusterHealth, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return models.ClusterHealth{}, fmt.Errorf("list nodes: %w", err)
+	}
+
+	pods, err := listPods(ctx, client, metav1.NamespaceAll)
+	if err != nil {
+		return models.ClusterHealth{}, fmt.Errorf("list pods: %w", err)
+	}
+
+	health := models.ClusterHealth{
+		ClusterName: cluster,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Nodes:       nodeReadiness(nodes.Items),
+		Pods:        podPhaseCounts(pods),
+		Signals:     map[string]string{},
+	}
+
+	components, source := controlPlaneHealth(ctx, client, pods)
+	health.ControlPlane = components
+	health.Signals["controlPlaneSource"] = source
+	health.Status = deriveStatus(health)
+	return health, nil
+}
+
+func listPods(ctx context.Context, client kubernetes.Interface, namespace string) ([]corev1.Pod, error) {
+	return listPodsMatching(ctx, client, namespace, "")
+}
+
+func nodeReadiness(nodes []corev1.Node) models.NodeReadiness {
+	readiness := models.NodeReadiness{Total: len(nodes)}
+	for _, node := range nodes {
+		if nodeConditionTrue(node, corev1.NodeReady) {
+			readiness.Ready++
+		} else {
+			readiness.NotReady++
+		}
+		if nodeConditionTrue(node, corev1.NodeDiskPressure) {
+			readiness.DiskPressure++
+		}
+		if nodeConditionTrue(node, corev1.NodeMemoryPressure) {
+			readiness.MemoryPressure++
+		}
+		if nodeConditionTrue(node, corev1.NodePIDPressure) {
+			readiness.PIDPressure++
+		}
+	}
+	return readiness
+}
+
+func nodeConditionTrue(node corev1.Node, conditionType corev1.NodeConditionType) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == conditionType {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func podPhaseCounts(pods []corev1.Pod) models.PodPhaseCounts {
+	counts := models.PodPhaseCounts{Total: len(pods)}
+	for _, pod := range pods {
+		switch pod.Status.Phase {
+		case corev1.PodRunning:
+			counts.Running++
+		case corev1.PodPending:
+			counts.Pending++
+		case corev1.PodSucceeded:
+			counts.Succeeded++
+		case corev1.PodFailed:
+			counts.Failed++
+		default:
+			counts.Unknown++
+		}
+	}
+	return counts
+}
+
+// controlPlaneHealth prefers the ComponentStatus API and falls back to the
+// static control-plane pods kubeadm runs in kube-system.
+func controlPlaneHealth(ctx context.Context, client kubernetes.Interface, pods []corev1.Pod) ([]models.ComponentHealth, string) {
+	statuses, err := client.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err == nil && len(statuses.Items) > 0 {
+		components := make([]models.ComponentHealth, 0, len(statuses.Items))
+		for _, cs := range statuses.Items {
+			component := models.ComponentHealth{Name: cs.Name}
+			for _, cond := range cs.Conditions {
+				if cond.Type == corev1.ComponentHealthy {
+					component.Healthy = cond.Status == corev1.ConditionTrue
+					component.Message = cond.Message
+					if cond.Error != "" {
+						component.Message = cond.Error
+					}
+				}
+			}
+			components = append(components, component)
+		}
+		return components, "componentstatuses"
+	}
+
+	var components []models.ComponentHealth
+	for _, pod := range pods {
+		if pod.Namespace != metav1.NamespaceSystem || pod.Labels["tier"] != "control-plane" {
+			continue
+		}
+		name := pod.Labels["component"]
+		if name == "" {
+			name = pod.Name
+		}
+		components = append(components, models.ComponentHealth{
+			Name:    name,
+			Healthy: podReady(pod),
+			Message: string(pod.Status.Phase),
+		})
+	}
+	if len(components) == 0 {
+		return nil, "unavailable"
+	}
+	return components, "static-pods"
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func deriveStatus(health models.ClusterHealth) string {
+	if health.Nodes.Total == 0 {
+		return models.StatusUnknown
+	}
+	if health.Nodes.Ready == 0 {
+		return models.StatusUnhealthy
+	}
+	for _, component := range health.ControlPlane {
+		if !component.Healthy {
+			return models.StatusUnhealthy
+		}
+	}
+	nodes := health.Nodes
+	if nodes.NotReady > 0 || nodes.DiskPressure > 0 || nodes.MemoryPressure > 0 || nodes.PIDPressure > 0 {
+		return models.StatusDegraded
+	}
+	if health.Pods.Failed > 0 || health.Pods.Unknown > 0 {
+		return models.StatusDegraded
+	}
+	return models.StatusHealthy
+}
@@ -0,0 +1,166 @@
+package collectors
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+func node(name string, conditions ...corev1.NodeConditionType) *corev1.Node {
+	n := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, c := range conditions {
+		n.Status.Conditions = append(n.Status.Conditions, corev1.NodeCondition{Type: c, Status: corev1.ConditionTrue})
+	}
+	return n
+}
+
+func pod(namespace, name string, phase corev1.PodPhase, ready bool, labels map[string]string) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Status: corev1.PodStatus{
+			Phase:      phase,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func controlPlanePod(component string, ready bool) *corev1.Pod {
+	return pod(metav1.NamespaceSystem, component+"-cp", corev1.PodRunning, ready,
+		map[string]string{"tier": "control-plane", "component": component})
+}
+
+func TestCollectClusterHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		wantStatus    string
+		wantNodes     models.NodeReadiness
+		wantPods      models.PodPhaseCounts
+		wantSource    string
+		wantUnhealthy []string
+	}{
+		{
+			name:       "empty cluster",
+			wantStatus: models.StatusUnknown,
+			wantSource: "unavailable",
+		},
+		{
+			name: "healthy",
+			objects: []runtime.Object{
+				node("a", corev1.NodeReady),
+				node("b", corev1.NodeReady),
+				pod("default", "web", corev1.PodRunning, true, nil),
+				pod("default", "job", corev1.PodSucceeded, false, nil),
+				controlPlanePod("kube-apiserver", true),
+				controlPlanePod("etcd", true),
+			},
+			wantStatus: models.StatusHealthy,
+			wantNodes:  models.NodeReadiness{Total: 2, Ready: 2},
+			wantPods:   models.PodPhaseCounts{Total: 4, Running: 3, Succeeded: 1},
+			wantSource: "static-pods",
+		},
+		{
+			name: "node pressure and failed pod degrade",
+			objects: []runtime.Object{
+				node("a", corev1.NodeReady, corev1.NodeDiskPressure),
+				node("b"),
+				pod("default", "web", corev1.PodFailed, false, nil),
+				pod("default", "new", corev1.PodPending, false, nil),
+			},
+			wantStatus: models.StatusDegraded,
+			wantNodes:  models.NodeReadiness{Total: 2, Ready: 1, NotReady: 1, DiskPressure: 1},
+			wantPods:   models.PodPhaseCounts{Total: 2, Pending: 1, Failed: 1},
+			wantSource: "unavailable",
+		},
+		{
+			name: "no ready node",
+			objects: []runtime.Object{
+				node("a", corev1.NodeMemoryPressure),
+			},
+			wantStatus: models.StatusUnhealthy,
+			wantNodes:  models.NodeReadiness{Total: 1, NotReady: 1, MemoryPressure: 1},
+			wantSource: "unavailable",
+		},
+		{
+			name: "unready control plane",
+			objects: []runtime.Object{
+				node("a", corev1.NodeReady),
+				controlPlanePod("kube-scheduler", false),
+			},
+			wantStatus:    models.StatusUnhealthy,
+			wantNodes:     models.NodeReadiness{Total: 1, Ready: 1},
+			wantPods:      models.PodPhaseCounts{Total: 1, Running: 1},
+			wantSource:    "static-pods",
+			wantUnhealthy: []string{"kube-scheduler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tt.objects...)
+			health, err := CollectClusterHealth(context.Background(), "test", client)
+			if err != nil {
+				t.Fatalf("CollectClusterHealth: %v", err)
+			}
+			if health.ClusterName != "test" {
+				t.Errorf("ClusterName = %q", health.ClusterName)
+			}
+			if health.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", health.Status, tt.wantStatus)
+			}
+			if health.Nodes != tt.wantNodes {
+				t.Errorf("Nodes = %+v, want %+v", health.Nodes, tt.wantNodes)
+			}
+			if health.Pods != tt.wantPods {
+				t.Errorf("Pods = %+v, want %+v", health.Pods, tt.wantPods)
+			}
+			if got := health.Signals["controlPlaneSource"]; got != tt.wantSource {
+				t.Errorf("controlPlaneSource = %q, want %q", got, tt.wantSource)
+			}
+			var unhealthy []string
+			for _, component := range health.ControlPlane {
+				if !component.Healthy {
+					unhealthy = append(unhealthy, component.Name)
+				}
+			}
+			if len(unhealthy) != len(tt.wantUnhealthy) || (len(unhealthy) > 0 && unhealthy[0] != tt.wantUnhealthy[0]) {
+				t.Errorf("unhealthy components = %v, want %v", unhealthy, tt.wantUnhealthy)
+			}
+		})
+	}
+}
+
+func TestCollectClusterHealthPrefersComponentStatuses(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		node("a", corev1.NodeReady),
+		&corev1.ComponentStatus{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd-0"},
+			Conditions: []corev1.ComponentCondition{{Type: corev1.ComponentHealthy, Status: corev1.ConditionFalse, Error: "connection refused"}},
+		},
+		controlPlanePod("etcd", true),
+	)
+
+	health, err := CollectClusterHealth(context.Background(), "test", client)
+	if err != nil {
+		t.Fatalf("CollectClusterHealth: %v", err)
+	}
+	if got := health.Signals["controlPlaneSource"]; got != "componentstatuses" {
+		t.Fatalf("controlPlaneSource = %q, want componentstatuses", got)
+	}
+	if len(health.ControlPlane) != 1 || health.ControlPlane[0].Healthy || health.ControlPlane[0].Message != "connection refused" {
+		t.Fatalf("ControlPlane = %+v", health.ControlPlane)
+	}
+	if health.Status != models.StatusUnhealthy {
+		t.Fatalf("Status = %q, want unhealthy", health.Status)
+	}
+}
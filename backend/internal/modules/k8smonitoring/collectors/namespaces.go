@@ -0,0 +1,122 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+// DefaultQuotaThreshold is the percent of a quota at which a namespace is
+// flagged as near its limit.
+const DefaultQuotaThreshold = 80.0
+
+// NamespaceFilter narrows a namespace listing. An empty Namespace lists all
+// namespaces; NearLimitOnly keeps those with a resource at or above
+// Threshold percent of its hard limit.
+type NamespaceFilter struct {
+	Namespace     string
+	Threshold     float64
+	NearLimitOnly bool
+}
+
+// CollectNamespaceUsage reports each namespace's ResourceQuota hard limits,
+// usage and percent consumed, along with how many of its pods are Pending.
+func CollectNamespaceUsage(ctx context.Context, client kubernetes.Interface, filter NamespaceFilter) ([]models.NamespaceUsage, error) {
+	if filter.Threshold <= 0 || filter.Threshold > 100 {
+		return nil, fmt.Errorf("%w: threshold must be between 0 and 100", ErrInvalidFilter)
+	}
+
+	var namespaces []corev1.Namespace
+	if filter.Namespace != "" {
+		namespace, err := client.CoreV1().Namespaces().Get(ctx, filter.Namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get namespace: %w", err)
+		}
+		namespaces = []corev1.Namespace{*namespace}
+	} else {
+		list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("list namespaces: %w", err)
+		}
+		namespaces = list.Items
+	}
+
+	quotas, err := client.CoreV1().ResourceQuotas(filter.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list resourcequotas: %w", err)
+	}
+	quotasByNamespace := map[string][]corev1.ResourceQuota{}
+	for _, quota := range quotas.Items {
+		quotasByNamespace[quota.Namespace] = append(quotasByNamespace[quota.Namespace], quota)
+	}
+
+	pods, err := listPods(ctx, client, filter.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	pending := map[string]int{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodPending {
+			pending[pod.Namespace]++
+		}
+	}
+
+	result := []models.NamespaceUsage{}
+	for _, namespace := range namespaces {
+		usage := models.NamespaceUsage{
+			Namespace:   namespace.Name,
+			Phase:       string(namespace.Status.Phase),
+			Quotas:      []models.QuotaUsage{},
+			PendingPods: pending[namespace.Name],
+		}
+		for _, quota := range quotasByNamespace[namespace.Name] {
+			quotaUsage := quotaUsage(quota, filter.Threshold)
+			for _, resource := range quotaUsage.Resources {
+				usage.MaxPercentUsed = math.Max(usage.MaxPercentUsed, resource.PercentUsed)
+				usage.NearLimit = usage.NearLimit || resource.NearLimit
+			}
+			usage.Quotas = append(usage.Quotas, quotaUsage)
+		}
+		if filter.NearLimitOnly && !usage.NearLimit {
+			continue
+		}
+		result = append(result, usage)
+	}
+	return result, nil
+}
+
+func quotaUsage(quota corev1.ResourceQuota, threshold float64) models.QuotaUsage {
+	usage := models.QuotaUsage{Name: quota.Name, Resources: []models.QuotaResource{}}
+	for name, hard := range quota.Status.Hard {
+		used := quota.Status.Used[name]
+		resource := models.QuotaResource{
+			Resource: string(name),
+			Hard:     hard.String(),
+			Used:     used.String(),
+		}
+
+		hardValue := hard.AsApproximateFloat64()
+		usedValue := used.AsApproximateFloat64()
+		switch {
+		case hardValue > 0:
+			resource.PercentUsed = math.Round(usedValue/hardValue*10000) / 100
+		case usedValue > 0:
+			resource.PercentUsed = 100
+		}
+		// A zero hard limit forbids the resource outright; it only counts
+		// against the namespace once something uses it anyway.
+		resource.NearLimit = resource.PercentUsed >= threshold
+		usage.Resources = append(usage.Resources, resource)
+	}
+	sort.Slice(usage.Resources, func(i, j int) bool {
+		return usage.Resources[i].Resource < usage.Resources[j].Resource
+	})
+	return usage
+}
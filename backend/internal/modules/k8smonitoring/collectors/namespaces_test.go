@@ -0,0 +1,161 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+func namespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+	}
+}
+
+// quota returns a ResourceQuota whose status holds the given hard and used
+// quantities, as "hard/used" per resource.
+func quota(namespace, name string, resources map[corev1.ResourceName]string) *corev1.ResourceQuota {
+	q := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status:     corev1.ResourceQuotaStatus{Hard: corev1.ResourceList{}, Used: corev1.ResourceList{}},
+	}
+	for resourceName, hardUsed := range resources {
+		hard, used, _ := strings.Cut(hardUsed, "/")
+		q.Status.Hard[resourceName] = resource.MustParse(hard)
+		q.Status.Used[resourceName] = resource.MustParse(used)
+	}
+	return q
+}
+
+func namespaceClient() *fake.Clientset {
+	return fake.NewSimpleClientset(
+		namespace("team-a"), namespace("team-b"), namespace("team-c"), namespace("team-d"),
+		quota("team-a", "compute", map[corev1.ResourceName]string{
+			corev1.ResourceRequestsCPU:    "4/3500m",
+			corev1.ResourceRequestsMemory: "8Gi/2Gi",
+		}),
+		quota("team-a", "objects", map[corev1.ResourceName]string{
+			corev1.ResourcePods: "20/3",
+		}),
+		quota("team-b", "objects", map[corev1.ResourceName]string{
+			corev1.ResourcePods:                  "10/5",
+			corev1.ResourceServicesLoadBalancers: "0/0",
+		}),
+		quota("team-c", "objects", map[corev1.ResourceName]string{
+			corev1.ResourceServicesLoadBalancers: "0/1",
+		}),
+		pod("team-a", "queued-1", corev1.PodPending, false, nil),
+		pod("team-a", "queued-2", corev1.PodPending, false, nil),
+		pod("team-a", "web", corev1.PodRunning, true, nil),
+		pod("team-d", "queued", corev1.PodPending, false, nil),
+	)
+}
+
+func TestQuotaUsage(t *testing.T) {
+	usage, err := CollectNamespaceUsage(context.Background(), namespaceClient(), NamespaceFilter{Threshold: DefaultQuotaThreshold})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]models.NamespaceUsage{}
+	for _, u := range usage {
+		byName[u.Namespace] = u
+	}
+
+	tests := []struct {
+		namespace     string
+		wantResources string
+		wantMax       float64
+		wantNearLimit bool
+		wantPending   int
+	}{
+		{
+			namespace: "team-a",
+			wantResources: "[{compute [{requests.cpu 4 3500m 87.5 true} {requests.memory 8Gi 2Gi 25 false}]}" +
+				" {objects [{pods 20 3 15 false}]}]",
+			wantMax:       87.5,
+			wantNearLimit: true,
+			wantPending:   2,
+		},
+		{
+			// A zero limit nothing uses forbids load balancers; it is not
+			// pressure.
+			namespace:     "team-b",
+			wantResources: "[{objects [{pods 10 5 50 false} {services.loadbalancers 0 0 0 false}]}]",
+			wantMax:       50,
+		},
+		{
+			namespace:     "team-c",
+			wantResources: "[{objects [{services.loadbalancers 0 1 100 true}]}]",
+			wantMax:       100,
+			wantNearLimit: true,
+		},
+		{namespace: "team-d", wantResources: "[]", wantPending: 1},
+	}
+	if len(byName) != len(tests) {
+		t.Fatalf("namespaces = %+v", usage)
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			got := byName[tt.namespace]
+			if resources := fmt.Sprint(got.Quotas); resources != tt.wantResources {
+				t.Errorf("quotas = %s\nwant     %s", resources, tt.wantResources)
+			}
+			if got.MaxPercentUsed != tt.wantMax || got.NearLimit != tt.wantNearLimit || got.PendingPods != tt.wantPending {
+				t.Errorf("max %v, near limit %v, pending %d; want %v, %v, %d",
+					got.MaxPercentUsed, got.NearLimit, got.PendingPods, tt.wantMax, tt.wantNearLimit, tt.wantPending)
+			}
+			if got.Phase != string(corev1.NamespaceActive) {
+				t.Errorf("phase = %q", got.Phase)
+			}
+		})
+	}
+}
+
+func TestCollectNamespaceUsageFilter(t *testing.T) {
+	client := namespaceClient()
+
+	tests := []struct {
+		name      string
+		filter    NamespaceFilter
+		wantNames []string
+		wantErr   bool
+	}{
+		{name: "all", filter: NamespaceFilter{Threshold: 80}, wantNames: []string{"team-a", "team-b", "team-c", "team-d"}},
+		{name: "near limit only", filter: NamespaceFilter{Threshold: 80, NearLimitOnly: true}, wantNames: []string{"team-a", "team-c"}},
+		{name: "lower threshold", filter: NamespaceFilter{Threshold: 50, NearLimitOnly: true}, wantNames: []string{"team-a", "team-b", "team-c"}},
+		{name: "full only", filter: NamespaceFilter{Threshold: 100, NearLimitOnly: true}, wantNames: []string{"team-c"}},
+		{name: "one namespace", filter: NamespaceFilter{Namespace: "team-b", Threshold: 80}, wantNames: []string{"team-b"}},
+		{name: "one namespace not near", filter: NamespaceFilter{Namespace: "team-b", Threshold: 80, NearLimitOnly: true}},
+		{name: "missing namespace", filter: NamespaceFilter{Namespace: "nope", Threshold: 80}, wantErr: true},
+		{name: "zero threshold", filter: NamespaceFilter{Threshold: 0}, wantErr: true},
+		{name: "threshold over 100", filter: NamespaceFilter{Threshold: 101}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := CollectNamespaceUsage(context.Background(), client, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CollectNamespaceUsage error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && tt.filter.Namespace == "" && !errors.Is(err, ErrInvalidFilter) {
+				t.Errorf("err = %v, want ErrInvalidFilter", err)
+			}
+			var names []string
+			for _, u := range usage {
+				names = append(names, u.Namespace)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// NodeFilter narrows a node listing. Conditions are ANDed together and use
+// the models.NodeCondition* names.
+type NodeFilter struct {
+	LabelSelector string
+	Conditions    []string
+}
+
+// CollectNodes lists nodes with their conditions, capacity and the requests
+// of the pods scheduled on them.
+func CollectNodes(ctx context.Context, client kubernetes.Interface, filter NodeFilter) ([]models.NodeHealth, error) {
+	if _, err := labels.Parse(filter.LabelSelector); err != nil {
+		return nil, fmt.Errorf("%w: labelSelector: %v", ErrInvalidFilter, err)
+	}
+	for _, condition := range filter.Conditions {
+		if !validNodeCondition(condition) {
+			return nil, fmt.Errorf("%w: unknown node condition %q", ErrInvalidFilter, condition)
+		}
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: filter.LabelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+
+	pods, err := listPods(ctx, client, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	requested := map[string]models.NodeResources{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || podTerminated(pod) {
+			continue
+		}
+		cpu, memory := podRequests(pod)
+		usage := requested[pod.Spec.NodeName]
+		usage.CPURequestedMillis += cpu
+		usage.MemoryRequestedBytes += memory
+		usage.PodsScheduled++
+		requested[pod.Spec.NodeName] = usage
+	}
+
+	result := []models.NodeHealth{}
+	for _, node := range nodes.Items {
+		health := nodeHealth(node, requested[node.Name])
+		if matchesNodeConditions(health, filter.Conditions) {
+			result = append(result, health)
+		}
+	}
+	return result, nil
+}
+
+func nodeHealth(node corev1.Node, requested models.NodeResources) models.NodeHealth {
+	allocatable := node.Status.Allocatable
+	resources := requested
+	resources.CPUAllocatableMillis = allocatable.Cpu().MilliValue()
+	resources.MemoryAllocatableBytes = allocatable.Memory().Value()
+	resources.PodsAllocatable = allocatable.Pods().Value()
+
+	info := node.Status.NodeInfo
+	return models.NodeHealth{
+		Name:          node.Name,
+		Labels:        node.Labels,
+		Unschedulable: node.Spec.Unschedulable,
+		Conditions: models.NodeConditions{
+			Ready:          nodeConditionTrue(node, corev1.NodeReady),
+			DiskPressure:   nodeConditionTrue(node, corev1.NodeDiskPressure),
+			MemoryPressure: nodeConditionTrue(node, corev1.NodeMemoryPressure),
+			PIDPressure:    nodeConditionTrue(node, corev1.NodePIDPressure),
+		},
+		Resources: resources,
+		Info: models.NodeInfo{
+			KubeletVersion:          info.KubeletVersion,
+			KernelVersion:           info.KernelVersion,
+			OSImage:                 info.OSImage,
+			ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+			Architecture:            info.Architecture,
+		},
+	}
+}
+
+func validNodeCondition(condition string) bool {
+	switch condition {
+	case models.NodeConditionReady, models.NodeConditionNotReady, models.NodeConditionDiskPressure,
+		models.NodeConditionMemoryPressure, models.NodeConditionPIDPressure:
+		return true
+	default:
+		return false
+	}
+}
+
+func matchesNodeConditions(node models.NodeHealth, conditions []string) bool {
+	for _, condition := range conditions {
+		var ok bool
+		switch condition {
+		case models.NodeConditionReady:
+			ok = node.Conditions.Ready
+		case models.NodeConditionNotReady:
+			ok = !node.Conditions.Ready
+		case models.NodeConditionDiskPressure:
+			ok = node.Conditions.DiskPressure
+		case models.NodeConditionMemoryPressure:
+			ok = node.Conditions.MemoryPressure
+		case models.NodeConditionPIDPressure:
+			ok = node.Conditions.PIDPressure
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func podTerminated(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// podRequests returns a pod's effective CPU (millicores) and memory (bytes)
+// requests the way the scheduler counts them: the larger of the summed app
+// containers and the largest init container, plus pod overhead.
+func podRequests(pod corev1.Pod) (int64, int64) {
+	var cpu, memory int64
+	for _, container := range pod.Spec.Containers {
+		cpu += container.Resources.Requests.Cpu().MilliValue()
+		memory += container.Resources.Requests.Memory().Value()
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if c := container.Resources.Requests.Cpu().MilliValue(); c > cpu {
+			cpu = c
+		}
+		if m := container.Resources.Requests.Memory().Value(); m > memory {
+			memory = m
+		}
+	}
+	cpu += pod.Spec.Overhead.Cpu().MilliValue()
+	memory += pod.Spec.Overhead.Memory().Value()
+	return cpu, memory
+}
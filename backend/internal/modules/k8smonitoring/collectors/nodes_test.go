@@ -0,0 +1,157 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+func withAllocatable(n *corev1.Node, cpu, memory, pods string, labels map[string]string) *corev1.Node {
+	n.Labels = labels
+	n.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+		corev1.ResourcePods:   resource.MustParse(pods),
+	}
+	n.Status.NodeInfo = corev1.NodeSystemInfo{KubeletVersion: "v1.30.2", Architecture: "amd64"}
+	return n
+}
+
+func requests(cpu, memory string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}}
+}
+
+// scheduled returns a pod on nodeName whose containers request the given
+// resources.
+func scheduled(name, nodeName string, phase corev1.PodPhase, containers ...corev1.ResourceRequirements) *corev1.Pod {
+	p := pod("default", name, phase, true, nil)
+	p.Spec.NodeName = nodeName
+	for i, r := range containers {
+		p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: fmt.Sprintf("c%d", i), Resources: r})
+	}
+	return p
+}
+
+func TestCollectNodes(t *testing.T) {
+	initHeavy := scheduled("migrate", "worker-1", corev1.PodRunning, requests("100m", "64Mi"))
+	initHeavy.Spec.InitContainers = []corev1.Container{{Name: "init", Resources: requests("1", "32Mi")}}
+	initHeavy.Spec.Overhead = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m"), corev1.ResourceMemory: resource.MustParse("1Mi")}
+
+	client := fake.NewSimpleClientset(
+		withAllocatable(node("worker-1", corev1.NodeReady), "4", "8Gi", "110", map[string]string{"pool": "general"}),
+		withAllocatable(node("worker-2", corev1.NodeReady, corev1.NodeDiskPressure), "2", "4Gi", "110", map[string]string{"pool": "general"}),
+		withAllocatable(node("gpu-1", corev1.NodeMemoryPressure, corev1.NodePIDPressure), "8", "32Gi", "64", map[string]string{"pool": "gpu"}),
+		scheduled("web", "worker-1", corev1.PodRunning, requests("250m", "256Mi"), requests("50m", "64Mi")),
+		initHeavy,
+		scheduled("batch", "worker-1", corev1.PodSucceeded, requests("2", "1Gi")),
+		scheduled("queued", "", corev1.PodPending, requests("1", "1Gi")),
+		scheduled("trainer", "gpu-1", corev1.PodPending, requests("4", "16Gi")),
+	)
+
+	nodes, err := CollectNodes(context.Background(), client, NodeFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]models.NodeHealth{}
+	for _, n := range nodes {
+		byName[n.Name] = n
+	}
+
+	// web counts its containers' sum; migrate its init container's larger
+	// CPU, its app container's larger memory, plus overhead. The finished
+	// and unscheduled pods count nowhere.
+	wantResources := map[string]models.NodeResources{
+		"worker-1": {
+			CPUAllocatableMillis: 4000, CPURequestedMillis: 300 + 1010,
+			MemoryAllocatableBytes: 8 << 30, MemoryRequestedBytes: (320 + 65) << 20,
+			PodsAllocatable: 110, PodsScheduled: 2,
+		},
+		"worker-2": {CPUAllocatableMillis: 2000, MemoryAllocatableBytes: 4 << 30, PodsAllocatable: 110},
+		"gpu-1": {
+			CPUAllocatableMillis: 8000, CPURequestedMillis: 4000,
+			MemoryAllocatableBytes: 32 << 30, MemoryRequestedBytes: 16 << 30,
+			PodsAllocatable: 64, PodsScheduled: 1,
+		},
+	}
+	wantConditions := map[string]models.NodeConditions{
+		"worker-1": {Ready: true},
+		"worker-2": {Ready: true, DiskPressure: true},
+		"gpu-1":    {MemoryPressure: true, PIDPressure: true},
+	}
+	if len(byName) != len(wantResources) {
+		t.Fatalf("nodes = %+v", nodes)
+	}
+	for name, want := range wantResources {
+		got := byName[name]
+		if got.Resources != want {
+			t.Errorf("%s resources = %+v, want %+v", name, got.Resources, want)
+		}
+		if got.Conditions != wantConditions[name] {
+			t.Errorf("%s conditions = %+v, want %+v", name, got.Conditions, wantConditions[name])
+		}
+		if got.Info.KubeletVersion != "v1.30.2" || got.Labels["pool"] == "" {
+			t.Errorf("%s info %+v, labels %v", name, got.Info, got.Labels)
+		}
+	}
+}
+
+func TestCollectNodesFilter(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		withAllocatable(node("worker-1", corev1.NodeReady), "4", "8Gi", "110", map[string]string{"pool": "general"}),
+		withAllocatable(node("worker-2", corev1.NodeReady, corev1.NodeDiskPressure), "2", "4Gi", "110", map[string]string{"pool": "general"}),
+		withAllocatable(node("gpu-1", corev1.NodeMemoryPressure), "8", "32Gi", "64", map[string]string{"pool": "gpu"}),
+		withAllocatable(node("gpu-2", corev1.NodeReady, corev1.NodeMemoryPressure), "8", "32Gi", "64", map[string]string{"pool": "gpu"}),
+	)
+
+	tests := []struct {
+		name      string
+		filter    NodeFilter
+		wantNames []string
+		wantErr   error
+	}{
+		{name: "everything", wantNames: []string{"gpu-1", "gpu-2", "worker-1", "worker-2"}},
+		{name: "label selector", filter: NodeFilter{LabelSelector: "pool=gpu"}, wantNames: []string{"gpu-1", "gpu-2"}},
+		{name: "set selector", filter: NodeFilter{LabelSelector: "pool notin (gpu)"}, wantNames: []string{"worker-1", "worker-2"}},
+		{name: "ready", filter: NodeFilter{Conditions: []string{models.NodeConditionReady}}, wantNames: []string{"gpu-2", "worker-1", "worker-2"}},
+		{name: "not ready", filter: NodeFilter{Conditions: []string{models.NodeConditionNotReady}}, wantNames: []string{"gpu-1"}},
+		{name: "disk pressure", filter: NodeFilter{Conditions: []string{models.NodeConditionDiskPressure}}, wantNames: []string{"worker-2"}},
+		{name: "PID pressure", filter: NodeFilter{Conditions: []string{models.NodeConditionPIDPressure}}},
+		{
+			name:      "conditions are ANDed",
+			filter:    NodeFilter{Conditions: []string{models.NodeConditionReady, models.NodeConditionMemoryPressure}},
+			wantNames: []string{"gpu-2"},
+		},
+		{
+			name:      "selector and condition",
+			filter:    NodeFilter{LabelSelector: "pool=gpu", Conditions: []string{models.NodeConditionNotReady}},
+			wantNames: []string{"gpu-1"},
+		},
+		{name: "unknown condition", filter: NodeFilter{Conditions: []string{"NetworkUnavailable"}}, wantErr: ErrInvalidFilter},
+		{name: "bad selector", filter: NodeFilter{LabelSelector: "pool in gpu"}, wantErr: ErrInvalidFilter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := CollectNodes(context.Background(), client, tt.filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CollectNodes error = %v, want %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, n := range nodes {
+				names = append(names, n.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// PodFilter narrows and pages a pod listing. An empty Namespace lists all
+// namespaces. Page is 1-based.
+type PodFilter struct {
+	Namespace     string
+	LabelSelector string
+	ProblemsOnly  bool
+	Page          int
+	PageSize      int
+}
+
+// CollectPods lists pods with container state, failure reasons and owning
+// workload. Pages are cut after problem filtering so ProblemsOnly pages stay
+// full; results are ordered by namespace and name.
+func CollectPods(ctx context.Context, client kubernetes.Interface, filter PodFilter) (models.PodList, error) {
+	if _, err := labels.Parse(filter.LabelSelector); err != nil {
+		return models.PodList{}, fmt.Errorf("%w: labelSelector: %v", ErrInvalidFilter, err)
+	}
+	if filter.Page < 1 {
+		return models.PodList{}, fmt.Errorf("%w: page must be at least 1", ErrInvalidFilter)
+	}
+	if filter.PageSize < 1 || filter.PageSize > MaxPageSize {
+		return models.PodList{}, fmt.Errorf("%w: pageSize must be between 1 and %d", ErrInvalidFilter, MaxPageSize)
+	}
+
+	pods, err := listPodsMatching(ctx, client, filter.Namespace, filter.LabelSelector)
+	if err != nil {
+		return models.PodList{}, fmt.Errorf("list pods: %w", err)
+	}
+	owners, err := newOwnerResolver(ctx, client, filter.Namespace)
+	if err != nil {
+		return models.PodList{}, err
+	}
+
+	var items []models.PodHealth
+	for _, pod := range pods {
+		health := podHealth(pod, owners)
+		if filter.ProblemsOnly && len(health.Problems) == 0 {
+			continue
+		}
+		items = append(items, health)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	list := models.PodList{Total: len(items), Page: filter.Page, PageSize: filter.PageSize, Items: []models.PodHealth{}}
+	// Compare page numbers rather than offsets: (Page-1)*PageSize overflows
+	// for a huge page and would slice from a negative start.
+	if pages := (len(items) + filter.PageSize - 1) / filter.PageSize; filter.Page <= pages {
+		start := (filter.Page - 1) * filter.PageSize
+		end := start + filter.PageSize
+		if end > len(items) {
+			end = len(items)
+		}
+		list.Items = items[start:end]
+	}
+	return list, nil
+}
+
+func listPodsMatching(ctx context.Context, client kubernetes.Interface, namespace, selector string) ([]corev1.Pod, error) {
+	var pods []corev1.Pod
+	opts := metav1.ListOptions{Limit: listPageSize, LabelSelector: selector}
+	for {
+		page, err := client.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, page.Items...)
+		if page.Continue == "" {
+			return pods, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+func podHealth(pod corev1.Pod, owners ownerResolver) models.PodHealth {
+	health := models.PodHealth{
+		Name:       pod.Name,
+		Namespace:  pod.Namespace,
+		NodeName:   pod.Spec.NodeName,
+		Phase:      string(pod.Status.Phase),
+		Reason:     pod.Status.Reason,
+		Ready:      podReady(pod),
+		Owner:      owners.resolve(pod),
+		Containers: []models.ContainerHealth{},
+		Problems:   []string{},
+	}
+	if pod.Status.StartTime != nil {
+		health.StartTime = pod.Status.StartTime.UTC().Format(time.RFC3339)
+	}
+
+	probes := map[string]bool{}
+	for _, container := range pod.Spec.Containers {
+		probes[container.Name] = container.ReadinessProbe != nil
+	}
+
+	problems := map[string]bool{}
+	for _, status := range pod.Status.ContainerStatuses {
+		container := containerHealth(status, probes[status.Name])
+		health.Containers = append(health.Containers, container)
+		health.Restarts += status.RestartCount
+
+		switch container.StateReason {
+		case models.PodProblemCrashLoopBackOff, models.PodProblemConfigError:
+			problems[container.StateReason] = true
+		case models.PodProblemImagePull, "ErrImagePull":
+			problems[models.PodProblemImagePull] = true
+		case models.PodProblemOOMKilled:
+			problems[models.PodProblemOOMKilled] = true
+		}
+		if container.LastTerminationReason == models.PodProblemOOMKilled {
+			problems[models.PodProblemOOMKilled] = true
+		}
+	}
+
+	switch pod.Status.Phase {
+	case corev1.PodFailed:
+		if pod.Status.Reason == models.PodProblemEvicted {
+			problems[models.PodProblemEvicted] = true
+		} else {
+			problems[models.PodProblemFailed] = true
+		}
+	case corev1.PodUnknown:
+		problems[models.PodProblemUnknown] = true
+	case corev1.PodPending:
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+				problems[models.PodProblemUnschedulable] = true
+			}
+		}
+	case corev1.PodRunning:
+		if !health.Ready && len(problems) == 0 {
+			problems[models.PodProblemNotReady] = true
+		}
+	}
+
+	for problem := range problems {
+		health.Problems = append(health.Problems, problem)
+	}
+	sort.Strings(health.Problems)
+	return health
+}
+
+func containerHealth(status corev1.ContainerStatus, hasReadinessProbe bool) models.ContainerHealth {
+	container := models.ContainerHealth{
+		Name:           status.Name,
+		Ready:          status.Ready,
+		RestartCount:   status.RestartCount,
+		ReadinessProbe: models.ProbeNone,
+	}
+	if hasReadinessProbe {
+		container.ReadinessProbe = models.ProbeFailing
+		if status.Ready {
+			container.ReadinessProbe = models.ProbePassing
+		}
+	}
+
+	switch {
+	case status.State.Running != nil:
+		container.State = "running"
+	case status.State.Waiting != nil:
+		container.State = "waiting"
+		container.StateReason = status.State.Waiting.Reason
+	case status.State.Terminated != nil:
+		container.State = "terminated"
+		container.StateReason = status.State.Terminated.Reason
+	default:
+		container.State = "unknown"
+	}
+
+	if last := status.LastTerminationState.Terminated; last != nil {
+		container.LastTerminationReason = last.Reason
+		container.LastExitCode = last.ExitCode
+		if !last.FinishedAt.IsZero() {
+			container.LastTerminatedAt = last.FinishedAt.UTC().Format(time.RFC3339)
+		}
+	}
+	return container
+}
+
+// ownerResolver walks pod owners up to their top-level workload, so a pod
+// created by a ReplicaSet reports its Deployment and a Job pod its CronJob.
+type ownerResolver struct {
+	replicaSets map[string]metav1.OwnerReference
+	jobs        map[string]metav1.OwnerReference
+}
+
+func newOwnerResolver(ctx context.Context, client kubernetes.Interface, namespace string) (ownerResolver, error) {
+	resolver := ownerResolver{
+		replicaSets: map[string]metav1.OwnerReference{},
+		jobs:        map[string]metav1.OwnerReference{},
+	}
+
+	replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ownerResolver{}, fmt.Errorf("list replicasets: %w", err)
+	}
+	for _, rs := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&rs); owner != nil {
+			resolver.replicaSets[rs.Namespace+"/"+rs.Name] = *owner
+		}
+	}
+
+	jobs, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ownerResolver{}, fmt.Errorf("list jobs: %w", err)
+	}
+	for _, job := range jobs.Items {
+		if owner := metav1.GetControllerOf(&job); owner != nil {
+			resolver.jobs[job.Namespace+"/"+job.Name] = *owner
+		}
+	}
+	return resolver, nil
+}
+
+func (r ownerResolver) resolve(pod corev1.Pod) *models.WorkloadRef {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return nil
+	}
+
+	key := pod.Namespace + "/" + owner.Name
+	switch owner.Kind {
+	case "ReplicaSet":
+		if parent, ok := r.replicaSets[key]; ok {
+			return &models.WorkloadRef{Kind: parent.Kind, Name: parent.Name}
+		}
+	case "Job":
+		if parent, ok := r.jobs[key]; ok {
+			return &models.WorkloadRef{Kind: parent.Kind, Name: parent.Name}
+		}
+	}
+	return &models.WorkloadRef{Kind: owner.Kind, Name: owner.Name}
+}
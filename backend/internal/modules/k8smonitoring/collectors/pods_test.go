@@ -0,0 +1,84 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+func crashLooping(p *corev1.Pod) *corev1.Pod {
+	p.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         "app",
+		RestartCount: 7,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: models.PodProblemCrashLoopBackOff}},
+	}}
+	return p
+}
+
+func TestCollectPodsPaging(t *testing.T) {
+	var objects []runtime.Object
+	for i := 0; i < 5; i++ {
+		objects = append(objects, pod("default", fmt.Sprintf("web-%d", i), corev1.PodRunning, true, nil))
+	}
+	objects = append(objects, crashLooping(pod("default", "worker", corev1.PodRunning, false, nil)))
+	client := fake.NewSimpleClientset(objects...)
+
+	tests := []struct {
+		name      string
+		filter    PodFilter
+		wantNames []string
+		wantTotal int
+		wantErr   error
+	}{
+		{name: "first page", filter: PodFilter{Page: 1, PageSize: 2}, wantNames: []string{"web-0", "web-1"}, wantTotal: 6},
+		{name: "last partial page", filter: PodFilter{Page: 2, PageSize: 4}, wantNames: []string{"web-4", "worker"}, wantTotal: 6},
+		{name: "past the end", filter: PodFilter{Page: 4, PageSize: 2}, wantTotal: 6},
+		{name: "huge page", filter: PodFilter{Page: math.MaxInt, PageSize: MaxPageSize}, wantTotal: 6},
+		{name: "huge page small size", filter: PodFilter{Page: math.MaxInt / 2, PageSize: 3}, wantTotal: 6},
+		{name: "problems only", filter: PodFilter{Page: 1, PageSize: 10, ProblemsOnly: true}, wantNames: []string{"worker"}, wantTotal: 1},
+		{name: "page zero", filter: PodFilter{Page: 0, PageSize: 10}, wantErr: ErrInvalidFilter},
+		{name: "page size too large", filter: PodFilter{Page: 1, PageSize: MaxPageSize + 1}, wantErr: ErrInvalidFilter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := CollectPods(context.Background(), client, tt.filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CollectPods error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if list.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", list.Total, tt.wantTotal)
+			}
+			var names []string
+			for _, item := range list.Items {
+				names = append(names, item.Name)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestCollectPodsReportsCrashLoop(t *testing.T) {
+	client := fake.NewSimpleClientset(crashLooping(pod("default", "worker", corev1.PodRunning, false, nil)))
+
+	list, err := CollectPods(context.Background(), client, PodFilter{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	worker := list.Items[0]
+	if worker.Restarts != 7 || len(worker.Problems) != 1 || worker.Problems[0] != models.PodProblemCrashLoopBackOff {
+		t.Fatalf("worker = %+v", worker)
+	}
+}
@@ -0,0 +1,197 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+// MetricNameLabel holds a series' metric name, as in Prometheus.
+const MetricNameLabel = "__name__"
+
+var podPhases = []corev1.PodPhase{
+	corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown,
+}
+
+var claimPhases = []corev1.PersistentVolumeClaimPhase{
+	corev1.ClaimPending, corev1.ClaimBound, corev1.ClaimLost,
+}
+
+var conditionStatuses = []corev1.ConditionStatus{
+	corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown,
+}
+
+// CollectSeries snapshots cluster state as kube-state-metrics style series,
+// so alert rules can be written against the same names whether or not
+// Prometheus scrapes the cluster. Nodes and pods are required; resource
+// kinds the credentials may not read are skipped.
+func CollectSeries(ctx context.Context, client kubernetes.Interface) ([]models.Series, error) {
+	var series []models.Series
+	add := func(name string, value float64, labels ...string) {
+		set := map[string]string{MetricNameLabel: name}
+		for i := 0; i+1 < len(labels); i += 2 {
+			set[labels[i]] = labels[i+1]
+		}
+		series = append(series, models.Series{Labels: set, Value: value})
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		for _, cond := range node.Status.Conditions {
+			for _, status := range conditionStatuses {
+				add("kube_node_status_condition", boolValue(cond.Status == status),
+					"node", node.Name, "condition", string(cond.Type), "status", toLowerStatus(status))
+			}
+		}
+		add("kube_node_spec_unschedulable", boolValue(node.Spec.Unschedulable), "node", node.Name)
+	}
+
+	pods, err := listPods(ctx, client, metav1.NamespaceAll)
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	for _, pod := range pods {
+		for _, phase := range podPhases {
+			add("kube_pod_status_phase", boolValue(pod.Status.Phase == phase),
+				"namespace", pod.Namespace, "pod", pod.Name, "phase", string(phase))
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			labels := []string{"namespace", pod.Namespace, "pod", pod.Name, "container", cs.Name}
+			add("kube_pod_container_status_restarts_total", float64(cs.RestartCount), labels...)
+			add("kube_pod_container_status_ready", boolValue(cs.Ready), labels...)
+			if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+				add("kube_pod_container_status_waiting_reason", 1, append(labels, "reason", cs.State.Waiting.Reason)...)
+			}
+			if cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason != "" {
+				add("kube_pod_container_status_last_terminated_reason", 1,
+					append(labels, "reason", cs.LastTerminationState.Terminated.Reason)...)
+			}
+		}
+	}
+
+	if deployments, err := client.AppsV1().Deployments(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		if !skippable(err) {
+			return nil, fmt.Errorf("list deployments: %w", err)
+		}
+	} else {
+		for _, d := range deployments.Items {
+			labels := []string{"namespace", d.Namespace, "deployment", d.Name}
+			add("kube_deployment_spec_replicas", float64(desiredReplicas(d.Spec.Replicas)), labels...)
+			add("kube_deployment_status_replicas_available", float64(d.Status.AvailableReplicas), labels...)
+			add("kube_deployment_status_replicas_ready", float64(d.Status.ReadyReplicas), labels...)
+			add("kube_deployment_status_replicas_unavailable", float64(d.Status.UnavailableReplicas), labels...)
+			add("kube_deployment_status_observed_generation", float64(d.Status.ObservedGeneration), labels...)
+			add("kube_deployment_metadata_generation", float64(d.Generation), labels...)
+		}
+	}
+
+	if statefulSets, err := client.AppsV1().StatefulSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		if !skippable(err) {
+			return nil, fmt.Errorf("list statefulsets: %w", err)
+		}
+	} else {
+		for _, sts := range statefulSets.Items {
+			labels := []string{"namespace", sts.Namespace, "statefulset", sts.Name}
+			add("kube_statefulset_replicas", float64(desiredReplicas(sts.Spec.Replicas)), labels...)
+			add("kube_statefulset_status_replicas_ready", float64(sts.Status.ReadyReplicas), labels...)
+			add("kube_statefulset_status_observed_generation", float64(sts.Status.ObservedGeneration), labels...)
+			add("kube_statefulset_metadata_generation", float64(sts.Generation), labels...)
+		}
+	}
+
+	if daemonSets, err := client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		if !skippable(err) {
+			return nil, fmt.Errorf("list daemonsets: %w", err)
+		}
+	} else {
+		for _, ds := range daemonSets.Items {
+			labels := []string{"namespace", ds.Namespace, "daemonset", ds.Name}
+			add("kube_daemonset_status_desired_number_scheduled", float64(ds.Status.DesiredNumberScheduled), labels...)
+			add("kube_daemonset_status_number_ready", float64(ds.Status.NumberReady), labels...)
+			add("kube_daemonset_status_number_unavailable", float64(ds.Status.NumberUnavailable), labels...)
+		}
+	}
+
+	if jobs, err := client.BatchV1().Jobs(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		if !skippable(err) {
+			return nil, fmt.Errorf("list jobs: %w", err)
+		}
+	} else {
+		for _, job := range jobs.Items {
+			labels := []string{"namespace", job.Namespace, "job_name", job.Name}
+			add("kube_job_status_active", float64(job.Status.Active), labels...)
+			add("kube_job_status_succeeded", float64(job.Status.Succeeded), labels...)
+			add("kube_job_status_failed", float64(job.Status.Failed), labels...)
+		}
+	}
+
+	if quotas, err := client.CoreV1().ResourceQuotas(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		if !skippable(err) {
+			return nil, fmt.Errorf("list resourcequotas: %w", err)
+		}
+	} else {
+		for _, quota := range quotas.Items {
+			for resource, hard := range quota.Status.Hard {
+				labels := []string{"namespace", quota.Namespace, "resourcequota", quota.Name, "resource", string(resource)}
+				add("kube_resourcequota", hard.AsApproximateFloat64(), append(labels, "type", "hard")...)
+				if used, ok := quota.Status.Used[resource]; ok {
+					add("kube_resourcequota", used.AsApproximateFloat64(), append(labels, "type", "used")...)
+				}
+			}
+		}
+	}
+
+	if claims, err := client.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		if !skippable(err) {
+			return nil, fmt.Errorf("list persistentvolumeclaims: %w", err)
+		}
+	} else {
+		for _, claim := range claims.Items {
+			for _, phase := range claimPhases {
+				add("kube_persistentvolumeclaim_status_phase", boolValue(claim.Status.Phase == phase),
+					"namespace", claim.Namespace, "persistentvolumeclaim", claim.Name, "phase", string(phase))
+			}
+		}
+	}
+
+	components, _ := controlPlaneHealth(ctx, client, pods)
+	for _, component := range components {
+		add("devoptics_control_plane_component_healthy", boolValue(component.Healthy), "component", component.Name)
+	}
+
+	return series, nil
+}
+
+// skippable reports list errors that mean the kind is unavailable to these
+// credentials rather than that the cluster is unreachable.
+func skippable(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsNotFound(err)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// toLowerStatus renders a condition status the way kube-state-metrics does.
+func toLowerStatus(status corev1.ConditionStatus) string {
+	switch status {
+	case corev1.ConditionTrue:
+		return "true"
+	case corev1.ConditionFalse:
+		return "false"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,56 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/prometheus"
+)
+
+// UtilizationQueries builds the node-exporter PromQL used for cluster CPU and
+// memory utilisation, selecting the cluster by clusterLabel.
+func UtilizationQueries(clusterLabel, cluster string) (cpu, memory string) {
+	selector := fmt.Sprintf("%s=%s", clusterLabel, prometheus.QuoteLabelValue(cluster))
+	cpu = fmt.Sprintf(`100 * (1 - avg(rate(node_cpu_seconds_total{mode="idle",%s}[5m])))`, selector)
+	memory = fmt.Sprintf(`100 * (1 - sum(node_memory_MemAvailable_bytes{%s}) / sum(node_memory_MemTotal_bytes{%s}))`, selector, selector)
+	return cpu, memory
+}
+
+// CollectUtilization queries Prometheus for the cluster's current CPU and
+// memory utilisation as percentages.
+func CollectUtilization(ctx context.Context, prom prometheus.Client, clusterLabel, cluster string) (models.ResourceUtilization, error) {
+	cpuQuery, memoryQuery := UtilizationQueries(clusterLabel, cluster)
+	now := time.Now()
+
+	cpu, err := queryScalar(ctx, prom, cpuQuery, now)
+	if err != nil {
+		return models.ResourceUtilization{}, fmt.Errorf("cpu utilisation: %w", err)
+	}
+	memory, err := queryScalar(ctx, prom, memoryQuery, now)
+	if err != nil {
+		return models.ResourceUtilization{}, fmt.Errorf("memory utilisation: %w", err)
+	}
+	return models.ResourceUtilization{CPUPercent: round2(cpu), MemoryPercent: round2(memory)}, nil
+}
+
+func queryScalar(ctx context.Context, prom prometheus.Client, query string, ts time.Time) (float64, error) {
+	result, err := prom.Query(ctx, query, ts)
+	if err != nil {
+		return 0, err
+	}
+	value, err := result.ScalarValue()
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, prometheus.ErrEmptyResult
+	}
+	return value, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,56 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/prometheus"
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+func scalarResponse(value string) string {
+	return `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1760702400,"` + value + `"]}]}}`
+}
+
+func TestCollectUtilization(t *testing.T) {
+	prodCPU, prodMemory := UtilizationQueries("cluster", "prod")
+	idleCPU, idleMemory := UtilizationQueries("cluster", "idle")
+	_, stagingMemory := UtilizationQueries("cluster", "staging")
+	recording := testutil.PrometheusRecording{
+		prodCPU:       scalarResponse("42.123"),
+		prodMemory:    scalarResponse("63.456"),
+		idleCPU:       scalarResponse("NaN"),
+		idleMemory:    scalarResponse("10"),
+		stagingMemory: scalarResponse("10"),
+	}
+	prom, err := prometheus.NewClient(prometheus.Options{URL: "http://prometheus.test", Transport: recording})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utilization, err := CollectUtilization(context.Background(), prom, "cluster", "prod")
+	if err != nil {
+		t.Fatalf("CollectUtilization: %v", err)
+	}
+	if utilization.CPUPercent != 42.12 || utilization.MemoryPercent != 63.46 {
+		t.Errorf("utilization = %+v, want 42.12%% cpu and 63.46%% memory", utilization)
+	}
+
+	// NaN comes from dividing by zero series, i.e. no data for the cluster.
+	if _, err := CollectUtilization(context.Background(), prom, "cluster", "idle"); !errors.Is(err, prometheus.ErrEmptyResult) {
+		t.Errorf("NaN cpu: err = %v, want ErrEmptyResult", err)
+	}
+	var apiErr *prometheus.APIError
+	if _, err := CollectUtilization(context.Background(), prom, "cluster", "staging"); !errors.As(err, &apiErr) {
+		t.Errorf("failed cpu query: err = %v, want an APIError", err)
+	}
+}
+
+func TestUtilizationQueriesQuoteCluster(t *testing.T) {
+	cpu, _ := UtilizationQueries("cluster", `a"b`)
+	if want := `cluster="a\"b"`; !strings.Contains(cpu, want) {
+		t.Errorf("cpu query %q does not select %s", cpu, want)
+	}
+}
@@ -0,0 +1,301 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+const (
+	revisionAnnotation        = "deployment.kubernetes.io/revision"
+	revisionHistoryAnnotation = "deployment.kubernetes.io/revision-history"
+
+	// StatefulSets have no progress deadline; treat a rollout that has not
+	// finished this long after it began as stalled.
+	statefulSetStallAfter = 10 * time.Minute
+)
+
+// WorkloadFilter narrows a workload listing. An empty Namespace lists all
+// namespaces and an empty Kind lists both Deployments and StatefulSets.
+type WorkloadFilter struct {
+	Namespace string
+	Kind      string
+}
+
+// CollectRollouts reports replica counts, rollout status and revision
+// history for Deployments and StatefulSets.
+func CollectRollouts(ctx context.Context, client kubernetes.Interface, filter WorkloadFilter) ([]models.WorkloadRollout, error) {
+	if filter.Kind != "" && filter.Kind != models.KindDeployment && filter.Kind != models.KindStatefulSet {
+		return nil, fmt.Errorf("%w: kind must be %s or %s", ErrInvalidFilter, models.KindDeployment, models.KindStatefulSet)
+	}
+
+	rollouts := []models.WorkloadRollout{}
+	if filter.Kind == "" || filter.Kind == models.KindDeployment {
+		deployments, err := collectDeployments(ctx, client, filter.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		rollouts = append(rollouts, deployments...)
+	}
+	if filter.Kind == "" || filter.Kind == models.KindStatefulSet {
+		statefulSets, err := collectStatefulSets(ctx, client, filter.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		rollouts = append(rollouts, statefulSets...)
+	}
+	return rollouts, nil
+}
+
+func collectDeployments(ctx context.Context, client kubernetes.Interface, namespace string) ([]models.WorkloadRollout, error) {
+	deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list deployments: %w", err)
+	}
+	replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list replicasets: %w", err)
+	}
+
+	owned := map[types.UID][]appsv1.ReplicaSet{}
+	for _, rs := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&rs); owner != nil && owner.Kind == models.KindDeployment {
+			owned[owner.UID] = append(owned[owner.UID], rs)
+		}
+	}
+
+	rollouts := make([]models.WorkloadRollout, 0, len(deployments.Items))
+	for _, deployment := range deployments.Items {
+		rollouts = append(rollouts, deploymentRollout(deployment, owned[deployment.UID]))
+	}
+	return rollouts, nil
+}
+
+func deploymentRollout(deployment appsv1.Deployment, replicaSets []appsv1.ReplicaSet) models.WorkloadRollout {
+	rollout := models.WorkloadRollout{
+		Kind:      models.KindDeployment,
+		Name:      deployment.Name,
+		Namespace: deployment.Namespace,
+		Replicas: models.ReplicaCounts{
+			Desired:   desiredReplicas(deployment.Spec.Replicas),
+			Ready:     deployment.Status.ReadyReplicas,
+			Updated:   deployment.Status.UpdatedReplicas,
+			Available: deployment.Status.AvailableReplicas,
+		},
+		Generation:         deployment.Generation,
+		ObservedGeneration: deployment.Status.ObservedGeneration,
+		CurrentRevision:    parseRevision(deployment.Annotations[revisionAnnotation]),
+		Revisions:          []models.WorkloadRevision{},
+	}
+	// Same comparison as the KubernetesDeploymentGenerationMismatch rule.
+	rollout.GenerationMismatch = rollout.ObservedGeneration != rollout.Generation
+
+	for _, rs := range replicaSets {
+		revision := models.WorkloadRevision{
+			Revision:  parseRevision(rs.Annotations[revisionAnnotation]),
+			Name:      rs.Name,
+			Images:    templateImages(rs.Spec.Template),
+			CreatedAt: rs.CreationTimestamp.UTC().Format(time.RFC3339),
+		}
+		revision.Current = revision.Revision == rollout.CurrentRevision
+		// A rollback re-activates an old ReplicaSet, which records the
+		// revisions it previously held.
+		if revision.Current && rs.Annotations[revisionHistoryAnnotation] != "" {
+			rollout.RolledBack = true
+		}
+		rollout.Revisions = append(rollout.Revisions, revision)
+	}
+	sortRevisions(rollout.Revisions)
+
+	progressing := deploymentCondition(deployment, appsv1.DeploymentProgressing)
+	switch {
+	case deployment.Spec.Paused:
+		rollout.Status = models.RolloutPaused
+	case progressing != nil && progressing.Reason == "ProgressDeadlineExceeded":
+		rollout.Status = models.RolloutStalled
+		rollout.Message = progressing.Message
+	case rollout.GenerationMismatch:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = "controller has not observed the latest spec"
+	case rollout.Replicas.Updated == rollout.Replicas.Desired &&
+		rollout.Replicas.Available == rollout.Replicas.Desired &&
+		deployment.Status.Replicas == rollout.Replicas.Desired:
+		rollout.Status = models.RolloutComplete
+	default:
+		rollout.Status = models.RolloutProgressing
+		if progressing != nil {
+			rollout.Message = progressing.Message
+		}
+	}
+	return rollout
+}
+
+func deploymentCondition(deployment appsv1.Deployment, conditionType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range deployment.Status.Conditions {
+		if deployment.Status.Conditions[i].Type == conditionType {
+			return &deployment.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func collectStatefulSets(ctx context.Context, client kubernetes.Interface, namespace string) ([]models.WorkloadRollout, error) {
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list statefulsets: %w", err)
+	}
+	revisions, err := client.AppsV1().ControllerRevisions(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list controllerrevisions: %w", err)
+	}
+
+	owned := map[types.UID][]appsv1.ControllerRevision{}
+	for _, cr := range revisions.Items {
+		if owner := metav1.GetControllerOf(&cr); owner != nil && owner.Kind == models.KindStatefulSet {
+			owned[owner.UID] = append(owned[owner.UID], cr)
+		}
+	}
+
+	rollouts := make([]models.WorkloadRollout, 0, len(statefulSets.Items))
+	for _, sts := range statefulSets.Items {
+		rollouts = append(rollouts, statefulSetRollout(sts, owned[sts.UID], time.Now()))
+	}
+	return rollouts, nil
+}
+
+func statefulSetRollout(sts appsv1.StatefulSet, history []appsv1.ControllerRevision, now time.Time) models.WorkloadRollout {
+	rollout := models.WorkloadRollout{
+		Kind:      models.KindStatefulSet,
+		Name:      sts.Name,
+		Namespace: sts.Namespace,
+		Replicas: models.ReplicaCounts{
+			Desired:   desiredReplicas(sts.Spec.Replicas),
+			Ready:     sts.Status.ReadyReplicas,
+			Updated:   sts.Status.UpdatedReplicas,
+			Available: sts.Status.AvailableReplicas,
+		},
+		Generation:         sts.Generation,
+		ObservedGeneration: sts.Status.ObservedGeneration,
+		Revisions:          []models.WorkloadRevision{},
+	}
+	rollout.GenerationMismatch = rollout.ObservedGeneration != rollout.Generation
+
+	var update *appsv1.ControllerRevision
+	for i, cr := range history {
+		revision := models.WorkloadRevision{
+			Revision:  cr.Revision,
+			Name:      cr.Name,
+			Images:    controllerRevisionImages(cr),
+			CreatedAt: cr.CreationTimestamp.UTC().Format(time.RFC3339),
+			Current:   cr.Name == sts.Status.UpdateRevision,
+		}
+		if revision.Current {
+			rollout.CurrentRevision = cr.Revision
+			update = &history[i]
+		}
+		rollout.Revisions = append(rollout.Revisions, revision)
+	}
+	sortRevisions(rollout.Revisions)
+	if update != nil {
+		rollout.RolledBack = restoredRevision(*update, history)
+	}
+
+	done := sts.Status.CurrentRevision == sts.Status.UpdateRevision &&
+		rollout.Replicas.Updated == rollout.Replicas.Desired &&
+		rollout.Replicas.Ready == rollout.Replicas.Desired
+	switch {
+	case rollout.GenerationMismatch:
+		rollout.Status = models.RolloutProgressing
+		rollout.Message = "controller has not observed the latest spec"
+	case done:
+		rollout.Status = models.RolloutComplete
+	case update != nil && now.Sub(rolloutStarted(sts, *update)) > statefulSetStallAfter:
+		rollout.Status = models.RolloutStalled
+		rollout.Message = fmt.Sprintf("rollout to %s not finished after %s", sts.Status.UpdateRevision, statefulSetStallAfter)
+	default:
+		rollout.Status = models.RolloutProgressing
+	}
+	return rollout
+}
+
+// restoredRevision reports whether the update revision was restored from
+// an older one. ControllerRevision names carry the template hash, so rolling
+// back to an earlier template reuses that revision under a new, highest
+// number instead of creating one: it is then older than revisions it
+// outranks.
+func restoredRevision(update appsv1.ControllerRevision, history []appsv1.ControllerRevision) bool {
+	for _, cr := range history {
+		if cr.Name != update.Name && cr.Revision < update.Revision &&
+			update.CreationTimestamp.Before(&cr.CreationTimestamp) {
+			return true
+		}
+	}
+	return false
+}
+
+// rolloutStarted estimates when the current rollout began: the last spec
+// write recorded in managed fields, or the update revision's creation when
+// that is later or no spec write is recorded. The revision alone is too old
+// after a rollback, which reuses an existing ControllerRevision.
+func rolloutStarted(sts appsv1.StatefulSet, update appsv1.ControllerRevision) time.Time {
+	started := update.CreationTimestamp.Time
+	for _, entry := range sts.ManagedFields {
+		// Status writes go to the status subresource and do not start a
+		// rollout.
+		if entry.Subresource == "" && entry.Time != nil && entry.Time.After(started) {
+			started = entry.Time.Time
+		}
+	}
+	return started
+}
+
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+func parseRevision(value string) int64 {
+	revision, _ := strconv.ParseInt(value, 10, 64)
+	return revision
+}
+
+func templateImages(template corev1.PodTemplateSpec) []string {
+	images := make([]string, 0, len(template.Spec.Containers))
+	for _, container := range template.Spec.Containers {
+		images = append(images, container.Image)
+	}
+	return images
+}
+
+// controllerRevisionImages reads container images from the template patch a
+// StatefulSet stores in each ControllerRevision.
+func controllerRevisionImages(cr appsv1.ControllerRevision) []string {
+	var patch struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec `json:"template"`
+		} `json:"spec"`
+	}
+	if len(cr.Data.Raw) == 0 || json.Unmarshal(cr.Data.Raw, &patch) != nil {
+		return []string{}
+	}
+	return templateImages(patch.Spec.Template)
+}
+
+func sortRevisions(revisions []models.WorkloadRevision) {
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision > revisions[j].Revision
+	})
+}
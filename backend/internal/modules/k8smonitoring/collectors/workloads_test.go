@@ -0,0 +1,133 @@
+package collectors
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+func controllerRevision(name string, revision int64, created time.Time) appsv1.ControllerRevision {
+	return appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Revision:   revision,
+	}
+}
+
+func statefulSet(current, update string, updated int32, specWritten time.Time) appsv1.StatefulSet {
+	replicas := int32(3)
+	sts := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", Generation: 2},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 2,
+			CurrentRevision:    current,
+			UpdateRevision:     update,
+			UpdatedReplicas:    updated,
+			ReadyReplicas:      3,
+			AvailableReplicas:  3,
+		},
+	}
+	if !specWritten.IsZero() {
+		written := metav1.NewTime(specWritten)
+		sts.ManagedFields = []metav1.ManagedFieldsEntry{
+			{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, Time: &written},
+			// Status writes are newer but must not restart the stall timer.
+			{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: &metav1.Time{Time: specWritten.Add(time.Hour)}},
+		}
+	}
+	return sts
+}
+
+func TestStatefulSetRollout(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	// db-a was the first template, db-b the second. Rolling back to db-a
+	// renumbers it to 3 while it keeps its original creation time.
+	forward := []appsv1.ControllerRevision{
+		controllerRevision("db-a", 1, now.Add(-2*day)),
+		controllerRevision("db-b", 2, now.Add(-time.Minute)),
+	}
+	rolledBack := []appsv1.ControllerRevision{
+		controllerRevision("db-a", 3, now.Add(-2*day)),
+		controllerRevision("db-b", 2, now.Add(-day)),
+	}
+
+	tests := []struct {
+		name           string
+		sts            appsv1.StatefulSet
+		history        []appsv1.ControllerRevision
+		wantStatus     string
+		wantRolledBack bool
+		wantRevision   int64
+	}{
+		{
+			name:         "complete",
+			sts:          statefulSet("db-b", "db-b", 3, time.Time{}),
+			history:      forward,
+			wantStatus:   models.RolloutComplete,
+			wantRevision: 2,
+		},
+		{
+			name:         "new revision progressing",
+			sts:          statefulSet("db-a", "db-b", 1, time.Time{}),
+			history:      forward,
+			wantStatus:   models.RolloutProgressing,
+			wantRevision: 2,
+		},
+		{
+			name: "new revision stalled",
+			sts:  statefulSet("db-a", "db-b", 1, time.Time{}),
+			history: []appsv1.ControllerRevision{
+				controllerRevision("db-a", 1, now.Add(-2*day)),
+				controllerRevision("db-b", 2, now.Add(-time.Hour)),
+			},
+			wantStatus:   models.RolloutStalled,
+			wantRevision: 2,
+		},
+		{
+			name:           "rollback just started",
+			sts:            statefulSet("db-b", "db-a", 1, now.Add(-time.Minute)),
+			history:        rolledBack,
+			wantStatus:     models.RolloutProgressing,
+			wantRolledBack: true,
+			wantRevision:   3,
+		},
+		{
+			name:           "rollback stalled",
+			sts:            statefulSet("db-b", "db-a", 1, now.Add(-time.Hour)),
+			history:        rolledBack,
+			wantStatus:     models.RolloutStalled,
+			wantRolledBack: true,
+			wantRevision:   3,
+		},
+		{
+			name:           "rollback complete",
+			sts:            statefulSet("db-a", "db-a", 3, now.Add(-time.Hour)),
+			history:        rolledBack,
+			wantStatus:     models.RolloutComplete,
+			wantRolledBack: true,
+			wantRevision:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollout := statefulSetRollout(tt.sts, tt.history, now)
+			if rollout.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q (%s)", rollout.Status, tt.wantStatus, rollout.Message)
+			}
+			if rollout.RolledBack != tt.wantRolledBack {
+				t.Errorf("RolledBack = %v, want %v", rollout.RolledBack, tt.wantRolledBack)
+			}
+			if rollout.CurrentRevision != tt.wantRevision {
+				t.Errorf("CurrentRevision = %d, want %d", rollout.CurrentRevision, tt.wantRevision)
+			}
+			if len(rollout.Revisions) == 0 || rollout.Revisions[0].Revision < rollout.Revisions[len(rollout.Revisions)-1].Revision {
+				t.Errorf("Revisions not newest first: %+v", rollout.Revisions)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package dashboards
+
+import (
+	"fmt"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+)
+
+// Default prices for the cost dashboard, roughly the on-demand price of a
+// vCPU and a GiB of memory per hour. They are textbox variables, so each
+// dashboard can be set to the fleet's real rates.
+const (
+	defaultCPUHourlyCost    = "0.0316"
+	defaultMemoryHourlyCost = "0.0042"
+)
+
+// hoursPerMonth turns an hourly price into a monthly one.
+const hoursPerMonth = 730
+
+func clusterDashboard(g generator) content {
+	cpu, memory := collectors.UtilizationQueries(g.label, "$cluster")
+	return content{
+		title:       "Cluster",
+		description: "Node readiness, pod phases and resource use and commitment of a cluster.",
+		panels: []Panel{
+			stat("Nodes ready", "none",
+				fmt.Sprintf(`sum(kube_node_status_condition%s)`, g.sel(`condition="Ready"`, `status="true"`))),
+			stat("Nodes not ready", "none",
+				fmt.Sprintf(`count(kube_node_status_condition%s == 0) or vector(0)`, g.sel(`condition="Ready"`, `status="true"`)), 1),
+			stat("Running pods", "none",
+				fmt.Sprintf(`sum(kube_pod_status_phase%s)`, g.sel(`phase="Running"`))),
+			stat("Pending pods", "none",
+				fmt.Sprintf(`sum(kube_pod_status_phase%s)`, g.sel(`phase="Pending"`)), 1, 10),
+
+			percentGauge("CPU utilisation", cpu),
+			percentGauge("Memory utilisation", memory),
+			percentGauge("CPU requests committed",
+				fmt.Sprintf(`100 * sum(kube_pod_container_resource_requests%s) / sum(kube_node_status_allocatable%s)`,
+					g.sel(`resource="cpu"`), g.sel(`resource="cpu"`))),
+			percentGauge("Memory requests committed",
+				fmt.Sprintf(`100 * sum(kube_pod_container_resource_requests%s) / sum(kube_node_status_allocatable%s)`,
+					g.sel(`resource="memory"`), g.sel(`resource="memory"`))),
+
+			timeseries("Pods by phase", "none",
+				query(fmt.Sprintf(`sum by (phase) (kube_pod_status_phase%s)`, g.sel()), "{{phase}}")),
+			timeseries("Container restarts by namespace", "none",
+				query(fmt.Sprintf(`sum by (namespace) (increase(kube_pod_container_status_restarts_total%s[1h]))`, g.sel()), "{{namespace}}")),
+			timeseries("CPU usage by namespace", "cores",
+				query(fmt.Sprintf(`sum by (namespace) (rate(container_cpu_usage_seconds_total%s[5m]))`, g.sel(`container!=""`)), "{{namespace}}")),
+			timeseries("Memory usage by namespace", "bytes",
+				query(fmt.Sprintf(`sum by (namespace) (container_memory_working_set_bytes%s)`, g.sel(`container!=""`)), "{{namespace}}")),
+		},
+	}
+}
+
+func namespaceDashboard(g generator) content {
+	ns := `namespace="$namespace"`
+	return content{
+		title:       "Namespace",
+		description: "Pods, resource use against requests and quota consumption of a namespace.",
+		variables: []Variable{
+			queryVariable("namespace", "Namespace",
+				fmt.Sprintf(`label_values(kube_namespace_status_phase%s, namespace)`, g.sel())),
+		},
+		panels: []Panel{
+			stat("Running pods", "none",
+				fmt.Sprintf(`sum(kube_pod_status_phase%s)`, g.sel(ns, `phase="Running"`))),
+			stat("Pending pods", "none",
+				fmt.Sprintf(`sum(kube_pod_status_phase%s)`, g.sel(ns, `phase="Pending"`)), 1, 10),
+			stat("Restarts (1h)", "none",
+				fmt.Sprintf(`sum(increase(kube_pod_container_status_restarts_total%s[1h]))`, g.sel(ns)), 1, 10),
+			stat("Deployments", "none",
+				fmt.Sprintf(`count(kube_deployment_spec_replicas%s) or vector(0)`, g.sel(ns))),
+
+			timeseries("CPU usage by pod", "cores",
+				query(fmt.Sprintf(`sum by (pod) (rate(container_cpu_usage_seconds_total%s[5m]))`, g.sel(ns, `container!=""`)), "{{pod}}"),
+				query(fmt.Sprintf(`sum(kube_pod_container_resource_requests%s)`, g.sel(ns, `resource="cpu"`)), "requests")),
+			timeseries("Memory usage by pod", "bytes",
+				query(fmt.Sprintf(`sum by (pod) (container_memory_working_set_bytes%s)`, g.sel(ns, `container!=""`)), "{{pod}}"),
+				query(fmt.Sprintf(`sum(kube_pod_container_resource_requests%s)`, g.sel(ns, `resource="memory"`)), "requests")),
+			timeseries("Quota used", "percent",
+				query(fmt.Sprintf(`100 * sum by (resource) (kube_resourcequota%s) / sum by (resource) (kube_resourcequota%s)`,
+					g.sel(ns, `type="used"`), g.sel(ns, `type="hard"`)), "{{resource}}")),
+			timeseries("Network", "Bps",
+				query(fmt.Sprintf(`sum(rate(container_network_receive_bytes_total%s[5m]))`, g.sel(ns)), "receive"),
+				query(fmt.Sprintf(`sum(rate(container_network_transmit_bytes_total%s[5m]))`, g.sel(ns)), "transmit")),
+		},
+	}
+}
+
+func workloadDashboard(g generator) content {
+	ns := `namespace="$namespace"`
+	deployment := `deployment="$deployment"`
+	// Deployment pods are named <deployment>-<pod-template-hash>-<suffix>.
+	pods := `pod=~"$deployment-[a-z0-9]+-[a-z0-9]+"`
+	return content{
+		title:       "Workload",
+		description: "Rollout state and per-pod resource use of a Deployment, and StatefulSet readiness in its namespace.",
+		variables: []Variable{
+			queryVariable("namespace", "Namespace",
+				fmt.Sprintf(`label_values(kube_namespace_status_phase%s, namespace)`, g.sel())),
+			queryVariable("deployment", "Deployment",
+				fmt.Sprintf(`label_values(kube_deployment_spec_replicas%s, deployment)`, g.sel(ns))),
+		},
+		panels: []Panel{
+			stat("Desired replicas", "none",
+				fmt.Sprintf(`sum(kube_deployment_spec_replicas%s)`, g.sel(ns, deployment))),
+			stat("Available replicas", "none",
+				fmt.Sprintf(`sum(kube_deployment_status_replicas_available%s)`, g.sel(ns, deployment))),
+			stat("Unavailable replicas", "none",
+				fmt.Sprintf(`sum(kube_deployment_status_replicas_unavailable%s)`, g.sel(ns, deployment)), 1),
+			stat("Generations not observed", "none",
+				fmt.Sprintf(`sum(kube_deployment_metadata_generation%s) - sum(kube_deployment_status_observed_generation%s)`,
+					g.sel(ns, deployment), g.sel(ns, deployment)), 1),
+
+			timeseries("Replicas", "none",
+				query(fmt.Sprintf(`sum(kube_deployment_spec_replicas%s)`, g.sel(ns, deployment)), "desired"),
+				query(fmt.Sprintf(`sum(kube_deployment_status_replicas_updated%s)`, g.sel(ns, deployment)), "updated"),
+				query(fmt.Sprintf(`sum(kube_deployment_status_replicas_available%s)`, g.sel(ns, deployment)), "available")),
+			timeseries("Container restarts by pod", "none",
+				query(fmt.Sprintf(`sum by (pod) (increase(kube_pod_container_status_restarts_total%s[1h]))`, g.sel(ns, pods)), "{{pod}}")),
+			timeseries("CPU usage by pod", "cores",
+				query(fmt.Sprintf(`sum by (pod) (rate(container_cpu_usage_seconds_total%s[5m]))`, g.sel(ns, pods, `container!=""`)), "{{pod}}")),
+			timeseries("Memory usage by pod", "bytes",
+				query(fmt.Sprintf(`sum by (pod) (container_memory_working_set_bytes%s)`, g.sel(ns, pods, `container!=""`)), "{{pod}}")),
+			timeseries("StatefulSet ready replicas", "none",
+				query(fmt.Sprintf(`sum by (statefulset) (kube_statefulset_status_replicas_ready%s)`, g.sel(ns)), "{{statefulset}} ready"),
+				query(fmt.Sprintf(`sum by (statefulset) (kube_statefulset_replicas%s)`, g.sel(ns)), "{{statefulset}} desired")),
+		},
+	}
+}
+
+func nodeDashboard(g generator) content {
+	node := `node="$node"`
+	// node-exporter series carry the scrape instance, not the node name;
+	// node_uname_info maps one to the other.
+	onNode := func(metric string) string {
+		return fmt.Sprintf(`(%s * on(instance) group_left(nodename) node_uname_info%s)`, metric, g.sel(`nodename="$node"`))
+	}
+	return content{
+		title:       "Node",
+		description: "Conditions, capacity and node-exporter CPU, memory, disk and network use of a node.",
+		variables: []Variable{
+			queryVariable("node", "Node",
+				fmt.Sprintf(`label_values(kube_node_info%s, node)`, g.sel())),
+		},
+		panels: []Panel{
+			stat("Ready", "none",
+				fmt.Sprintf(`sum(kube_node_status_condition%s)`, g.sel(node, `condition="Ready"`, `status="true"`))),
+			stat("Pods", "none",
+				fmt.Sprintf(`count(kube_pod_info%s) or vector(0)`, g.sel(node))),
+			stat("Allocatable CPU", "cores",
+				fmt.Sprintf(`sum(kube_node_status_allocatable%s)`, g.sel(node, `resource="cpu"`))),
+			stat("Allocatable memory", "bytes",
+				fmt.Sprintf(`sum(kube_node_status_allocatable%s)`, g.sel(node, `resource="memory"`))),
+
+			percentGauge("CPU utilisation",
+				fmt.Sprintf(`100 * (1 - avg(%s))`, onNode(fmt.Sprintf(`rate(node_cpu_seconds_total%s[5m])`, g.sel(`mode="idle"`))))),
+			percentGauge("Memory utilisation",
+				fmt.Sprintf(`100 * (1 - sum(%s) / sum(%s))`,
+					onNode(fmt.Sprintf(`node_memory_MemAvailable_bytes%s`, g.sel())),
+					onNode(fmt.Sprintf(`node_memory_MemTotal_bytes%s`, g.sel())))),
+			percentGauge("CPU requests committed",
+				fmt.Sprintf(`100 * sum(kube_pod_container_resource_requests%s) / sum(kube_node_status_allocatable%s)`,
+					g.sel(node, `resource="cpu"`), g.sel(node, `resource="cpu"`))),
+			percentGauge("Memory requests committed",
+				fmt.Sprintf(`100 * sum(kube_pod_container_resource_requests%s) / sum(kube_node_status_allocatable%s)`,
+					g.sel(node, `resource="memory"`), g.sel(node, `resource="memory"`))),
+
+			timeseries("Conditions", "none",
+				query(fmt.Sprintf(`sum by (condition) (kube_node_status_condition%s)`, g.sel(node, `status="true"`)), "{{condition}}")),
+			timeseries("Disk used by mount point", "percent",
+				query(fmt.Sprintf(`100 * (1 - sum by (mountpoint) (%s) / sum by (mountpoint) (%s))`,
+					onNode(fmt.Sprintf(`node_filesystem_avail_bytes%s`, g.sel(`fstype!~"tmpfs|overlay"`))),
+					onNode(fmt.Sprintf(`node_filesystem_size_bytes%s`, g.sel(`fstype!~"tmpfs|overlay"`)))), "{{mountpoint}}")),
+			timeseries("Network", "Bps",
+				query(fmt.Sprintf(`sum(%s)`, onNode(fmt.Sprintf(`rate(node_network_receive_bytes_total%s[5m])`, g.sel(`device!="lo"`)))), "receive"),
+				query(fmt.Sprintf(`sum(%s)`, onNode(fmt.Sprintf(`rate(node_network_transmit_bytes_total%s[5m])`, g.sel(`device!="lo"`)))), "transmit")),
+			timeseries("Load average", "none",
+				query(fmt.Sprintf(`sum(%s)`, onNode(fmt.Sprintf(`node_load1%s`, g.sel()))), "1m"),
+				query(fmt.Sprintf(`sum(%s)`, onNode(fmt.Sprintf(`node_load5%s`, g.sel()))), "5m"),
+				query(fmt.Sprintf(`sum(%s)`, onNode(fmt.Sprintf(`node_load15%s`, g.sel()))), "15m")),
+		},
+	}
+}
+
+func costDashboard(g generator) content {
+	ns := `namespace=~"$namespace"`
+	namespaces := queryVariable("namespace", "Namespace",
+		fmt.Sprintf(`label_values(kube_namespace_status_phase%s, namespace)`, g.sel()))
+	namespaces.Multi = true
+	namespaces.IncludeAll = true
+	namespaces.AllValue = ".*"
+	namespaces.Current = &Option{Text: "All", Value: []string{"$__all"}, Selected: true}
+
+	// Cost is allocated by resource requests, which is what the scheduler
+	// reserves, whether or not the pods use them.
+	cost := func(by string) string {
+		return fmt.Sprintf(`(sum%s (kube_pod_container_resource_requests%s) * $cpu_hourly_cost + sum%s (kube_pod_container_resource_requests%s) / 1073741824 * $memory_hourly_cost) * %d`,
+			by, g.sel(ns, `resource="cpu"`), by, g.sel(ns, `resource="memory"`), hoursPerMonth)
+	}
+	return content{
+		title:       "Cost",
+		description: "Monthly cost allocated by resource requests, and requested capacity left idle.",
+		variables: []Variable{
+			namespaces,
+			textboxVariable("cpu_hourly_cost", "CPU cost per core-hour", defaultCPUHourlyCost),
+			textboxVariable("memory_hourly_cost", "Memory cost per GiB-hour", defaultMemoryHourlyCost),
+		},
+		panels: []Panel{
+			stat("Estimated monthly cost", "currencyUSD", cost("")),
+			stat("Requested CPU", "cores",
+				fmt.Sprintf(`sum(kube_pod_container_resource_requests%s)`, g.sel(ns, `resource="cpu"`))),
+			stat("Requested memory", "bytes",
+				fmt.Sprintf(`sum(kube_pod_container_resource_requests%s)`, g.sel(ns, `resource="memory"`))),
+			stat("Idle CPU requests", "percent",
+				fmt.Sprintf(`100 * (1 - sum(rate(container_cpu_usage_seconds_total%s[5m])) / sum(kube_pod_container_resource_requests%s))`,
+					g.sel(ns, `container!=""`), g.sel(ns, `resource="cpu"`)), 50, 75),
+
+			timeseries("Monthly cost by namespace", "currencyUSD",
+				query(cost(" by (namespace)"), "{{namespace}}")),
+			timeseries("Idle CPU by namespace", "cores",
+				query(fmt.Sprintf(`sum by (namespace) (kube_pod_container_resource_requests%s) - sum by (namespace) (rate(container_cpu_usage_seconds_total%s[5m]))`,
+					g.sel(ns, `resource="cpu"`), g.sel(ns, `container!=""`)), "{{namespace}}")),
+			timeseries("Idle memory by namespace", "bytes",
+				query(fmt.Sprintf(`sum by (namespace) (kube_pod_container_resource_requests%s) - sum by (namespace) (container_memory_working_set_bytes%s)`,
+					g.sel(ns, `resource="memory"`), g.sel(ns, `container!=""`)), "{{namespace}}")),
+			timeseries("CPU requested and used", "cores",
+				query(fmt.Sprintf(`sum(kube_pod_container_resource_requests%s)`, g.sel(ns, `resource="cpu"`)), "requested"),
+				query(fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total%s[5m]))`, g.sel(ns, `container!=""`)), "used")),
+			table("Monthly cost by namespace", "currencyUSD", cost(" by (namespace)")),
+		},
+	}
+}
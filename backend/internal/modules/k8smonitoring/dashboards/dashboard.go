@@ -0,0 +1,139 @@
+package dashboards
+
+// Dashboard is the subset of the Grafana dashboard JSON model the generator
+// emits. It can be imported through the Grafana UI or saved with the
+// dashboard HTTP API.
+type Dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description,omitempty"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	Editable      bool       `json:"editable"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Refresh       string     `json:"refresh"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+// Variable types used by the generated dashboards.
+const (
+	VariableDatasource = "datasource"
+	VariableQuery      = "query"
+	VariableCustom     = "custom"
+	VariableConstant   = "constant"
+	VariableTextbox    = "textbox"
+)
+
+// Variable hide modes.
+const (
+	HideNone     = 0
+	HideVariable = 2
+)
+
+// Variable refresh modes for query variables.
+const (
+	RefreshOnLoad      = 1
+	RefreshOnTimeRange = 2
+)
+
+// Variable is a dashboard template variable.
+type Variable struct {
+	Name       string         `json:"name"`
+	Label      string         `json:"label,omitempty"`
+	Type       string         `json:"type"`
+	Query      string         `json:"query"`
+	Datasource *DatasourceRef `json:"datasource,omitempty"`
+	Current    *Option        `json:"current,omitempty"`
+	Options    []Option       `json:"options"`
+	Refresh    int            `json:"refresh,omitempty"`
+	Multi      bool           `json:"multi"`
+	IncludeAll bool           `json:"includeAll"`
+	AllValue   string         `json:"allValue,omitempty"`
+	Hide       int            `json:"hide"`
+	Sort       int            `json:"sort,omitempty"`
+}
+
+// Option is a selectable or selected variable value. Value is a list when
+// the variable allows several values.
+type Option struct {
+	Text     string      `json:"text"`
+	Value    interface{} `json:"value"`
+	Selected bool        `json:"selected"`
+}
+
+type DatasourceRef struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// Panel types used by the generated dashboards.
+const (
+	PanelStat       = "stat"
+	PanelTimeSeries = "timeseries"
+	PanelGauge      = "gauge"
+	PanelTable      = "table"
+)
+
+type Panel struct {
+	ID          int                    `json:"id"`
+	Type        string                 `json:"type"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description,omitempty"`
+	GridPos     GridPos                `json:"gridPos"`
+	Datasource  *DatasourceRef         `json:"datasource,omitempty"`
+	Targets     []Target               `json:"targets"`
+	FieldConfig FieldConfig            `json:"fieldConfig"`
+	Options     map[string]interface{} `json:"options,omitempty"`
+}
+
+type GridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type Target struct {
+	RefID        string         `json:"refId"`
+	Datasource   *DatasourceRef `json:"datasource,omitempty"`
+	Expr         string         `json:"expr"`
+	LegendFormat string         `json:"legendFormat,omitempty"`
+	Instant      bool           `json:"instant,omitempty"`
+	Format       string         `json:"format,omitempty"`
+}
+
+type FieldConfig struct {
+	Defaults  FieldDefaults `json:"defaults"`
+	Overrides []interface{} `json:"overrides"`
+}
+
+type FieldDefaults struct {
+	Unit       string      `json:"unit,omitempty"`
+	Min        *float64    `json:"min,omitempty"`
+	Max        *float64    `json:"max,omitempty"`
+	Decimals   *int        `json:"decimals,omitempty"`
+	Thresholds *Thresholds `json:"thresholds,omitempty"`
+}
+
+type Thresholds struct {
+	Mode  string          `json:"mode"`
+	Steps []ThresholdStep `json:"steps"`
+}
+
+// ThresholdStep colours values from Value upwards; the first step has no
+// Value and covers everything below the next one.
+type ThresholdStep struct {
+	Color string   `json:"color"`
+	Value *float64 `json:"value"`
+}
@@ -0,0 +1,309 @@
+// Package dashboards generates Grafana dashboards for the Kubernetes
+// dashboard categories of the monitoring spec (section 4.2) over the
+// kube-state-metrics, cAdvisor and node-exporter series that Prometheus
+// scrapes from registered clusters.
+package dashboards
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnknownCategory = errors.New("unknown dashboard category")
+
+// Dashboard categories.
+const (
+	CategoryCluster   = "cluster"
+	CategoryNamespace = "namespace"
+	CategoryWorkload  = "workload"
+	CategoryNode      = "node"
+	CategoryCost      = "cost"
+)
+
+// Categories lists every category in the order dashboards are generated.
+var Categories = []string{CategoryCluster, CategoryNamespace, CategoryWorkload, CategoryNode, CategoryCost}
+
+// Grafana rejects dashboard UIDs longer than this.
+const maxUIDLength = 40
+
+const schemaVersion = 39
+
+// Options parameterises the generated dashboards.
+type Options struct {
+	// ClusterLabel is the label naming the cluster on every series, as in
+	// prometheus.cluster_label.
+	ClusterLabel string
+	// Clusters are the registered cluster names the cluster variable offers.
+	Clusters []string
+	// Cluster pins the dashboards to one registered cluster: the cluster
+	// variable becomes a hidden constant and the UID and title carry the
+	// cluster name. Empty generates fleet dashboards with a cluster
+	// selector.
+	Cluster string
+}
+
+// Generate returns the dashboard for category.
+func Generate(category string, opts Options) (Dashboard, error) {
+	build, ok := builders[category]
+	if !ok {
+		return Dashboard{}, fmt.Errorf("%w: %q", ErrUnknownCategory, category)
+	}
+
+	g := generator{label: opts.ClusterLabel}
+	built := build(g)
+
+	title := "Kubernetes / " + built.title
+	tags := []string{"devoptics", "kubernetes", category}
+	if opts.Cluster != "" {
+		title += " / " + opts.Cluster
+		tags = append(tags, opts.Cluster)
+	}
+
+	variables := []Variable{datasourceVariable(), clusterVariable(opts)}
+	variables = append(variables, built.variables...)
+
+	return Dashboard{
+		UID:           dashboardUID(category, opts.Cluster),
+		Title:         title,
+		Description:   built.description,
+		Tags:          tags,
+		Timezone:      "browser",
+		Editable:      true,
+		SchemaVersion: schemaVersion,
+		Refresh:       "1m",
+		Time:          TimeRange{From: "now-6h", To: "now"},
+		Templating:    Templating{List: variables},
+		Panels:        layout(built.panels),
+	}, nil
+}
+
+// GenerateAll returns the dashboard of every category.
+func GenerateAll(opts Options) ([]Dashboard, error) {
+	dashboards := make([]Dashboard, 0, len(Categories))
+	for _, category := range Categories {
+		dashboard, err := Generate(category, opts)
+		if err != nil {
+			return nil, err
+		}
+		dashboards = append(dashboards, dashboard)
+	}
+	return dashboards, nil
+}
+
+// dashboardUID is stable per category and cluster, so pushing again
+// overwrites the same dashboard. Names too long for Grafana are shortened
+// with a hash suffix.
+func dashboardUID(category, cluster string) string {
+	uid := "k8s-" + category
+	if cluster != "" {
+		uid += "-" + cluster
+	}
+	if len(uid) <= maxUIDLength {
+		return uid
+	}
+	sum := sha256.Sum256([]byte(uid))
+	return uid[:maxUIDLength-9] + "-" + hex.EncodeToString(sum[:4])
+}
+
+// content is what a category contributes to its dashboard.
+type content struct {
+	title       string
+	description string
+	variables   []Variable
+	panels      []Panel
+}
+
+var builders = map[string]func(generator) content{
+	CategoryCluster:   clusterDashboard,
+	CategoryNamespace: namespaceDashboard,
+	CategoryWorkload:  workloadDashboard,
+	CategoryNode:      nodeDashboard,
+	CategoryCost:      costDashboard,
+}
+
+// generator builds PromQL selectors scoped to the $cluster variable.
+type generator struct {
+	label string
+}
+
+// sel returns a selector matching the selected cluster and matchers.
+func (g generator) sel(matchers ...string) string {
+	return "{" + strings.Join(append([]string{g.label + `="$cluster"`}, matchers...), ",") + "}"
+}
+
+var prometheusDatasource = &DatasourceRef{Type: "prometheus", UID: "${datasource}"}
+
+func datasourceVariable() Variable {
+	return Variable{
+		Name:    "datasource",
+		Label:   "Data source",
+		Type:    VariableDatasource,
+		Query:   "prometheus",
+		Options: []Option{},
+	}
+}
+
+func clusterVariable(opts Options) Variable {
+	if opts.Cluster != "" {
+		return Variable{
+			Name:    "cluster",
+			Type:    VariableConstant,
+			Query:   opts.Cluster,
+			Current: &Option{Text: opts.Cluster, Value: opts.Cluster, Selected: true},
+			Options: []Option{{Text: opts.Cluster, Value: opts.Cluster, Selected: true}},
+			Hide:    HideVariable,
+		}
+	}
+
+	variable := Variable{
+		Name:    "cluster",
+		Label:   "Cluster",
+		Type:    VariableCustom,
+		Query:   strings.Join(opts.Clusters, ","),
+		Options: make([]Option, 0, len(opts.Clusters)),
+	}
+	for i, name := range opts.Clusters {
+		option := Option{Text: name, Value: name, Selected: i == 0}
+		variable.Options = append(variable.Options, option)
+		if i == 0 {
+			variable.Current = &option
+		}
+	}
+	return variable
+}
+
+// queryVariable offers the values returned by a label_values query,
+// refreshed with the time range.
+func queryVariable(name, label, query string) Variable {
+	return Variable{
+		Name:       name,
+		Label:      label,
+		Type:       VariableQuery,
+		Query:      query,
+		Datasource: prometheusDatasource,
+		Options:    []Option{},
+		Refresh:    RefreshOnTimeRange,
+		Sort:       1,
+	}
+}
+
+func textboxVariable(name, label, value string) Variable {
+	option := Option{Text: value, Value: value, Selected: true}
+	return Variable{
+		Name:    name,
+		Label:   label,
+		Type:    VariableTextbox,
+		Query:   value,
+		Current: &option,
+		Options: []Option{option},
+	}
+}
+
+func query(expr, legend string) Target {
+	return Target{Expr: expr, LegendFormat: legend}
+}
+
+func stat(title, unit, expr string, thresholds ...float64) Panel {
+	return Panel{
+		Type:        PanelStat,
+		Title:       title,
+		GridPos:     GridPos{W: 6, H: 4},
+		Targets:     []Target{{Expr: expr, Instant: true}},
+		FieldConfig: fieldConfig(unit, thresholds),
+		Options: map[string]interface{}{
+			"reduceOptions": map[string]interface{}{"calcs": []string{"lastNotNull"}, "fields": "", "values": false},
+			"colorMode":     "value",
+			"graphMode":     "none",
+		},
+	}
+}
+
+// percentGauge shows a 0-100 percentage, amber from 80 and red from 90.
+func percentGauge(title, expr string) Panel {
+	panel := Panel{
+		Type:        PanelGauge,
+		Title:       title,
+		GridPos:     GridPos{W: 6, H: 6},
+		Targets:     []Target{{Expr: expr, Instant: true}},
+		FieldConfig: fieldConfig("percent", []float64{80, 90}),
+		Options: map[string]interface{}{
+			"reduceOptions": map[string]interface{}{"calcs": []string{"lastNotNull"}, "fields": "", "values": false},
+		},
+	}
+	lower, upper := 0.0, 100.0
+	panel.FieldConfig.Defaults.Min = &lower
+	panel.FieldConfig.Defaults.Max = &upper
+	return panel
+}
+
+func timeseries(title, unit string, targets ...Target) Panel {
+	return Panel{
+		Type:        PanelTimeSeries,
+		Title:       title,
+		GridPos:     GridPos{W: 12, H: 8},
+		Targets:     targets,
+		FieldConfig: fieldConfig(unit, nil),
+		Options: map[string]interface{}{
+			"legend":  map[string]interface{}{"displayMode": "list", "placement": "bottom"},
+			"tooltip": map[string]interface{}{"mode": "multi"},
+		},
+	}
+}
+
+// table shows the current value of each series returned by expr, one row
+// per series.
+func table(title, unit, expr string) Panel {
+	return Panel{
+		Type:        PanelTable,
+		Title:       title,
+		GridPos:     GridPos{W: 24, H: 8},
+		Targets:     []Target{{Expr: expr, Instant: true, Format: "table"}},
+		FieldConfig: fieldConfig(unit, nil),
+		Options:     map[string]interface{}{"showHeader": true},
+	}
+}
+
+// fieldConfig colours values green, then amber and red from the given
+// thresholds.
+func fieldConfig(unit string, thresholds []float64) FieldConfig {
+	steps := []ThresholdStep{{Color: "green"}}
+	for i, value := range thresholds {
+		value := value
+		color := "red"
+		if i == 0 && len(thresholds) > 1 {
+			color = "orange"
+		}
+		steps = append(steps, ThresholdStep{Color: color, Value: &value})
+	}
+	return FieldConfig{
+		Defaults:  FieldDefaults{Unit: unit, Thresholds: &Thresholds{Mode: "absolute", Steps: steps}},
+		Overrides: []interface{}{},
+	}
+}
+
+// layout numbers panels, points them at the selected data source and flows
+// them left to right into rows 24 units wide.
+func layout(panels []Panel) []Panel {
+	x, y, rowHeight := 0, 0, 0
+	for i := range panels {
+		panel := &panels[i]
+		if x+panel.GridPos.W > 24 {
+			x, y, rowHeight = 0, y+rowHeight, 0
+		}
+		panel.ID = i + 1
+		panel.GridPos.X, panel.GridPos.Y = x, y
+		panel.Datasource = prometheusDatasource
+		for j := range panel.Targets {
+			panel.Targets[j].RefID = string(rune('A' + j))
+			panel.Targets[j].Datasource = prometheusDatasource
+		}
+		x += panel.GridPos.W
+		if panel.GridPos.H > rowHeight {
+			rowHeight = panel.GridPos.H
+		}
+	}
+	return panels
+}
@@ -0,0 +1,137 @@
+// Package grafana is a client for the dashboard endpoints of the Grafana
+// HTTP API.
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client saves dashboards through the Grafana HTTP API.
+type Client interface {
+	// SaveDashboard creates or overwrites the dashboard with the same UID
+	// in the folder with folderUID, or the General folder if it is empty.
+	SaveDashboard(ctx context.Context, dashboard interface{}, folderUID string) (SaveResult, error)
+}
+
+// Options configures a Client. Transport defaults to http.DefaultTransport.
+type Options struct {
+	URL string
+	// APIToken is a service account token with dashboard write access.
+	APIToken  string
+	Timeout   time.Duration
+	Transport http.RoundTripper
+}
+
+// SaveResult is Grafana's response to a saved dashboard.
+type SaveResult struct {
+	ID      int64  `json:"id"`
+	UID     string `json:"uid"`
+	URL     string `json:"url"`
+	Status  string `json:"status"`
+	Version int    `json:"version"`
+}
+
+// APIError is an error response from Grafana.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("grafana returned %d: %s", e.StatusCode, e.Message)
+}
+
+type client struct {
+	baseURL  *url.URL
+	apiToken string
+	http     *http.Client
+}
+
+// NewClient returns a Client for the Grafana server at opts.URL.
+func NewClient(opts Options) (Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(opts.URL, "/"))
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid grafana url %q", opts.URL)
+	}
+
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &client{
+		baseURL:  baseURL,
+		apiToken: opts.APIToken,
+		http:     &http.Client{Timeout: opts.Timeout, Transport: transport},
+	}, nil
+}
+
+type saveRequest struct {
+	Dashboard interface{} `json:"dashboard"`
+	FolderUID string      `json:"folderUid,omitempty"`
+	Overwrite bool        `json:"overwrite"`
+	Message   string      `json:"message,omitempty"`
+}
+
+func (c *client) SaveDashboard(ctx context.Context, dashboard interface{}, folderUID string) (SaveResult, error) {
+	body, err := json.Marshal(saveRequest{
+		Dashboard: dashboard,
+		FolderUID: folderUID,
+		Overwrite: true,
+		Message:   "Generated by DevOptics",
+	})
+	if err != nil {
+		return SaveResult{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String()+"/api/dashboards/db", bytes.NewReader(body))
+	if err != nil {
+		return SaveResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return SaveResult{}, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return SaveResult{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		message := truncate(raw, 200)
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			message = apiErr.Message
+		}
+		return SaveResult{}, &APIError{StatusCode: resp.StatusCode, Message: message}
+	}
+
+	var result SaveResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return SaveResult{}, fmt.Errorf("decode grafana response: %w", err)
+	}
+	return result, nil
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) > n {
+		return string(b[:n]) + "..."
+	}
+	return string(b)
+}
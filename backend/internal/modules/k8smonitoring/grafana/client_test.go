@@ -0,0 +1,182 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+type testDashboard struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+func newStandInClient(t *testing.T, standIn *testutil.GrafanaStandIn, token string) (Client, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+	client, err := NewClient(Options{URL: server.URL + "/", APIToken: token, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+func TestSaveDashboard(t *testing.T) {
+	standIn := &testutil.GrafanaStandIn{Token: "service-account"}
+	client, server := newStandInClient(t, standIn, "service-account")
+
+	first, err := client.SaveDashboard(context.Background(), testDashboard{UID: "k8s-cluster", Title: "Cluster"}, "ops")
+	if err != nil {
+		t.Fatalf("SaveDashboard: %v", err)
+	}
+	if first.UID != "k8s-cluster" || first.URL != "/d/k8s-cluster" || first.Status != "success" || first.Version != 1 {
+		t.Errorf("first save = %+v", first)
+	}
+
+	// Pushing again overwrites the dashboard as a new version.
+	second, err := client.SaveDashboard(context.Background(), testDashboard{UID: "k8s-cluster", Title: "Cluster v2"}, "ops")
+	if err != nil {
+		t.Fatalf("SaveDashboard again: %v", err)
+	}
+	if second.Version != 2 {
+		t.Errorf("second save version = %d, want 2", second.Version)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/dashboards/uid/k8s-cluster", nil)
+	req.Header.Set("Authorization", "Bearer service-account")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var saved struct {
+		Dashboard testDashboard `json:"dashboard"`
+		Meta      struct {
+			FolderUID string `json:"folderUid"`
+			Version   int    `json:"version"`
+		} `json:"meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Dashboard.Title != "Cluster v2" || saved.Meta.FolderUID != "ops" || saved.Meta.Version != 2 {
+		t.Errorf("saved %+v", saved)
+	}
+}
+
+func TestSaveDashboardRequest(t *testing.T) {
+	var got *http.Request
+	var body saveRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		json.NewDecoder(r.Body).Decode(&body)
+		io.WriteString(w, `{"id":1,"uid":"a","url":"/d/a","status":"success","version":1}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Options{URL: server.URL + "/grafana/", APIToken: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SaveDashboard(context.Background(), testDashboard{UID: "a", Title: "A"}, ""); err != nil {
+		t.Fatalf("SaveDashboard: %v", err)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/grafana/api/dashboards/db" {
+		t.Errorf("request = %s %s", got.Method, got.URL.Path)
+	}
+	if got.Header.Get("Authorization") != "Bearer secret" || got.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("headers = %v", got.Header)
+	}
+	if !body.Overwrite || body.FolderUID != "" || body.Message == "" {
+		t.Errorf("body = %+v, want an overwrite into the General folder with a message", body)
+	}
+}
+
+func TestSaveDashboardErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		standIn     *testutil.GrafanaStandIn
+		token       string
+		dashboard   testDashboard
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "wrong token",
+			standIn:     &testutil.GrafanaStandIn{Token: "service-account"},
+			token:       "expired",
+			dashboard:   testDashboard{UID: "a", Title: "A"},
+			wantStatus:  http.StatusUnauthorized,
+			wantMessage: "invalid API key",
+		},
+		{
+			name:        "invalid dashboard",
+			standIn:     &testutil.GrafanaStandIn{},
+			dashboard:   testDashboard{UID: "a"},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Dashboard title and uid are required",
+		},
+		{
+			name:        "rejected",
+			standIn:     &testutil.GrafanaStandIn{Reject: map[string]string{"a": "Dashboard too large"}},
+			dashboard:   testDashboard{UID: "a", Title: "A"},
+			wantStatus:  http.StatusBadRequest,
+			wantMessage: "Dashboard too large",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newStandInClient(t, tt.standIn, tt.token)
+			_, err := client.SaveDashboard(context.Background(), tt.dashboard, "")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus || apiErr.Message != tt.wantMessage {
+				t.Fatalf("SaveDashboard error = %v, want %d %q", err, tt.wantStatus, tt.wantMessage)
+			}
+			if len(tt.standIn.Dashboards()) != 0 {
+				t.Errorf("stored %v after a failed save", tt.standIn.Dashboards())
+			}
+		})
+	}
+
+	t.Run("non-JSON error body", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			io.WriteString(w, "<html>"+strings.Repeat("x", 500)+"</html>")
+		}))
+		defer server.Close()
+		client, _ := NewClient(Options{URL: server.URL})
+		_, err := client.SaveDashboard(context.Background(), testDashboard{UID: "a", Title: "A"}, "")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || len(apiErr.Message) != 203 || !strings.HasSuffix(apiErr.Message, "...") {
+			t.Fatalf("SaveDashboard error = %v, want a truncated 502 body", err)
+		}
+	})
+
+	t.Run("undecodable success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "ok")
+		}))
+		defer server.Close()
+		client, _ := NewClient(Options{URL: server.URL})
+		if _, err := client.SaveDashboard(context.Background(), testDashboard{UID: "a", Title: "A"}, ""); err == nil || !strings.Contains(err.Error(), "decode grafana response") {
+			t.Fatalf("SaveDashboard error = %v", err)
+		}
+	})
+}
+
+func TestNewClientRejectsInvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "grafana.example.com", "://bad"} {
+		if _, err := NewClient(Options{URL: raw}); err == nil {
+			t.Errorf("NewClient(%q) succeeded", raw)
+		}
+	}
+}
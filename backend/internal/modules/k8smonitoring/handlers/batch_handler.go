@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+type BatchHandler struct {
+	service services.BatchService
+}
+
+func NewBatchHandler(service services.BatchService) *BatchHandler {
+	return &BatchHandler{service: service}
+}
+
+// ListJobs returns Job execution state, optionally scoped to :namespace and
+// filtered by status (running, succeeded, failed, suspended).
+func (h *BatchHandler) ListJobs(c *gin.Context) {
+	filter := collectors.JobFilter{
+		Namespace: c.Param("namespace"),
+		Status:    c.Query("status"),
+	}
+
+	jobs, err := h.service.ListJobs(c.Request.Context(), c.Param("cluster"), filter)
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cluster": c.Param("cluster"), "jobs": jobs})
+}
+
+// ListCronJobs returns CronJob schedule health, optionally scoped to
+// :namespace.
+func (h *BatchHandler) ListCronJobs(c *gin.Context) {
+	cronJobs, err := h.service.ListCronJobs(c.Request.Context(), c.Param("cluster"), c.Param("namespace"))
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cluster": c.Param("cluster"), "cronJobs": cronJobs})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+type ClusterHandler struct {
+	service services.ClusterService
+}
+
+func NewClusterHandler(service services.ClusterService) *ClusterHandler {
+	return &ClusterHandler{service: service}
+}
+
+type clusterRequest struct {
+	APIEndpoint    string            `json:"apiEndpoint"`
+	CredentialsRef string            `json:"credentialsRef" binding:"required"`
+	Labels         map[string]string `json:"labels"`
+	Environment    string            `json:"environment"`
+}
+
+// ListClusters returns every registered cluster.
+func (h *ClusterHandler) ListClusters(c *gin.Context) {
+	clusters, err := h.service.ListClusters()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"clusters": clusters})
+}
+
+// GetCluster returns a single registered cluster.
+func (h *ClusterHandler) GetCluster(c *gin.Context) {
+	cluster, err := h.service.GetCluster(c.Param("cluster"))
+	if err != nil {
+		writeClusterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cluster)
+}
+
+// CreateCluster registers a new cluster.
+func (h *ClusterHandler) CreateCluster(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+		clusterRequest
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cluster, err := h.service.CreateCluster(req.toModel(req.Name))
+	if err != nil {
+		writeClusterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cluster)
+}
+
+// UpdateCluster replaces a registered cluster's connection details and labels.
+func (h *ClusterHandler) UpdateCluster(c *gin.Context) {
+	var req clusterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cluster, err := h.service.UpdateCluster(req.toModel(c.Param("cluster")))
+	if err != nil {
+		writeClusterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cluster)
+}
+
+// DeleteCluster removes a cluster from the registry.
+func (h *ClusterHandler) DeleteCluster(c *gin.Context) {
+	if err := h.service.DeleteCluster(c.Param("cluster")); err != nil {
+		writeClusterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+func (r clusterRequest) toModel(name string) models.Cluster {
+	return models.Cluster{
+		Name:           name,
+		APIEndpoint:    r.APIEndpoint,
+		CredentialsRef: r.CredentialsRef,
+		Labels:         r.Labels,
+		Environment:    r.Environment,
+	}
+}
+
+func writeClusterError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrClusterNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrClusterExists):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrInvalidCluster):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
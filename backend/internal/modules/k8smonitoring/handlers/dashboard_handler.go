@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/dashboards"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+type DashboardHandler struct {
+	service services.DashboardService
+}
+
+func NewDashboardHandler(service services.DashboardService) *DashboardHandler {
+	return &DashboardHandler{service: service}
+}
+
+// ListDashboards returns the Grafana dashboard of every category, pinned to
+// the cluster query parameter when it is set.
+func (h *DashboardHandler) ListDashboards(c *gin.Context) {
+	generated, err := h.service.ListDashboards(c.Query("cluster"))
+	if err != nil {
+		writeDashboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dashboards": generated})
+}
+
+// DownloadDashboard returns one category's dashboard as a JSON file ready
+// for Grafana's dashboard import.
+func (h *DashboardHandler) DownloadDashboard(c *gin.Context) {
+	dashboard, err := h.service.GetDashboard(c.Param("category"), c.Query("cluster"))
+	if err != nil {
+		writeDashboardError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, dashboard.UID))
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// PushDashboards saves the dashboards to the configured Grafana and reports
+// the outcome of each.
+func (h *DashboardHandler) PushDashboards(c *gin.Context) {
+	pushes, err := h.service.PushDashboards(c.Request.Context(), c.Query("cluster"))
+	if err != nil {
+		writeDashboardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"dashboards": pushes})
+}
+
+func writeDashboardError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrClusterNotFound), errors.Is(err, dashboards.ErrUnknownCategory):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrGrafanaNotConfigured):
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
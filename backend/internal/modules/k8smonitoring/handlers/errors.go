@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// writeCollectError maps errors from live cluster reads to HTTP statuses.
+// Anything not caused by the request is treated as an upstream failure.
+func writeCollectError(c *gin.Context, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, repositories.ErrClusterNotFound), apierrors.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.Is(err, collectors.ErrInvalidFilter):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
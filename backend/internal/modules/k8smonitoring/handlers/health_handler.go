@@ -16,7 +16,7 @@ func NewHealthHandler(service services.HealthService) *HealthHandler {
 	return &HealthHandler{service: service}
 }
 
-// GetClusterHealth returns a basic health snapshot for a cluster.
+// GetClusterHealth returns a live health snapshot for a cluster.
 func (h *HealthHandler) GetClusterHealth(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if cluster == "" {
@@ -24,6 +24,22 @@ func (h *HealthHandler) GetClusterHealth(c *gin.Context) {
 		return
 	}
 
-	snapshot := h.service.GetClusterHealth(cluster)
+	snapshot, err := h.service.GetClusterHealth(c.Request.Context(), cluster)
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, snapshot)
 }
+
+// GetFleetHealth returns a worst-of rollup across all registered clusters.
+func (h *HealthHandler) GetFleetHealth(c *gin.Context) {
+	fleet, err := h.service.GetFleetHealth(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, fleet)
+}
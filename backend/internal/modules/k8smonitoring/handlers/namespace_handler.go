@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+type NamespaceHandler struct {
+	service services.NamespaceService
+}
+
+func NewNamespaceHandler(service services.NamespaceService) *NamespaceHandler {
+	return &NamespaceHandler{service: service}
+}
+
+// ListNamespaces returns ResourceQuota usage for every namespace in a
+// cluster. threshold sets the near-limit percent and nearLimitOnly=true keeps
+// only flagged namespaces.
+func (h *NamespaceHandler) ListNamespaces(c *gin.Context) {
+	filter, ok := namespaceFilter(c)
+	if !ok {
+		return
+	}
+
+	namespaces, err := h.service.ListNamespaces(c.Request.Context(), c.Param("cluster"), filter)
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cluster": c.Param("cluster"), "namespaces": namespaces})
+}
+
+// GetNamespace returns ResourceQuota usage for a single namespace.
+func (h *NamespaceHandler) GetNamespace(c *gin.Context) {
+	filter, ok := namespaceFilter(c)
+	if !ok {
+		return
+	}
+	filter.Namespace = c.Param("namespace")
+	filter.NearLimitOnly = false
+
+	namespaces, err := h.service.ListNamespaces(c.Request.Context(), c.Param("cluster"), filter)
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+	if len(namespaces) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "namespace not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, namespaces[0])
+}
+
+func namespaceFilter(c *gin.Context) (collectors.NamespaceFilter, bool) {
+	threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", strconv.FormatFloat(collectors.DefaultQuotaThreshold, 'f', -1, 64)), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be a number"})
+		return collectors.NamespaceFilter{}, false
+	}
+	nearLimitOnly, err := strconv.ParseBool(c.DefaultQuery("nearLimitOnly", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nearLimitOnly must be a boolean"})
+		return collectors.NamespaceFilter{}, false
+	}
+	return collectors.NamespaceFilter{Threshold: threshold, NearLimitOnly: nearLimitOnly}, true
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+type NodeHandler struct {
+	service services.NodeService
+}
+
+func NewNodeHandler(service services.NodeService) *NodeHandler {
+	return &NodeHandler{service: service}
+}
+
+// ListNodes returns node health for a cluster. The optional labelSelector
+// query uses Kubernetes selector syntax; condition may be repeated or
+// comma-separated and every listed condition must hold.
+func (h *NodeHandler) ListNodes(c *gin.Context) {
+	filter := collectors.NodeFilter{
+		LabelSelector: c.Query("labelSelector"),
+		Conditions:    splitQueryList(c.QueryArray("condition")),
+	}
+
+	nodes, err := h.service.ListNodes(c.Request.Context(), c.Param("cluster"), filter)
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cluster": c.Param("cluster"), "nodes": nodes})
+}
+
+func splitQueryList(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+type PodHandler struct {
+	service services.PodService
+}
+
+func NewPodHandler(service services.PodService) *PodHandler {
+	return &PodHandler{service: service}
+}
+
+// ListPods returns one page of pod health for a cluster, optionally scoped to
+// the :namespace path parameter. problemsOnly=true keeps only pods with a
+// detected problem such as CrashLoopBackOff, OOMKilled or Evicted.
+func (h *PodHandler) ListPods(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be an integer"})
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(collectors.DefaultPageSize)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "pageSize must be an integer"})
+		return
+	}
+	problemsOnly, err := strconv.ParseBool(c.DefaultQuery("problemsOnly", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "problemsOnly must be a boolean"})
+		return
+	}
+
+	filter := collectors.PodFilter{
+		Namespace:     c.Param("namespace"),
+		LabelSelector: c.Query("labelSelector"),
+		ProblemsOnly:  problemsOnly,
+		Page:          page,
+		PageSize:      pageSize,
+	}
+
+	pods, err := h.service.ListPods(c.Request.Context(), c.Param("cluster"), filter)
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pods)
+}
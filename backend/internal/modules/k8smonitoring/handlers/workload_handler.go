@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/services"
+)
+
+type WorkloadHandler struct {
+	service services.WorkloadService
+}
+
+func NewWorkloadHandler(service services.WorkloadService) *WorkloadHandler {
+	return &WorkloadHandler{service: service}
+}
+
+// ListRollouts returns rollout status and revision history for Deployments
+// and StatefulSets, optionally scoped to :namespace and filtered by kind.
+func (h *WorkloadHandler) ListRollouts(c *gin.Context) {
+	filter := collectors.WorkloadFilter{
+		Namespace: c.Param("namespace"),
+		Kind:      c.Query("kind"),
+	}
+
+	rollouts, err := h.service.ListRollouts(c.Request.Context(), c.Param("cluster"), filter)
+	if err != nil {
+		writeCollectError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cluster": c.Param("cluster"), "workloads": rollouts})
+}
@@ -0,0 +1,50 @@
+package models
+
+// Job states.
+const (
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobSuspended = "suspended"
+)
+
+// JobStatus is the execution state of a single Job.
+type JobStatus struct {
+	Name            string       `json:"name"`
+	Namespace       string       `json:"namespace"`
+	Owner           *WorkloadRef `json:"owner,omitempty"`
+	Status          string       `json:"status"`
+	Active          int32        `json:"active"`
+	Succeeded       int32        `json:"succeeded"`
+	Failed          int32        `json:"failed"`
+	Completions     int32        `json:"completions"`
+	StartTime       string       `json:"startTime,omitempty"`
+	CompletionTime  string       `json:"completionTime,omitempty"`
+	DurationSeconds float64      `json:"durationSeconds,omitempty"`
+	FailureReason   string       `json:"failureReason,omitempty"`
+	FailureMessage  string       `json:"failureMessage,omitempty"`
+}
+
+// CronJobStatus is the schedule health of a CronJob and the jobs it owns.
+type CronJobStatus struct {
+	Name               string    `json:"name"`
+	Namespace          string    `json:"namespace"`
+	Schedule           string    `json:"schedule"`
+	TimeZone           string    `json:"timeZone,omitempty"`
+	Suspended          bool      `json:"suspended"`
+	LastScheduleTime   string    `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime string    `json:"lastSuccessfulTime,omitempty"`
+	NextScheduleTime   string    `json:"nextScheduleTime,omitempty"`
+	MissedSchedule     bool      `json:"missedSchedule"`
+	MissedRuns         int       `json:"missedRuns"`
+	ScheduleError      string    `json:"scheduleError,omitempty"`
+	Jobs               JobCounts `json:"jobs"`
+	AvgDurationSeconds float64   `json:"avgDurationSeconds,omitempty"`
+}
+
+// JobCounts counts a CronJob's child jobs by state.
+type JobCounts struct {
+	Active    int `json:"active"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Cluster is a registered Kubernetes cluster.
+//
+// CredentialsRef tells the collectors how to authenticate: "in-cluster" uses
+// the backend pod's service account, "kubeconfig" the current context of the
+// configured kubeconfig, and "kubeconfig:<context>" a named context in it.
+type Cluster struct {
+	Name           string            `json:"name"`
+	APIEndpoint    string            `json:"apiEndpoint"`
+	CredentialsRef string            `json:"credentialsRef"`
+	Labels         map[string]string `json:"labels"`
+	Environment    string            `json:"environment"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
+}
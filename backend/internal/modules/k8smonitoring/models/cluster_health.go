@@ -1,9 +1,70 @@
 package models
 
-// ClusterHealth represents a minimal health snapshot for a Kubernetes cluster.
+// Cluster health statuses.
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+	StatusUnknown   = "unknown"
+)
+
+var statusRank = map[string]int{
+	StatusHealthy:   0,
+	StatusDegraded:  1,
+	StatusUnknown:   2,
+	StatusUnhealthy: 3,
+}
+
+// WorseStatus returns whichever of a and b is the worse health status.
+func WorseStatus(a, b string) string {
+	if statusRank[b] > statusRank[a] {
+		return b
+	}
+	return a
+}
+
+// ClusterHealth represents a health snapshot for a Kubernetes cluster.
 type ClusterHealth struct {
-	ClusterName string            `json:"clusterName"`
-	Status      string            `json:"status"`
-	Timestamp   string            `json:"timestamp"`
-	Signals     map[string]string `json:"signals"`
+	ClusterName  string               `json:"clusterName"`
+	Status       string               `json:"status"`
+	Timestamp    string               `json:"timestamp"`
+	Nodes        NodeReadiness        `json:"nodes"`
+	Pods         PodPhaseCounts       `json:"pods"`
+	ControlPlane []ComponentHealth    `json:"controlPlane"`
+	Utilization  *ResourceUtilization `json:"utilization,omitempty"`
+	Signals      map[string]string    `json:"signals"`
+}
+
+// NodeReadiness summarises node conditions across all nodes.
+type NodeReadiness struct {
+	Total          int `json:"total"`
+	Ready          int `json:"ready"`
+	NotReady       int `json:"notReady"`
+	DiskPressure   int `json:"diskPressure"`
+	MemoryPressure int `json:"memoryPressure"`
+	PIDPressure    int `json:"pidPressure"`
+}
+
+// PodPhaseCounts counts pods by lifecycle phase.
+type PodPhaseCounts struct {
+	Total     int `json:"total"`
+	Running   int `json:"running"`
+	Pending   int `json:"pending"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Unknown   int `json:"unknown"`
+}
+
+// ComponentHealth reports the status of a control-plane component.
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// ResourceUtilization is cluster-wide CPU and memory utilisation from
+// Prometheus, as percentages of capacity.
+type ResourceUtilization struct {
+	CPUPercent    float64 `json:"cpuPercent"`
+	MemoryPercent float64 `json:"memoryPercent"`
 }
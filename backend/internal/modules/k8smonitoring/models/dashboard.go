@@ -0,0 +1,18 @@
+package models
+
+// Dashboard push outcomes.
+const (
+	PushSuccess = "success"
+	PushFailed  = "failed"
+)
+
+// DashboardPush is the outcome of saving one generated dashboard to
+// Grafana. URL and Version are Grafana's; Error is set when the save failed.
+type DashboardPush struct {
+	UID     string `json:"uid"`
+	Title   string `json:"title"`
+	Outcome string `json:"outcome"`
+	URL     string `json:"url,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
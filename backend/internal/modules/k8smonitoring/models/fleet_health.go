@@ -0,0 +1,19 @@
+package models
+
+// FleetHealth aggregates health snapshots across every registered cluster.
+type FleetHealth struct {
+	Status       string            `json:"status"`
+	Timestamp    string            `json:"timestamp"`
+	StatusCounts map[string]int    `json:"statusCounts"`
+	Clusters     []ClusterSnapshot `json:"clusters"`
+}
+
+// ClusterSnapshot is one cluster's entry in a fleet rollup. Error is set
+// when collection failed; the cluster then counts as unknown.
+type ClusterSnapshot struct {
+	ClusterName string         `json:"clusterName"`
+	Environment string         `json:"environment"`
+	Status      string         `json:"status"`
+	Health      *ClusterHealth `json:"health,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// NamespaceUsage reports ResourceQuota consumption for a namespace.
+type NamespaceUsage struct {
+	Namespace      string       `json:"namespace"`
+	Phase          string       `json:"phase"`
+	Quotas         []QuotaUsage `json:"quotas"`
+	MaxPercentUsed float64      `json:"maxPercentUsed"`
+	NearLimit      bool         `json:"nearLimit"`
+	PendingPods    int          `json:"pendingPods"`
+}
+
+// QuotaUsage is the usage of a single ResourceQuota.
+type QuotaUsage struct {
+	Name      string          `json:"name"`
+	Resources []QuotaResource `json:"resources"`
+}
+
+// QuotaResource compares one resource's hard limit with its current usage.
+type QuotaResource struct {
+	Resource    string  `json:"resource"`
+	Hard        string  `json:"hard"`
+	Used        string  `json:"used"`
+	PercentUsed float64 `json:"percentUsed"`
+	NearLimit   bool    `json:"nearLimit"`
+}
@@ -0,0 +1,48 @@
+package models
+
+// Node condition names accepted by node filters.
+const (
+	NodeConditionReady          = "Ready"
+	NodeConditionNotReady       = "NotReady"
+	NodeConditionDiskPressure   = "DiskPressure"
+	NodeConditionMemoryPressure = "MemoryPressure"
+	NodeConditionPIDPressure    = "PIDPressure"
+)
+
+// NodeHealth is the health and capacity of a single node.
+type NodeHealth struct {
+	Name          string            `json:"name"`
+	Labels        map[string]string `json:"labels"`
+	Unschedulable bool              `json:"unschedulable"`
+	Conditions    NodeConditions    `json:"conditions"`
+	Resources     NodeResources     `json:"resources"`
+	Info          NodeInfo          `json:"info"`
+}
+
+// NodeConditions holds the node conditions reported by the kubelet.
+type NodeConditions struct {
+	Ready          bool `json:"ready"`
+	DiskPressure   bool `json:"diskPressure"`
+	MemoryPressure bool `json:"memoryPressure"`
+	PIDPressure    bool `json:"pidPressure"`
+}
+
+// NodeResources compares allocatable capacity with the requests of the
+// non-terminated pods scheduled on the node.
+type NodeResources struct {
+	CPUAllocatableMillis   int64 `json:"cpuAllocatableMillis"`
+	CPURequestedMillis     int64 `json:"cpuRequestedMillis"`
+	MemoryAllocatableBytes int64 `json:"memoryAllocatableBytes"`
+	MemoryRequestedBytes   int64 `json:"memoryRequestedBytes"`
+	PodsAllocatable        int64 `json:"podsAllocatable"`
+	PodsScheduled          int64 `json:"podsScheduled"`
+}
+
+// NodeInfo carries the node's software versions.
+type NodeInfo struct {
+	KubeletVersion          string `json:"kubeletVersion"`
+	KernelVersion           string `json:"kernelVersion"`
+	OSImage                 string `json:"osImage"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+	Architecture            string `json:"architecture"`
+}
@@ -0,0 +1,63 @@
+package models
+
+// Pod problem reasons surfaced by the pod listing.
+const (
+	PodProblemCrashLoopBackOff = "CrashLoopBackOff"
+	PodProblemOOMKilled        = "OOMKilled"
+	PodProblemEvicted          = "Evicted"
+	PodProblemImagePull        = "ImagePullBackOff"
+	PodProblemConfigError      = "CreateContainerConfigError"
+	PodProblemUnschedulable    = "Unschedulable"
+	PodProblemNotReady         = "NotReady"
+	PodProblemFailed           = "Failed"
+	PodProblemUnknown          = "Unknown"
+)
+
+// Readiness probe states reported per container.
+const (
+	ProbeNone    = "none"
+	ProbePassing = "passing"
+	ProbeFailing = "failing"
+)
+
+// PodHealth is the health of a single pod and its containers.
+type PodHealth struct {
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	NodeName   string            `json:"nodeName"`
+	Phase      string            `json:"phase"`
+	Reason     string            `json:"reason,omitempty"`
+	Ready      bool              `json:"ready"`
+	Restarts   int32             `json:"restarts"`
+	StartTime  string            `json:"startTime,omitempty"`
+	Owner      *WorkloadRef      `json:"owner,omitempty"`
+	Containers []ContainerHealth `json:"containers"`
+	Problems   []string          `json:"problems"`
+}
+
+// WorkloadRef identifies the top-level workload that owns a pod.
+type WorkloadRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// ContainerHealth is the state of one container in a pod.
+type ContainerHealth struct {
+	Name                  string `json:"name"`
+	Ready                 bool   `json:"ready"`
+	RestartCount          int32  `json:"restartCount"`
+	State                 string `json:"state"`
+	StateReason           string `json:"stateReason,omitempty"`
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+	LastExitCode          int32  `json:"lastExitCode,omitempty"`
+	LastTerminatedAt      string `json:"lastTerminatedAt,omitempty"`
+	ReadinessProbe        string `json:"readinessProbe"`
+}
+
+// PodList is one page of a pod listing.
+type PodList struct {
+	Items    []PodHealth `json:"items"`
+	Total    int         `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Series is one labelled sample of cluster state, named after the
+// equivalent kube-state-metrics series. Labels include __name__.
+type Series struct {
+	Labels map[string]string `json:"labels"`
+	Value  float64           `json:"value"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// Workload kinds with rollout tracking.
+const (
+	KindDeployment  = "Deployment"
+	KindStatefulSet = "StatefulSet"
+)
+
+// Rollout states.
+const (
+	RolloutComplete    = "complete"
+	RolloutProgressing = "progressing"
+	RolloutStalled     = "stalled"
+	RolloutPaused      = "paused"
+)
+
+// WorkloadRollout is the replica and rollout state of a Deployment or
+// StatefulSet.
+type WorkloadRollout struct {
+	Kind               string             `json:"kind"`
+	Name               string             `json:"name"`
+	Namespace          string             `json:"namespace"`
+	Replicas           ReplicaCounts      `json:"replicas"`
+	Generation         int64              `json:"generation"`
+	ObservedGeneration int64              `json:"observedGeneration"`
+	GenerationMismatch bool               `json:"generationMismatch"`
+	Status             string             `json:"status"`
+	Message            string             `json:"message,omitempty"`
+	RolledBack         bool               `json:"rolledBack"`
+	CurrentRevision    int64              `json:"currentRevision"`
+	Revisions          []WorkloadRevision `json:"revisions"`
+}
+
+// ReplicaCounts compares desired replicas with the controller's status.
+type ReplicaCounts struct {
+	Desired   int32 `json:"desired"`
+	Ready     int32 `json:"ready"`
+	Updated   int32 `json:"updated"`
+	Available int32 `json:"available"`
+}
+
+// WorkloadRevision is one entry in a workload's revision history, backed by
+// a ReplicaSet for Deployments and a ControllerRevision for StatefulSets.
+type WorkloadRevision struct {
+	Revision  int64    `json:"revision"`
+	Name      string   `json:"name"`
+	Images    []string `json:"images"`
+	CreatedAt string   `json:"createdAt"`
+	Current   bool     `json:"current"`
+}
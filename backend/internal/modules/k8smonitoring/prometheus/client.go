@@ -0,0 +1,287 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result types returned by the Prometheus HTTP API.
+const (
+	ResultVector = "vector"
+	ResultMatrix = "matrix"
+	ResultScalar = "scalar"
+	ResultString = "string"
+)
+
+// maxResponseBytes bounds a query response read into memory. A range query
+// over many series can be large, but not this large.
+const maxResponseBytes = 32 << 20
+
+var ErrEmptyResult = errors.New("query returned no samples")
+
+// Client runs PromQL queries against the Prometheus HTTP API.
+type Client interface {
+	Query(ctx context.Context, query string, ts time.Time) (Result, error)
+	QueryRange(ctx context.Context, query string, r Range) (Result, error)
+}
+
+// Options configures a Client. Transport defaults to http.DefaultTransport;
+// tests replace it with testutil.PrometheusRecording to run offline.
+type Options struct {
+	URL         string
+	BearerToken string
+	Timeout     time.Duration
+	Transport   http.RoundTripper
+}
+
+// Range bounds a range query.
+type Range struct {
+	Start time.Time
+	End   time.Time
+	Step  time.Duration
+}
+
+// Result is the decoded data of a query response. Only the field matching
+// Type is populated.
+type Result struct {
+	Type     string   `json:"type"`
+	Vector   []Sample `json:"vector,omitempty"`
+	Matrix   []Series `json:"matrix,omitempty"`
+	Scalar   *Point   `json:"scalar,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Sample is one element of an instant vector.
+type Sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  Point             `json:"value"`
+}
+
+// Series is one element of a range matrix.
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	Values []Point           `json:"values"`
+}
+
+// Point is a single timestamped value.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// APIError is an error reported by Prometheus in a query response.
+type APIError struct {
+	Type    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("prometheus %s: %s", e.Type, e.Message)
+}
+
+type client struct {
+	baseURL     *url.URL
+	bearerToken string
+	http        *http.Client
+}
+
+// NewClient returns a Client for the Prometheus server at opts.URL.
+func NewClient(opts Options) (Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(opts.URL, "/"))
+	if err != nil || baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid prometheus url %q", opts.URL)
+	}
+
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &client{
+		baseURL:     baseURL,
+		bearerToken: opts.BearerToken,
+		http:        &http.Client{Timeout: opts.Timeout, Transport: transport},
+	}, nil
+}
+
+func (c *client) Query(ctx context.Context, query string, ts time.Time) (Result, error) {
+	form := url.Values{"query": {query}}
+	if !ts.IsZero() {
+		form.Set("time", formatTime(ts))
+	}
+	return c.do(ctx, "/api/v1/query", form)
+}
+
+func (c *client) QueryRange(ctx context.Context, query string, r Range) (Result, error) {
+	if r.Step <= 0 || !r.End.After(r.Start) {
+		return Result{}, errors.New("range query needs a positive step and end after start")
+	}
+	form := url.Values{
+		"query": {query},
+		"start": {formatTime(r.Start)},
+		"end":   {formatTime(r.End)},
+		"step":  {strconv.FormatFloat(r.Step.Seconds(), 'f', -1, 64)},
+	}
+	return c.do(ctx, "/api/v1/query_range", form)
+}
+
+func (c *client) do(ctx context.Context, path string, form url.Values) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String()+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if err != nil {
+		return Result{}, err
+	}
+	if len(body) > maxResponseBytes {
+		return Result{}, fmt.Errorf("prometheus response exceeds %d bytes", maxResponseBytes)
+	}
+	return decodeResponse(resp.StatusCode, body)
+}
+
+type apiResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType"`
+	Error     string          `json:"error"`
+	Warnings  []string        `json:"warnings"`
+}
+
+type apiData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+func decodeResponse(status int, body []byte) (Result, error) {
+	var resp apiResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Result{}, fmt.Errorf("prometheus returned %d: %s", status, truncate(body, 200))
+	}
+	if resp.Status != "success" {
+		return Result{}, &APIError{Type: resp.ErrorType, Message: resp.Error}
+	}
+
+	var data apiData
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return Result{}, fmt.Errorf("decode prometheus data: %w", err)
+	}
+
+	result := Result{Type: data.ResultType, Warnings: resp.Warnings}
+	switch data.ResultType {
+	case ResultVector:
+		var raw []struct {
+			Metric map[string]string `json:"metric"`
+			Value  rawPoint          `json:"value"`
+		}
+		if err := json.Unmarshal(data.Result, &raw); err != nil {
+			return Result{}, fmt.Errorf("decode vector: %w", err)
+		}
+		result.Vector = make([]Sample, 0, len(raw))
+		for _, s := range raw {
+			result.Vector = append(result.Vector, Sample{Metric: s.Metric, Value: Point(s.Value)})
+		}
+	case ResultMatrix:
+		var raw []struct {
+			Metric map[string]string `json:"metric"`
+			Values []rawPoint        `json:"values"`
+		}
+		if err := json.Unmarshal(data.Result, &raw); err != nil {
+			return Result{}, fmt.Errorf("decode matrix: %w", err)
+		}
+		result.Matrix = make([]Series, 0, len(raw))
+		for _, s := range raw {
+			series := Series{Metric: s.Metric, Values: make([]Point, 0, len(s.Values))}
+			for _, v := range s.Values {
+				series.Values = append(series.Values, Point(v))
+			}
+			result.Matrix = append(result.Matrix, series)
+		}
+	case ResultScalar:
+		var raw rawPoint
+		if err := json.Unmarshal(data.Result, &raw); err != nil {
+			return Result{}, fmt.Errorf("decode scalar: %w", err)
+		}
+		point := Point(raw)
+		result.Scalar = &point
+	case ResultString:
+	default:
+		return Result{}, fmt.Errorf("unsupported result type %q", data.ResultType)
+	}
+	return result, nil
+}
+
+// rawPoint decodes Prometheus' [<unix seconds>, "<value>"] pairs.
+type rawPoint Point
+
+func (p *rawPoint) UnmarshalJSON(b []byte) error {
+	var pair [2]interface{}
+	if err := json.Unmarshal(b, &pair); err != nil {
+		return err
+	}
+	ts, ok := pair[0].(float64)
+	if !ok {
+		return fmt.Errorf("invalid sample timestamp %v", pair[0])
+	}
+	raw, ok := pair[1].(string)
+	if !ok {
+		return fmt.Errorf("invalid sample value %v", pair[1])
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return err
+	}
+	sec := int64(ts)
+	p.Time = time.Unix(sec, int64((ts-float64(sec))*1e9)).UTC()
+	p.Value = value
+	return nil
+}
+
+// ScalarValue returns the single value of a scalar result or a one-element
+// vector, which is what aggregation queries such as avg(...) produce.
+func (r Result) ScalarValue() (float64, error) {
+	switch {
+	case r.Scalar != nil:
+		return r.Scalar.Value, nil
+	case len(r.Vector) == 1:
+		return r.Vector[0].Value.Value, nil
+	case len(r.Vector) == 0 && r.Type == ResultVector:
+		return 0, ErrEmptyResult
+	default:
+		return 0, fmt.Errorf("expected a single sample, got %s with %d series", r.Type, len(r.Vector)+len(r.Matrix))
+	}
+}
+
+// QuoteLabelValue quotes a value for use in a PromQL label matcher.
+func QuoteLabelValue(value string) string {
+	return strconv.Quote(value)
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', 3, 64)
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) > n {
+		return string(b[:n]) + "..."
+	}
+	return string(b)
+}
@@ -0,0 +1,180 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+var recording = testutil.PrometheusRecording{
+	`up`: `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"__name__":"up","job":"node"},"value":[1760702400.5,"1"]},
+		{"metric":{"__name__":"up","job":"kubelet"},"value":[1760702400.5,"0"]}]}}`,
+	`avg(up)`: `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{},"value":[1760702400,"0.5"]}]},"warnings":["partial data"]}`,
+	`absent_metric`: `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+	`scalar(1)`:     `{"status":"success","data":{"resultType":"scalar","result":[1760702400,"1"]}}`,
+	`rate(x[5m])`: `{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"pod":"a"},"values":[[1760702400,"1.5"],[1760702460,"2.5"]]}]}}`,
+	`bad(`: `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+}
+
+func newRecordedClient(t *testing.T) Client {
+	t.Helper()
+	client, err := NewClient(Options{URL: "http://prometheus.test/", Transport: recording})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestQuery(t *testing.T) {
+	client := newRecordedClient(t)
+	tests := []struct {
+		name       string
+		query      string
+		wantType   string
+		wantScalar float64
+		wantErr    error
+		wantAPIErr string
+	}{
+		{name: "single sample", query: `avg(up)`, wantType: ResultVector, wantScalar: 0.5},
+		{name: "scalar", query: `scalar(1)`, wantType: ResultScalar, wantScalar: 1},
+		{name: "empty vector", query: `absent_metric`, wantType: ResultVector, wantErr: ErrEmptyResult},
+		{name: "api error", query: `bad(`, wantAPIErr: "bad_data"},
+		{name: "not recorded", query: `missing`, wantAPIErr: "recording"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.Query(context.Background(), tt.query, time.Now())
+			if tt.wantAPIErr != "" {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Type != tt.wantAPIErr {
+					t.Fatalf("Query error = %v, want APIError %s", err, tt.wantAPIErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if result.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", result.Type, tt.wantType)
+			}
+			value, err := result.ScalarValue()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ScalarValue error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && value != tt.wantScalar {
+				t.Errorf("ScalarValue = %v, want %v", value, tt.wantScalar)
+			}
+		})
+	}
+}
+
+func TestQueryVector(t *testing.T) {
+	result, err := newRecordedClient(t).Query(context.Background(), `up`, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Vector) != 2 {
+		t.Fatalf("Vector = %+v, want 2 samples", result.Vector)
+	}
+	sample := result.Vector[1]
+	if sample.Metric["job"] != "kubelet" || sample.Value.Value != 0 {
+		t.Errorf("Vector[1] = %+v", sample)
+	}
+	if want := time.Unix(1760702400, 5e8).UTC(); !sample.Value.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", sample.Value.Time, want)
+	}
+	if _, err := result.ScalarValue(); err == nil {
+		t.Error("ScalarValue of two samples succeeded")
+	}
+}
+
+func TestQueryRange(t *testing.T) {
+	client := newRecordedClient(t)
+	end := time.Unix(1760702460, 0)
+	result, err := client.QueryRange(context.Background(), `rate(x[5m])`, Range{Start: end.Add(-time.Minute), End: end, Step: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Type != ResultMatrix || len(result.Matrix) != 1 || len(result.Matrix[0].Values) != 2 {
+		t.Fatalf("result = %+v, want one series of two points", result)
+	}
+	if got := result.Matrix[0].Values[1].Value; got != 2.5 {
+		t.Errorf("second point = %v, want 2.5", got)
+	}
+
+	if _, err := client.QueryRange(context.Background(), `rate(x[5m])`, Range{Start: end, End: end, Step: time.Minute}); err == nil {
+		t.Error("QueryRange with an empty range succeeded")
+	}
+}
+
+func TestClientRequest(t *testing.T) {
+	var got *http.Request
+	var form string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got, form = r, string(body)
+		w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1760702400,"1"]}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(Options{URL: server.URL + "/", BearerToken: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Query(context.Background(), `up{cluster="prod"}`, time.Unix(1760702400, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/api/v1/query" {
+		t.Errorf("request = %s %s, want POST /api/v1/query", got.Method, got.URL.Path)
+	}
+	if auth := got.Header.Get("Authorization"); auth != "Bearer secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if !strings.Contains(form, "time=1760702400.000") {
+		t.Errorf("form = %q, want the evaluation time", form)
+	}
+}
+
+// endless streams an unbounded response body.
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	return len(p), nil
+}
+
+type endlessTransport struct{}
+
+func (endlessTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(endless{}), Request: req}, nil
+}
+
+func TestClientLimitsResponseSize(t *testing.T) {
+	client, err := NewClient(Options{URL: "http://prometheus.test", Transport: endlessTransport{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Query(context.Background(), `up`, time.Time{}); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("Query error = %v, want a size limit error", err)
+	}
+}
+
+func TestNewClientRejectsInvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "prometheus:9090", "://bad"} {
+		if _, err := NewClient(Options{URL: raw}); err == nil {
+			t.Errorf("NewClient(%q) succeeded", raw)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+)
+
+var ErrClusterNotFound = errors.New("cluster not found")
+
+// ClusterRepository persists the cluster registry.
+type ClusterRepository interface {
+	Create(cluster models.Cluster) (models.Cluster, error)
+	GetByName(name string) (models.Cluster, error)
+	List() ([]models.Cluster, error)
+	Update(cluster models.Cluster) (models.Cluster, error)
+	Delete(name string) error
+}
+
+type clusterRepository struct {
+	db *sql.DB
+}
+
+func NewClusterRepository(db *sql.DB) ClusterRepository {
+	return &clusterRepository{db: db}
+}
+
+const clusterColumns = `name, api_endpoint, credentials_ref, labels, environment, created_at, updated_at`
+
+func (r *clusterRepository) Create(cluster models.Cluster) (models.Cluster, error) {
+	labels, err := json.Marshal(cluster.Labels)
+	if err != nil {
+		return models.Cluster{}, err
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO k8s_clusters (name, api_endpoint, credentials_ref, labels, environment)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING `+clusterColumns,
+		cluster.Name, cluster.APIEndpoint, cluster.CredentialsRef, labels, cluster.Environment,
+	)
+	return scanCluster(row)
+}
+
+func (r *clusterRepository) GetByName(name string) (models.Cluster, error) {
+	row := r.db.QueryRow(`SELECT `+clusterColumns+` FROM k8s_clusters WHERE name = $1`, name)
+	return scanCluster(row)
+}
+
+func (r *clusterRepository) List() ([]models.Cluster, error) {
+	rows, err := r.db.Query(`SELECT ` + clusterColumns + ` FROM k8s_clusters ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clusters []models.Cluster
+	for rows.Next() {
+		cluster, err := scanCluster(rows)
+		if err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, rows.Err()
+}
+
+func (r *clusterRepository) Update(cluster models.Cluster) (models.Cluster, error) {
+	labels, err := json.Marshal(cluster.Labels)
+	if err != nil {
+		return models.Cluster{}, err
+	}
+
+	row := r.db.QueryRow(
+		`UPDATE k8s_clusters
+		 SET api_endpoint = $2, credentials_ref = $3, labels = $4, environment = $5, updated_at = NOW()
+		 WHERE name = $1
+		 RETURNING `+clusterColumns,
+		cluster.Name, cluster.APIEndpoint, cluster.CredentialsRef, labels, cluster.Environment,
+	)
+	return scanCluster(row)
+}
+
+func (r *clusterRepository) Delete(name string) error {
+	res, err := r.db.Exec(`DELETE FROM k8s_clusters WHERE name = $1`, name)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrClusterNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCluster(row rowScanner) (models.Cluster, error) {
+	var cluster models.Cluster
+	var labels []byte
+	if err := row.Scan(
+		&cluster.Name, &cluster.APIEndpoint, &cluster.CredentialsRef, &labels,
+		&cluster.Environment, &cluster.CreatedAt, &cluster.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Cluster{}, ErrClusterNotFound
+		}
+		return models.Cluster{}, err
+	}
+	if err := json.Unmarshal(labels, &cluster.Labels); err != nil {
+		return models.Cluster{}, err
+	}
+	return cluster, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// BatchService reports Job and CronJob health for registered clusters.
+type BatchService interface {
+	ListJobs(ctx context.Context, clusterName string, filter collectors.JobFilter) ([]models.JobStatus, error)
+	ListCronJobs(ctx context.Context, clusterName, namespace string) ([]models.CronJobStatus, error)
+}
+
+type batchService struct {
+	clusterClients
+}
+
+// NewBatchService returns a BatchService that reads batch workloads live from
+// the cluster.
+func NewBatchService(clusters repositories.ClusterRepository, clients collectors.ClientProvider) BatchService {
+	return &batchService{clusterClients{clusters: clusters, clients: clients}}
+}
+
+func (s *batchService) ListJobs(ctx context.Context, clusterName string, filter collectors.JobFilter) ([]models.JobStatus, error) {
+	client, err := s.clientFor(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return collectors.CollectJobs(ctx, client, filter)
+}
+
+func (s *batchService) ListCronJobs(ctx context.Context, clusterName, namespace string) ([]models.CronJobStatus, error) {
+	client, err := s.clientFor(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return collectors.CollectCronJobs(ctx, client, namespace)
+}
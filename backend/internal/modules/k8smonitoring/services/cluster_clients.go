@@ -0,0 +1,22 @@
+package services
+
+import (
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// clusterClients resolves registered cluster names to clientsets.
+type clusterClients struct {
+	clusters repositories.ClusterRepository
+	clients  collectors.ClientProvider
+}
+
+func (r clusterClients) clientFor(clusterName string) (kubernetes.Interface, error) {
+	cluster, err := r.clusters.GetByName(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return r.clients.ClientFor(cluster)
+}
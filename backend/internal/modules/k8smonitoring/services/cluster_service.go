@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+var (
+	ErrClusterExists  = errors.New("cluster already exists")
+	ErrInvalidCluster = errors.New("invalid cluster")
+)
+
+var clusterNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// ClusterService manages the registry of monitored clusters.
+type ClusterService interface {
+	ListClusters() ([]models.Cluster, error)
+	GetCluster(name string) (models.Cluster, error)
+	CreateCluster(cluster models.Cluster) (models.Cluster, error)
+	UpdateCluster(cluster models.Cluster) (models.Cluster, error)
+	DeleteCluster(name string) error
+}
+
+type clusterService struct {
+	repo    repositories.ClusterRepository
+	clients collectors.ClientProvider
+}
+
+// NewClusterService returns a ClusterService backed by repo. Updating or
+// deleting a cluster drops its cached client from clients.
+func NewClusterService(repo repositories.ClusterRepository, clients collectors.ClientProvider) ClusterService {
+	return &clusterService{repo: repo, clients: clients}
+}
+
+func (s *clusterService) ListClusters() ([]models.Cluster, error) {
+	clusters, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if clusters == nil {
+		clusters = []models.Cluster{}
+	}
+	return clusters, nil
+}
+
+func (s *clusterService) GetCluster(name string) (models.Cluster, error) {
+	return s.repo.GetByName(name)
+}
+
+func (s *clusterService) CreateCluster(cluster models.Cluster) (models.Cluster, error) {
+	if err := validateCluster(cluster); err != nil {
+		return models.Cluster{}, err
+	}
+
+	if _, err := s.repo.GetByName(cluster.Name); err == nil {
+		return models.Cluster{}, ErrClusterExists
+	} else if !errors.Is(err, repositories.ErrClusterNotFound) {
+		return models.Cluster{}, err
+	}
+
+	return s.repo.Create(normalizeCluster(cluster))
+}
+
+func (s *clusterService) UpdateCluster(cluster models.Cluster) (models.Cluster, error) {
+	if err := validateCluster(cluster); err != nil {
+		return models.Cluster{}, err
+	}
+	updated, err := s.repo.Update(normalizeCluster(cluster))
+	if err != nil {
+		return models.Cluster{}, err
+	}
+	s.clients.Forget(cluster.Name)
+	return updated, nil
+}
+
+func (s *clusterService) DeleteCluster(name string) error {
+	if err := s.repo.Delete(name); err != nil {
+		return err
+	}
+	s.clients.Forget(name)
+	return nil
+}
+
+func validateCluster(cluster models.Cluster) error {
+	if !clusterNamePattern.MatchString(cluster.Name) {
+		return fmt.Errorf("%w: name must be a lowercase DNS label", ErrInvalidCluster)
+	}
+	if cluster.APIEndpoint != "" {
+		u, err := url.Parse(cluster.APIEndpoint)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%w: apiEndpoint must be an http(s) URL", ErrInvalidCluster)
+		}
+	}
+	if err := collectors.ValidateCredentialsRef(cluster.CredentialsRef); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCluster, err)
+	}
+	return nil
+}
+
+func normalizeCluster(cluster models.Cluster) models.Cluster {
+	if cluster.Labels == nil {
+		cluster.Labels = map[string]string{}
+	}
+	return cluster
+}
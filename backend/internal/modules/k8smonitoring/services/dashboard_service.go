@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/dashboards"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/grafana"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+var ErrGrafanaNotConfigured = errors.New("grafana is not configured")
+
+// DashboardService generates Grafana dashboards for the registered
+// clusters. An empty cluster name generates fleet dashboards with a cluster
+// selector; a registered name generates dashboards pinned to that cluster.
+type DashboardService interface {
+	ListDashboards(cluster string) ([]dashboards.Dashboard, error)
+	GetDashboard(category, cluster string) (dashboards.Dashboard, error)
+	// PushDashboards saves every dashboard to Grafana, overwriting earlier
+	// pushes, and reports each outcome.
+	PushDashboards(ctx context.Context, cluster string) ([]models.DashboardPush, error)
+}
+
+type dashboardService struct {
+	clusters     repositories.ClusterRepository
+	clusterLabel string
+	grafana      grafana.Client
+	folderUID    string
+}
+
+// NewDashboardService returns a DashboardService whose queries select
+// clusters by clusterLabel. Pushes go to grafanaClient, into the folder
+// with folderUID; a nil grafanaClient disables them.
+func NewDashboardService(clusters repositories.ClusterRepository, clusterLabel string, grafanaClient grafana.Client, folderUID string) DashboardService {
+	return &dashboardService{
+		clusters:     clusters,
+		clusterLabel: clusterLabel,
+		grafana:      grafanaClient,
+		folderUID:    folderUID,
+	}
+}
+
+func (s *dashboardService) ListDashboards(cluster string) ([]dashboards.Dashboard, error) {
+	opts, err := s.options(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return dashboards.GenerateAll(opts)
+}
+
+func (s *dashboardService) GetDashboard(category, cluster string) (dashboards.Dashboard, error) {
+	opts, err := s.options(cluster)
+	if err != nil {
+		return dashboards.Dashboard{}, err
+	}
+	return dashboards.Generate(category, opts)
+}
+
+// PushDashboards keeps going after a failed save, so one rejected
+// dashboard does not hold back the others.
+func (s *dashboardService) PushDashboards(ctx context.Context, cluster string) ([]models.DashboardPush, error) {
+	if s.grafana == nil {
+		return nil, ErrGrafanaNotConfigured
+	}
+	generated, err := s.ListDashboards(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	pushes := make([]models.DashboardPush, 0, len(generated))
+	for _, dashboard := range generated {
+		push := models.DashboardPush{UID: dashboard.UID, Title: dashboard.Title}
+		saved, err := s.grafana.SaveDashboard(ctx, dashboard, s.folderUID)
+		if err != nil {
+			push.Outcome = models.PushFailed
+			push.Error = err.Error()
+		} else {
+			push.Outcome = models.PushSuccess
+			push.URL = saved.URL
+			push.Version = saved.Version
+		}
+		pushes = append(pushes, push)
+	}
+	return pushes, nil
+}
+
+// options offers every registered cluster in the cluster selector and, when
+// cluster is set, pins the dashboards to it.
+func (s *dashboardService) options(cluster string) (dashboards.Options, error) {
+	opts := dashboards.Options{ClusterLabel: s.clusterLabel}
+	if cluster != "" {
+		if _, err := s.clusters.GetByName(cluster); err != nil {
+			return dashboards.Options{}, err
+		}
+		opts.Cluster = cluster
+		return opts, nil
+	}
+
+	registered, err := s.clusters.List()
+	if err != nil {
+		return dashboards.Options{}, err
+	}
+	for _, c := range registered {
+		opts.Clusters = append(opts.Clusters, c.Name)
+	}
+	return opts, nil
+}
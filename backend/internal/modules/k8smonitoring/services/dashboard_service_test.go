@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/grafana"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+func newGrafanaStandIn(t *testing.T, standIn *testutil.GrafanaStandIn) grafana.Client {
+	t.Helper()
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+	client, err := grafana.NewClient(grafana.Options{URL: server.URL, APIToken: standIn.Token, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestPushDashboards(t *testing.T) {
+	clusters := memClusters{"prod": {Name: "prod"}, "staging": {Name: "staging"}}
+
+	tests := []struct {
+		name       string
+		cluster    string
+		reject     map[string]string
+		wantUIDs   []string
+		wantFailed map[string]string
+	}{
+		{
+			name:     "fleet",
+			wantUIDs: []string{"k8s-cluster", "k8s-namespace", "k8s-workload", "k8s-node", "k8s-cost"},
+		},
+		{
+			name:     "pinned cluster",
+			cluster:  "prod",
+			wantUIDs: []string{"k8s-cluster-prod", "k8s-namespace-prod", "k8s-workload-prod", "k8s-node-prod", "k8s-cost-prod"},
+		},
+		{
+			name:       "one dashboard rejected",
+			reject:     map[string]string{"k8s-node": "Dashboard too large"},
+			wantUIDs:   []string{"k8s-cluster", "k8s-namespace", "k8s-workload", "k8s-node", "k8s-cost"},
+			wantFailed: map[string]string{"k8s-node": "Dashboard too large"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := &testutil.GrafanaStandIn{Token: "service-account", Reject: tt.reject}
+			service := NewDashboardService(clusters, "cluster", newGrafanaStandIn(t, standIn), "k8s")
+
+			pushes, err := service.PushDashboards(context.Background(), tt.cluster)
+			if err != nil {
+				t.Fatalf("PushDashboards: %v", err)
+			}
+			if len(pushes) != len(tt.wantUIDs) {
+				t.Fatalf("got %d pushes, want %d", len(pushes), len(tt.wantUIDs))
+			}
+			saved := standIn.Dashboards()
+			for i, push := range pushes {
+				if push.UID != tt.wantUIDs[i] || push.Title == "" {
+					t.Errorf("push %d = %+v, want UID %s", i, push, tt.wantUIDs[i])
+				}
+				if message, failed := tt.wantFailed[push.UID]; failed {
+					if push.Outcome != models.PushFailed || !strings.Contains(push.Error, message) {
+						t.Errorf("push %s = %+v, want a failure mentioning %q", push.UID, push, message)
+					}
+					if _, ok := saved[push.UID]; ok {
+						t.Errorf("rejected dashboard %s was stored", push.UID)
+					}
+					continue
+				}
+				if push.Outcome != models.PushSuccess || push.URL != "/d/"+push.UID || push.Version != 1 || push.Error != "" {
+					t.Errorf("push %s = %+v, want a first successful save", push.UID, push)
+				}
+				if stored, ok := saved[push.UID]; !ok || stored.FolderUID != "k8s" {
+					t.Errorf("stored %s = %+v, want it in folder k8s", push.UID, stored)
+				}
+			}
+		})
+	}
+}
+
+func TestPushDashboardsOverwrites(t *testing.T) {
+	standIn := &testutil.GrafanaStandIn{}
+	service := NewDashboardService(memClusters{}, "cluster", newGrafanaStandIn(t, standIn), "")
+
+	for want := 1; want <= 2; want++ {
+		pushes, err := service.PushDashboards(context.Background(), "")
+		if err != nil {
+			t.Fatalf("PushDashboards: %v", err)
+		}
+		for _, push := range pushes {
+			if push.Outcome != models.PushSuccess || push.Version != want {
+				t.Errorf("push %d of %s = %+v, want version %d", want, push.UID, push, want)
+			}
+		}
+	}
+}
+
+func TestPushDashboardsErrors(t *testing.T) {
+	standIn := &testutil.GrafanaStandIn{}
+
+	if _, err := NewDashboardService(memClusters{}, "cluster", nil, "").PushDashboards(context.Background(), ""); !errors.Is(err, ErrGrafanaNotConfigured) {
+		t.Errorf("without a client: err = %v, want ErrGrafanaNotConfigured", err)
+	}
+
+	service := NewDashboardService(memClusters{}, "cluster", newGrafanaStandIn(t, standIn), "")
+	if _, err := service.PushDashboards(context.Background(), "missing"); !errors.Is(err, repositories.ErrClusterNotFound) {
+		t.Errorf("unknown cluster: err = %v, want ErrClusterNotFound", err)
+	}
+	if saved := standIn.Dashboards(); len(saved) != 0 {
+		t.Errorf("stored %v for an unknown cluster", saved)
+	}
+}
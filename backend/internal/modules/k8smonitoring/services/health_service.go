@@ -1,30 +1,126 @@
 package services
 
 import (
+	"context"
+	"sync"
 	"time"
 
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
 	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/prometheus"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
 )
 
 // HealthService provides health snapshots for Kubernetes clusters.
 type HealthService interface {
-	GetClusterHealth(clusterName string) models.ClusterHealth
+	GetClusterHealth(ctx context.Context, clusterName string) (models.ClusterHealth, error)
+	GetFleetHealth(ctx context.Context) (models.FleetHealth, error)
 }
 
-type healthService struct{}
+type healthService struct {
+	clusterClients
+	clusterTimeout time.Duration
+	metrics        prometheus.Client
+	clusterLabel   string
+}
+
+// NewHealthService returns a HealthService that collects snapshots live from
+// registered clusters. clusterTimeout bounds each cluster's collection during
+// a fleet rollup. When metrics is non-nil, snapshots are enriched with CPU
+// and memory utilisation from series labelled clusterLabel=<cluster name>.
+func NewHealthService(clusters repositories.ClusterRepository, clients collectors.ClientProvider, clusterTimeout time.Duration, metrics prometheus.Client, clusterLabel string) HealthService {
+	return &healthService{
+		clusterClients: clusterClients{clusters: clusters, clients: clients},
+		clusterTimeout: clusterTimeout,
+		metrics:        metrics,
+		clusterLabel:   clusterLabel,
+	}
+}
+
+func (s *healthService) GetClusterHealth(ctx context.Context, clusterName string) (models.ClusterHealth, error) {
+	cluster, err := s.clusters.GetByName(clusterName)
+	if err != nil {
+		return models.ClusterHealth{}, err
+	}
+
+	return s.collect(ctx, cluster)
+}
+
+// GetFleetHealth collects every registered cluster concurrently. A cluster
+// that fails or times out is reported with its error rather than failing the
+// whole rollup.
+func (s *healthService) GetFleetHealth(ctx context.Context) (models.FleetHealth, error) {
+	clusters, err := s.clusters.List()
+	if err != nil {
+		return models.FleetHealth{}, err
+	}
+
+	snapshots := make([]models.ClusterSnapshot, len(clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func(i int, cluster models.Cluster) {
+			defer wg.Done()
+			snapshots[i] = s.snapshot(ctx, cluster)
+		}(i, cluster)
+	}
+	wg.Wait()
 
-// NewHealthService returns a new HealthService.
-func NewHealthService() HealthService {
-	return &healthService{}
+	fleet := models.FleetHealth{
+		Status:       models.StatusHealthy,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		StatusCounts: map[string]int{},
+		Clusters:     snapshots,
+	}
+	if len(snapshots) == 0 {
+		fleet.Status = models.StatusUnknown
+	}
+	for _, snapshot := range snapshots {
+		fleet.StatusCounts[snapshot.Status]++
+		fleet.Status = models.WorseStatus(fleet.Status, snapshot.Status)
+	}
+	return fleet, nil
+}
+
+func (s *healthService) snapshot(ctx context.Context, cluster models.Cluster) models.ClusterSnapshot {
+	snapshot := models.ClusterSnapshot{
+		ClusterName: cluster.Name,
+		Environment: cluster.Environment,
+		Status:      models.StatusUnknown,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.clusterTimeout)
+	defer cancel()
+
+	health, err := s.collect(ctx, cluster)
+	if err != nil {
+		snapshot.Error = err.Error()
+		return snapshot
+	}
+	snapshot.Status = health.Status
+	snapshot.Health = &health
+	return snapshot
 }
 
-func (s *healthService) GetClusterHealth(clusterName string) models.ClusterHealth {
-	return models.ClusterHealth{
-		ClusterName: clusterName,
-		Status:      "unknown",
-		Timestamp:   time.Now().UTC().Format(time.RFC3339),
-		Signals: map[string]string{
-			"note": "k8s monitoring module scaffold (no collectors wired yet)",
-		},
+func (s *healthService) collect(ctx context.Context, cluster models.Cluster) (models.ClusterHealth, error) {
+	client, err := s.clients.ClientFor(cluster)
+	if err != nil {
+		return models.ClusterHealth{}, err
+	}
+	health, err := collectors.CollectClusterHealth(ctx, cluster.Name, client)
+	if err != nil {
+		return models.ClusterHealth{}, err
+	}
+
+	// Utilisation is supplementary; a Prometheus outage should not hide the
+	// API server's view of the cluster.
+	if s.metrics != nil {
+		utilization, err := collectors.CollectUtilization(ctx, s.metrics, s.clusterLabel, cluster.Name)
+		if err != nil {
+			health.Signals["utilization"] = "unavailable: " + err.Error()
+		} else {
+			health.Utilization = &utilization
+		}
 	}
+	return health, nil
 }
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/prometheus"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+	"github.com/fbisdevoptics/backend/internal/testutil"
+)
+
+// staticProvider serves pre-built clientsets keyed by cluster name.
+type staticProvider map[string]kubernetes.Interface
+
+func (p staticProvider) ClientFor(cluster models.Cluster) (kubernetes.Interface, error) {
+	client, ok := p[cluster.Name]
+	if !ok {
+		return nil, collectors.ErrClusterNotConfigured
+	}
+	return client, nil
+}
+
+func (p staticProvider) Forget(string) {}
+
+// memClusters is an in-memory ClusterRepository.
+type memClusters map[string]models.Cluster
+
+func (r memClusters) Create(cluster models.Cluster) (models.Cluster, error) {
+	r[cluster.Name] = cluster
+	return cluster, nil
+}
+
+func (r memClusters) GetByName(name string) (models.Cluster, error) {
+	cluster, ok := r[name]
+	if !ok {
+		return models.Cluster{}, repositories.ErrClusterNotFound
+	}
+	return cluster, nil
+}
+
+func (r memClusters) List() ([]models.Cluster, error) {
+	clusters := make([]models.Cluster, 0, len(r))
+	for _, name := range []string{"prod", "staging", "dev"} {
+		if cluster, ok := r[name]; ok {
+			clusters = append(clusters, cluster)
+		}
+	}
+	return clusters, nil
+}
+
+func (r memClusters) Update(cluster models.Cluster) (models.Cluster, error) {
+	if _, ok := r[cluster.Name]; !ok {
+		return models.Cluster{}, repositories.ErrClusterNotFound
+	}
+	r[cluster.Name] = cluster
+	return cluster, nil
+}
+
+func (r memClusters) Delete(name string) error {
+	if _, ok := r[name]; !ok {
+		return repositories.ErrClusterNotFound
+	}
+	delete(r, name)
+	return nil
+}
+
+func readyNode(name string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		}},
+	}
+}
+
+func TestGetClusterHealth(t *testing.T) {
+	clusters := memClusters{"prod": {Name: "prod", Environment: "production"}}
+	service := NewHealthService(clusters, staticProvider{"prod": fake.NewSimpleClientset(readyNode("a"))}, time.Second, nil, "")
+
+	health, err := service.GetClusterHealth(context.Background(), "prod")
+	if err != nil {
+		t.Fatalf("GetClusterHealth: %v", err)
+	}
+	if health.ClusterName != "prod" || health.Status != models.StatusHealthy || health.Nodes.Ready != 1 {
+		t.Fatalf("health = %+v", health)
+	}
+
+	if _, err := service.GetClusterHealth(context.Background(), "missing"); !errors.Is(err, repositories.ErrClusterNotFound) {
+		t.Fatalf("unknown cluster: err = %v, want ErrClusterNotFound", err)
+	}
+}
+
+func TestGetClusterHealthUtilization(t *testing.T) {
+	clusters := memClusters{
+		"prod":    {Name: "prod", Environment: "production"},
+		"staging": {Name: "staging", Environment: "staging"},
+	}
+	clients := staticProvider{
+		"prod":    fake.NewSimpleClientset(readyNode("a")),
+		"staging": fake.NewSimpleClientset(readyNode("a")),
+	}
+	cpu, memory := collectors.UtilizationQueries("cluster", "prod")
+	sample := func(value string) string {
+		return `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1760702400,"` + value + `"]}]}}`
+	}
+	// staging has no recording, as if Prometheus rejected its queries.
+	recording := testutil.PrometheusRecording{cpu: sample("12.5"), memory: sample("80")}
+	metrics, err := prometheus.NewClient(prometheus.Options{URL: "http://prometheus.test", Transport: recording})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewHealthService(clusters, clients, time.Second, metrics, "cluster")
+
+	health, err := service.GetClusterHealth(context.Background(), "prod")
+	if err != nil {
+		t.Fatalf("GetClusterHealth: %v", err)
+	}
+	if health.Utilization == nil || health.Utilization.CPUPercent != 12.5 || health.Utilization.MemoryPercent != 80 {
+		t.Errorf("prod utilization = %+v", health.Utilization)
+	}
+
+	health, err = service.GetClusterHealth(context.Background(), "staging")
+	if err != nil {
+		t.Fatalf("GetClusterHealth without metrics: %v", err)
+	}
+	if health.Utilization != nil || health.Signals["utilization"] == "" || health.Status != models.StatusHealthy {
+		t.Errorf("staging = %+v, want healthy with the utilisation failure in signals", health)
+	}
+}
+
+func TestGetFleetHealth(t *testing.T) {
+	clusters := memClusters{
+		"prod":    {Name: "prod", Environment: "production"},
+		"staging": {Name: "staging", Environment: "staging"},
+		"dev":     {Name: "dev", Environment: "development"},
+	}
+	clients := staticProvider{
+		"prod":    fake.NewSimpleClientset(readyNode("a"), readyNode("b")),
+		"staging": fake.NewSimpleClientset(readyNode("a"), &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "b"}}),
+		// dev has no client, so its collection fails.
+	}
+	service := NewHealthService(clusters, clients, time.Second, nil, "")
+
+	fleet, err := service.GetFleetHealth(context.Background())
+	if err != nil {
+		t.Fatalf("GetFleetHealth: %v", err)
+	}
+	if len(fleet.Clusters) != 3 {
+		t.Fatalf("got %d clusters, want 3", len(fleet.Clusters))
+	}
+
+	want := map[string]string{"prod": models.StatusHealthy, "staging": models.StatusDegraded, "dev": models.StatusUnknown}
+	for _, snapshot := range fleet.Clusters {
+		if snapshot.Status != want[snapshot.ClusterName] {
+			t.Errorf("%s: status %q, want %q", snapshot.ClusterName, snapshot.Status, want[snapshot.ClusterName])
+		}
+	}
+	dev := fleet.Clusters[2]
+	if dev.Error == "" || dev.Health != nil {
+		t.Errorf("dev snapshot = %+v, want an error and no health", dev)
+	}
+	if fleet.StatusCounts[models.StatusHealthy] != 1 || fleet.StatusCounts[models.StatusDegraded] != 1 || fleet.StatusCounts[models.StatusUnknown] != 1 {
+		t.Errorf("StatusCounts = %v", fleet.StatusCounts)
+	}
+	if fleet.Status != models.WorseStatus(models.StatusDegraded, models.StatusUnknown) {
+		t.Errorf("fleet status = %q", fleet.Status)
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// NamespaceService reports ResourceQuota usage per namespace.
+type NamespaceService interface {
+	ListNamespaces(ctx context.Context, clusterName string, filter collectors.NamespaceFilter) ([]models.NamespaceUsage, error)
+}
+
+type namespaceService struct {
+	clusterClients
+}
+
+// NewNamespaceService returns a NamespaceService that reads quotas live from
+// the cluster.
+func NewNamespaceService(clusters repositories.ClusterRepository, clients collectors.ClientProvider) NamespaceService {
+	return &namespaceService{clusterClients{clusters: clusters, clients: clients}}
+}
+
+func (s *namespaceService) ListNamespaces(ctx context.Context, clusterName string, filter collectors.NamespaceFilter) ([]models.NamespaceUsage, error) {
+	client, err := s.clientFor(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return collectors.CollectNamespaceUsage(ctx, client, filter)
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// NodeService lists node health for registered clusters.
+type NodeService interface {
+	ListNodes(ctx context.Context, clusterName string, filter collectors.NodeFilter) ([]models.NodeHealth, error)
+}
+
+type nodeService struct {
+	clusterClients
+}
+
+// NewNodeService returns a NodeService that reads nodes live from the cluster.
+func NewNodeService(clusters repositories.ClusterRepository, clients collectors.ClientProvider) NodeService {
+	return &nodeService{clusterClients{clusters: clusters, clients: clients}}
+}
+
+func (s *nodeService) ListNodes(ctx context.Context, clusterName string, filter collectors.NodeFilter) ([]models.NodeHealth, error) {
+	client, err := s.clientFor(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return collectors.CollectNodes(ctx, client, filter)
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// PodService lists pod and container health for registered clusters.
+type PodService interface {
+	ListPods(ctx context.Context, clusterName string, filter collectors.PodFilter) (models.PodList, error)
+}
+
+type podService struct {
+	clusterClients
+}
+
+// NewPodService returns a PodService that reads pods live from the cluster.
+func NewPodService(clusters repositories.ClusterRepository, clients collectors.ClientProvider) PodService {
+	return &podService{clusterClients{clusters: clusters, clients: clients}}
+}
+
+func (s *podService) ListPods(ctx context.Context, clusterName string, filter collectors.PodFilter) (models.PodList, error) {
+	client, err := s.clientFor(clusterName)
+	if err != nil {
+		return models.PodList{}, err
+	}
+	return collectors.CollectPods(ctx, client, filter)
+}
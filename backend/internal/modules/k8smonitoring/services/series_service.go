@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// ClusterUpMetric is 1 for each registered cluster whose series were
+// collected and 0 for one that could not be reached.
+const ClusterUpMetric = "devoptics_cluster_up"
+
+// SeriesService snapshots every registered cluster as labelled series for
+// rule evaluation.
+type SeriesService interface {
+	FleetSeries(ctx context.Context) ([]models.Series, error)
+}
+
+type seriesService struct {
+	clusterClients
+	clusterTimeout time.Duration
+	clusterLabel   string
+}
+
+// NewSeriesService returns a SeriesService. Each series is labelled
+// clusterLabel=<cluster name>, plus environment when the cluster has one, to
+// match the label strategy used for Prometheus series.
+func NewSeriesService(clusters repositories.ClusterRepository, clients collectors.ClientProvider, clusterTimeout time.Duration, clusterLabel string) SeriesService {
+	return &seriesService{
+		clusterClients: clusterClients{clusters: clusters, clients: clients},
+		clusterTimeout: clusterTimeout,
+		clusterLabel:   clusterLabel,
+	}
+}
+
+// FleetSeries collects clusters concurrently. A cluster that fails
+// contributes only its devoptics_cluster_up series with value 0.
+func (s *seriesService) FleetSeries(ctx context.Context) ([]models.Series, error) {
+	clusters, err := s.clusters.List()
+	if err != nil {
+		return nil, err
+	}
+
+	perCluster := make([][]models.Series, len(clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func(i int, cluster models.Cluster) {
+			defer wg.Done()
+			perCluster[i] = s.collect(ctx, cluster)
+		}(i, cluster)
+	}
+	wg.Wait()
+
+	var series []models.Series
+	for _, items := range perCluster {
+		series = append(series, items...)
+	}
+	return series, nil
+}
+
+func (s *seriesService) collect(ctx context.Context, cluster models.Cluster) []models.Series {
+	ctx, cancel := context.WithTimeout(ctx, s.clusterTimeout)
+	defer cancel()
+
+	up := 0.0
+	var series []models.Series
+	if client, err := s.clients.ClientFor(cluster); err == nil {
+		if collected, err := collectors.CollectSeries(ctx, client); err == nil {
+			series = collected
+			up = 1
+		}
+	}
+	series = append(series, models.Series{
+		Labels: map[string]string{collectors.MetricNameLabel: ClusterUpMetric},
+		Value:  up,
+	})
+
+	for _, item := range series {
+		item.Labels[s.clusterLabel] = cluster.Name
+		if cluster.Environment != "" {
+			item.Labels["environment"] = cluster.Environment
+		}
+	}
+	return series
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/collectors"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/models"
+	"github.com/fbisdevoptics/backend/internal/modules/k8smonitoring/repositories"
+)
+
+// WorkloadService reports rollout state for Deployments and StatefulSets.
+type WorkloadService interface {
+	ListRollouts(ctx context.Context, clusterName string, filter collectors.WorkloadFilter) ([]models.WorkloadRollout, error)
+}
+
+type workloadService struct {
+	clusterClients
+}
+
+// NewWorkloadService returns a WorkloadService that reads workloads live from
+// the cluster.
+func NewWorkloadService(clusters repositories.ClusterRepository, clients collectors.ClientProvider) WorkloadService {
+	return &workloadService{clusterClients{clusters: clusters, clients: clients}}
+}
+
+func (s *workloadService) ListRollouts(ctx context.Context, clusterName string, filter collectors.WorkloadFilter) ([]models.WorkloadRollout, error) {
+	client, err := s.clientFor(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return collectors.CollectRollouts(ctx, client, filter)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/fbisdevoptics/backend/internal/modules/metrics/services"
+)
+
+const (
+	unmatchedRoute = "unmatched"
+	// latencyBucketCount hourly buckets keep two days of latency totals for
+	// the dashboard summary.
+	latencyBucketCount = 48
+)
+
+// RequestMetrics records per-route request counts and latency histograms,
+// keyed by Gin's route template, and serves them in Prometheus text format.
+// It also acts as the summary's LatencySource.
+type RequestMetrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	// excluded routes are exported but kept out of the summary latency, so
+	// scrapes, health probes and requests no route matched do not drag the
+	// API average down.
+	excluded map[string]bool
+
+	mu      sync.Mutex
+	buckets [latencyBucketCount]latencyBucket
+}
+
+type latencyBucket struct {
+	hour  time.Time
+	count int
+	sumMs float64
+}
+
+// NewRequestMetrics returns RequestMetrics with its own registry, which also
+// exports Go runtime and process metrics. Requests to excludedRoutes, and
+// requests that matched no route, are still exported but do not count
+// towards LatencyStats.
+func NewRequestMetrics(excludedRoutes ...string) *RequestMetrics {
+	m := &RequestMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests handled, by route template, method and status class.",
+		}, []string{"route", "method", "status_class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency, by route template and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		excluded: map[string]bool{unmatchedRoute: true},
+	}
+	for _, route := range excludedRoutes {
+		m.excluded[route] = true
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Middleware records every request after the handler chain completes.
+func (m *RequestMetrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		elapsed := time.Since(start)
+
+		// FullPath is the route template (/users/:id), which keeps label
+		// cardinality bounded; unmatched paths share one label.
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+
+		m.requests.WithLabelValues(route, method, statusClass(c.Writer.Status())).Inc()
+		m.duration.WithLabelValues(route, method).Observe(elapsed.Seconds())
+		if !m.excluded[route] {
+			m.observe(start, elapsed)
+		}
+	}
+}
+
+// Handler serves the registry in Prometheus text exposition format.
+func (m *RequestMetrics) Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// LatencyStats returns the average latency of requests since the start of
+// the hour containing since.
+func (m *RequestMetrics) LatencyStats(_ context.Context, since time.Time) (services.LatencyStats, error) {
+	from := since.Truncate(time.Hour)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var count int
+	var sumMs float64
+	for _, bucket := range m.buckets {
+		if bucket.count > 0 && !bucket.hour.Before(from) {
+			count += bucket.count
+			sumMs += bucket.sumMs
+		}
+	}
+	stats := services.LatencyStats{Count: count}
+	if count > 0 {
+		stats.AvgMs = sumMs / float64(count)
+	}
+	return stats, nil
+}
+
+func (m *RequestMetrics) observe(start time.Time, elapsed time.Duration) {
+	hour := start.Truncate(time.Hour)
+	idx := int(hour.Unix()/3600) % latencyBucketCount
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bucket := &m.buckets[idx]
+	if !bucket.hour.Equal(hour) {
+		*bucket = latencyBucket{hour: hour}
+	}
+	bucket.count++
+	bucket.sumMs += float64(elapsed) / float64(time.Millisecond)
+}
+
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := NewRequestMetrics("/metrics")
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/api/v1/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+	router.GET("/api/v1/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+	router.GET("/metrics", m.Handler())
+
+	for _, request := range []struct{ method, path string }{
+		{http.MethodGet, "/api/v1/users/1"},
+		{http.MethodGet, "/api/v1/users/2"},
+		{http.MethodGet, "/api/v1/users/3"},
+		{http.MethodPost, "/api/v1/users"},
+		{http.MethodGet, "/api/v1/fail"},
+		{http.MethodGet, "/nope"},
+		{http.MethodGet, "/api/v1/users/1/avatar"},
+		{http.MethodGet, "/metrics"},
+	} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.path, nil))
+	}
+
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	exposition := resp.Body.String()
+
+	// Requests are keyed by route template, not path, and unmatched paths
+	// share one series.
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/api/v1/users/:id",status_class="2xx"} 3`,
+		`http_requests_total{method="POST",route="/api/v1/users",status_class="4xx"} 1`,
+		`http_requests_total{method="GET",route="/api/v1/fail",status_class="5xx"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status_class="4xx"} 2`,
+		`http_requests_total{method="GET",route="/metrics",status_class="2xx"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/api/v1/users/:id"} 3`,
+		`http_request_duration_seconds_count{method="GET",route="unmatched"} 2`,
+		"go_goroutines ",
+	} {
+		if !strings.Contains(exposition, want) {
+			t.Errorf("exposition lacks %s", want)
+		}
+	}
+	if strings.Contains(exposition, `route="/api/v1/users/1"`) {
+		t.Error("a raw path became a route label")
+	}
+
+	// Only the five requests to API routes count towards the summary;
+	// scrapes and unmatched paths do not.
+	stats, err := m.LatencyStats(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 5 {
+		t.Errorf("LatencyStats counted %d requests, want 5", stats.Count)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	for status, want := range map[int]string{
+		http.StatusOK:                  "2xx",
+		http.StatusNoContent:           "2xx",
+		http.StatusMovedPermanently:    "3xx",
+		http.StatusNotFound:            "4xx",
+		http.StatusTooManyRequests:     "4xx",
+		http.StatusInternalServerError: "5xx",
+		http.StatusServiceUnavailable:  "5xx",
+	} {
+		if got := statusClass(status); got != want {
+			t.Errorf("statusClass(%d) = %s, want %s", status, got, want)
+		}
+	}
+}
+
+func TestLatencyBuckets(t *testing.T) {
+	start := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	m := NewRequestMetrics()
+	m.observe(start.Add(10*time.Minute), 100*time.Millisecond)
+	m.observe(start.Add(50*time.Minute), 100*time.Millisecond)
+	m.observe(start.Add(65*time.Minute), 400*time.Millisecond)
+
+	steps := []struct {
+		name string
+		// observe, when set, is recorded at observeAt before the query.
+		observeAt time.Time
+		observe   time.Duration
+		since     time.Time
+		wantCount int
+		wantAvgMs float64
+	}{
+		{name: "whole hours from since", since: start.Add(30 * time.Minute), wantCount: 3, wantAvgMs: 200},
+		{name: "later hour only", since: start.Add(time.Hour), wantCount: 1, wantAvgMs: 400},
+		{name: "nothing yet", since: start.Add(2 * time.Hour)},
+		{
+			// Two days on, the first hour's bucket is reused and its old
+			// totals dropped.
+			name:      "ring wraps",
+			observeAt: start.Add(48*time.Hour + 5*time.Minute),
+			observe:   40 * time.Millisecond,
+			since:     start,
+			wantCount: 2,
+			wantAvgMs: 220,
+		},
+		{name: "after the wrap", since: start.Add(47 * time.Hour), wantCount: 1, wantAvgMs: 40},
+		{
+			// The next hour reuses the second bucket, so however far back
+			// since reaches, only the last two days are counted.
+			name:      "since beyond the ring",
+			observeAt: start.Add(49*time.Hour + 5*time.Minute),
+			observe:   10 * time.Millisecond,
+			since:     start.Add(-24 * time.Hour),
+			wantCount: 2,
+			wantAvgMs: 25,
+		},
+	}
+	for _, step := range steps {
+		if !step.observeAt.IsZero() {
+			m.observe(step.observeAt, step.observe)
+		}
+		stats, err := m.LatencyStats(context.Background(), step.since)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Count != step.wantCount || math.Abs(stats.AvgMs-step.wantAvgMs) > 1e-9 {
+			t.Errorf("%s: LatencyStats = %+v, want %d requests averaging %vms", step.name, stats, step.wantCount, step.wantAvgMs)
+		}
+	}
+}
@@ -2,33 +2,22 @@ package handlers
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/metrics/services"
 )
 
 type SummaryHandler struct {
-	startedAt time.Time
+	service services.SummaryService
 }
 
-func NewSummaryHandler(startedAt time.Time) *SummaryHandler {
-	return &SummaryHandler{startedAt: startedAt}
+func NewSummaryHandler(service services.SummaryService) *SummaryHandler {
+	return &SummaryHandler{service: service}
 }
 
+// GetSummary returns the dashboard headline figures. Figures without a data
+// source are omitted and listed under "unavailable".
 func (h *SummaryHandler) GetSummary(c *gin.Context) {
-	uptime := time.Since(h.startedAt)
-	c.JSON(http.StatusOK, gin.H{
-		"totalEndpoints":    137,
-		"avgLatencyMs":      177,
-		"uptimeTodayPercent": 100,
-		"scheduledApis":     6,
-		"totalRunsToday":    1227,
-		"successRateToday":  97,
-		"scheduledRunsToday": 4,
-		"pipelineSuccess":   100,
-		"serversActive":     "0/0",
-		"serverUptime":      100,
-		"monitoringSince":   h.startedAt.Format(time.RFC3339),
-		"uptimeSeconds":     int(uptime.Seconds()),
-	})
+	c.JSON(http.StatusOK, h.service.GetSummary(c.Request.Context()))
 }
@@ -0,0 +1,20 @@
+package models
+
+// Summary is the dashboard headline payload. Fields whose data source is not
+// wired, or has no data for the window yet, are omitted and their JSON names
+// listed in Unavailable.
+type Summary struct {
+	TotalEndpoints     *int     `json:"totalEndpoints,omitempty"`
+	AvgLatencyMs       *float64 `json:"avgLatencyMs,omitempty"`
+	UptimeTodayPercent *float64 `json:"uptimeTodayPercent,omitempty"`
+	ScheduledAPIs      *int     `json:"scheduledApis,omitempty"`
+	TotalRunsToday     *int     `json:"totalRunsToday,omitempty"`
+	SuccessRateToday   *float64 `json:"successRateToday,omitempty"`
+	ScheduledRunsToday *int     `json:"scheduledRunsToday,omitempty"`
+	PipelineSuccess    *float64 `json:"pipelineSuccess,omitempty"`
+	ServersActive      *string  `json:"serversActive,omitempty"`
+	ServerUptime       *float64 `json:"serverUptime,omitempty"`
+	MonitoringSince    string   `json:"monitoringSince"`
+	UptimeSeconds      int      `json:"uptimeSeconds"`
+	Unavailable        []string `json:"unavailable"`
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/metrics/models"
+)
+
+// pipelineWindow is how far back pipeline outcomes count towards the
+// success rate; pipelines run too rarely for a per-day figure.
+const pipelineWindow = 7 * 24 * time.Hour
+
+// EndpointStats describes monitored API endpoints and their probes since the
+// start of the window.
+type EndpointStats struct {
+	Total    int
+	Probes   int
+	UpProbes int
+}
+
+// LatencyStats describes request latencies observed since the start of the
+// window.
+type LatencyStats struct {
+	Count int
+	AvgMs float64
+}
+
+// RunStats describes scheduled API runs since the start of the window.
+type RunStats struct {
+	ScheduledAPIs int
+	Total         int
+	Succeeded     int
+	Scheduled     int
+}
+
+// PipelineStats describes CI/CD pipeline outcomes since the start of the
+// window.
+type PipelineStats struct {
+	Total     int
+	Succeeded int
+}
+
+// ServerStats describes registered servers. UptimePercent is nil when no
+// server has reported in the window.
+type ServerStats struct {
+	Active        int
+	Total         int
+	UptimePercent *float64
+}
+
+// The sources report stats since a point in time. They are plain functions
+// so the modules behind them need not import this package; the caller
+// converts each module's own stats type.
+type (
+	EndpointSource func(ctx context.Context, since time.Time) (EndpointStats, error)
+	LatencySource  func(ctx context.Context, since time.Time) (LatencyStats, error)
+	RunSource      func(ctx context.Context, since time.Time) (RunStats, error)
+	PipelineSource func(ctx context.Context, since time.Time) (PipelineStats, error)
+	ServerSource   func(ctx context.Context, since time.Time) (ServerStats, error)
+)
+
+// Sources feed the summary. A nil source leaves its fields unavailable.
+type Sources struct {
+	Endpoints EndpointSource
+	Latency   LatencySource
+	Runs      RunSource
+	Pipelines PipelineSource
+	Servers   ServerSource
+}
+
+// SummaryService computes the dashboard summary from recorded data.
+type SummaryService interface {
+	GetSummary(ctx context.Context) models.Summary
+}
+
+type summaryService struct {
+	startedAt time.Time
+	sources   Sources
+	now       func() time.Time
+	logger    *zap.Logger
+}
+
+// NewSummaryService returns a SummaryService reading from sources. A source
+// that fails is logged and its fields reported unavailable.
+func NewSummaryService(startedAt time.Time, sources Sources, logger *zap.Logger) SummaryService {
+	return &summaryService{startedAt: startedAt, sources: sources, now: time.Now, logger: logger}
+}
+
+func (s *summaryService) GetSummary(ctx context.Context) models.Summary {
+	now := s.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	summary := models.Summary{
+		MonitoringSince: s.startedAt.Format(time.RFC3339),
+		UptimeSeconds:   int(now.Sub(s.startedAt).Seconds()),
+		Unavailable:     []string{},
+	}
+	unavailable := func(fields ...string) {
+		summary.Unavailable = append(summary.Unavailable, fields...)
+	}
+
+	if stats, err := s.endpointStats(ctx, today); err != nil {
+		unavailable("totalEndpoints", "uptimeTodayPercent")
+	} else {
+		summary.TotalEndpoints = &stats.Total
+		if stats.Probes > 0 {
+			summary.UptimeTodayPercent = percent(stats.UpProbes, stats.Probes)
+		} else {
+			unavailable("uptimeTodayPercent")
+		}
+	}
+
+	if stats, err := s.latencyStats(ctx, today); err != nil || stats.Count == 0 {
+		unavailable("avgLatencyMs")
+	} else {
+		avg := round1(stats.AvgMs)
+		summary.AvgLatencyMs = &avg
+	}
+
+	if stats, err := s.runStats(ctx, today); err != nil {
+		unavailable("scheduledApis", "totalRunsToday", "successRateToday", "scheduledRunsToday")
+	} else {
+		summary.ScheduledAPIs = &stats.ScheduledAPIs
+		summary.TotalRunsToday = &stats.Total
+		summary.ScheduledRunsToday = &stats.Scheduled
+		if stats.Total > 0 {
+			summary.SuccessRateToday = percent(stats.Succeeded, stats.Total)
+		} else {
+			unavailable("successRateToday")
+		}
+	}
+
+	if stats, err := s.pipelineStats(ctx, now.Add(-pipelineWindow)); err != nil || stats.Total == 0 {
+		unavailable("pipelineSuccess")
+	} else {
+		summary.PipelineSuccess = percent(stats.Succeeded, stats.Total)
+	}
+
+	if stats, err := s.serverStats(ctx, today); err != nil {
+		unavailable("serversActive", "serverUptime")
+	} else {
+		active := fmt.Sprintf("%d/%d", stats.Active, stats.Total)
+		summary.ServersActive = &active
+		if stats.UptimePercent != nil {
+			uptime := round1(*stats.UptimePercent)
+			summary.ServerUptime = &uptime
+		} else {
+			unavailable("serverUptime")
+		}
+	}
+
+	return summary
+}
+
+var errNoSource = errors.New("no data source")
+
+func (s *summaryService) endpointStats(ctx context.Context, since time.Time) (EndpointStats, error) {
+	if s.sources.Endpoints == nil {
+		return EndpointStats{}, errNoSource
+	}
+	stats, err := s.sources.Endpoints(ctx, since)
+	s.logFailure("endpoints", err)
+	return stats, err
+}
+
+func (s *summaryService) latencyStats(ctx context.Context, since time.Time) (LatencyStats, error) {
+	if s.sources.Latency == nil {
+		return LatencyStats{}, errNoSource
+	}
+	stats, err := s.sources.Latency(ctx, since)
+	s.logFailure("latency", err)
+	return stats, err
+}
+
+func (s *summaryService) runStats(ctx context.Context, since time.Time) (RunStats, error) {
+	if s.sources.Runs == nil {
+		return RunStats{}, errNoSource
+	}
+	stats, err := s.sources.Runs(ctx, since)
+	s.logFailure("runs", err)
+	return stats, err
+}
+
+func (s *summaryService) pipelineStats(ctx context.Context, since time.Time) (PipelineStats, error) {
+	if s.sources.Pipelines == nil {
+		return PipelineStats{}, errNoSource
+	}
+	stats, err := s.sources.Pipelines(ctx, since)
+	s.logFailure("pipelines", err)
+	return stats, err
+}
+
+func (s *summaryService) serverStats(ctx context.Context, since time.Time) (ServerStats, error) {
+	if s.sources.Servers == nil {
+		return ServerStats{}, errNoSource
+	}
+	stats, err := s.sources.Servers(ctx, since)
+	s.logFailure("servers", err)
+	return stats, err
+}
+
+// logFailure records why a source's fields are unavailable. A missing
+// source is configuration, not a failure.
+func (s *summaryService) logFailure(source string, err error) {
+	if err != nil {
+		s.logger.Warn("summary source failed", zap.String("source", source), zap.Error(err))
+	}
+}
+
+func percent(part, total int) *float64 {
+	value := round1(float64(part) / float64(total) * 100)
+	return &value
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestGetSummary(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	uptime := 99.94
+
+	var endpointsSince, pipelinesSince time.Time
+	sources := Sources{
+		Endpoints: func(_ context.Context, since time.Time) (EndpointStats, error) {
+			endpointsSince = since
+			return EndpointStats{Total: 4, Probes: 3, UpProbes: 2}, nil
+		},
+		Latency: func(context.Context, time.Time) (LatencyStats, error) {
+			return LatencyStats{Count: 10, AvgMs: 12.34}, nil
+		},
+		Runs: func(context.Context, time.Time) (RunStats, error) {
+			return RunStats{ScheduledAPIs: 2}, nil
+		},
+		Pipelines: func(_ context.Context, since time.Time) (PipelineStats, error) {
+			pipelinesSince = since
+			return PipelineStats{Total: 8, Succeeded: 6}, nil
+		},
+		Servers: func(context.Context, time.Time) (ServerStats, error) {
+			return ServerStats{Active: 1, Total: 3, UptimePercent: &uptime}, nil
+		},
+	}
+	service := &summaryService{startedAt: now.Add(-time.Hour), sources: sources, now: func() time.Time { return now }, logger: zap.NewNop()}
+
+	summary := service.GetSummary(context.Background())
+	if !endpointsSince.Equal(today) || !pipelinesSince.Equal(now.Add(-pipelineWindow)) {
+		t.Errorf("windows start %v and %v, want %v and %v", endpointsSince, pipelinesSince, today, now.Add(-pipelineWindow))
+	}
+	if *summary.TotalEndpoints != 4 || *summary.UptimeTodayPercent != 66.7 || *summary.AvgLatencyMs != 12.3 {
+		t.Errorf("endpoint fields = %d, %v, %v", *summary.TotalEndpoints, *summary.UptimeTodayPercent, *summary.AvgLatencyMs)
+	}
+	if *summary.PipelineSuccess != 75 || *summary.ServersActive != "1/3" || *summary.ServerUptime != 99.9 {
+		t.Errorf("pipeline and server fields = %v, %q, %v", *summary.PipelineSuccess, *summary.ServersActive, *summary.ServerUptime)
+	}
+	// No runs yet today: the count is known, the rate is not.
+	if *summary.TotalRunsToday != 0 || summary.SuccessRateToday != nil {
+		t.Errorf("run fields = %d, %v", *summary.TotalRunsToday, summary.SuccessRateToday)
+	}
+	if want := []string{"successRateToday"}; !reflect.DeepEqual(summary.Unavailable, want) {
+		t.Errorf("Unavailable = %v, want %v", summary.Unavailable, want)
+	}
+	if summary.UptimeSeconds != 3600 {
+		t.Errorf("UptimeSeconds = %d, want 3600", summary.UptimeSeconds)
+	}
+}
+
+func TestGetSummaryLogsFailedSources(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	sources := Sources{
+		Endpoints: func(context.Context, time.Time) (EndpointStats, error) {
+			return EndpointStats{}, errors.New("connection refused")
+		},
+		// The other sources are not wired, which is not worth a warning.
+	}
+	service := NewSummaryService(time.Now(), sources, zap.New(core))
+
+	summary := service.GetSummary(context.Background())
+	want := []string{
+		"totalEndpoints", "uptimeTodayPercent", "avgLatencyMs",
+		"scheduledApis", "totalRunsToday", "successRateToday", "scheduledRunsToday",
+		"pipelineSuccess", "serversActive", "serverUptime",
+	}
+	if !reflect.DeepEqual(summary.Unavailable, want) {
+		t.Errorf("Unavailable = %v, want %v", summary.Unavailable, want)
+	}
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["source"] != "endpoints" || fields["error"] != "connection refused" {
+		t.Errorf("log fields = %v", fields)
+	}
+}
@@ -0,0 +1,140 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	alertmodels "github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+// Channel delivers notifications to one destination.
+type Channel interface {
+	Type() string
+	// Target names the destination in the delivery log without revealing
+	// credentials.
+	Target() string
+	// SendResolved reports whether resolved alerts are sent.
+	SendResolved() bool
+	Send(ctx context.Context, notification models.Notification) error
+}
+
+// PermanentError marks a failure that retrying cannot fix, such as a
+// rejected payload or bad credentials.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// IsPermanent reports whether err should not be retried.
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// FromReceiver builds the channels configured for a receiver.
+func FromReceiver(receiver models.Receiver, client *http.Client) []Channel {
+	var channels []Channel
+	for _, cfg := range receiver.SlackConfigs {
+		channels = append(channels, NewSlack(cfg, client))
+	}
+	for _, cfg := range receiver.PagerDutyConfigs {
+		channels = append(channels, NewPagerDuty(cfg, client))
+	}
+	for _, cfg := range receiver.EmailConfigs {
+		channels = append(channels, NewEmail(cfg))
+	}
+	for _, cfg := range receiver.WebhookConfigs {
+		channels = append(channels, NewWebhook(cfg, client))
+	}
+	return channels
+}
+
+// postJSON posts body as JSON. Rate limiting and server errors are
+// retryable; other non-2xx responses are permanent.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return err
+	}
+	return &PermanentError{Err: err}
+}
+
+// title summarises a notification, e.g. "[FIRING:2] KubernetesNodeNotReady".
+func title(notification models.Notification) string {
+	seen := map[string]bool{}
+	var names []string
+	for _, alert := range notification.Alerts {
+		name := alert.Labels[alertmodels.AlertNameLabel]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return fmt.Sprintf("[%s:%d] %s", strings.ToUpper(notification.Status), len(notification.Alerts), strings.Join(names, ", "))
+}
+
+// describe renders an alert as plain text: its summary or description,
+// then its labels.
+func describe(alert alertmodels.Alert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)", alert.Labels[alertmodels.AlertNameLabel], alert.State)
+	for _, name := range []string{"summary", "description", "runbook_url"} {
+		if text := alert.Annotations[name]; text != "" {
+			fmt.Fprintf(&b, "\n  %s: %s", name, text)
+		}
+	}
+	fmt.Fprintf(&b, "\n  labels: %s", formatLabels(alert.Labels))
+	return b.String()
+}
+
+func formatLabels(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func sendResolved(flag *bool) bool {
+	return flag == nil || *flag
+}
@@ -0,0 +1,322 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	alertmodels "github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+// receiver is a local HTTP endpoint that records the requests posted to it
+// and answers with status.
+type receiver struct {
+	*httptest.Server
+	status int
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	t.Helper()
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, req)
+		r.bodies = append(r.bodies, body)
+		status := r.status
+		r.mu.Unlock()
+		w.WriteHeader(status)
+		io.WriteString(w, http.StatusText(status))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+// request returns the i-th request received.
+func (r *receiver) request(t *testing.T, i int) *http.Request {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i >= len(r.requests) {
+		t.Fatalf("received %d requests, want at least %d", len(r.requests), i+1)
+	}
+	return r.requests[i]
+}
+
+func (r *receiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// decode unmarshals the i-th posted body into v.
+func (r *receiver) decode(t *testing.T, i int, v interface{}) {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i >= len(r.bodies) {
+		t.Fatalf("received %d requests, want at least %d", len(r.bodies), i+1)
+	}
+	if got := r.requests[i].Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if err := json.Unmarshal(r.bodies[i], v); err != nil {
+		t.Fatalf("decode %s: %v", r.bodies[i], err)
+	}
+}
+
+func testAlert(id, name, state string) alertmodels.Alert {
+	activeAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	alert := alertmodels.Alert{
+		ID:          id,
+		RuleID:      "rule-" + name,
+		RuleName:    name,
+		Fingerprint: "fp-" + id,
+		State:       state,
+		Severity:    alertmodels.SeverityCritical,
+		Labels:      map[string]string{alertmodels.AlertNameLabel: name, "namespace": "payments"},
+		Annotations: map[string]string{"summary": name + " is failing"},
+		Value:       3,
+		ActiveAt:    activeAt,
+	}
+	if state == alertmodels.StateResolved {
+		resolvedAt := activeAt.Add(time.Hour)
+		alert.ResolvedAt = &resolvedAt
+	}
+	return alert
+}
+
+func testNotification(alerts ...alertmodels.Alert) models.Notification {
+	status := models.StatusResolved
+	for _, alert := range alerts {
+		if alert.State != alertmodels.StateResolved {
+			status = models.StatusFiring
+		}
+	}
+	return models.Notification{
+		Receiver:          "team",
+		Status:            status,
+		Alerts:            alerts,
+		GroupLabels:       map[string]string{"namespace": "payments"},
+		CommonLabels:      map[string]string{"namespace": "payments"},
+		CommonAnnotations: map[string]string{},
+	}
+}
+
+func TestPostJSONStatus(t *testing.T) {
+	tests := []struct {
+		status        int
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusAccepted},
+		{status: http.StatusBadRequest, wantErr: true, wantPermanent: true},
+		{status: http.StatusUnauthorized, wantErr: true, wantPermanent: true},
+		{status: http.StatusNotFound, wantErr: true, wantPermanent: true},
+		{status: http.StatusTooManyRequests, wantErr: true},
+		{status: http.StatusInternalServerError, wantErr: true},
+		{status: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := newReceiver(t, tt.status)
+			err := postJSON(context.Background(), server.Client(), server.URL, map[string]string{"X-Test": "1"}, map[string]string{"a": "b"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("postJSON error = %v, want error %v", err, tt.wantErr)
+			}
+			if IsPermanent(err) != tt.wantPermanent {
+				t.Errorf("IsPermanent(%v) = %v, want %v", err, IsPermanent(err), tt.wantPermanent)
+			}
+			if err != nil && !strings.Contains(err.Error(), http.StatusText(tt.status)) {
+				t.Errorf("error %q does not quote the response body", err)
+			}
+			if got := server.request(t, 0).Header.Get("X-Test"); got != "1" {
+				t.Errorf("X-Test header = %q", got)
+			}
+		})
+	}
+
+	t.Run("unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+		err := postJSON(context.Background(), http.DefaultClient, url, nil, map[string]string{})
+		if err == nil || IsPermanent(err) {
+			t.Fatalf("postJSON error = %v, want a retryable error", err)
+		}
+	})
+}
+
+func TestSlack(t *testing.T) {
+	server := newReceiver(t, http.StatusOK)
+	channel := NewSlack(models.SlackConfig{APIURL: server.URL, Channel: "#alerts", Username: "devoptics"}, server.Client())
+	if channel.Type() != models.ChannelSlack || channel.Target() != "#alerts" || !channel.SendResolved() {
+		t.Errorf("Type, Target, SendResolved = %q, %q, %v", channel.Type(), channel.Target(), channel.SendResolved())
+	}
+
+	firing := testNotification(testAlert("a1", "HighErrorRate", alertmodels.StateFiring), testAlert("a2", "HighErrorRate", alertmodels.StateFiring))
+	resolved := testNotification(testAlert("a1", "HighErrorRate", alertmodels.StateResolved))
+	for _, notification := range []models.Notification{firing, resolved} {
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	var message slackMessage
+	server.decode(t, 0, &message)
+	if message.Channel != "#alerts" || message.Username != "devoptics" || message.Text != "[FIRING:2] HighErrorRate" {
+		t.Errorf("message = %+v", message)
+	}
+	if len(message.Attachments) != 1 || message.Attachments[0].Color != "danger" {
+		t.Fatalf("attachments = %+v", message.Attachments)
+	}
+	text := message.Attachments[0].Text
+	if !strings.Contains(text, "summary: HighErrorRate is failing") || !strings.Contains(text, `namespace="payments"`) {
+		t.Errorf("attachment text = %q", text)
+	}
+
+	server.decode(t, 1, &message)
+	if message.Text != "[RESOLVED:1] HighErrorRate" || message.Attachments[0].Color != "good" {
+		t.Errorf("resolved message = %+v", message)
+	}
+
+	if target := NewSlack(models.SlackConfig{APIURL: server.URL}, nil).Target(); target != "incoming webhook" {
+		t.Errorf("Target without channel = %q", target)
+	}
+}
+
+func TestPagerDuty(t *testing.T) {
+	server := newReceiver(t, http.StatusAccepted)
+	off := false
+	channel := NewPagerDuty(models.PagerDutyConfig{RoutingKey: "secret-key-1234", URL: server.URL, SendResolved: &off}, server.Client())
+	if channel.Type() != models.ChannelPagerDuty || channel.Target() != "routing key ...1234" || channel.SendResolved() {
+		t.Errorf("Type, Target, SendResolved = %q, %q, %v", channel.Type(), channel.Target(), channel.SendResolved())
+	}
+
+	warning := testAlert("a2", "DiskFilling", alertmodels.StateFiring)
+	warning.Severity = alertmodels.SeverityWarning
+	warning.Annotations["summary"] = strings.Repeat("x", 2000)
+	notification := testNotification(
+		testAlert("a1", "HighErrorRate", alertmodels.StateFiring),
+		warning,
+		testAlert("a3", "PodCrashLooping", alertmodels.StateResolved),
+	)
+	if err := channel.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	// One event per alert.
+	var events [3]pagerDutyEvent
+	for i := range events {
+		server.decode(t, i, &events[i])
+		if events[i].RoutingKey != "secret-key-1234" {
+			t.Errorf("event %d routing key = %q", i, events[i].RoutingKey)
+		}
+	}
+	if e := events[0]; e.EventAction != "trigger" || e.DedupKey != "rule-HighErrorRate/fp-a1" || e.Payload == nil {
+		t.Fatalf("first event = %+v", e)
+	}
+	if p := events[0].Payload; p.Summary != "HighErrorRate: HighErrorRate is failing" || p.Severity != "critical" || p.Source != "devoptics" {
+		t.Errorf("first payload = %+v", p)
+	}
+	if p := events[1].Payload; p == nil || len(p.Summary) != 1024 || !strings.HasSuffix(p.Summary, "...") || p.Severity != "warning" {
+		t.Errorf("second payload = %+v", p)
+	}
+	if e := events[2]; e.EventAction != "resolve" || e.DedupKey != "rule-PodCrashLooping/fp-a3" || e.Payload != nil {
+		t.Errorf("resolve event = %+v", e)
+	}
+
+	// A rejected event stops the batch and names the alert.
+	server.mu.Lock()
+	server.status = http.StatusBadRequest
+	server.mu.Unlock()
+	err := channel.Send(context.Background(), notification)
+	if err == nil || !IsPermanent(err) || !strings.Contains(err.Error(), "alert a1") {
+		t.Errorf("Send error = %v, want a permanent error for a1", err)
+	}
+	if server.count() != 4 {
+		t.Errorf("sent %d requests, want the batch to stop at the first failure", server.count())
+	}
+
+	if url := NewPagerDuty(models.PagerDutyConfig{RoutingKey: "k"}, nil).(*pagerDuty).cfg.URL; url != DefaultPagerDutyURL {
+		t.Errorf("default URL = %q", url)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	server := newReceiver(t, http.StatusOK)
+	channel := NewWebhook(models.WebhookConfig{URL: server.URL + "/hooks/alerts?token=secret", BearerToken: "t0ken", MaxAlerts: 2}, server.Client())
+	if channel.Type() != models.ChannelWebhook || channel.Target() != server.URL+"/hooks/alerts" {
+		t.Errorf("Type, Target = %q, %q", channel.Type(), channel.Target())
+	}
+
+	notification := testNotification(
+		testAlert("a1", "HighErrorRate", alertmodels.StateFiring),
+		testAlert("a2", "HighErrorRate", alertmodels.StateResolved),
+		testAlert("a3", "HighErrorRate", alertmodels.StateFiring),
+	)
+	if err := channel.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	request := server.request(t, 0)
+	if request.Header.Get("Authorization") != "Bearer t0ken" || request.URL.Query().Get("token") != "secret" {
+		t.Errorf("request %s with Authorization %q", request.URL, request.Header.Get("Authorization"))
+	}
+	var message webhookMessage
+	server.decode(t, 0, &message)
+	if message.Version != "4" || message.Receiver != "team" || message.Status != models.StatusFiring {
+		t.Errorf("message = %+v", message)
+	}
+	if message.GroupLabels["namespace"] != "payments" || message.CommonLabels["namespace"] != "payments" {
+		t.Errorf("group and common labels = %v, %v", message.GroupLabels, message.CommonLabels)
+	}
+	if len(message.Alerts) != 2 || message.TruncatedAlerts != 1 {
+		t.Fatalf("sent %d alerts with %d truncated, want 2 and 1", len(message.Alerts), message.TruncatedAlerts)
+	}
+	first, second := message.Alerts[0], message.Alerts[1]
+	if first.ID != "a1" || first.Status != alertmodels.StateFiring || first.Fingerprint != "fp-a1" || first.EndsAt != nil {
+		t.Errorf("first alert = %+v", first)
+	}
+	if second.Status != alertmodels.StateResolved || second.EndsAt == nil || !second.EndsAt.Equal(second.StartsAt.Add(time.Hour)) {
+		t.Errorf("resolved alert = %+v", second)
+	}
+
+	// Without a token no Authorization header is sent.
+	plain := NewWebhook(models.WebhookConfig{URL: server.URL}, server.Client())
+	if err := plain.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := server.request(t, 1).Header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none", got)
+	}
+}
+
+func TestFromReceiver(t *testing.T) {
+	receiver := models.Receiver{
+		Name:             "everything",
+		SlackConfigs:     []models.SlackConfig{{APIURL: "https://hooks.example.com"}},
+		PagerDutyConfigs: []models.PagerDutyConfig{{RoutingKey: "k"}},
+		EmailConfigs:     []models.EmailConfig{{To: []string{"ops@example.com"}, Smarthost: "smtp.example.com:587"}},
+		WebhookConfigs:   []models.WebhookConfig{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}},
+	}
+	var types []string
+	for _, channel := range FromReceiver(receiver, http.DefaultClient) {
+		types = append(types, channel.Type())
+	}
+	if got := strings.Join(types, ","); got != "slack,pagerduty,email,webhook,webhook" {
+		t.Errorf("channel types = %s", got)
+	}
+}
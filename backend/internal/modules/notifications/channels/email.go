@@ -0,0 +1,110 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+type email struct {
+	cfg models.EmailConfig
+}
+
+// NewEmail returns a Channel that sends a plain-text message through an
+// SMTP smarthost.
+func NewEmail(cfg models.EmailConfig) Channel {
+	return &email{cfg: cfg}
+}
+
+func (e *email) Type() string { return models.ChannelEmail }
+
+func (e *email) Target() string { return strings.Join(e.cfg.To, ", ") }
+
+func (e *email) SendResolved() bool { return sendResolved(e.cfg.SendResolved) }
+
+func (e *email) Send(ctx context.Context, notification models.Notification) error {
+	host, _, err := net.SplitHostPort(e.cfg.Smarthost)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("invalid smarthost: %w", err)}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.cfg.Smarthost)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	} else if e.cfg.RequireTLS == nil || *e.cfg.RequireTLS {
+		return &PermanentError{Err: errors.New("smarthost does not support STARTTLS and require_tls is set")}
+	}
+
+	if e.cfg.AuthUsername != "" {
+		auth := smtp.PlainAuth("", e.cfg.AuthUsername, e.cfg.AuthPassword, host)
+		if err := client.Auth(auth); err != nil {
+			return &PermanentError{Err: fmt.Errorf("smtp auth: %w", err)}
+		}
+	}
+
+	if err := client.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range e.cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(e.message(notification)); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (e *email) message(notification models.Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(title(notification)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	for i, alert := range notification.Alerts {
+		if i > 0 {
+			b.WriteString("\r\n\r\n")
+		}
+		b.WriteString(strings.ReplaceAll(describe(alert), "\n", "\r\n"))
+	}
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,254 @@
+package channels
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	alertmodels "github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+// smtpServer is a local SMTP stand-in that accepts mail without TLS and
+// records what it receives. With startTLS it advertises STARTTLS but
+// refuses to negotiate it.
+type smtpServer struct {
+	addr     string
+	startTLS bool
+	username string
+	password string
+	// reject is a recipient answered with a 550.
+	reject string
+
+	mu       sync.Mutex
+	commands []string
+	from     string
+	to       []string
+	data     string
+}
+
+func newSMTPServer(t *testing.T, configure func(*smtpServer)) *smtpServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{addr: listener.Addr().String()}
+	if configure != nil {
+		configure(s)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP stand-in")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+		s.mu.Lock()
+		s.commands = append(s.commands, verb)
+		s.mu.Unlock()
+
+		switch verb {
+		case "EHLO", "HELO":
+			text.PrintfLine("250-localhost")
+			if s.startTLS {
+				text.PrintfLine("250-STARTTLS")
+			}
+			text.PrintfLine("250 AUTH PLAIN")
+		case "STARTTLS":
+			text.PrintfLine("454 TLS not available")
+		case "AUTH":
+			_, encoded, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(encoded)
+			if string(decoded) == "\x00"+s.username+"\x00"+s.password {
+				text.PrintfLine("235 authenticated")
+			} else {
+				text.PrintfLine("535 authentication failed")
+			}
+		case "MAIL":
+			s.mu.Lock()
+			s.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			s.mu.Unlock()
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			to := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if to == s.reject {
+				text.PrintfLine("550 no such user")
+				continue
+			}
+			s.mu.Lock()
+			s.to = append(s.to, to)
+			s.mu.Unlock()
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := readData(text.Reader.R)
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = data
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+// readData reads a message body up to the terminating "." line, keeping
+// CRLF line endings.
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+func (s *smtpServer) received() (commands []string, from string, to []string, data string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...), s.from, append([]string(nil), s.to...), s.data
+}
+
+func TestEmail(t *testing.T) {
+	off := false
+	server := newSMTPServer(t, func(s *smtpServer) {
+		s.username = "devoptics"
+		s.password = "hunter2"
+	})
+	channel := NewEmail(models.EmailConfig{
+		To:           []string{"oncall@example.com", "payments@example.com"},
+		From:         "devoptics@example.com",
+		Smarthost:    server.addr,
+		AuthUsername: "devoptics",
+		AuthPassword: "hunter2",
+		RequireTLS:   &off,
+	})
+	if channel.Type() != models.ChannelEmail || channel.Target() != "oncall@example.com, payments@example.com" {
+		t.Errorf("Type, Target = %q, %q", channel.Type(), channel.Target())
+	}
+
+	notification := testNotification(
+		testAlert("a1", "HighErrorRate", alertmodels.StateFiring),
+		testAlert("a2", "HighErrorRate", alertmodels.StateFiring),
+	)
+	if err := channel.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	commands, from, to, data := server.received()
+	if got := strings.Join(commands, " "); got != "EHLO AUTH MAIL RCPT RCPT DATA QUIT" {
+		t.Errorf("commands = %s", got)
+	}
+	if from != "devoptics@example.com" || strings.Join(to, ",") != "oncall@example.com,payments@example.com" {
+		t.Errorf("envelope from %q to %v", from, to)
+	}
+	for _, want := range []string{
+		"From: devoptics@example.com\r\n",
+		"To: oncall@example.com, payments@example.com\r\n",
+		"Subject: [FIRING:2] HighErrorRate\r\n",
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\n",
+		"HighErrorRate (firing)\r\n  summary: HighErrorRate is failing\r\n",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestEmailFailures(t *testing.T) {
+	off := false
+	config := func(smarthost string) models.EmailConfig {
+		return models.EmailConfig{To: []string{"oncall@example.com"}, From: "devoptics@example.com", Smarthost: smarthost, RequireTLS: &off}
+	}
+	send := func(cfg models.EmailConfig) error {
+		return NewEmail(cfg).Send(context.Background(), testNotification(testAlert("a1", "HighErrorRate", alertmodels.StateFiring)))
+	}
+
+	t.Run("require_tls without STARTTLS", func(t *testing.T) {
+		server := newSMTPServer(t, nil)
+		cfg := config(server.addr)
+		cfg.RequireTLS = nil
+		err := send(cfg)
+		if !IsPermanent(err) || !strings.Contains(err.Error(), "STARTTLS") {
+			t.Fatalf("Send error = %v, want a permanent STARTTLS error", err)
+		}
+		if commands, _, _, _ := server.received(); strings.Contains(strings.Join(commands, " "), "MAIL") {
+			t.Errorf("sent mail in the clear: %v", commands)
+		}
+	})
+	t.Run("STARTTLS offered", func(t *testing.T) {
+		// STARTTLS is used whenever it is offered, even without require_tls.
+		server := newSMTPServer(t, func(s *smtpServer) { s.startTLS = true })
+		err := send(config(server.addr))
+		if err == nil || IsPermanent(err) {
+			t.Fatalf("Send error = %v, want a retryable TLS error", err)
+		}
+		if commands, _, _, _ := server.received(); strings.Join(commands, " ") != "EHLO STARTTLS" {
+			t.Errorf("commands = %v", commands)
+		}
+	})
+	t.Run("bad credentials", func(t *testing.T) {
+		server := newSMTPServer(t, func(s *smtpServer) { s.username, s.password = "devoptics", "hunter2" })
+		cfg := config(server.addr)
+		cfg.AuthUsername, cfg.AuthPassword = "devoptics", "wrong"
+		if err := send(cfg); !IsPermanent(err) || !strings.Contains(err.Error(), "smtp auth") {
+			t.Fatalf("Send error = %v, want a permanent auth error", err)
+		}
+	})
+	t.Run("rejected recipient", func(t *testing.T) {
+		server := newSMTPServer(t, func(s *smtpServer) { s.reject = "oncall@example.com" })
+		if err := send(config(server.addr)); err == nil || !strings.Contains(err.Error(), "550") {
+			t.Fatalf("Send error = %v, want the 550", err)
+		}
+	})
+	t.Run("invalid smarthost", func(t *testing.T) {
+		if err := send(config("smtp.example.com")); !IsPermanent(err) {
+			t.Fatalf("Send error = %v, want a permanent error", err)
+		}
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := listener.Addr().String()
+		listener.Close()
+		if err := send(config(addr)); err == nil || IsPermanent(err) {
+			t.Fatalf("Send error = %v, want a retryable error", err)
+		}
+	})
+}
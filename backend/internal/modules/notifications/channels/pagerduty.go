@@ -0,0 +1,106 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	alertmodels "github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+// DefaultPagerDutyURL is the PagerDuty Events API v2 endpoint.
+const DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+type pagerDuty struct {
+	cfg    models.PagerDutyConfig
+	client *http.Client
+}
+
+// NewPagerDuty returns a Channel that sends one Events API v2 event per
+// alert, deduplicated by the alert's fingerprint so a resolve closes the
+// incident its trigger opened.
+func NewPagerDuty(cfg models.PagerDutyConfig, client *http.Client) Channel {
+	if cfg.URL == "" {
+		cfg.URL = DefaultPagerDutyURL
+	}
+	return &pagerDuty{cfg: cfg, client: client}
+}
+
+func (p *pagerDuty) Type() string { return models.ChannelPagerDuty }
+
+func (p *pagerDuty) Target() string {
+	key := p.cfg.RoutingKey
+	if len(key) > 4 {
+		key = key[len(key)-4:]
+	}
+	return "routing key ..." + key
+}
+
+func (p *pagerDuty) SendResolved() bool { return sendResolved(p.cfg.SendResolved) }
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	CustomDetails map[string]interface{} `json:"custom_details"`
+}
+
+func (p *pagerDuty) Send(ctx context.Context, notification models.Notification) error {
+	for _, alert := range notification.Alerts {
+		event := pagerDutyEvent{
+			RoutingKey:  p.cfg.RoutingKey,
+			EventAction: "trigger",
+			DedupKey:    alert.RuleID + "/" + alert.Fingerprint,
+		}
+		if alert.State == alertmodels.StateResolved {
+			event.EventAction = "resolve"
+		} else {
+			event.Payload = &pagerDutyPayload{
+				Summary:  pagerDutySummary(alert),
+				Source:   "devoptics",
+				Severity: pagerDutySeverity(alert.Severity),
+				CustomDetails: map[string]interface{}{
+					"labels":      alert.Labels,
+					"annotations": alert.Annotations,
+					"value":       alert.Value,
+				},
+			}
+		}
+		if err := postJSON(ctx, p.client, p.cfg.URL, nil, event); err != nil {
+			return fmt.Errorf("alert %s: %w", alert.ID, err)
+		}
+	}
+	return nil
+}
+
+func pagerDutySummary(alert alertmodels.Alert) string {
+	summary := alert.Labels[alertmodels.AlertNameLabel]
+	if text := alert.Annotations["summary"]; text != "" {
+		summary += ": " + text
+	}
+	// PagerDuty rejects summaries over 1024 characters.
+	if len(summary) > 1024 {
+		summary = summary[:1021] + "..."
+	}
+	return summary
+}
+
+// pagerDutySeverity maps alert severities onto the four PagerDuty accepts.
+func pagerDutySeverity(severity string) string {
+	switch severity {
+	case alertmodels.SeverityCritical:
+		return "critical"
+	case alertmodels.SeverityInfo:
+		return "info"
+	default:
+		return "warning"
+	}
+}
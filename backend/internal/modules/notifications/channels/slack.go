@@ -0,0 +1,60 @@
+package channels
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+type slack struct {
+	cfg    models.SlackConfig
+	client *http.Client
+}
+
+// NewSlack returns a Channel that posts to a Slack incoming webhook.
+func NewSlack(cfg models.SlackConfig, client *http.Client) Channel {
+	return &slack{cfg: cfg, client: client}
+}
+
+func (s *slack) Type() string { return models.ChannelSlack }
+
+func (s *slack) Target() string {
+	if s.cfg.Channel != "" {
+		return s.cfg.Channel
+	}
+	return "incoming webhook"
+}
+
+func (s *slack) SendResolved() bool { return sendResolved(s.cfg.SendResolved) }
+
+type slackMessage struct {
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color string `json:"color"`
+	Text  string `json:"text"`
+}
+
+func (s *slack) Send(ctx context.Context, notification models.Notification) error {
+	color := "good"
+	if notification.Status == models.StatusFiring {
+		color = "danger"
+	}
+	lines := make([]string, 0, len(notification.Alerts))
+	for _, alert := range notification.Alerts {
+		lines = append(lines, describe(alert))
+	}
+
+	return postJSON(ctx, s.client, s.cfg.APIURL, nil, slackMessage{
+		Channel:     s.cfg.Channel,
+		Username:    s.cfg.Username,
+		Text:        title(notification),
+		Attachments: []slackAttachment{{Color: color, Text: strings.Join(lines, "\n\n")}},
+	})
+}
@@ -0,0 +1,92 @@
+package channels
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+type webhook struct {
+	cfg    models.WebhookConfig
+	client *http.Client
+}
+
+// NewWebhook returns a Channel that posts notifications as JSON, in the
+// shape of an Alertmanager webhook.
+func NewWebhook(cfg models.WebhookConfig, client *http.Client) Channel {
+	return &webhook{cfg: cfg, client: client}
+}
+
+func (w *webhook) Type() string { return models.ChannelWebhook }
+
+// Target is the URL without its query string, which may carry a token.
+func (w *webhook) Target() string {
+	parsed, err := url.Parse(w.cfg.URL)
+	if err != nil {
+		return "webhook"
+	}
+	return parsed.Scheme + "://" + parsed.Host + parsed.Path
+}
+
+func (w *webhook) SendResolved() bool { return sendResolved(w.cfg.SendResolved) }
+
+type webhookMessage struct {
+	Version           string            `json:"version"`
+	Receiver          string            `json:"receiver"`
+	Status            string            `json:"status"`
+	Alerts            []webhookAlert    `json:"alerts"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+}
+
+type webhookAlert struct {
+	ID          string            `json:"id"`
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      *time.Time        `json:"endsAt,omitempty"`
+	Fingerprint string            `json:"fingerprint"`
+}
+
+func (w *webhook) Send(ctx context.Context, notification models.Notification) error {
+	alerts := notification.Alerts
+	truncated := 0
+	if w.cfg.MaxAlerts > 0 && len(alerts) > w.cfg.MaxAlerts {
+		truncated = len(alerts) - w.cfg.MaxAlerts
+		alerts = alerts[:w.cfg.MaxAlerts]
+	}
+
+	message := webhookMessage{
+		Version:           "4",
+		Receiver:          notification.Receiver,
+		Status:            notification.Status,
+		Alerts:            make([]webhookAlert, 0, len(alerts)),
+		GroupLabels:       notification.GroupLabels,
+		CommonLabels:      notification.CommonLabels,
+		CommonAnnotations: notification.CommonAnnotations,
+		TruncatedAlerts:   truncated,
+	}
+	for _, alert := range alerts {
+		message.Alerts = append(message.Alerts, webhookAlert{
+			ID:          alert.ID,
+			Status:      alert.State,
+			Labels:      alert.Labels,
+			Annotations: alert.Annotations,
+			StartsAt:    alert.ActiveAt,
+			EndsAt:      alert.ResolvedAt,
+			Fingerprint: alert.Fingerprint,
+		})
+	}
+
+	var headers map[string]string
+	if w.cfg.BearerToken != "" {
+		headers = map[string]string{"Authorization": "Bearer " + w.cfg.BearerToken}
+	}
+	return postJSON(ctx, w.client, w.cfg.URL, headers, message)
+}
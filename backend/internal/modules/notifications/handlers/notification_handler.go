@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/services"
+)
+
+type NotificationHandler struct {
+	service services.NotificationService
+}
+
+func NewNotificationHandler(service services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// ListReceivers returns the configured receivers and their channel types.
+func (h *NotificationHandler) ListReceivers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"receivers": h.service.ListReceivers()})
+}
+
+// ListDeliveries returns the delivery log newest first, optionally filtered
+// by receiver and outcome (success or failed).
+func (h *NotificationHandler) ListDeliveries(c *gin.Context) {
+	filter := repositories.DeliveryFilter{Receiver: c.Query("receiver"), Outcome: c.Query("outcome")}
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	deliveries, err := h.service.ListDeliveries(filter)
+	if err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// TestReceiver sends a test alert to a receiver and returns the deliveries.
+func (h *NotificationHandler) TestReceiver(c *gin.Context) {
+	deliveries, err := h.service.TestReceiver(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		writeNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func writeNotificationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrReceiverNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidFilter):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,73 @@
+package models
+
+// Config is the notification routing file: a tree of routes that match
+// alert labels to named receivers, in the style of an Alertmanager config.
+type Config struct {
+	Route     Route      `yaml:"route" json:"route"`
+	Receivers []Receiver `yaml:"receivers" json:"receivers"`
+}
+
+// Route sends alerts whose labels match to Receiver. An alert is passed to
+// the first matching child route, or handled by this route if no child
+// matches. Continue lets later siblings match as well. The root route
+// matches every alert.
+//
+// Alerts routed to the same receiver are batched into one notification
+// per distinct set of GroupBy label values; "..." groups by every label.
+// Children without a receiver or group_by inherit their parent's.
+type Route struct {
+	Receiver string            `yaml:"receiver" json:"receiver"`
+	GroupBy  []string          `yaml:"group_by" json:"groupBy,omitempty"`
+	Match    map[string]string `yaml:"match" json:"match,omitempty"`
+	// MatchRE values are anchored regular expressions.
+	MatchRE  map[string]string `yaml:"match_re" json:"matchRe,omitempty"`
+	Continue bool              `yaml:"continue" json:"continue,omitempty"`
+	Routes   []Route           `yaml:"routes" json:"routes,omitempty"`
+}
+
+// Receiver is a named set of channels that all get every notification
+// routed to it.
+type Receiver struct {
+	Name             string            `yaml:"name" json:"name"`
+	SlackConfigs     []SlackConfig     `yaml:"slack_configs" json:"-"`
+	PagerDutyConfigs []PagerDutyConfig `yaml:"pagerduty_configs" json:"-"`
+	EmailConfigs     []EmailConfig     `yaml:"email_configs" json:"-"`
+	WebhookConfigs   []WebhookConfig   `yaml:"webhook_configs" json:"-"`
+}
+
+// SlackConfig posts to a Slack incoming webhook.
+type SlackConfig struct {
+	APIURL       string `yaml:"api_url"`
+	Channel      string `yaml:"channel"`
+	Username     string `yaml:"username"`
+	SendResolved *bool  `yaml:"send_resolved"`
+}
+
+// PagerDutyConfig sends PagerDuty Events API v2 events. URL defaults to
+// the public events endpoint.
+type PagerDutyConfig struct {
+	RoutingKey   string `yaml:"routing_key"`
+	URL          string `yaml:"url"`
+	SendResolved *bool  `yaml:"send_resolved"`
+}
+
+// EmailConfig sends mail through an SMTP smarthost (host:port). With
+// RequireTLS, which defaults to true, delivery fails unless the server
+// offers STARTTLS.
+type EmailConfig struct {
+	To           []string `yaml:"to"`
+	From         string   `yaml:"from"`
+	Smarthost    string   `yaml:"smarthost"`
+	AuthUsername string   `yaml:"auth_username"`
+	AuthPassword string   `yaml:"auth_password"`
+	RequireTLS   *bool    `yaml:"require_tls"`
+	SendResolved *bool    `yaml:"send_resolved"`
+}
+
+// WebhookConfig posts the notification as JSON to URL.
+type WebhookConfig struct {
+	URL          string `yaml:"url"`
+	BearerToken  string `yaml:"bearer_token"`
+	MaxAlerts    int    `yaml:"max_alerts"`
+	SendResolved *bool  `yaml:"send_resolved"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	alertmodels "github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+)
+
+// Notification statuses. A notification is firing if any of its alerts is.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Channel types.
+const (
+	ChannelSlack     = "slack"
+	ChannelPagerDuty = "pagerduty"
+	ChannelEmail     = "email"
+	ChannelWebhook   = "webhook"
+)
+
+// Delivery outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+)
+
+// Notification is a batch of alerts sent to one receiver.
+type Notification struct {
+	Receiver          string
+	Status            string
+	Alerts            []alertmodels.Alert
+	GroupLabels       map[string]string
+	CommonLabels      map[string]string
+	CommonAnnotations map[string]string
+}
+
+// Delivery records sending one notification through one channel.
+type Delivery struct {
+	ID         int64     `json:"id"`
+	Receiver   string    `json:"receiver"`
+	Channel    string    `json:"channel"`
+	Target     string    `json:"target"`
+	Status     string    `json:"status"`
+	AlertIDs   []string  `json:"alertIds"`
+	Outcome    string    `json:"outcome"`
+	Attempts   int       `json:"attempts"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// ReceiverSummary describes a receiver without exposing channel secrets.
+type ReceiverSummary struct {
+	Name     string   `json:"name"`
+	Channels []string `json:"channels"`
+}
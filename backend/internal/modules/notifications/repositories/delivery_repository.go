@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+// DeliveryFilter narrows a delivery log listing. Empty fields match every
+// delivery.
+type DeliveryFilter struct {
+	Receiver string
+	Outcome  string
+	Limit    int
+}
+
+// DeliveryRepository persists the notification delivery log.
+type DeliveryRepository interface {
+	Create(delivery models.Delivery) (models.Delivery, error)
+	List(filter DeliveryFilter) ([]models.Delivery, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+type deliveryRepository struct {
+	db *sql.DB
+}
+
+func NewDeliveryRepository(db *sql.DB) DeliveryRepository {
+	return &deliveryRepository{db: db}
+}
+
+const deliveryColumns = `id, receiver, channel, target, status, alert_ids, outcome, attempts, error,
+	created_at, finished_at`
+
+func (r *deliveryRepository) Create(delivery models.Delivery) (models.Delivery, error) {
+	alertIDs, err := json.Marshal(delivery.AlertIDs)
+	if err != nil {
+		return models.Delivery{}, err
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO notification_deliveries (receiver, channel, target, status, alert_ids, outcome,
+		   attempts, error, created_at, finished_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		 RETURNING `+deliveryColumns,
+		delivery.Receiver, delivery.Channel, delivery.Target, delivery.Status, alertIDs, delivery.Outcome,
+		delivery.Attempts, delivery.Error, delivery.CreatedAt, delivery.FinishedAt,
+	)
+	return scanDelivery(row)
+}
+
+// List returns deliveries newest first.
+func (r *deliveryRepository) List(filter DeliveryFilter) ([]models.Delivery, error) {
+	rows, err := r.db.Query(
+		`SELECT `+deliveryColumns+` FROM notification_deliveries
+		 WHERE ($1 = '' OR receiver = $1) AND ($2 = '' OR outcome = $2)
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $3`,
+		filter.Receiver, filter.Outcome, filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.Delivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *deliveryRepository) DeleteBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM notification_deliveries WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDelivery(row rowScanner) (models.Delivery, error) {
+	var delivery models.Delivery
+	var alertIDs []byte
+	if err := row.Scan(
+		&delivery.ID, &delivery.Receiver, &delivery.Channel, &delivery.Target, &delivery.Status, &alertIDs,
+		&delivery.Outcome, &delivery.Attempts, &delivery.Error, &delivery.CreatedAt, &delivery.FinishedAt,
+	); err != nil {
+		return models.Delivery{}, err
+	}
+	if err := json.Unmarshal(alertIDs, &delivery.AlertIDs); err != nil {
+		return models.Delivery{}, err
+	}
+	return delivery, nil
+}
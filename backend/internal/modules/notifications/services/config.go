@@ -0,0 +1,155 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+var ErrInvalidConfig = errors.New("invalid notification config")
+
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// LoadConfig reads and validates a routing file. Unknown fields are
+// rejected so a misspelt key does not silently drop a route.
+func LoadConfig(path string) (models.Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return models.Config{}, err
+	}
+
+	var cfg models.Config
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return models.Config{}, fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		return models.Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ValidateConfig checks that receivers are named uniquely, that every route
+// names a known receiver (or inherits one) and that every channel has the
+// fields it needs. An empty config, which routes nothing, is valid.
+func ValidateConfig(cfg models.Config) error {
+	receivers := map[string]bool{}
+	for _, receiver := range cfg.Receivers {
+		if receiver.Name == "" {
+			return fmt.Errorf("%w: receiver without a name", ErrInvalidConfig)
+		}
+		if receivers[receiver.Name] {
+			return fmt.Errorf("%w: duplicate receiver %q", ErrInvalidConfig, receiver.Name)
+		}
+		receivers[receiver.Name] = true
+		if err := validateReceiver(receiver); err != nil {
+			return fmt.Errorf("%w: receiver %q: %v", ErrInvalidConfig, receiver.Name, err)
+		}
+	}
+
+	if len(cfg.Receivers) == 0 && cfg.Route.Receiver == "" && len(cfg.Route.Routes) == 0 {
+		return nil
+	}
+	if cfg.Route.Receiver == "" {
+		return fmt.Errorf("%w: the root route needs a receiver", ErrInvalidConfig)
+	}
+	if len(cfg.Route.Match) > 0 || len(cfg.Route.MatchRE) > 0 {
+		return fmt.Errorf("%w: the root route matches every alert and cannot have matchers", ErrInvalidConfig)
+	}
+	if err := validateRoute(cfg.Route, receivers); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+func validateRoute(route models.Route, receivers map[string]bool) error {
+	if route.Receiver != "" && !receivers[route.Receiver] {
+		return fmt.Errorf("route refers to unknown receiver %q", route.Receiver)
+	}
+	for _, name := range route.GroupBy {
+		if name == "..." && len(route.GroupBy) == 1 {
+			continue
+		}
+		if !labelNamePattern.MatchString(name) {
+			return fmt.Errorf("group_by: invalid label name %q", name)
+		}
+	}
+	for name, pattern := range route.MatchRE {
+		if _, err := regexp.Compile(anchor(pattern)); err != nil {
+			return fmt.Errorf("match_re %s: %v", name, err)
+		}
+	}
+	for _, child := range route.Routes {
+		if err := validateRoute(child, receivers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateReceiver(receiver models.Receiver) error {
+	for _, cfg := range receiver.SlackConfigs {
+		if err := validateURL(cfg.APIURL); err != nil {
+			return fmt.Errorf("slack api_url: %v", err)
+		}
+	}
+	for _, cfg := range receiver.PagerDutyConfigs {
+		if cfg.RoutingKey == "" {
+			return errors.New("pagerduty routing_key is required")
+		}
+		if cfg.URL != "" {
+			if err := validateURL(cfg.URL); err != nil {
+				return fmt.Errorf("pagerduty url: %v", err)
+			}
+		}
+	}
+	for _, cfg := range receiver.EmailConfigs {
+		if len(cfg.To) == 0 {
+			return errors.New("email to is required")
+		}
+		for _, address := range append([]string{cfg.From}, cfg.To...) {
+			if _, err := mail.ParseAddress(address); err != nil {
+				return fmt.Errorf("email address %q: %v", address, err)
+			}
+		}
+		if cfg.Smarthost == "" {
+			return errors.New("email smarthost is required")
+		}
+	}
+	for _, cfg := range receiver.WebhookConfigs {
+		if err := validateURL(cfg.URL); err != nil {
+			return fmt.Errorf("webhook url: %v", err)
+		}
+		if cfg.MaxAlerts < 0 {
+			return errors.New("webhook max_alerts cannot be negative")
+		}
+	}
+	return nil
+}
+
+func validateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// anchor makes a match_re pattern match whole label values, as in
+// Alertmanager.
+func anchor(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
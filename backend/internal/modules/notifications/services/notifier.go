@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	alertmodels "github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	alertingservices "github.com/fbisdevoptics/backend/internal/modules/alerting/services"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/channels"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/repositories"
+)
+
+var (
+	ErrReceiverNotFound = errors.New("receiver not found")
+	ErrInvalidFilter    = errors.New("invalid filter")
+)
+
+const (
+	DefaultDeliveryLimit = 100
+	MaxDeliveryLimit     = 1000
+)
+
+// NotificationService exposes the configured receivers and the delivery
+// log.
+type NotificationService interface {
+	ListReceivers() []models.ReceiverSummary
+	ListDeliveries(filter repositories.DeliveryFilter) ([]models.Delivery, error)
+	// TestReceiver sends a synthetic firing alert to every channel of a
+	// receiver and waits for the deliveries to finish.
+	TestReceiver(ctx context.Context, name string) ([]models.Delivery, error)
+}
+
+// NotifierOptions tunes delivery. Zero values fall back to defaults.
+type NotifierOptions struct {
+	// MaxAttempts bounds how often a delivery is tried, including the
+	// first attempt.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry; it doubles with
+	// every retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout bounds each attempt.
+	Timeout time.Duration
+	// Retention is how long the delivery log is kept; zero keeps it
+	// forever.
+	Retention time.Duration
+	// Client sends the HTTP-based channels.
+	Client *http.Client
+}
+
+// Notifier routes alerts to receivers and delivers them through each
+// receiver's channels, retrying transient failures with backoff and
+// recording every delivery.
+type Notifier struct {
+	router    *Router
+	receivers map[string][]channels.Channel
+	summaries []models.ReceiverSummary
+	repo      repositories.DeliveryRepository
+	opts      NotifierOptions
+	logger    *zap.Logger
+	inflight  sync.WaitGroup
+}
+
+// NewNotifier returns a Notifier for cfg. A zero cfg routes nothing.
+func NewNotifier(cfg models.Config, repo repositories.DeliveryRepository, opts NotifierOptions, logger *zap.Logger) (*Notifier, error) {
+	router, err := NewRouter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{}
+	}
+
+	n := &Notifier{
+		router:    router,
+		receivers: make(map[string][]channels.Channel, len(cfg.Receivers)),
+		summaries: make([]models.ReceiverSummary, 0, len(cfg.Receivers)),
+		repo:      repo,
+		opts:      opts,
+		logger:    logger,
+	}
+	for _, receiver := range cfg.Receivers {
+		built := channels.FromReceiver(receiver, opts.Client)
+		n.receivers[receiver.Name] = built
+		summary := models.ReceiverSummary{Name: receiver.Name, Channels: make([]string, 0, len(built))}
+		for _, channel := range built {
+			summary.Channels = append(summary.Channels, channel.Type())
+		}
+		n.summaries = append(n.summaries, summary)
+	}
+	return n, nil
+}
+
+// Notify routes alerts that started firing or resolved and delivers them
+// in the background, one notification per receiver and group. Deliveries
+// still retrying when ctx is cancelled are recorded as failed.
+func (n *Notifier) Notify(ctx context.Context, alerts []alertmodels.Alert) {
+	for _, notification := range n.route(alerts) {
+		for _, channel := range n.receivers[notification.Receiver] {
+			filtered, ok := forChannel(notification, channel)
+			if !ok {
+				continue
+			}
+			n.inflight.Add(1)
+			go func(channel channels.Channel, notification models.Notification) {
+				defer n.inflight.Done()
+				n.deliver(ctx, channel, notification)
+			}(channel, filtered)
+		}
+	}
+}
+
+// Wait blocks until every delivery started by Notify has finished.
+func (n *Notifier) Wait() {
+	n.inflight.Wait()
+}
+
+// Run prunes the delivery log hourly until ctx is cancelled. Every replica
+// may prune; the delete is idempotent.
+func (n *Notifier) Run(ctx context.Context) {
+	if n.opts.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		removed, err := n.repo.DeleteBefore(time.Now().Add(-n.opts.Retention))
+		if err != nil {
+			n.logger.Warn("failed to prune notification deliveries", zap.Error(err))
+		} else if removed > 0 {
+			n.logger.Info("pruned notification deliveries", zap.Int64("removed", removed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *Notifier) ListReceivers() []models.ReceiverSummary {
+	return n.summaries
+}
+
+func (n *Notifier) ListDeliveries(filter repositories.DeliveryFilter) ([]models.Delivery, error) {
+	switch filter.Outcome {
+	case "", models.OutcomeSuccess, models.OutcomeFailed:
+	default:
+		return nil, fmt.Errorf("%w: unknown outcome %q", ErrInvalidFilter, filter.Outcome)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultDeliveryLimit
+	}
+	if filter.Limit > MaxDeliveryLimit {
+		filter.Limit = MaxDeliveryLimit
+	}
+	return n.repo.List(filter)
+}
+
+func (n *Notifier) TestReceiver(ctx context.Context, name string) ([]models.Delivery, error) {
+	built, ok := n.receivers[name]
+	if !ok {
+		return nil, ErrReceiverNotFound
+	}
+
+	now := time.Now()
+	alert := alertmodels.Alert{
+		ID:          "test",
+		RuleName:    "DevOpticsTestNotification",
+		Fingerprint: "test",
+		State:       alertmodels.StateFiring,
+		Severity:    alertmodels.SeverityInfo,
+		Priority:    alertmodels.Priority(alertmodels.SeverityInfo),
+		Labels: map[string]string{
+			alertmodels.AlertNameLabel: "DevOpticsTestNotification",
+			alertmodels.SeverityLabel:  alertmodels.SeverityInfo,
+		},
+		Annotations:     map[string]string{"summary": "Test notification for receiver " + name},
+		ActiveAt:        now,
+		FiredAt:         &now,
+		LastEvaluatedAt: now,
+	}
+	notification := newNotification(name, map[string]string{}, []alertmodels.Alert{alert})
+
+	deliveries := make([]models.Delivery, len(built))
+	var wg sync.WaitGroup
+	for i, channel := range built {
+		wg.Add(1)
+		go func(i int, channel channels.Channel) {
+			defer wg.Done()
+			deliveries[i] = n.deliver(ctx, channel, notification)
+		}(i, channel)
+	}
+	wg.Wait()
+	return deliveries, nil
+}
+
+// route batches alerts by receiver and group labels, in the order groups
+// are first matched. Within a group an alert is sent once per fingerprint;
+// the most recently activated alert wins.
+func (n *Notifier) route(alerts []alertmodels.Alert) []models.Notification {
+	type group struct {
+		receiver     string
+		labels       map[string]string
+		fingerprints []string
+		alerts       map[string]alertmodels.Alert
+	}
+	var order []*group
+	groups := map[string]*group{}
+	for _, alert := range alerts {
+		for _, match := range n.router.Match(alert.Labels) {
+			labels := alertingservices.GroupLabels(alert.Labels, match.GroupBy)
+			key := match.Receiver + "\x00" + alertingservices.Fingerprint(labels)
+			g, ok := groups[key]
+			if !ok {
+				g = &group{receiver: match.Receiver, labels: labels, alerts: map[string]alertmodels.Alert{}}
+				groups[key] = g
+				order = append(order, g)
+			}
+			kept, seen := g.alerts[alert.Fingerprint]
+			if !seen {
+				g.fingerprints = append(g.fingerprints, alert.Fingerprint)
+			}
+			if !seen || alert.ActiveAt.After(kept.ActiveAt) {
+				g.alerts[alert.Fingerprint] = alert
+			}
+		}
+	}
+
+	notifications := make([]models.Notification, 0, len(order))
+	for _, g := range order {
+		batch := make([]alertmodels.Alert, 0, len(g.fingerprints))
+		for _, fingerprint := range g.fingerprints {
+			batch = append(batch, g.alerts[fingerprint])
+		}
+		notifications = append(notifications, newNotification(g.receiver, g.labels, batch))
+	}
+	return notifications
+}
+
+// deliver sends notification through channel, retrying until it succeeds,
+// fails permanently, runs out of attempts or ctx is cancelled, and records
+// the outcome.
+func (n *Notifier) deliver(ctx context.Context, channel channels.Channel, notification models.Notification) models.Delivery {
+	delivery := models.Delivery{
+		Receiver:  notification.Receiver,
+		Channel:   channel.Type(),
+		Target:    channel.Target(),
+		Status:    notification.Status,
+		AlertIDs:  make([]string, 0, len(notification.Alerts)),
+		CreatedAt: time.Now(),
+	}
+	for _, alert := range notification.Alerts {
+		delivery.AlertIDs = append(delivery.AlertIDs, alert.ID)
+	}
+
+	backoff := n.opts.InitialBackoff
+	var err error
+	for {
+		delivery.Attempts++
+		attemptCtx, cancel := context.WithTimeout(ctx, n.opts.Timeout)
+		err = channel.Send(attemptCtx, notification)
+		cancel()
+		if err == nil || channels.IsPermanent(err) || delivery.Attempts >= n.opts.MaxAttempts {
+			break
+		}
+
+		n.logger.Debug("retrying notification delivery",
+			zap.String("receiver", delivery.Receiver),
+			zap.String("channel", delivery.Channel),
+			zap.Int("attempt", delivery.Attempts),
+			zap.Error(err),
+		)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		backoff *= 2
+		if backoff > n.opts.MaxBackoff {
+			backoff = n.opts.MaxBackoff
+		}
+	}
+
+	delivery.FinishedAt = time.Now()
+	delivery.Outcome = models.OutcomeSuccess
+	if err != nil {
+		delivery.Outcome = models.OutcomeFailed
+		delivery.Error = err.Error()
+		n.logger.Warn("notification delivery failed",
+			zap.String("receiver", delivery.Receiver),
+			zap.String("channel", delivery.Channel),
+			zap.String("target", delivery.Target),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(err),
+		)
+	}
+
+	stored, storeErr := n.repo.Create(delivery)
+	if storeErr != nil {
+		n.logger.Warn("failed to record notification delivery", zap.String("receiver", delivery.Receiver), zap.Error(storeErr))
+		return delivery
+	}
+	return stored
+}
+
+// forChannel drops resolved alerts from a notification for channels that
+// do not send them, and reports whether anything is left to send.
+func forChannel(notification models.Notification, channel channels.Channel) (models.Notification, bool) {
+	if channel.SendResolved() {
+		return notification, true
+	}
+	var firing []alertmodels.Alert
+	for _, alert := range notification.Alerts {
+		if alert.State != alertmodels.StateResolved {
+			firing = append(firing, alert)
+		}
+	}
+	if len(firing) == 0 {
+		return models.Notification{}, false
+	}
+	return newNotification(notification.Receiver, notification.GroupLabels, firing), true
+}
+
+func newNotification(receiver string, groupLabels map[string]string, alerts []alertmodels.Alert) models.Notification {
+	notification := models.Notification{
+		Receiver:          receiver,
+		Status:            models.StatusResolved,
+		Alerts:            alerts,
+		GroupLabels:       groupLabels,
+		CommonLabels:      commonValues(alerts, func(alert alertmodels.Alert) map[string]string { return alert.Labels }),
+		CommonAnnotations: commonValues(alerts, func(alert alertmodels.Alert) map[string]string { return alert.Annotations }),
+	}
+	for _, alert := range alerts {
+		if alert.State != alertmodels.StateResolved {
+			notification.Status = models.StatusFiring
+			break
+		}
+	}
+	return notification
+}
+
+// commonValues returns the name/value pairs shared by every alert.
+func commonValues(alerts []alertmodels.Alert, values func(alertmodels.Alert) map[string]string) map[string]string {
+	common := map[string]string{}
+	if len(alerts) == 0 {
+		return common
+	}
+	for name, value := range values(alerts[0]) {
+		common[name] = value
+	}
+	for _, alert := range alerts[1:] {
+		current := values(alert)
+		for name, value := range common {
+			if current[name] != value {
+				delete(common, name)
+			}
+		}
+	}
+	return common
+}
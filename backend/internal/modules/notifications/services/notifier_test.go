@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	alertmodels "github.com/fbisdevoptics/backend/internal/modules/alerting/models"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/repositories"
+)
+
+// memDeliveries is an in-memory DeliveryRepository.
+type memDeliveries struct {
+	mu         sync.Mutex
+	deliveries []models.Delivery
+}
+
+func (r *memDeliveries) Create(delivery models.Delivery) (models.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.ID = int64(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, delivery)
+	return delivery, nil
+}
+
+func (r *memDeliveries) List(filter repositories.DeliveryFilter) ([]models.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []models.Delivery
+	for i := len(r.deliveries) - 1; i >= 0 && len(list) < filter.Limit; i-- {
+		delivery := r.deliveries[i]
+		if (filter.Receiver == "" || delivery.Receiver == filter.Receiver) && (filter.Outcome == "" || delivery.Outcome == filter.Outcome) {
+			list = append(list, delivery)
+		}
+	}
+	return list, nil
+}
+
+func (r *memDeliveries) DeleteBefore(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.deliveries[:0]
+	for _, delivery := range r.deliveries {
+		if !delivery.CreatedAt.Before(before) {
+			kept = append(kept, delivery)
+		}
+	}
+	removed := int64(len(r.deliveries) - len(kept))
+	r.deliveries = kept
+	return removed, nil
+}
+
+// webhookBody is the part of a webhook notification the tests look at.
+type webhookBody struct {
+	Receiver    string            `json:"receiver"`
+	Status      string            `json:"status"`
+	GroupLabels map[string]string `json:"groupLabels"`
+	Alerts      []struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"alerts"`
+}
+
+// hooks is a local HTTP endpoint standing in for every webhook receiver.
+// Requests are recorded by path and answered with the next status queued
+// for that path, or 200 once the queue is empty.
+type hooks struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses map[string][]int
+	bodies   map[string][]webhookBody
+}
+
+func newHooks(t *testing.T) *hooks {
+	t.Helper()
+	h := &hooks{statuses: map[string][]int{}, bodies: map[string][]webhookBody{}}
+	h.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body webhookBody
+		raw, _ := io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+
+		h.mu.Lock()
+		h.bodies[r.URL.Path] = append(h.bodies[r.URL.Path], body)
+		status := http.StatusOK
+		if queued := h.statuses[r.URL.Path]; len(queued) > 0 {
+			status, h.statuses[r.URL.Path] = queued[0], queued[1:]
+		}
+		h.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(h.Close)
+	return h
+}
+
+func (h *hooks) respond(path string, statuses ...int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.statuses[path] = statuses
+}
+
+func (h *hooks) received(path string) []webhookBody {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]webhookBody(nil), h.bodies[path]...)
+}
+
+func (h *hooks) receiver(name string, sendResolved *bool) models.Receiver {
+	return models.Receiver{Name: name, WebhookConfigs: []models.WebhookConfig{{URL: h.URL + "/" + name, SendResolved: sendResolved}}}
+}
+
+func newTestNotifier(t *testing.T, cfg models.Config, opts NotifierOptions) (*Notifier, *memDeliveries) {
+	t.Helper()
+	if opts.InitialBackoff == 0 {
+		opts.InitialBackoff = time.Millisecond
+	}
+	repo := &memDeliveries{}
+	notifier, err := NewNotifier(cfg, repo, opts, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewNotifier: %v", err)
+	}
+	return notifier, repo
+}
+
+func notifierAlert(id, state string, labels map[string]string) alertmodels.Alert {
+	return alertmodels.Alert{
+		ID:          id,
+		RuleID:      "r1",
+		Fingerprint: "fp-" + id,
+		State:       state,
+		Labels:      labels,
+		Annotations: map[string]string{},
+		ActiveAt:    time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func alertIDs(body webhookBody) string {
+	ids := make([]string, 0, len(body.Alerts))
+	for _, alert := range body.Alerts {
+		ids = append(ids, alert.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestNotifierRoutesAndGroups(t *testing.T) {
+	server := newHooks(t)
+	notifier, repo := newTestNotifier(t, models.Config{
+		Route: models.Route{
+			Receiver: "team",
+			GroupBy:  []string{"alertname"},
+			Routes: []models.Route{
+				{Match: map[string]string{"severity": "critical"}, Receiver: "pager", GroupBy: []string{"..."}, Continue: true},
+			},
+		},
+		Receivers: []models.Receiver{server.receiver("team", nil), server.receiver("pager", nil)},
+	}, NotifierOptions{})
+
+	disk := map[string]string{"alertname": "DiskFull", "instance": "a"}
+	notifier.Notify(context.Background(), []alertmodels.Alert{
+		notifierAlert("a1", alertmodels.StateFiring, disk),
+		notifierAlert("a2", alertmodels.StateFiring, map[string]string{"alertname": "DiskFull", "instance": "b"}),
+		notifierAlert("a3", alertmodels.StateResolved, map[string]string{"alertname": "HighLatency"}),
+		notifierAlert("a4", alertmodels.StateFiring, map[string]string{"alertname": "NodeDown", "severity": "critical"}),
+	})
+	notifier.Wait()
+
+	// NodeDown matches the pager route and no later sibling, so the team
+	// receiver does not get it.
+	team := server.received("/team")
+	sort.Slice(team, func(i, j int) bool { return team[i].GroupLabels["alertname"] < team[j].GroupLabels["alertname"] })
+	if len(team) != 2 {
+		t.Fatalf("team got %d notifications, want one per alertname", len(team))
+	}
+	if alertIDs(team[0]) != "a1,a2" || team[0].Status != models.StatusFiring || team[0].GroupLabels["alertname"] != "DiskFull" {
+		t.Errorf("DiskFull notification = %+v", team[0])
+	}
+	if alertIDs(team[1]) != "a3" || team[1].Status != models.StatusResolved {
+		t.Errorf("HighLatency notification = %+v", team[1])
+	}
+
+	pager := server.received("/pager")
+	if len(pager) != 1 || alertIDs(pager[0]) != "a4" || pager[0].GroupLabels["severity"] != "critical" || pager[0].Receiver != "pager" {
+		t.Fatalf("pager got %+v, want NodeDown grouped by every label", pager)
+	}
+
+	deliveries, _ := repo.List(repositories.DeliveryFilter{Limit: 10})
+	if len(deliveries) != 3 {
+		t.Fatalf("recorded %d deliveries, want 3", len(deliveries))
+	}
+	for _, delivery := range deliveries {
+		if delivery.Outcome != models.OutcomeSuccess || delivery.Attempts != 1 || delivery.Channel != models.ChannelWebhook || delivery.ID == 0 {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	}
+}
+
+func TestNotifierDeduplicatesWithinGroup(t *testing.T) {
+	server := newHooks(t)
+	notifier, _ := newTestNotifier(t, models.Config{
+		Route:     models.Route{Receiver: "team", GroupBy: []string{"alertname"}},
+		Receivers: []models.Receiver{server.receiver("team", nil)},
+	}, NotifierOptions{})
+
+	labels := map[string]string{"alertname": "DiskFull"}
+	older := notifierAlert("old", alertmodels.StateResolved, labels)
+	newer := notifierAlert("new", alertmodels.StateFiring, labels)
+	older.Fingerprint, newer.Fingerprint = "same", "same"
+	newer.ActiveAt = older.ActiveAt.Add(time.Minute)
+	notifier.Notify(context.Background(), []alertmodels.Alert{newer, older})
+	notifier.Wait()
+
+	if got := server.received("/team"); len(got) != 1 || alertIDs(got[0]) != "new" {
+		t.Fatalf("team got %+v, want only the most recently activated alert", got)
+	}
+}
+
+func TestNotifierRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantOutcome  string
+		wantAttempts int
+		wantErr      string
+	}{
+		{name: "first try", wantOutcome: models.OutcomeSuccess, wantAttempts: 1},
+		{name: "after transient failures", statuses: []int{503, 429}, wantOutcome: models.OutcomeSuccess, wantAttempts: 3},
+		{name: "permanent failure", statuses: []int{400}, wantOutcome: models.OutcomeFailed, wantAttempts: 1, wantErr: "unexpected status 400"},
+		{name: "out of attempts", statuses: []int{500, 502, 503, 200}, wantOutcome: models.OutcomeFailed, wantAttempts: 3, wantErr: "unexpected status 503"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newHooks(t)
+			server.respond("/team", tt.statuses...)
+			notifier, repo := newTestNotifier(t, models.Config{
+				Route:     models.Route{Receiver: "team"},
+				Receivers: []models.Receiver{server.receiver("team", nil)},
+			}, NotifierOptions{MaxAttempts: 3})
+
+			notifier.Notify(context.Background(), []alertmodels.Alert{notifierAlert("a1", alertmodels.StateFiring, map[string]string{"alertname": "A"})})
+			notifier.Wait()
+
+			deliveries, _ := repo.List(repositories.DeliveryFilter{Limit: 10})
+			if len(deliveries) != 1 {
+				t.Fatalf("recorded %d deliveries, want 1", len(deliveries))
+			}
+			delivery := deliveries[0]
+			if delivery.Outcome != tt.wantOutcome || delivery.Attempts != tt.wantAttempts || !strings.Contains(delivery.Error, tt.wantErr) {
+				t.Errorf("delivery = %+v", delivery)
+			}
+			if got := len(server.received("/team")); got != tt.wantAttempts {
+				t.Errorf("webhook received %d requests, want %d", got, tt.wantAttempts)
+			}
+			if delivery.Status != models.StatusFiring || strings.Join(delivery.AlertIDs, ",") != "a1" || delivery.Target != server.URL+"/team" {
+				t.Errorf("delivery = %+v", delivery)
+			}
+			if delivery.FinishedAt.Before(delivery.CreatedAt) {
+				t.Errorf("finished %v before it was created %v", delivery.FinishedAt, delivery.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestNotifierStopsRetryingOnCancel(t *testing.T) {
+	server := newHooks(t)
+	server.respond("/team", 503, 503, 503)
+	notifier, repo := newTestNotifier(t, models.Config{
+		Route:     models.Route{Receiver: "team"},
+		Receivers: []models.Receiver{server.receiver("team", nil)},
+	}, NotifierOptions{MaxAttempts: 3, InitialBackoff: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	notifier.Notify(ctx, []alertmodels.Alert{notifierAlert("a1", alertmodels.StateFiring, map[string]string{"alertname": "A"})})
+	for len(server.received("/team")) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	notifier.Wait()
+
+	deliveries, _ := repo.List(repositories.DeliveryFilter{Limit: 10})
+	if len(deliveries) != 1 || deliveries[0].Outcome != models.OutcomeFailed || deliveries[0].Attempts != 1 {
+		t.Fatalf("deliveries = %+v, want one failed attempt", deliveries)
+	}
+}
+
+func TestNotifierSendResolved(t *testing.T) {
+	server := newHooks(t)
+	off := false
+	notifier, repo := newTestNotifier(t, models.Config{
+		Route: models.Route{Receiver: "team", GroupBy: []string{"alertname"}, Routes: []models.Route{
+			{Match: map[string]string{"severity": "critical"}, Receiver: "quiet", Continue: true},
+			{Match: map[string]string{"severity": "critical"}, Receiver: "team"},
+		}},
+		Receivers: []models.Receiver{server.receiver("team", nil), server.receiver("quiet", &off)},
+	}, NotifierOptions{})
+
+	critical := func(id, name, state string) alertmodels.Alert {
+		return notifierAlert(id, state, map[string]string{"alertname": name, "severity": "critical"})
+	}
+	notifier.Notify(context.Background(), []alertmodels.Alert{
+		critical("a1", "Mixed", alertmodels.StateFiring),
+		critical("a2", "Mixed", alertmodels.StateResolved),
+		critical("a3", "AllResolved", alertmodels.StateResolved),
+	})
+	notifier.Wait()
+
+	if team := server.received("/team"); len(team) != 2 {
+		t.Errorf("team got %d notifications, want both groups", len(team))
+	}
+	quiet := server.received("/quiet")
+	if len(quiet) != 1 || alertIDs(quiet[0]) != "a1" || quiet[0].Status != models.StatusFiring {
+		t.Fatalf("quiet got %+v, want the firing alert only", quiet)
+	}
+	if deliveries, _ := repo.List(repositories.DeliveryFilter{Receiver: "quiet", Limit: 10}); len(deliveries) != 1 {
+		t.Errorf("recorded %d quiet deliveries, want 1", len(deliveries))
+	}
+}
+
+func TestTestReceiver(t *testing.T) {
+	server := newHooks(t)
+	server.respond("/broken", 404)
+	notifier, repo := newTestNotifier(t, models.Config{
+		Route: models.Route{Receiver: "team"},
+		Receivers: []models.Receiver{
+			{Name: "team", WebhookConfigs: []models.WebhookConfig{{URL: server.URL + "/team"}, {URL: server.URL + "/broken"}}},
+		},
+	}, NotifierOptions{})
+
+	if _, err := notifier.TestReceiver(context.Background(), "nobody"); !errors.Is(err, ErrReceiverNotFound) {
+		t.Fatalf("TestReceiver(nobody) error = %v", err)
+	}
+
+	deliveries, err := notifier.TestReceiver(context.Background(), "team")
+	if err != nil {
+		t.Fatalf("TestReceiver: %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[0].Outcome != models.OutcomeSuccess || deliveries[1].Outcome != models.OutcomeFailed {
+		t.Fatalf("deliveries = %+v, want one success and one failure in channel order", deliveries)
+	}
+	if deliveries[0].ID == 0 || strings.Join(deliveries[0].AlertIDs, ",") != "test" {
+		t.Errorf("delivery = %+v, want the stored test alert", deliveries[0])
+	}
+	sent := server.received("/team")
+	if len(sent) != 1 || alertIDs(sent[0]) != "test" || sent[0].Status != models.StatusFiring {
+		t.Errorf("team got %+v", sent)
+	}
+	if stored, _ := repo.List(repositories.DeliveryFilter{Limit: 10}); len(stored) != 2 {
+		t.Errorf("stored %d deliveries, want 2", len(stored))
+	}
+
+	summaries := notifier.ListReceivers()
+	if len(summaries) != 1 || summaries[0].Name != "team" || strings.Join(summaries[0].Channels, ",") != "webhook,webhook" {
+		t.Errorf("ListReceivers = %+v", summaries)
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	notifier, repo := newTestNotifier(t, models.Config{}, NotifierOptions{})
+	for i := 0; i < MaxDeliveryLimit+5; i++ {
+		repo.Create(models.Delivery{Receiver: "team", Outcome: models.OutcomeSuccess})
+	}
+	repo.Create(models.Delivery{Receiver: "pager", Outcome: models.OutcomeFailed})
+
+	if _, err := notifier.ListDeliveries(repositories.DeliveryFilter{Outcome: "pending"}); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("unknown outcome error = %v", err)
+	}
+	if list, _ := notifier.ListDeliveries(repositories.DeliveryFilter{}); len(list) != DefaultDeliveryLimit {
+		t.Errorf("default limit listed %d", len(list))
+	}
+	if list, _ := notifier.ListDeliveries(repositories.DeliveryFilter{Limit: MaxDeliveryLimit * 2}); len(list) != MaxDeliveryLimit {
+		t.Errorf("limit above the maximum listed %d", len(list))
+	}
+	if list, _ := notifier.ListDeliveries(repositories.DeliveryFilter{Outcome: models.OutcomeFailed}); len(list) != 1 || list[0].Receiver != "pager" {
+		t.Errorf("failed deliveries = %+v", list)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"regexp"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+// Router resolves the receivers for an alert's labels from a routing tree.
+type Router struct {
+	root *route
+}
+
+// Match is a receiver an alert is routed to and the labels its
+// notifications are grouped by.
+type Match struct {
+	Receiver string
+	GroupBy  []string
+}
+
+type route struct {
+	receiver string
+	groupBy  []string
+	match    map[string]string
+	matchRE  map[string]*regexp.Regexp
+	cont     bool
+	routes   []*route
+}
+
+// NewRouter compiles the routing tree of a validated config. Child routes
+// without a receiver or group_by inherit their parent's.
+func NewRouter(cfg models.Config) (*Router, error) {
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return &Router{root: compileRoute(cfg.Route, &route{})}, nil
+}
+
+func compileRoute(cfg models.Route, parent *route) *route {
+	compiled := &route{
+		receiver: cfg.Receiver,
+		groupBy:  cfg.GroupBy,
+		match:    cfg.Match,
+		matchRE:  make(map[string]*regexp.Regexp, len(cfg.MatchRE)),
+		cont:     cfg.Continue,
+	}
+	if compiled.receiver == "" {
+		compiled.receiver = parent.receiver
+	}
+	if compiled.groupBy == nil {
+		compiled.groupBy = parent.groupBy
+	}
+	for name, pattern := range cfg.MatchRE {
+		compiled.matchRE[name] = regexp.MustCompile(anchor(pattern))
+	}
+	for _, child := range cfg.Routes {
+		compiled.routes = append(compiled.routes, compileRoute(child, compiled))
+	}
+	return compiled
+}
+
+// Match returns the receivers an alert with labels is sent to, in
+// routing-tree order. A receiver reached through several routes is listed
+// once, with the group_by of the first. It is empty only when the config
+// has no root receiver.
+func (r *Router) Match(labels map[string]string) []Match {
+	if r.root.receiver == "" {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var matches []Match
+	for _, matched := range r.root.resolve(labels) {
+		if !seen[matched.receiver] {
+			seen[matched.receiver] = true
+			matches = append(matches, Match{Receiver: matched.receiver, GroupBy: matched.groupBy})
+		}
+	}
+	return matches
+}
+
+// resolve returns nil if the route does not match. Otherwise it returns the
+// routes chosen by the first matching child, and by later matching
+// siblings while each matching child has continue set, or the route itself
+// if no child matches.
+func (r *route) resolve(labels map[string]string) []*route {
+	if !r.matches(labels) {
+		return nil
+	}
+
+	var matched []*route
+	for _, child := range r.routes {
+		found := child.resolve(labels)
+		if found == nil {
+			continue
+		}
+		matched = append(matched, found...)
+		if !child.cont {
+			break
+		}
+	}
+	if matched == nil {
+		matched = []*route{r}
+	}
+	return matched
+}
+
+func (r *route) matches(labels map[string]string) bool {
+	for name, value := range r.match {
+		if labels[name] != value {
+			return false
+		}
+	}
+	for name, pattern := range r.matchRE {
+		if !pattern.MatchString(labels[name]) {
+			return false
+		}
+	}
+	return true
+}
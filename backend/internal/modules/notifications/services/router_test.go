@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fbisdevoptics/backend/internal/modules/notifications/models"
+)
+
+func receivers(names ...string) []models.Receiver {
+	list := make([]models.Receiver, 0, len(names))
+	for _, name := range names {
+		list = append(list, models.Receiver{Name: name, WebhookConfigs: []models.WebhookConfig{{URL: "https://example.com/" + name}}})
+	}
+	return list
+}
+
+func TestRouterMatch(t *testing.T) {
+	router, err := NewRouter(models.Config{
+		Route: models.Route{
+			Receiver: "default",
+			GroupBy:  []string{"alertname"},
+			Routes: []models.Route{
+				{Match: map[string]string{"severity": "critical"}, Receiver: "pager", Continue: true},
+				{
+					Match:   map[string]string{"team": "payments"},
+					GroupBy: []string{"alertname", "namespace"},
+					Routes: []models.Route{
+						{Match: map[string]string{"env": "dev"}, Receiver: "payments-dev"},
+					},
+				},
+				{MatchRE: map[string]string{"namespace": "kube-.*|monitoring"}, Receiver: "platform", Continue: true},
+				{Match: map[string]string{"namespace": "kube-system"}, Receiver: "pager"},
+			},
+		},
+		Receivers: receivers("default", "pager", "payments-dev", "platform"),
+	})
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   []Match
+	}{
+		{name: "no child matches", labels: map[string]string{"alertname": "A"}, want: []Match{{"default", []string{"alertname"}}}},
+		{
+			name:   "child without receiver inherits",
+			labels: map[string]string{"team": "payments"},
+			want:   []Match{{"default", []string{"alertname", "namespace"}}},
+		},
+		{
+			name:   "grandchild inherits group_by",
+			labels: map[string]string{"team": "payments", "env": "dev"},
+			want:   []Match{{"payments-dev", []string{"alertname", "namespace"}}},
+		},
+		{
+			name:   "continue reaches later siblings",
+			labels: map[string]string{"severity": "critical", "team": "payments"},
+			want:   []Match{{"pager", []string{"alertname"}}, {"default", []string{"alertname", "namespace"}}},
+		},
+		{
+			name:   "continue with no later match",
+			labels: map[string]string{"severity": "critical"},
+			want:   []Match{{"pager", []string{"alertname"}}},
+		},
+		{name: "regex is anchored", labels: map[string]string{"namespace": "monitoring"}, want: []Match{{"platform", []string{"alertname"}}}},
+		{name: "regex partial value", labels: map[string]string{"namespace": "monitoring-2"}, want: []Match{{"default", []string{"alertname"}}}},
+		{
+			name:   "first match wins",
+			labels: map[string]string{"team": "payments", "namespace": "monitoring"},
+			want:   []Match{{"default", []string{"alertname", "namespace"}}},
+		},
+		{
+			name:   "continue through several matches",
+			labels: map[string]string{"namespace": "kube-system"},
+			want:   []Match{{"platform", []string{"alertname"}}, {"pager", []string{"alertname"}}},
+		},
+		{
+			name:   "receiver listed once",
+			labels: map[string]string{"severity": "critical", "namespace": "kube-system", "team": "x"},
+			want:   []Match{{"pager", []string{"alertname"}}, {"platform", []string{"alertname"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := router.Match(tt.labels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Match(%v) = %+v, want %+v", tt.labels, got, tt.want)
+			}
+		})
+	}
+
+	empty, err := NewRouter(models.Config{})
+	if err != nil {
+		t.Fatalf("NewRouter(empty): %v", err)
+	}
+	if got := empty.Match(map[string]string{"alertname": "A"}); got != nil {
+		t.Errorf("empty config routed to %+v", got)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	valid := func() models.Config {
+		return models.Config{
+			Route:     models.Route{Receiver: "team", Routes: []models.Route{{Match: map[string]string{"a": "b"}, Receiver: "team"}}},
+			Receivers: receivers("team"),
+		}
+	}
+	tests := []struct {
+		name    string
+		change  func(*models.Config)
+		wantErr string
+	}{
+		{name: "valid", change: func(*models.Config) {}},
+		{name: "empty", change: func(c *models.Config) { *c = models.Config{} }},
+		{name: "unnamed receiver", change: func(c *models.Config) { c.Receivers[0].Name = "" }, wantErr: "receiver without a name"},
+		{name: "duplicate receiver", change: func(c *models.Config) { c.Receivers = append(c.Receivers, c.Receivers[0]) }, wantErr: "duplicate receiver"},
+		{name: "receivers without route", change: func(c *models.Config) { c.Route = models.Route{} }, wantErr: "root route needs a receiver"},
+		{name: "root matchers", change: func(c *models.Config) { c.Route.Match = map[string]string{"a": "b"} }, wantErr: "cannot have matchers"},
+		{name: "unknown receiver", change: func(c *models.Config) { c.Route.Routes[0].Receiver = "nobody" }, wantErr: `unknown receiver "nobody"`},
+		{name: "group_by label", change: func(c *models.Config) { c.Route.GroupBy = []string{"team-name"} }, wantErr: "invalid label name"},
+		{name: "group_by all", change: func(c *models.Config) { c.Route.GroupBy = []string{"..."} }},
+		{name: "group_by all mixed", change: func(c *models.Config) { c.Route.GroupBy = []string{"...", "team"} }, wantErr: "invalid label name"},
+		{name: "match_re", change: func(c *models.Config) { c.Route.Routes[0].MatchRE = map[string]string{"a": "("} }, wantErr: "match_re a"},
+		{name: "slack url", change: func(c *models.Config) {
+			c.Receivers[0].SlackConfigs = []models.SlackConfig{{APIURL: "hooks.slack.com/x"}}
+		}, wantErr: "slack api_url"},
+		{name: "pagerduty key", change: func(c *models.Config) {
+			c.Receivers[0].PagerDutyConfigs = []models.PagerDutyConfig{{}}
+		}, wantErr: "routing_key is required"},
+		{name: "email to", change: func(c *models.Config) {
+			c.Receivers[0].EmailConfigs = []models.EmailConfig{{From: "a@example.com", Smarthost: "smtp:25"}}
+		}, wantErr: "email to is required"},
+		{name: "email address", change: func(c *models.Config) {
+			c.Receivers[0].EmailConfigs = []models.EmailConfig{{To: []string{"not an address"}, From: "a@example.com", Smarthost: "smtp:25"}}
+		}, wantErr: "email address"},
+		{name: "email smarthost", change: func(c *models.Config) {
+			c.Receivers[0].EmailConfigs = []models.EmailConfig{{To: []string{"b@example.com"}, From: "a@example.com"}}
+		}, wantErr: "smarthost is required"},
+		{name: "webhook max_alerts", change: func(c *models.Config) { c.Receivers[0].WebhookConfigs[0].MaxAlerts = -1 }, wantErr: "max_alerts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.change(&cfg)
+			err := ValidateConfig(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateConfig: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateConfig error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join("..", "..", "..", "..", "notifications.yaml.example"))
+	if err != nil {
+		t.Fatalf("example config: %v", err)
+	}
+	if cfg.Route.Receiver != "platform-slack" || len(cfg.Receivers) != 3 {
+		t.Errorf("example config = %+v", cfg)
+	}
+
+	misspelt := filepath.Join(t.TempDir(), "notifications.yaml")
+	if err := os.WriteFile(misspelt, []byte("route:\n  reciever: team\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(misspelt); !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), "reciever") {
+		t.Errorf("LoadConfig error = %v, want the unknown field", err)
+	}
+}
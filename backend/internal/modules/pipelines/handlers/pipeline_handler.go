@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/models"
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/services"
+)
+
+// maxWebhookBytes caps webhook payloads; workflow_run events are well under
+// this.
+const maxWebhookBytes = 5 << 20
+
+type PipelineHandler struct {
+	service       services.PipelineService
+	webhookSecret []byte
+}
+
+// NewPipelineHandler returns a PipelineHandler. Webhooks are rejected while
+// webhookSecret is empty.
+func NewPipelineHandler(service services.PipelineService, webhookSecret string) *PipelineHandler {
+	return &PipelineHandler{service: service, webhookSecret: []byte(webhookSecret)}
+}
+
+// GitHubWebhook ingests GitHub Actions workflow_run events. Other event
+// types are acknowledged and ignored so the webhook can subscribe broadly.
+func (h *PipelineHandler) GitHubWebhook(c *gin.Context) {
+	body, ok := h.verifiedBody(c, c.GetHeader("X-Hub-Signature-256"))
+	if !ok {
+		return
+	}
+
+	if event := c.GetHeader("X-GitHub-Event"); event != "workflow_run" {
+		c.JSON(http.StatusOK, gin.H{"status": "ignored", "event": event})
+		return
+	}
+
+	run, err := services.ParseGitHubWorkflowRun(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.ingest(c, run)
+}
+
+// GenericWebhook ingests runs in the generic JSON format.
+func (h *PipelineHandler) GenericWebhook(c *gin.Context) {
+	body, ok := h.verifiedBody(c, c.GetHeader("X-Signature-256"))
+	if !ok {
+		return
+	}
+
+	run, err := services.ParseGenericRun(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.ingest(c, run)
+}
+
+func (h *PipelineHandler) verifiedBody(c *gin.Context, signature string) ([]byte, bool) {
+	if len(h.webhookSecret) == 0 {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "pipeline webhook secret is not configured"})
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err := services.VerifySignature(h.webhookSecret, body, signature); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return body, true
+}
+
+func (h *PipelineHandler) ingest(c *gin.Context, run models.Run) {
+	stored, err := h.service.Ingest(run)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, stored)
+}
+
+// ListRuns returns recent runs, newest first, filtered by the repository,
+// branch, workflow and status query parameters.
+func (h *PipelineHandler) ListRuns(c *gin.Context) {
+	limit, ok := intQuery(c, "limit")
+	if !ok {
+		return
+	}
+
+	runs, err := h.service.ListRuns(runFilter(c), limit)
+	if err != nil {
+		writePipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+// GetStats returns success rate, daily duration trend and flaky workflows
+// over the last days days (default 30), with the same filters as ListRuns.
+func (h *PipelineHandler) GetStats(c *gin.Context) {
+	days, ok := intQuery(c, "days")
+	if !ok {
+		return
+	}
+
+	stats, err := h.service.Stats(runFilter(c), days)
+	if err != nil {
+		writePipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// ListBranches summarises each repository branch with runs in the last days
+// days.
+func (h *PipelineHandler) ListBranches(c *gin.Context) {
+	days, ok := intQuery(c, "days")
+	if !ok {
+		return
+	}
+
+	branches, err := h.service.ListBranches(days)
+	if err != nil {
+		writePipelineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"branches": branches})
+}
+
+func runFilter(c *gin.Context) repositories.RunFilter {
+	return repositories.RunFilter{
+		Repository: c.Query("repository"),
+		Branch:     c.Query("branch"),
+		Workflow:   c.Query("workflow"),
+		Status:     c.Query("status"),
+	}
+}
+
+func intQuery(c *gin.Context, name string) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a positive integer"})
+		return 0, false
+	}
+	return value, true
+}
+
+func writePipelineError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrInvalidFilter) {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Webhook providers.
+const (
+	ProviderGitHub  = "github"
+	ProviderGeneric = "generic"
+)
+
+// Run statuses. Only success and failure count towards success rates;
+// cancelled covers runs that were cancelled, skipped or otherwise produced no
+// verdict.
+const (
+	StatusRunning   = "running"
+	StatusSuccess   = "success"
+	StatusFailure   = "failure"
+	StatusCancelled = "cancelled"
+)
+
+// Run is one execution of a CI/CD workflow.
+type Run struct {
+	ID              int64      `json:"id"`
+	Provider        string     `json:"provider"`
+	ExternalID      string     `json:"externalId"`
+	Repository      string     `json:"repository"`
+	Branch          string     `json:"branch"`
+	Workflow        string     `json:"workflow"`
+	CommitSHA       string     `json:"commitSha"`
+	Attempt         int        `json:"attempt"`
+	Status          string     `json:"status"`
+	URL             string     `json:"url,omitempty"`
+	StartedAt       time.Time  `json:"startedAt"`
+	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+	DurationSeconds *float64   `json:"durationSeconds,omitempty"`
+	ReceivedAt      time.Time  `json:"receivedAt"`
+}
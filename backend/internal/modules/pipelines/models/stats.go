@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Stats summarises completed runs over a window.
+type Stats struct {
+	Since       time.Time       `json:"since"`
+	Total       int             `json:"total"`
+	Succeeded   int             `json:"succeeded"`
+	Failed      int             `json:"failed"`
+	Cancelled   int             `json:"cancelled"`
+	SuccessRate *float64        `json:"successRate"`
+	Trend       []DailyStats    `json:"trend"`
+	Flaky       []FlakyWorkflow `json:"flaky"`
+}
+
+// OutcomeStats counts runs that finished since a point in time. Cancelled
+// runs are in neither count.
+type OutcomeStats struct {
+	Total     int
+	Succeeded int
+}
+
+// DailyStats is one day of the duration and success trend.
+type DailyStats struct {
+	Date               string   `json:"date"`
+	Runs               int      `json:"runs"`
+	SuccessRate        *float64 `json:"successRate"`
+	AvgDurationSeconds float64  `json:"avgDurationSeconds"`
+	P95DurationSeconds float64  `json:"p95DurationSeconds"`
+}
+
+// FlakyWorkflow is a workflow that both failed and succeeded on the same
+// commit within the window.
+type FlakyWorkflow struct {
+	Repository   string  `json:"repository"`
+	Workflow     string  `json:"workflow"`
+	Commits      int     `json:"commits"`
+	FlakyCommits int     `json:"flakyCommits"`
+	FlakyRate    float64 `json:"flakyRate"`
+}
+
+// BranchSummary describes the runs of one repository branch.
+type BranchSummary struct {
+	Repository  string    `json:"repository"`
+	Branch      string    `json:"branch"`
+	Runs        int       `json:"runs"`
+	Succeeded   int       `json:"succeeded"`
+	Failed      int       `json:"failed"`
+	SuccessRate *float64  `json:"successRate"`
+	LastStatus  string    `json:"lastStatus"`
+	LastRunAt   time.Time `json:"lastRunAt"`
+}
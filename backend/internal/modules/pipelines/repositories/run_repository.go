@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/models"
+)
+
+var ErrRunNotFound = errors.New("pipeline run not found")
+
+// RunFilter narrows queries to a repository, branch, workflow or status.
+// Empty fields match everything.
+type RunFilter struct {
+	Repository string
+	Branch     string
+	Workflow   string
+	Status     string
+}
+
+// OutcomeCounts counts finished runs by status.
+type OutcomeCounts struct {
+	Succeeded int
+	Failed    int
+	Cancelled int
+}
+
+// RunRepository persists pipeline runs and answers the aggregate queries
+// behind the pipelines API.
+type RunRepository interface {
+	// Upsert stores run, replacing an earlier event for the same provider
+	// and external ID. A finished run is never reverted to running by a
+	// late event.
+	Upsert(run models.Run) (models.Run, error)
+	List(filter RunFilter, limit int) ([]models.Run, error)
+	CountOutcomes(filter RunFilter, since time.Time) (OutcomeCounts, error)
+	DailyStats(filter RunFilter, since time.Time) ([]models.DailyStats, error)
+	FlakyWorkflows(filter RunFilter, since time.Time) ([]models.FlakyWorkflow, error)
+	Branches(since time.Time) ([]models.BranchSummary, error)
+}
+
+type runRepository struct {
+	db *sql.DB
+}
+
+func NewRunRepository(db *sql.DB) RunRepository {
+	return &runRepository{db: db}
+}
+
+const runColumns = `id, provider, external_id, repository, branch, workflow, commit_sha, attempt,
+	status, url, started_at, finished_at, received_at`
+
+func (r *runRepository) Upsert(run models.Run) (models.Run, error) {
+	row := r.db.QueryRow(
+		`INSERT INTO pipeline_runs (provider, external_id, repository, branch, workflow, commit_sha,
+		   attempt, status, url, started_at, finished_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		 ON CONFLICT (provider, external_id) DO UPDATE
+		 SET repository = EXCLUDED.repository, branch = EXCLUDED.branch, workflow = EXCLUDED.workflow,
+		     commit_sha = EXCLUDED.commit_sha, attempt = EXCLUDED.attempt, status = EXCLUDED.status,
+		     url = EXCLUDED.url, started_at = EXCLUDED.started_at, finished_at = EXCLUDED.finished_at,
+		     received_at = NOW()
+		 WHERE pipeline_runs.finished_at IS NULL OR EXCLUDED.finished_at IS NOT NULL
+		 RETURNING `+runColumns,
+		run.Provider, run.ExternalID, run.Repository, run.Branch, run.Workflow, run.CommitSHA,
+		run.Attempt, run.Status, run.URL, run.StartedAt, run.FinishedAt,
+	)
+	stored, err := scanRun(row)
+	if errors.Is(err, ErrRunNotFound) {
+		// The update was skipped because the stored run already finished.
+		row = r.db.QueryRow(
+			`SELECT `+runColumns+` FROM pipeline_runs WHERE provider = $1 AND external_id = $2`,
+			run.Provider, run.ExternalID,
+		)
+		return scanRun(row)
+	}
+	return stored, err
+}
+
+func (r *runRepository) List(filter RunFilter, limit int) ([]models.Run, error) {
+	where, args := filterClause(filter, nil)
+	args = append(args, limit)
+	rows, err := r.db.Query(
+		`SELECT `+runColumns+` FROM pipeline_runs`+where+
+			fmt.Sprintf(` ORDER BY started_at DESC LIMIT $%d`, len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.Run
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *runRepository) CountOutcomes(filter RunFilter, since time.Time) (OutcomeCounts, error) {
+	where, args := filterClause(filter, &since)
+	var counts OutcomeCounts
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FILTER (WHERE status = 'success'),
+		        COUNT(*) FILTER (WHERE status = 'failure'),
+		        COUNT(*) FILTER (WHERE status = 'cancelled')
+		 FROM pipeline_runs`+where,
+		args...,
+	).Scan(&counts.Succeeded, &counts.Failed, &counts.Cancelled)
+	return counts, err
+}
+
+func (r *runRepository) DailyStats(filter RunFilter, since time.Time) ([]models.DailyStats, error) {
+	where, args := filterClause(filter, &since)
+	rows, err := r.db.Query(
+		`SELECT to_char(date_trunc('day', finished_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD'),
+		        COUNT(*),
+		        COUNT(*) FILTER (WHERE status = 'success'),
+		        COUNT(*) FILTER (WHERE status = 'failure'),
+		        COALESCE(AVG(EXTRACT(EPOCH FROM finished_at - started_at)), 0),
+		        COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM finished_at - started_at)), 0)
+		 FROM pipeline_runs`+where+`
+		 GROUP BY 1 ORDER BY 1`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []models.DailyStats
+	for rows.Next() {
+		var day models.DailyStats
+		var succeeded, failed int
+		if err := rows.Scan(&day.Date, &day.Runs, &succeeded, &failed,
+			&day.AvgDurationSeconds, &day.P95DurationSeconds); err != nil {
+			return nil, err
+		}
+		day.SuccessRate = successRate(succeeded, failed)
+		days = append(days, day)
+	}
+	return days, rows.Err()
+}
+
+func (r *runRepository) FlakyWorkflows(filter RunFilter, since time.Time) ([]models.FlakyWorkflow, error) {
+	where, args := filterClause(filter, &since)
+	rows, err := r.db.Query(
+		`SELECT repository, workflow, COUNT(*),
+		        COUNT(*) FILTER (WHERE passed AND failed)
+		 FROM (
+		   SELECT repository, workflow, commit_sha,
+		          bool_or(status = 'success') AS passed,
+		          bool_or(status = 'failure') AS failed
+		   FROM pipeline_runs`+where+` AND commit_sha <> ''
+		   GROUP BY repository, workflow, commit_sha
+		 ) commits
+		 GROUP BY repository, workflow
+		 HAVING COUNT(*) FILTER (WHERE passed AND failed) > 0
+		 ORDER BY 4 DESC, repository, workflow`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flaky []models.FlakyWorkflow
+	for rows.Next() {
+		var workflow models.FlakyWorkflow
+		if err := rows.Scan(&workflow.Repository, &workflow.Workflow, &workflow.Commits,
+			&workflow.FlakyCommits); err != nil {
+			return nil, err
+		}
+		workflow.FlakyRate = round2(100 * float64(workflow.FlakyCommits) / float64(workflow.Commits))
+		flaky = append(flaky, workflow)
+	}
+	return flaky, rows.Err()
+}
+
+func (r *runRepository) Branches(since time.Time) ([]models.BranchSummary, error) {
+	rows, err := r.db.Query(
+		`SELECT DISTINCT ON (repository, branch)
+		        repository, branch,
+		        COUNT(*) OVER w,
+		        COUNT(*) FILTER (WHERE status = 'success') OVER w,
+		        COUNT(*) FILTER (WHERE status = 'failure') OVER w,
+		        status, started_at
+		 FROM pipeline_runs
+		 WHERE started_at >= $1
+		 WINDOW w AS (PARTITION BY repository, branch)
+		 ORDER BY repository, branch, started_at DESC`,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var branches []models.BranchSummary
+	for rows.Next() {
+		var branch models.BranchSummary
+		if err := rows.Scan(&branch.Repository, &branch.Branch, &branch.Runs, &branch.Succeeded,
+			&branch.Failed, &branch.LastStatus, &branch.LastRunAt); err != nil {
+			return nil, err
+		}
+		branch.SuccessRate = successRate(branch.Succeeded, branch.Failed)
+		branches = append(branches, branch)
+	}
+	return branches, rows.Err()
+}
+
+// filterClause builds a WHERE clause for filter. When since is set the
+// clause is limited to runs that finished at or after it.
+func filterClause(filter RunFilter, since *time.Time) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	add := func(cond string, value interface{}) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if since != nil {
+		add("finished_at >= $%d", *since)
+	}
+	if filter.Repository != "" {
+		add("repository = $%d", filter.Repository)
+	}
+	if filter.Branch != "" {
+		add("branch = $%d", filter.Branch)
+	}
+	if filter.Workflow != "" {
+		add("workflow = $%d", filter.Workflow)
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+
+	if len(conds) == 0 {
+		return " WHERE TRUE", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func successRate(succeeded, failed int) *float64 {
+	if succeeded+failed == 0 {
+		return nil
+	}
+	rate := round2(100 * float64(succeeded) / float64(succeeded+failed))
+	return &rate
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanRun(row rowScanner) (models.Run, error) {
+	var run models.Run
+	var finishedAt sql.NullTime
+	if err := row.Scan(
+		&run.ID, &run.Provider, &run.ExternalID, &run.Repository, &run.Branch, &run.Workflow,
+		&run.CommitSHA, &run.Attempt, &run.Status, &run.URL, &run.StartedAt, &finishedAt,
+		&run.ReceivedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Run{}, ErrRunNotFound
+		}
+		return models.Run{}, err
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+		duration := finishedAt.Time.Sub(run.StartedAt).Seconds()
+		run.DurationSeconds = &duration
+	}
+	return run, nil
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/models"
+)
+
+// testDB opens TEST_DATABASE_URL with a scratch schema holding an empty
+// pipeline_runs table, and skips the test when it is not set: the guards
+// under test are in SQL.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One connection, so the search path set below applies to every query.
+	db.SetMaxOpenConns(1)
+	schema := fmt.Sprintf("pipelines_test_%d", time.Now().UnixNano())
+	if _, err := db.Exec(`CREATE SCHEMA ` + schema + `; SET search_path TO ` + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec(`DROP SCHEMA ` + schema + ` CASCADE`)
+		db.Close()
+	})
+	if _, err := db.Exec(`
+CREATE TABLE pipeline_runs (
+  id BIGSERIAL PRIMARY KEY,
+  provider TEXT NOT NULL,
+  external_id TEXT NOT NULL,
+  repository TEXT NOT NULL,
+  branch TEXT NOT NULL DEFAULT '',
+  workflow TEXT NOT NULL,
+  commit_sha TEXT NOT NULL DEFAULT '',
+  attempt INTEGER NOT NULL DEFAULT 1,
+  status TEXT NOT NULL,
+  url TEXT NOT NULL DEFAULT '',
+  started_at TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ,
+  received_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (provider, external_id)
+)`); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestUpsertKeepsFinishedRuns(t *testing.T) {
+	started := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(5 * time.Minute)
+	running := models.Run{
+		Provider:   models.ProviderGitHub,
+		ExternalID: "42:1",
+		Repository: "org/service",
+		Branch:     "main",
+		Workflow:   "build",
+		Attempt:    1,
+		Status:     models.StatusRunning,
+		StartedAt:  started,
+	}
+	succeeded := running
+	succeeded.Status = models.StatusSuccess
+	succeeded.FinishedAt = &finished
+	failed := succeeded
+	failed.Status = models.StatusFailure
+	renamed := running
+	renamed.Workflow = "build and test"
+
+	tests := []struct {
+		name       string
+		events     []models.Run
+		wantStatus string
+		wantFlow   string
+		wantDone   bool
+	}{
+		{name: "running then finished", events: []models.Run{running, succeeded}, wantStatus: models.StatusSuccess, wantFlow: "build", wantDone: true},
+		{name: "late running event", events: []models.Run{running, succeeded, running}, wantStatus: models.StatusSuccess, wantFlow: "build", wantDone: true},
+		{name: "finished event redelivered", events: []models.Run{succeeded, failed}, wantStatus: models.StatusFailure, wantFlow: "build", wantDone: true},
+		{name: "running updated", events: []models.Run{running, renamed}, wantStatus: models.StatusRunning, wantFlow: "build and test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewRunRepository(testDB(t))
+			var first, last models.Run
+			for i, event := range tt.events {
+				stored, err := repo.Upsert(event)
+				if err != nil {
+					t.Fatalf("event %d: %v", i, err)
+				}
+				if i == 0 {
+					first = stored
+				}
+				last = stored
+			}
+			// Every event, including a skipped one, returns the stored run.
+			if last.ID != first.ID || last.Status != tt.wantStatus || last.Workflow != tt.wantFlow || (last.FinishedAt != nil) != tt.wantDone {
+				t.Errorf("last Upsert returned %+v, want run %d %s", last, first.ID, tt.wantStatus)
+			}
+			runs, err := repo.List(RunFilter{}, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(runs) != 1 || runs[0].Status != tt.wantStatus || runs[0].Workflow != tt.wantFlow {
+				t.Errorf("stored runs = %+v, want one %s run", runs, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUpsertKeysOnAttempt(t *testing.T) {
+	repo := NewRunRepository(testDB(t))
+	started := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(time.Minute)
+	run := models.Run{
+		Provider:   models.ProviderGitHub,
+		ExternalID: "42:1",
+		Repository: "org/service",
+		Workflow:   "build",
+		Attempt:    1,
+		Status:     models.StatusFailure,
+		StartedAt:  started,
+		FinishedAt: &finished,
+	}
+	if _, err := repo.Upsert(run); err != nil {
+		t.Fatal(err)
+	}
+	run.ExternalID, run.Attempt, run.Status, run.FinishedAt = "42:2", 2, models.StatusRunning, nil
+	if _, err := repo.Upsert(run); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := repo.List(RunFilter{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Errorf("stored runs = %+v, want one per attempt", runs)
+	}
+}
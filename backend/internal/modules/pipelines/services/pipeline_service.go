@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/models"
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/repositories"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+const (
+	DefaultRunLimit = 50
+	MaxRunLimit     = 500
+
+	DefaultStatsDays = 30
+	MaxStatsDays     = 365
+)
+
+// PipelineService ingests CI/CD runs and reports on them.
+type PipelineService interface {
+	Ingest(run models.Run) (models.Run, error)
+	ListRuns(filter repositories.RunFilter, limit int) ([]models.Run, error)
+	// Stats covers runs that finished in the last days days.
+	Stats(filter repositories.RunFilter, days int) (models.Stats, error)
+	ListBranches(days int) ([]models.BranchSummary, error)
+
+	// PipelineStats feeds the dashboard summary.
+	PipelineStats(ctx context.Context, since time.Time) (models.OutcomeStats, error)
+}
+
+type pipelineService struct {
+	repo repositories.RunRepository
+}
+
+// NewPipelineService returns a PipelineService backed by repo.
+func NewPipelineService(repo repositories.RunRepository) PipelineService {
+	return &pipelineService{repo: repo}
+}
+
+func (s *pipelineService) Ingest(run models.Run) (models.Run, error) {
+	return s.repo.Upsert(run)
+}
+
+func (s *pipelineService) ListRuns(filter repositories.RunFilter, limit int) ([]models.Run, error) {
+	if err := validateFilter(filter); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultRunLimit
+	}
+	if limit > MaxRunLimit {
+		limit = MaxRunLimit
+	}
+
+	runs, err := s.repo.List(filter, limit)
+	if err != nil {
+		return nil, err
+	}
+	if runs == nil {
+		runs = []models.Run{}
+	}
+	return runs, nil
+}
+
+func (s *pipelineService) Stats(filter repositories.RunFilter, days int) (models.Stats, error) {
+	if err := validateFilter(filter); err != nil {
+		return models.Stats{}, err
+	}
+	since, err := windowStart(days)
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	counts, err := s.repo.CountOutcomes(filter, since)
+	if err != nil {
+		return models.Stats{}, err
+	}
+	trend, err := s.repo.DailyStats(filter, since)
+	if err != nil {
+		return models.Stats{}, err
+	}
+	flaky, err := s.repo.FlakyWorkflows(filter, since)
+	if err != nil {
+		return models.Stats{}, err
+	}
+	if trend == nil {
+		trend = []models.DailyStats{}
+	}
+	if flaky == nil {
+		flaky = []models.FlakyWorkflow{}
+	}
+
+	stats := models.Stats{
+		Since:     since,
+		Total:     counts.Succeeded + counts.Failed + counts.Cancelled,
+		Succeeded: counts.Succeeded,
+		Failed:    counts.Failed,
+		Cancelled: counts.Cancelled,
+		Trend:     trend,
+		Flaky:     flaky,
+	}
+	if decided := counts.Succeeded + counts.Failed; decided > 0 {
+		rate := math.Round(10000*float64(counts.Succeeded)/float64(decided)) / 100
+		stats.SuccessRate = &rate
+	}
+	return stats, nil
+}
+
+func (s *pipelineService) ListBranches(days int) ([]models.BranchSummary, error) {
+	since, err := windowStart(days)
+	if err != nil {
+		return nil, err
+	}
+	branches, err := s.repo.Branches(since)
+	if err != nil {
+		return nil, err
+	}
+	if branches == nil {
+		branches = []models.BranchSummary{}
+	}
+	return branches, nil
+}
+
+// PipelineStats counts runs that succeeded or failed since the given time;
+// cancelled runs are left out of the success rate.
+func (s *pipelineService) PipelineStats(ctx context.Context, since time.Time) (models.OutcomeStats, error) {
+	counts, err := s.repo.CountOutcomes(repositories.RunFilter{}, since)
+	if err != nil {
+		return models.OutcomeStats{}, err
+	}
+	return models.OutcomeStats{
+		Total:     counts.Succeeded + counts.Failed,
+		Succeeded: counts.Succeeded,
+	}, nil
+}
+
+func windowStart(days int) (time.Time, error) {
+	if days == 0 {
+		days = DefaultStatsDays
+	}
+	if days < 1 || days > MaxStatsDays {
+		return time.Time{}, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidFilter, MaxStatsDays)
+	}
+	return time.Now().UTC().AddDate(0, 0, -days), nil
+}
+
+func validateFilter(filter repositories.RunFilter) error {
+	switch filter.Status {
+	case "", models.StatusRunning, models.StatusSuccess, models.StatusFailure, models.StatusCancelled:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, filter.Status)
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/models"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrInvalidPayload   = errors.New("invalid webhook payload")
+)
+
+// VerifySignature checks a "sha256=<hex>" HMAC-SHA256 signature of body, the
+// format GitHub sends in X-Hub-Signature-256.
+func VerifySignature(secret, body []byte, signature string) error {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type githubWorkflowRunEvent struct {
+	Action      string `json:"action"`
+	WorkflowRun struct {
+		ID           int64      `json:"id"`
+		Name         string     `json:"name"`
+		HeadBranch   string     `json:"head_branch"`
+		HeadSHA      string     `json:"head_sha"`
+		Status       string     `json:"status"`
+		Conclusion   string     `json:"conclusion"`
+		RunAttempt   int        `json:"run_attempt"`
+		HTMLURL      string     `json:"html_url"`
+		CreatedAt    time.Time  `json:"created_at"`
+		RunStartedAt *time.Time `json:"run_started_at"`
+		UpdatedAt    time.Time  `json:"updated_at"`
+	} `json:"workflow_run"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// ParseGitHubWorkflowRun converts a GitHub Actions workflow_run webhook
+// payload into a run.
+func ParseGitHubWorkflowRun(body []byte) (models.Run, error) {
+	var event githubWorkflowRunEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return models.Run{}, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	wr := event.WorkflowRun
+	if wr.ID == 0 || event.Repository.FullName == "" {
+		return models.Run{}, fmt.Errorf("%w: missing workflow_run.id or repository.full_name", ErrInvalidPayload)
+	}
+
+	attempt := wr.RunAttempt
+	if attempt == 0 {
+		attempt = 1
+	}
+	startedAt := wr.CreatedAt
+	if wr.RunStartedAt != nil {
+		startedAt = *wr.RunStartedAt
+	}
+
+	run := models.Run{
+		Provider:   models.ProviderGitHub,
+		ExternalID: fmt.Sprintf("%d:%d", wr.ID, attempt),
+		Repository: event.Repository.FullName,
+		Branch:     wr.HeadBranch,
+		Workflow:   wr.Name,
+		CommitSHA:  wr.HeadSHA,
+		Attempt:    attempt,
+		Status:     models.StatusRunning,
+		URL:        wr.HTMLURL,
+		StartedAt:  startedAt,
+	}
+	if wr.Status == "completed" {
+		finishedAt := wr.UpdatedAt
+		run.FinishedAt = &finishedAt
+		run.Status = githubConclusionStatus(wr.Conclusion)
+	}
+	return run, nil
+}
+
+// githubConclusionStatus maps a workflow run conclusion onto a run status.
+// Conclusions that say nothing about the code, such as skipped or
+// action_required, are treated as cancelled so they do not skew success
+// rates.
+func githubConclusionStatus(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return models.StatusSuccess
+	case "failure", "timed_out", "startup_failure":
+		return models.StatusFailure
+	default:
+		return models.StatusCancelled
+	}
+}
+
+// GenericRunEvent is the payload accepted by the generic webhook, for CI
+// systems other than GitHub Actions.
+type GenericRunEvent struct {
+	ID         string     `json:"id"`
+	Repository string     `json:"repository"`
+	Branch     string     `json:"branch"`
+	Workflow   string     `json:"workflow"`
+	Commit     string     `json:"commit"`
+	Attempt    int        `json:"attempt"`
+	Status     string     `json:"status"`
+	URL        string     `json:"url"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
+// ParseGenericRun converts a generic webhook payload into a run.
+func ParseGenericRun(body []byte) (models.Run, error) {
+	var event GenericRunEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return models.Run{}, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if event.ID == "" || event.Repository == "" || event.Workflow == "" {
+		return models.Run{}, fmt.Errorf("%w: id, repository and workflow are required", ErrInvalidPayload)
+	}
+	if event.StartedAt.IsZero() {
+		return models.Run{}, fmt.Errorf("%w: startedAt is required", ErrInvalidPayload)
+	}
+
+	switch event.Status {
+	case models.StatusRunning:
+		if event.FinishedAt != nil {
+			return models.Run{}, fmt.Errorf("%w: running runs cannot have finishedAt", ErrInvalidPayload)
+		}
+	case models.StatusSuccess, models.StatusFailure, models.StatusCancelled:
+		if event.FinishedAt == nil {
+			return models.Run{}, fmt.Errorf("%w: finishedAt is required for status %q", ErrInvalidPayload, event.Status)
+		}
+		if event.FinishedAt.Before(event.StartedAt) {
+			return models.Run{}, fmt.Errorf("%w: finishedAt is before startedAt", ErrInvalidPayload)
+		}
+	default:
+		return models.Run{}, fmt.Errorf("%w: status must be running, success, failure or cancelled", ErrInvalidPayload)
+	}
+
+	attempt := event.Attempt
+	if attempt == 0 {
+		attempt = 1
+	}
+	return models.Run{
+		Provider:   models.ProviderGeneric,
+		ExternalID: fmt.Sprintf("%s:%d", event.ID, attempt),
+		Repository: event.Repository,
+		Branch:     event.Branch,
+		Workflow:   event.Workflow,
+		CommitSHA:  event.Commit,
+		Attempt:    attempt,
+		Status:     event.Status,
+		URL:        event.URL,
+		StartedAt:  event.StartedAt,
+		FinishedAt: event.FinishedAt,
+	}, nil
+}
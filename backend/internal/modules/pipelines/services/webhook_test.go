@@ -0,0 +1,307 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/pipelines/models"
+)
+
+func sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := []byte(`{"action":"completed"}`)
+	good := sign(secret, body)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		body      []byte
+		signature string
+		wantErr   error
+	}{
+		{name: "good", secret: secret, body: body, signature: good},
+		{name: "wrong secret", secret: []byte("other"), body: body, signature: good, wantErr: ErrInvalidSignature},
+		{name: "tampered body", secret: secret, body: []byte(`{"action":"requested"}`), signature: good, wantErr: ErrInvalidSignature},
+		{name: "missing prefix", secret: secret, body: body, signature: good[len("sha256="):], wantErr: ErrInvalidSignature},
+		{name: "sha1 prefix", secret: secret, body: body, signature: "sha1=" + good[len("sha256="):], wantErr: ErrInvalidSignature},
+		{name: "not hex", secret: secret, body: body, signature: "sha256=zz", wantErr: ErrInvalidSignature},
+		{name: "truncated", secret: secret, body: body, signature: good[:len(good)-2], wantErr: ErrInvalidSignature},
+		{name: "empty", secret: secret, body: body, signature: "", wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySignature(tt.secret, tt.body, tt.signature); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// workflowRun loads the recorded workflow_run delivery and applies edit to
+// its workflow_run object.
+func workflowRun(t *testing.T, edit func(run map[string]interface{})) []byte {
+	t.Helper()
+	body, err := os.ReadFile("testdata/workflow_run_completed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edit == nil {
+		return body
+	}
+	var event map[string]interface{}
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatal(err)
+	}
+	edit(event["workflow_run"].(map[string]interface{}))
+	body, err = json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestParseGitHubWorkflowRun(t *testing.T) {
+	created := time.Date(2020, 1, 22, 19, 33, 8, 0, time.UTC)
+	started := time.Date(2020, 1, 22, 19, 35, 2, 0, time.UTC)
+	updated := time.Date(2020, 1, 22, 19, 41, 17, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		edit    func(run map[string]interface{})
+		want    models.Run
+		wantErr error
+	}{
+		{
+			name: "completed",
+			want: models.Run{
+				Provider:   models.ProviderGitHub,
+				ExternalID: "30433642:2",
+				Repository: "octo-org/octo-repo",
+				Branch:     "main",
+				Workflow:   "Build",
+				CommitSHA:  "acb5820ced9479c074f688cc328bf03f341a511d",
+				Attempt:    2,
+				Status:     models.StatusFailure,
+				URL:        "https://github.com/octo-org/octo-repo/actions/runs/30433642",
+				StartedAt:  started,
+				FinishedAt: &updated,
+			},
+		},
+		{
+			name: "in progress",
+			edit: func(run map[string]interface{}) {
+				run["status"] = "in_progress"
+				run["conclusion"] = nil
+			},
+			want: models.Run{
+				Provider:   models.ProviderGitHub,
+				ExternalID: "30433642:2",
+				Repository: "octo-org/octo-repo",
+				Branch:     "main",
+				Workflow:   "Build",
+				CommitSHA:  "acb5820ced9479c074f688cc328bf03f341a511d",
+				Attempt:    2,
+				Status:     models.StatusRunning,
+				URL:        "https://github.com/octo-org/octo-repo/actions/runs/30433642",
+				StartedAt:  started,
+			},
+		},
+		{
+			// Deliveries from before run attempts and run_started_at existed.
+			name: "without attempt or start time",
+			edit: func(run map[string]interface{}) {
+				delete(run, "run_attempt")
+				delete(run, "run_started_at")
+				run["conclusion"] = "success"
+			},
+			want: models.Run{
+				Provider:   models.ProviderGitHub,
+				ExternalID: "30433642:1",
+				Repository: "octo-org/octo-repo",
+				Branch:     "main",
+				Workflow:   "Build",
+				CommitSHA:  "acb5820ced9479c074f688cc328bf03f341a511d",
+				Attempt:    1,
+				Status:     models.StatusSuccess,
+				URL:        "https://github.com/octo-org/octo-repo/actions/runs/30433642",
+				StartedAt:  created,
+				FinishedAt: &updated,
+			},
+		},
+		{
+			name:    "missing run ID",
+			edit:    func(run map[string]interface{}) { delete(run, "id") },
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "malformed time",
+			edit:    func(run map[string]interface{}) { run["created_at"] = "yesterday" },
+			wantErr: ErrInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGitHubWorkflowRun(workflowRun(t, tt.edit))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !sameRun(got, tt.want) {
+				t.Errorf("run = %+v\nwant  %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseGitHubWorkflowRun([]byte(`{"action":"completed","workflow_run":{"id":1}}`)); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("without a repository: err = %v, want ErrInvalidPayload", err)
+	}
+	if _, err := ParseGitHubWorkflowRun([]byte(`not json`)); !errors.Is(err, ErrInvalidPayload) {
+		t.Errorf("not JSON: err = %v, want ErrInvalidPayload", err)
+	}
+}
+
+// sameRun compares runs, treating equal instants as equal times.
+func sameRun(a, b models.Run) bool {
+	if (a.FinishedAt == nil) != (b.FinishedAt == nil) || (a.FinishedAt != nil && !a.FinishedAt.Equal(*b.FinishedAt)) {
+		return false
+	}
+	if !a.StartedAt.Equal(b.StartedAt) {
+		return false
+	}
+	a.StartedAt, b.StartedAt = time.Time{}, time.Time{}
+	a.FinishedAt, b.FinishedAt = nil, nil
+	return a == b
+}
+
+func TestGitHubConclusionStatus(t *testing.T) {
+	for conclusion, want := range map[string]string{
+		"success":         models.StatusSuccess,
+		"failure":         models.StatusFailure,
+		"timed_out":       models.StatusFailure,
+		"startup_failure": models.StatusFailure,
+		"cancelled":       models.StatusCancelled,
+		"skipped":         models.StatusCancelled,
+		"neutral":         models.StatusCancelled,
+		"action_required": models.StatusCancelled,
+		"stale":           models.StatusCancelled,
+		"":                models.StatusCancelled,
+	} {
+		if got := githubConclusionStatus(conclusion); got != want {
+			t.Errorf("githubConclusionStatus(%q) = %s, want %s", conclusion, got, want)
+		}
+	}
+}
+
+func TestParseGenericRun(t *testing.T) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	finished := started.Add(6*time.Minute + 30*time.Second)
+
+	tests := []struct {
+		name    string
+		body    string
+		want    models.Run
+		wantErr error
+	}{
+		{
+			name: "finished",
+			body: `{"id":"build-1234","repository":"org/service","branch":"main","workflow":"build","commit":"9fceb02","attempt":2,
+				"status":"success","url":"https://ci.example.com/builds/1234","startedAt":"2024-05-01T10:00:00Z","finishedAt":"2024-05-01T10:06:30Z"}`,
+			want: models.Run{
+				Provider:   models.ProviderGeneric,
+				ExternalID: "build-1234:2",
+				Repository: "org/service",
+				Branch:     "main",
+				Workflow:   "build",
+				CommitSHA:  "9fceb02",
+				Attempt:    2,
+				Status:     models.StatusSuccess,
+				URL:        "https://ci.example.com/builds/1234",
+				StartedAt:  started,
+				FinishedAt: &finished,
+			},
+		},
+		{
+			name: "running, first attempt by default",
+			body: `{"id":"build-1234","repository":"org/service","workflow":"build","status":"running","startedAt":"2024-05-01T10:00:00Z"}`,
+			want: models.Run{
+				Provider:   models.ProviderGeneric,
+				ExternalID: "build-1234:1",
+				Repository: "org/service",
+				Workflow:   "build",
+				Attempt:    1,
+				Status:     models.StatusRunning,
+				StartedAt:  started,
+			},
+		},
+		{
+			name:    "missing id",
+			body:    `{"repository":"org/service","workflow":"build","status":"running","startedAt":"2024-05-01T10:00:00Z"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "missing repository",
+			body:    `{"id":"b","workflow":"build","status":"running","startedAt":"2024-05-01T10:00:00Z"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "missing workflow",
+			body:    `{"id":"b","repository":"org/service","status":"running","startedAt":"2024-05-01T10:00:00Z"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "missing startedAt",
+			body:    `{"id":"b","repository":"org/service","workflow":"build","status":"running"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "running with finishedAt",
+			body:    `{"id":"b","repository":"org/service","workflow":"build","status":"running","startedAt":"2024-05-01T10:00:00Z","finishedAt":"2024-05-01T10:06:30Z"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "finished without finishedAt",
+			body:    `{"id":"b","repository":"org/service","workflow":"build","status":"failure","startedAt":"2024-05-01T10:00:00Z"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "finished before started",
+			body:    `{"id":"b","repository":"org/service","workflow":"build","status":"cancelled","startedAt":"2024-05-01T10:00:00Z","finishedAt":"2024-05-01T09:59:59Z"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "unknown status",
+			body:    `{"id":"b","repository":"org/service","workflow":"build","status":"passed","startedAt":"2024-05-01T10:00:00Z","finishedAt":"2024-05-01T10:06:30Z"}`,
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "malformed time",
+			body:    `{"id":"b","repository":"org/service","workflow":"build","status":"running","startedAt":"10:00"}`,
+			wantErr: ErrInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGenericRun([]byte(tt.body))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !sameRun(got, tt.want) {
+				t.Errorf("run = %+v\nwant  %+v", got, tt.want)
+			}
+		})
+	}
+}
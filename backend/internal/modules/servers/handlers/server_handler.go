@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fbisdevoptics/backend/internal/modules/servers/models"
+	"github.com/fbisdevoptics/backend/internal/modules/servers/repositories"
+	"github.com/fbisdevoptics/backend/internal/modules/servers/services"
+)
+
+const (
+	defaultUptimeHours = 24
+	// maxUptimeHours keeps uptime windows within the default heartbeat
+	// retention.
+	maxUptimeHours = 720
+)
+
+type ServerHandler struct {
+	service services.ServerService
+}
+
+func NewServerHandler(service services.ServerService) *ServerHandler {
+	return &ServerHandler{service: service}
+}
+
+type serverRequest struct {
+	Name                     string            `json:"name" binding:"required"`
+	Labels                   map[string]string `json:"labels"`
+	HeartbeatIntervalSeconds int               `json:"heartbeatIntervalSeconds"`
+}
+
+// ListServers returns every registered server with its status and uptime
+// over the last hours hours (default 24).
+func (h *ServerHandler) ListServers(c *gin.Context) {
+	window, ok := uptimeWindow(c)
+	if !ok {
+		return
+	}
+
+	servers, err := h.service.ListServers(window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status := c.Query("status"); status != "" {
+		filtered := []models.Server{}
+		for _, server := range servers {
+			if server.Status == status {
+				filtered = append(filtered, server)
+			}
+		}
+		servers = filtered
+	}
+
+	c.JSON(http.StatusOK, gin.H{"servers": servers})
+}
+
+// GetServer returns a single server with its status and uptime.
+func (h *ServerHandler) GetServer(c *gin.Context) {
+	window, ok := uptimeWindow(c)
+	if !ok {
+		return
+	}
+
+	server, err := h.service.GetServer(c.Param("id"), window)
+	if err != nil {
+		writeServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, server)
+}
+
+// RegisterServer adds a server and returns its agent token. The token is not
+// shown again.
+func (h *ServerHandler) RegisterServer(c *gin.Context) {
+	var req serverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	registration, err := h.service.RegisterServer(req.toModel(""))
+	if err != nil {
+		writeServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, registration)
+}
+
+// UpdateServer replaces a server's name, labels and heartbeat interval.
+func (h *ServerHandler) UpdateServer(c *gin.Context) {
+	var req serverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	server, err := h.service.UpdateServer(req.toModel(c.Param("id")))
+	if err != nil {
+		writeServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, server)
+}
+
+// DeleteServer removes a server and its heartbeat history.
+func (h *ServerHandler) DeleteServer(c *gin.Context) {
+	if err := h.service.DeleteServer(c.Param("id")); err != nil {
+		writeServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// RotateToken issues a new agent token for a server.
+func (h *ServerHandler) RotateToken(c *gin.Context) {
+	registration, err := h.service.RotateToken(c.Param("id"))
+	if err != nil {
+		writeServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, registration)
+}
+
+// Heartbeat records an agent check-in. Agents authenticate with their token
+// as a bearer token.
+func (h *ServerHandler) Heartbeat(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing agent token"})
+		return
+	}
+
+	var heartbeat models.Heartbeat
+	if err := c.ShouldBindJSON(&heartbeat); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	server, err := h.service.Heartbeat(token, heartbeat)
+	if err != nil {
+		writeServerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":                       server.ID,
+		"status":                   server.Status,
+		"heartbeatIntervalSeconds": server.HeartbeatIntervalSeconds,
+	})
+}
+
+func (r serverRequest) toModel(id string) models.Server {
+	return models.Server{
+		ID:                       id,
+		Name:                     r.Name,
+		Labels:                   r.Labels,
+		HeartbeatIntervalSeconds: r.HeartbeatIntervalSeconds,
+	}
+}
+
+func uptimeWindow(c *gin.Context) (time.Duration, bool) {
+	hours := defaultUptimeHours
+	if raw := c.Query("hours"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxUptimeHours {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hours must be between 1 and " + strconv.Itoa(maxUptimeHours)})
+			return 0, false
+		}
+		hours = parsed
+	}
+	return time.Duration(hours) * time.Hour, true
+}
+
+func writeServerError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repositories.ErrServerNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidToken):
+		status = http.StatusUnauthorized
+	case errors.Is(err, services.ErrInvalidServer), errors.Is(err, services.ErrInvalidHeartbeat):
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Server statuses. A server is pending until its first heartbeat and
+// inactive once it misses enough heartbeats in a row.
+const (
+	StatusPending  = "pending"
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+)
+
+// Server is a host that reports through an agent heartbeat.
+type Server struct {
+	ID                       string            `json:"id"`
+	Name                     string            `json:"name"`
+	Labels                   map[string]string `json:"labels"`
+	HeartbeatIntervalSeconds int               `json:"heartbeatIntervalSeconds"`
+	Hostname                 string            `json:"hostname,omitempty"`
+	OS                       string            `json:"os,omitempty"`
+	Load                     *LoadAverage      `json:"load,omitempty"`
+	Disks                    []DiskUsage       `json:"disks"`
+	LastHeartbeatAt          *time.Time        `json:"lastHeartbeatAt,omitempty"`
+	Status                   string            `json:"status"`
+	UptimePercent            *float64          `json:"uptimePercent,omitempty"`
+	CreatedAt                time.Time         `json:"createdAt"`
+	UpdatedAt                time.Time         `json:"updatedAt"`
+}
+
+// LoadAverage is the 1, 5 and 15 minute system load.
+type LoadAverage struct {
+	One     float64 `json:"one"`
+	Five    float64 `json:"five"`
+	Fifteen float64 `json:"fifteen"`
+}
+
+// DiskUsage describes one mounted filesystem.
+type DiskUsage struct {
+	Mount       string  `json:"mount"`
+	TotalBytes  uint64  `json:"totalBytes"`
+	UsedBytes   uint64  `json:"usedBytes"`
+	UsedPercent float64 `json:"usedPercent"`
+}
+
+// FleetStats describes registered servers. UptimePercent is nil when no
+// server has reported since the start of the window.
+type FleetStats struct {
+	Active        int
+	Total         int
+	UptimePercent *float64
+}
+
+// Heartbeat is what an agent reports on each check-in.
+type Heartbeat struct {
+	Hostname string      `json:"hostname"`
+	OS       string      `json:"os"`
+	Load     LoadAverage `json:"load"`
+	Disks    []DiskUsage `json:"disks"`
+}
+
+// Registration is returned once when a server is registered or its agent
+// token is rotated. Only a hash of the token is stored.
+type Registration struct {
+	Server     Server `json:"server"`
+	AgentToken string `json:"agentToken"`
+}
@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/servers/models"
+)
+
+var ErrServerNotFound = errors.New("server not found")
+
+// HeartbeatCoverage summarises a server's heartbeats within a window, for
+// uptime calculation. CoveredSeconds sums min(gap, threshold) over
+// consecutive heartbeats inside the window.
+type HeartbeatCoverage struct {
+	ServerID       string
+	Before         *time.Time
+	First          *time.Time
+	Last           *time.Time
+	CoveredSeconds float64
+}
+
+// ServerRepository persists servers and their heartbeat history.
+type ServerRepository interface {
+	Create(server models.Server, tokenHash string) (models.Server, error)
+	GetByID(id string) (models.Server, error)
+	GetByTokenHash(tokenHash string) (models.Server, error)
+	List() ([]models.Server, error)
+	Update(server models.Server) (models.Server, error)
+	SetTokenHash(id, tokenHash string) error
+	Delete(id string) error
+
+	// RecordHeartbeat stores the latest agent report on the server and
+	// appends to its heartbeat history.
+	RecordHeartbeat(id string, heartbeat models.Heartbeat, at time.Time) (models.Server, error)
+	// Coverage returns heartbeat coverage since the given time for every
+	// server. Each heartbeat covers up to missedHeartbeats times the server's
+	// heartbeat interval.
+	Coverage(since time.Time, missedHeartbeats int) ([]HeartbeatCoverage, error)
+	DeleteHeartbeatsBefore(before time.Time) (int64, error)
+}
+
+type serverRepository struct {
+	db *sql.DB
+}
+
+func NewServerRepository(db *sql.DB) ServerRepository {
+	return &serverRepository{db: db}
+}
+
+const serverColumns = `id, name, labels, heartbeat_interval_seconds, hostname, os, load, disks,
+	last_heartbeat_at, created_at, updated_at`
+
+func (r *serverRepository) Create(server models.Server, tokenHash string) (models.Server, error) {
+	labels, err := json.Marshal(server.Labels)
+	if err != nil {
+		return models.Server{}, err
+	}
+
+	row := r.db.QueryRow(
+		`INSERT INTO servers (name, labels, heartbeat_interval_seconds, agent_token_hash)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING `+serverColumns,
+		server.Name, labels, server.HeartbeatIntervalSeconds, tokenHash,
+	)
+	return scanServer(row)
+}
+
+func (r *serverRepository) GetByID(id string) (models.Server, error) {
+	row := r.db.QueryRow(`SELECT `+serverColumns+` FROM servers WHERE id = $1`, id)
+	return scanServer(row)
+}
+
+func (r *serverRepository) GetByTokenHash(tokenHash string) (models.Server, error) {
+	row := r.db.QueryRow(`SELECT `+serverColumns+` FROM servers WHERE agent_token_hash = $1`, tokenHash)
+	return scanServer(row)
+}
+
+func (r *serverRepository) List() ([]models.Server, error) {
+	rows, err := r.db.Query(`SELECT ` + serverColumns + ` FROM servers ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var servers []models.Server
+	for rows.Next() {
+		server, err := scanServer(rows)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, server)
+	}
+	return servers, rows.Err()
+}
+
+func (r *serverRepository) Update(server models.Server) (models.Server, error) {
+	labels, err := json.Marshal(server.Labels)
+	if err != nil {
+		return models.Server{}, err
+	}
+
+	row := r.db.QueryRow(
+		`UPDATE servers
+		 SET name = $2, labels = $3, heartbeat_interval_seconds = $4, updated_at = NOW()
+		 WHERE id = $1
+		 RETURNING `+serverColumns,
+		server.ID, server.Name, labels, server.HeartbeatIntervalSeconds,
+	)
+	return scanServer(row)
+}
+
+func (r *serverRepository) SetTokenHash(id, tokenHash string) error {
+	res, err := r.db.Exec(
+		`UPDATE servers SET agent_token_hash = $2, updated_at = NOW() WHERE id = $1`,
+		id, tokenHash,
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrServerNotFound
+	}
+	return nil
+}
+
+func (r *serverRepository) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM servers WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrServerNotFound
+	}
+	return nil
+}
+
+func (r *serverRepository) RecordHeartbeat(id string, heartbeat models.Heartbeat, at time.Time) (models.Server, error) {
+	load, err := json.Marshal(heartbeat.Load)
+	if err != nil {
+		return models.Server{}, err
+	}
+	disks, err := json.Marshal(heartbeat.Disks)
+	if err != nil {
+		return models.Server{}, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Server{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(
+		`UPDATE servers
+		 SET hostname = $2, os = $3, load = $4, disks = $5, last_heartbeat_at = $6
+		 WHERE id = $1
+		 RETURNING `+serverColumns,
+		id, heartbeat.Hostname, heartbeat.OS, load, disks, at,
+	)
+	server, err := scanServer(row)
+	if err != nil {
+		return models.Server{}, err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO server_heartbeats (server_id, received_at, load1) VALUES ($1, $2, $3)`,
+		id, at, heartbeat.Load.One,
+	); err != nil {
+		return models.Server{}, err
+	}
+	return server, tx.Commit()
+}
+
+func (r *serverRepository) Coverage(since time.Time, missedHeartbeats int) ([]HeartbeatCoverage, error) {
+	rows, err := r.db.Query(
+		`SELECT s.id,
+		        (SELECT MAX(received_at) FROM server_heartbeats b
+		         WHERE b.server_id = s.id AND b.received_at < $1),
+		        MIN(g.received_at),
+		        MAX(g.received_at),
+		        COALESCE(SUM(LEAST(EXTRACT(EPOCH FROM g.gap), s.heartbeat_interval_seconds * $2))
+		                 FILTER (WHERE g.gap IS NOT NULL), 0)
+		 FROM servers s
+		 LEFT JOIN (
+		   SELECT server_id, received_at,
+		          received_at - LAG(received_at) OVER (PARTITION BY server_id ORDER BY received_at) AS gap
+		   FROM server_heartbeats
+		   WHERE received_at >= $1
+		 ) g ON g.server_id = s.id
+		 GROUP BY s.id`,
+		since, missedHeartbeats,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coverage []HeartbeatCoverage
+	for rows.Next() {
+		var c HeartbeatCoverage
+		var before, first, last sql.NullTime
+		if err := rows.Scan(&c.ServerID, &before, &first, &last, &c.CoveredSeconds); err != nil {
+			return nil, err
+		}
+		c.Before = nullTimePtr(before)
+		c.First = nullTimePtr(first)
+		c.Last = nullTimePtr(last)
+		coverage = append(coverage, c)
+	}
+	return coverage, rows.Err()
+}
+
+func (r *serverRepository) DeleteHeartbeatsBefore(before time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM server_heartbeats WHERE received_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanServer(row rowScanner) (models.Server, error) {
+	var server models.Server
+	var labels, load, disks []byte
+	var lastHeartbeat sql.NullTime
+	if err := row.Scan(
+		&server.ID, &server.Name, &labels, &server.HeartbeatIntervalSeconds, &server.Hostname,
+		&server.OS, &load, &disks, &lastHeartbeat, &server.CreatedAt, &server.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Server{}, ErrServerNotFound
+		}
+		return models.Server{}, err
+	}
+	server.LastHeartbeatAt = nullTimePtr(lastHeartbeat)
+	if err := json.Unmarshal(labels, &server.Labels); err != nil {
+		return models.Server{}, err
+	}
+	if load != nil {
+		server.Load = &models.LoadAverage{}
+		if err := json.Unmarshal(load, server.Load); err != nil {
+			return models.Server{}, err
+		}
+	}
+	server.Disks = []models.DiskUsage{}
+	if disks != nil {
+		if err := json.Unmarshal(disks, &server.Disks); err != nil {
+			return models.Server{}, err
+		}
+	}
+	return server, nil
+}
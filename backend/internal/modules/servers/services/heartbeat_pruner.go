@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fbisdevoptics/backend/internal/modules/servers/repositories"
+)
+
+// HeartbeatPruner deletes heartbeat history older than the retention period.
+type HeartbeatPruner struct {
+	repo      repositories.ServerRepository
+	retention time.Duration
+	logger    *zap.Logger
+}
+
+// NewHeartbeatPruner returns a pruner; a zero retention keeps history
+// forever.
+func NewHeartbeatPruner(repo repositories.ServerRepository, retention time.Duration, logger *zap.Logger) *HeartbeatPruner {
+	return &HeartbeatPruner{repo: repo, retention: retention, logger: logger}
+}
+
+// Run prunes hourly until ctx is cancelled. Every replica may prune; the
+// delete is idempotent.
+func (p *HeartbeatPruner) Run(ctx context.Context) {
+	if p.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		removed, err := p.repo.DeleteHeartbeatsBefore(time.Now().Add(-p.retention))
+		if err != nil {
+			p.logger.Warn("failed to prune server heartbeats", zap.Error(err))
+		} else if removed > 0 {
+			p.logger.Info("pruned server heartbeats", zap.Int64("removed", removed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
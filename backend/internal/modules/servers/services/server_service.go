@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/servers/models"
+	"github.com/fbisdevoptics/backend/internal/modules/servers/repositories"
+)
+
+var (
+	ErrInvalidServer    = errors.New("invalid server")
+	ErrInvalidHeartbeat = errors.New("invalid heartbeat")
+	ErrInvalidToken     = errors.New("invalid agent token")
+)
+
+const (
+	DefaultHeartbeatIntervalSeconds = 30
+	MinHeartbeatIntervalSeconds     = 5
+	MaxHeartbeatIntervalSeconds     = 3600
+)
+
+// ServerService manages the server inventory and agent heartbeats.
+type ServerService interface {
+	ListServers(window time.Duration) ([]models.Server, error)
+	GetServer(id string, window time.Duration) (models.Server, error)
+	RegisterServer(server models.Server) (models.Registration, error)
+	UpdateServer(server models.Server) (models.Server, error)
+	DeleteServer(id string) error
+	RotateToken(id string) (models.Registration, error)
+
+	// Heartbeat records a report from the agent holding token.
+	Heartbeat(token string, heartbeat models.Heartbeat) (models.Server, error)
+
+	// ServerStats feeds the dashboard summary.
+	ServerStats(ctx context.Context, since time.Time) (models.FleetStats, error)
+}
+
+type serverService struct {
+	repo             repositories.ServerRepository
+	missedHeartbeats int
+	now              func() time.Time
+}
+
+// NewServerService returns a ServerService backed by repo. A server becomes
+// inactive after missedHeartbeats consecutive heartbeats fail to arrive.
+func NewServerService(repo repositories.ServerRepository, missedHeartbeats int) ServerService {
+	if missedHeartbeats < 1 {
+		missedHeartbeats = 1
+	}
+	return &serverService{repo: repo, missedHeartbeats: missedHeartbeats, now: time.Now}
+}
+
+func (s *serverService) ListServers(window time.Duration) ([]models.Server, error) {
+	servers, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+	if servers == nil {
+		servers = []models.Server{}
+	}
+
+	now := s.now()
+	uptime, err := s.uptimes(servers, now.Add(-window), now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range servers {
+		s.decorate(&servers[i], now)
+		servers[i].UptimePercent = uptime[servers[i].ID]
+	}
+	return servers, nil
+}
+
+func (s *serverService) GetServer(id string, window time.Duration) (models.Server, error) {
+	server, err := s.repo.GetByID(id)
+	if err != nil {
+		return models.Server{}, err
+	}
+
+	now := s.now()
+	uptime, err := s.uptimes([]models.Server{server}, now.Add(-window), now)
+	if err != nil {
+		return models.Server{}, err
+	}
+	s.decorate(&server, now)
+	server.UptimePercent = uptime[server.ID]
+	return server, nil
+}
+
+func (s *serverService) RegisterServer(server models.Server) (models.Registration, error) {
+	server = normalizeServer(server)
+	if err := validateServer(server); err != nil {
+		return models.Registration{}, err
+	}
+
+	token, hash, err := newAgentToken()
+	if err != nil {
+		return models.Registration{}, err
+	}
+	created, err := s.repo.Create(server, hash)
+	if err != nil {
+		return models.Registration{}, err
+	}
+	s.decorate(&created, s.now())
+	return models.Registration{Server: created, AgentToken: token}, nil
+}
+
+func (s *serverService) UpdateServer(server models.Server) (models.Server, error) {
+	server = normalizeServer(server)
+	if err := validateServer(server); err != nil {
+		return models.Server{}, err
+	}
+	updated, err := s.repo.Update(server)
+	if err != nil {
+		return models.Server{}, err
+	}
+	s.decorate(&updated, s.now())
+	return updated, nil
+}
+
+func (s *serverService) DeleteServer(id string) error {
+	return s.repo.Delete(id)
+}
+
+// RotateToken issues a new agent token; the previous one stops working
+// immediately.
+func (s *serverService) RotateToken(id string) (models.Registration, error) {
+	token, hash, err := newAgentToken()
+	if err != nil {
+		return models.Registration{}, err
+	}
+	if err := s.repo.SetTokenHash(id, hash); err != nil {
+		return models.Registration{}, err
+	}
+	server, err := s.repo.GetByID(id)
+	if err != nil {
+		return models.Registration{}, err
+	}
+	s.decorate(&server, s.now())
+	return models.Registration{Server: server, AgentToken: token}, nil
+}
+
+func (s *serverService) Heartbeat(token string, heartbeat models.Heartbeat) (models.Server, error) {
+	if token == "" {
+		return models.Server{}, ErrInvalidToken
+	}
+	server, err := s.repo.GetByTokenHash(hashToken(token))
+	if errors.Is(err, repositories.ErrServerNotFound) {
+		return models.Server{}, ErrInvalidToken
+	}
+	if err != nil {
+		return models.Server{}, err
+	}
+
+	heartbeat, err = normalizeHeartbeat(heartbeat)
+	if err != nil {
+		return models.Server{}, err
+	}
+
+	now := s.now()
+	updated, err := s.repo.RecordHeartbeat(server.ID, heartbeat, now)
+	if err != nil {
+		return models.Server{}, err
+	}
+	s.decorate(&updated, now)
+	return updated, nil
+}
+
+// ServerStats reports how many servers are active and their mean uptime
+// since the given time. Servers that have never reported are counted in the
+// total but left out of the uptime average.
+func (s *serverService) ServerStats(ctx context.Context, since time.Time) (models.FleetStats, error) {
+	servers, err := s.repo.List()
+	if err != nil {
+		return models.FleetStats{}, err
+	}
+
+	now := s.now()
+	uptime, err := s.uptimes(servers, since, now)
+	if err != nil {
+		return models.FleetStats{}, err
+	}
+
+	stats := models.FleetStats{Total: len(servers)}
+	var sum float64
+	var reported int
+	for _, server := range servers {
+		s.decorate(&server, now)
+		if server.Status == models.StatusActive {
+			stats.Active++
+		}
+		if value := uptime[server.ID]; value != nil {
+			sum += *value
+			reported++
+		}
+	}
+	if reported > 0 {
+		mean := round2(sum / float64(reported))
+		stats.UptimePercent = &mean
+	}
+	return stats, nil
+}
+
+// decorate fills in the fields derived from the last heartbeat.
+func (s *serverService) decorate(server *models.Server, now time.Time) {
+	switch {
+	case server.LastHeartbeatAt == nil:
+		server.Status = models.StatusPending
+	case now.Sub(*server.LastHeartbeatAt) > s.threshold(*server):
+		server.Status = models.StatusInactive
+	default:
+		server.Status = models.StatusActive
+	}
+}
+
+// threshold is how long a server counts as up after a heartbeat.
+func (s *serverService) threshold(server models.Server) time.Duration {
+	return time.Duration(server.HeartbeatIntervalSeconds*s.missedHeartbeats) * time.Second
+}
+
+// uptimes returns each of servers' uptime percentage over [since, now]. A
+// server counts as up for one threshold after every heartbeat, so uptime
+// agrees with when the server would have been shown as active. The window
+// starts at registration for servers registered after since; servers with no
+// window or no heartbeats at all get no value.
+func (s *serverService) uptimes(servers []models.Server, since, now time.Time) (map[string]*float64, error) {
+	coverage, err := s.repo.Coverage(since, s.missedHeartbeats)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]repositories.HeartbeatCoverage, len(coverage))
+	for _, c := range coverage {
+		byID[c.ServerID] = c
+	}
+
+	uptime := make(map[string]*float64, len(servers))
+	for _, server := range servers {
+		c := byID[server.ID]
+		if c.First == nil && c.Before == nil {
+			continue
+		}
+
+		start := since
+		if server.CreatedAt.After(start) {
+			start = server.CreatedAt
+		}
+		window := now.Sub(start).Seconds()
+		if window <= 0 {
+			continue
+		}
+		threshold := s.threshold(server).Seconds()
+
+		covered := c.CoveredSeconds
+		if c.Before != nil {
+			// The last heartbeat before the window may still cover its start.
+			end := c.Before.Add(s.threshold(server))
+			if c.First != nil && c.First.Before(end) {
+				end = *c.First
+			}
+			covered += math.Max(0, end.Sub(start).Seconds())
+		}
+		if c.Last != nil {
+			covered += math.Min(now.Sub(*c.Last).Seconds(), threshold)
+		}
+
+		value := round2(100 * math.Min(covered, window) / window)
+		uptime[server.ID] = &value
+	}
+	return uptime, nil
+}
+
+func normalizeServer(server models.Server) models.Server {
+	server.Name = strings.TrimSpace(server.Name)
+	if server.HeartbeatIntervalSeconds == 0 {
+		server.HeartbeatIntervalSeconds = DefaultHeartbeatIntervalSeconds
+	}
+	if server.Labels == nil {
+		server.Labels = map[string]string{}
+	}
+	return server
+}
+
+func validateServer(server models.Server) error {
+	if server.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidServer)
+	}
+	if server.HeartbeatIntervalSeconds < MinHeartbeatIntervalSeconds ||
+		server.HeartbeatIntervalSeconds > MaxHeartbeatIntervalSeconds {
+		return fmt.Errorf("%w: heartbeatIntervalSeconds must be between %d and %d",
+			ErrInvalidServer, MinHeartbeatIntervalSeconds, MaxHeartbeatIntervalSeconds)
+	}
+	return nil
+}
+
+func normalizeHeartbeat(heartbeat models.Heartbeat) (models.Heartbeat, error) {
+	heartbeat.Hostname = strings.TrimSpace(heartbeat.Hostname)
+	if heartbeat.Hostname == "" {
+		return models.Heartbeat{}, fmt.Errorf("%w: hostname is required", ErrInvalidHeartbeat)
+	}
+	load := heartbeat.Load
+	if load.One < 0 || load.Five < 0 || load.Fifteen < 0 {
+		return models.Heartbeat{}, fmt.Errorf("%w: load averages cannot be negative", ErrInvalidHeartbeat)
+	}
+
+	if heartbeat.Disks == nil {
+		heartbeat.Disks = []models.DiskUsage{}
+	}
+	for i, disk := range heartbeat.Disks {
+		if disk.Mount == "" {
+			return models.Heartbeat{}, fmt.Errorf("%w: disk mount is required", ErrInvalidHeartbeat)
+		}
+		if disk.UsedBytes > disk.TotalBytes {
+			return models.Heartbeat{}, fmt.Errorf("%w: disk %s uses more than its total", ErrInvalidHeartbeat, disk.Mount)
+		}
+		// Derive the percentage rather than trusting the agent's.
+		if disk.TotalBytes > 0 {
+			heartbeat.Disks[i].UsedPercent = round2(100 * float64(disk.UsedBytes) / float64(disk.TotalBytes))
+		}
+	}
+	return heartbeat, nil
+}
+
+// newAgentToken returns a random agent token and the hash stored for it.
+func newAgentToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
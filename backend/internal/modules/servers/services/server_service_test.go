@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/fbisdevoptics/backend/internal/modules/servers/models"
+	"github.com/fbisdevoptics/backend/internal/modules/servers/repositories"
+)
+
+// memServers is an in-memory ServerRepository. Coverage follows the same
+// rules as the SQL query it stands in for.
+type memServers struct {
+	servers    []models.Server
+	tokens     map[string]string // server ID by token hash
+	heartbeats map[string][]time.Time
+}
+
+func newMemServers() *memServers {
+	return &memServers{tokens: map[string]string{}, heartbeats: map[string][]time.Time{}}
+}
+
+func (r *memServers) Create(server models.Server, tokenHash string) (models.Server, error) {
+	server.ID = fmt.Sprintf("server-%d", len(r.servers)+1)
+	r.servers = append(r.servers, server)
+	r.tokens[tokenHash] = server.ID
+	return server, nil
+}
+
+func (r *memServers) GetByID(id string) (models.Server, error) {
+	for _, server := range r.servers {
+		if server.ID == id {
+			return server, nil
+		}
+	}
+	return models.Server{}, repositories.ErrServerNotFound
+}
+
+func (r *memServers) GetByTokenHash(tokenHash string) (models.Server, error) {
+	id, ok := r.tokens[tokenHash]
+	if !ok {
+		return models.Server{}, repositories.ErrServerNotFound
+	}
+	return r.GetByID(id)
+}
+
+func (r *memServers) List() ([]models.Server, error) {
+	return append([]models.Server(nil), r.servers...), nil
+}
+
+func (r *memServers) Update(server models.Server) (models.Server, error) {
+	for i := range r.servers {
+		if r.servers[i].ID == server.ID {
+			r.servers[i].Name = server.Name
+			r.servers[i].Labels = server.Labels
+			r.servers[i].HeartbeatIntervalSeconds = server.HeartbeatIntervalSeconds
+			return r.servers[i], nil
+		}
+	}
+	return models.Server{}, repositories.ErrServerNotFound
+}
+
+func (r *memServers) SetTokenHash(id, tokenHash string) error {
+	for hash, owner := range r.tokens {
+		if owner == id {
+			delete(r.tokens, hash)
+		}
+	}
+	r.tokens[tokenHash] = id
+	return nil
+}
+
+func (r *memServers) Delete(id string) error {
+	for i := range r.servers {
+		if r.servers[i].ID == id {
+			r.servers = append(r.servers[:i], r.servers[i+1:]...)
+			return nil
+		}
+	}
+	return repositories.ErrServerNotFound
+}
+
+func (r *memServers) RecordHeartbeat(id string, heartbeat models.Heartbeat, at time.Time) (models.Server, error) {
+	for i := range r.servers {
+		if r.servers[i].ID == id {
+			load := heartbeat.Load
+			r.servers[i].Hostname = heartbeat.Hostname
+			r.servers[i].OS = heartbeat.OS
+			r.servers[i].Load = &load
+			r.servers[i].Disks = heartbeat.Disks
+			r.servers[i].LastHeartbeatAt = &at
+			r.heartbeats[id] = append(r.heartbeats[id], at)
+			return r.servers[i], nil
+		}
+	}
+	return models.Server{}, repositories.ErrServerNotFound
+}
+
+func (r *memServers) Coverage(since time.Time, missedHeartbeats int) ([]repositories.HeartbeatCoverage, error) {
+	var coverage []repositories.HeartbeatCoverage
+	for _, server := range r.servers {
+		c := repositories.HeartbeatCoverage{ServerID: server.ID}
+		threshold := float64(server.HeartbeatIntervalSeconds * missedHeartbeats)
+		beats := append([]time.Time(nil), r.heartbeats[server.ID]...)
+		sort.Slice(beats, func(i, j int) bool { return beats[i].Before(beats[j]) })
+		for i := range beats {
+			at := beats[i]
+			if at.Before(since) {
+				c.Before = &at
+				continue
+			}
+			if c.First == nil {
+				c.First = &at
+			} else {
+				c.CoveredSeconds += math.Min(at.Sub(*c.Last).Seconds(), threshold)
+			}
+			c.Last = &at
+		}
+		coverage = append(coverage, c)
+	}
+	return coverage, nil
+}
+
+func (r *memServers) DeleteHeartbeatsBefore(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// every returns heartbeat times from start up to and including end, step
+// apart.
+func every(start, end time.Time, step time.Duration) []time.Time {
+	var times []time.Time
+	for at := start; !at.After(end); at = at.Add(step) {
+		times = append(times, at)
+	}
+	return times
+}
+
+func TestServerUptime(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+	longAgo := now.Add(-30 * 24 * time.Hour)
+	minute := time.Minute
+
+	tests := []struct {
+		name       string
+		createdAt  time.Time
+		heartbeats []time.Time
+		wantUptime *float64
+		wantStatus string
+	}{
+		{
+			name:       "every heartbeat arrives",
+			createdAt:  longAgo,
+			heartbeats: every(since, now.Add(-minute), minute),
+			wantUptime: percent(100),
+			wantStatus: models.StatusActive,
+		},
+		{
+			// The heartbeat 30s before the window covers its first 30s, up
+			// to the first heartbeat inside it.
+			name:       "heartbeat before the window covers its start",
+			createdAt:  longAgo,
+			heartbeats: every(since.Add(-30*time.Second), now.Add(-30*time.Second), minute),
+			wantUptime: percent(100),
+			wantStatus: models.StatusActive,
+		},
+		{
+			// Covered for the threshold after the last heartbeat, which
+			// arrived before the window.
+			name:       "only a heartbeat before the window",
+			createdAt:  longAgo,
+			heartbeats: []time.Time{since.Add(-minute)},
+			wantUptime: percent(1.67),
+			wantStatus: models.StatusInactive,
+		},
+		{
+			// A 30 minute gap counts for only the two minute threshold:
+			// 600s, then 120s of the gap, then 1140s and the 60s since the
+			// last heartbeat, of 3600s.
+			name:      "gap longer than the threshold",
+			createdAt: longAgo,
+			heartbeats: append(
+				every(since, since.Add(10*minute), minute),
+				every(since.Add(40*minute), now.Add(-minute), minute)...,
+			),
+			wantUptime: percent(53.33),
+			wantStatus: models.StatusActive,
+		},
+		{
+			name:       "registered inside the window",
+			createdAt:  now.Add(-30 * minute),
+			heartbeats: every(now.Add(-30*minute), now.Add(-minute), minute),
+			wantUptime: percent(100),
+			wantStatus: models.StatusActive,
+		},
+		{
+			name:       "stopped reporting",
+			createdAt:  longAgo,
+			heartbeats: every(since, now.Add(-30*minute), minute),
+			wantUptime: percent(53.33),
+			wantStatus: models.StatusInactive,
+		},
+		{
+			name:       "never reported",
+			createdAt:  now.Add(-10 * minute),
+			wantStatus: models.StatusPending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemServers()
+			server, _ := repo.Create(models.Server{Name: "db-1", HeartbeatIntervalSeconds: 60, CreatedAt: tt.createdAt}, "hash")
+			for _, at := range tt.heartbeats {
+				if _, err := repo.RecordHeartbeat(server.ID, models.Heartbeat{Hostname: "db-1"}, at); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s := NewServerService(repo, 2).(*serverService)
+			s.now = func() time.Time { return now }
+
+			got, err := s.GetServer(server.ID, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if !samePercent(got.UptimePercent, tt.wantUptime) {
+				t.Errorf("uptime = %s, want %s", formatPercent(got.UptimePercent), formatPercent(tt.wantUptime))
+			}
+
+			listed, err := s.ListServers(time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(listed) != 1 || listed[0].Status != got.Status || !samePercent(listed[0].UptimePercent, got.UptimePercent) {
+				t.Errorf("ListServers = %+v, want the same as GetServer", listed)
+			}
+		})
+	}
+}
+
+func percent(v float64) *float64 { return &v }
+
+func samePercent(a, b *float64) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+func formatPercent(v *float64) string {
+	if v == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%.2f%%", *v)
+}
+
+func TestServerStats(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+	repo := newMemServers()
+	up, _ := repo.Create(models.Server{Name: "up", HeartbeatIntervalSeconds: 60, CreatedAt: since}, "up")
+	down, _ := repo.Create(models.Server{Name: "down", HeartbeatIntervalSeconds: 60, CreatedAt: since}, "down")
+	repo.Create(models.Server{Name: "new", HeartbeatIntervalSeconds: 60, CreatedAt: now}, "new")
+	for _, at := range every(since, now.Add(-time.Minute), time.Minute) {
+		repo.RecordHeartbeat(up.ID, models.Heartbeat{Hostname: "up"}, at)
+	}
+	repo.RecordHeartbeat(down.ID, models.Heartbeat{Hostname: "down"}, since)
+
+	s := NewServerService(repo, 2).(*serverService)
+	s.now = func() time.Time { return now }
+	stats, err := s.ServerStats(context.Background(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server that never reported counts in the total only: (100 +
+	// 3.33) / 2.
+	if stats.Total != 3 || stats.Active != 1 || !samePercent(stats.UptimePercent, percent(51.67)) {
+		t.Errorf("stats = %+v, uptime %s", stats, formatPercent(stats.UptimePercent))
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	repo := newMemServers()
+	s := NewServerService(repo, 2).(*serverService)
+	s.now = func() time.Time { return now }
+	registration, err := s.RegisterServer(models.Server{Name: " db-1 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registration.Server.Status != models.StatusPending || registration.Server.HeartbeatIntervalSeconds != DefaultHeartbeatIntervalSeconds {
+		t.Errorf("registered %+v", registration.Server)
+	}
+	token := registration.AgentToken
+	if _, ok := repo.tokens[token]; ok {
+		t.Error("the agent token was stored in the clear")
+	}
+
+	valid := models.Heartbeat{Hostname: " db-1.internal ", Disks: []models.DiskUsage{{Mount: "/", TotalBytes: 200, UsedBytes: 50, UsedPercent: 99}}}
+	tests := []struct {
+		name      string
+		token     string
+		heartbeat models.Heartbeat
+		wantErr   error
+	}{
+		{name: "no token", heartbeat: valid, wantErr: ErrInvalidToken},
+		{name: "unknown token", token: "not-a-token", heartbeat: valid, wantErr: ErrInvalidToken},
+		{name: "token hash presented as the token", token: hashToken(token), heartbeat: valid, wantErr: ErrInvalidToken},
+		{name: "no hostname", token: token, heartbeat: models.Heartbeat{Hostname: "  "}, wantErr: ErrInvalidHeartbeat},
+		{name: "negative load", token: token, heartbeat: models.Heartbeat{Hostname: "h", Load: models.LoadAverage{Five: -1}}, wantErr: ErrInvalidHeartbeat},
+		{name: "disk without mount", token: token, heartbeat: models.Heartbeat{Hostname: "h", Disks: []models.DiskUsage{{TotalBytes: 1}}}, wantErr: ErrInvalidHeartbeat},
+		{name: "disk over full", token: token, heartbeat: models.Heartbeat{Hostname: "h", Disks: []models.DiskUsage{{Mount: "/", TotalBytes: 1, UsedBytes: 2}}}, wantErr: ErrInvalidHeartbeat},
+		{name: "valid", token: token, heartbeat: valid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := s.Heartbeat(tt.token, tt.heartbeat)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if server.Status != models.StatusActive || server.LastHeartbeatAt == nil || !server.LastHeartbeatAt.Equal(now) {
+				t.Errorf("server = %+v, want active as of now", server)
+			}
+			// The hostname is trimmed and the disk percentage derived, not
+			// taken from the agent.
+			if server.Hostname != "db-1.internal" || server.Disks[0].UsedPercent != 25 {
+				t.Errorf("stored heartbeat %q %+v", server.Hostname, server.Disks)
+			}
+		})
+	}
+
+	rotated, err := s.RotateToken(registration.Server.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Heartbeat(token, valid); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("rotated-out token: err = %v, want ErrInvalidToken", err)
+	}
+	if _, err := s.Heartbeat(rotated.AgentToken, valid); err != nil {
+		t.Errorf("new token: %v", err)
+	}
+}
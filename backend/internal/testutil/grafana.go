@@ -0,0 +1,125 @@
+package testutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// GrafanaStandIn is an in-memory stand-in for the Grafana dashboard API. It
+// serves POST /api/dashboards/db and GET /api/dashboards/uid/<uid>,
+// versioning each save; serve it with httptest.NewServer and point a
+// Grafana client at the server's URL. When Token is set, requests must
+// carry it as a bearer token.
+type GrafanaStandIn struct {
+	Token string
+	// Reject fails saves of the dashboards with these UIDs with a 400 and
+	// the given message.
+	Reject map[string]string
+
+	mu         sync.Mutex
+	dashboards map[string]GrafanaDashboard
+}
+
+// GrafanaDashboard is a dashboard saved in a GrafanaStandIn.
+type GrafanaDashboard struct {
+	FolderUID string          `json:"folderUid"`
+	Version   int             `json:"version"`
+	Dashboard json.RawMessage `json:"dashboard"`
+}
+
+func (s *GrafanaStandIn) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.Token != "" && req.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeGrafanaJSON(w, http.StatusUnauthorized, map[string]string{"message": "invalid API key"})
+		return
+	}
+
+	switch {
+	case req.Method == http.MethodPost && req.URL.Path == "/api/dashboards/db":
+		s.save(w, req)
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/api/dashboards/uid/"):
+		s.get(w, strings.TrimPrefix(req.URL.Path, "/api/dashboards/uid/"))
+	default:
+		writeGrafanaJSON(w, http.StatusNotFound, map[string]string{"message": "Not found"})
+	}
+}
+
+// Dashboards returns a copy of the saved dashboards keyed by UID.
+func (s *GrafanaStandIn) Dashboards() map[string]GrafanaDashboard {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]GrafanaDashboard, len(s.dashboards))
+	for uid, stored := range s.dashboards {
+		out[uid] = stored
+	}
+	return out
+}
+
+func (s *GrafanaStandIn) save(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+		FolderUID string          `json:"folderUid"`
+		Overwrite bool            `json:"overwrite"`
+	}
+	var meta struct {
+		UID   string `json:"uid"`
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || json.Unmarshal(body.Dashboard, &meta) != nil {
+		writeGrafanaJSON(w, http.StatusBadRequest, map[string]string{"message": "bad request data"})
+		return
+	}
+	if meta.UID == "" || meta.Title == "" {
+		writeGrafanaJSON(w, http.StatusBadRequest, map[string]string{"message": "Dashboard title and uid are required"})
+		return
+	}
+	if message, ok := s.Reject[meta.UID]; ok {
+		writeGrafanaJSON(w, http.StatusBadRequest, map[string]string{"message": message})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dashboards == nil {
+		s.dashboards = map[string]GrafanaDashboard{}
+	}
+	previous, exists := s.dashboards[meta.UID]
+	if exists && !body.Overwrite {
+		writeGrafanaJSON(w, http.StatusPreconditionFailed, map[string]string{
+			"message": "A dashboard with the same uid already exists",
+			"status":  "name-exists",
+		})
+		return
+	}
+	stored := GrafanaDashboard{FolderUID: body.FolderUID, Version: previous.Version + 1, Dashboard: body.Dashboard}
+	s.dashboards[meta.UID] = stored
+
+	writeGrafanaJSON(w, http.StatusOK, map[string]interface{}{
+		"id":      len(s.dashboards),
+		"uid":     meta.UID,
+		"url":     "/d/" + meta.UID,
+		"status":  "success",
+		"version": stored.Version,
+	})
+}
+
+func (s *GrafanaStandIn) get(w http.ResponseWriter, uid string) {
+	s.mu.Lock()
+	stored, ok := s.dashboards[uid]
+	s.mu.Unlock()
+	if !ok {
+		writeGrafanaJSON(w, http.StatusNotFound, map[string]string{"message": "Dashboard not found"})
+		return
+	}
+	writeGrafanaJSON(w, http.StatusOK, map[string]interface{}{
+		"dashboard": stored.Dashboard,
+		"meta":      map[string]interface{}{"folderUid": stored.FolderUID, "version": stored.Version},
+	})
+}
+
+func writeGrafanaJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
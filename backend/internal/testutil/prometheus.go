@@ -0,0 +1,66 @@
+// Package testutil holds stand-ins for external services that tests across
+// modules share. Besides tests, only development commands such as
+// cmd/oidc-standin import it; the server never does.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PrometheusRecording is an http.RoundTripper that replays Prometheus
+// responses captured earlier, keyed by the PromQL query, so a Prometheus
+// client runs without a server. Queries with no recording get a
+// Prometheus-style error response.
+type PrometheusRecording map[string]string
+
+func (r PrometheusRecording) RoundTrip(req *http.Request) (*http.Response, error) {
+	query, err := requestQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	status := http.StatusOK
+	body, ok := r[query]
+	if !ok {
+		status = http.StatusBadRequest
+		encoded, _ := json.Marshal(map[string]string{
+			"status":    "error",
+			"errorType": "recording",
+			"error":     fmt.Sprintf("no recorded response for %q", query),
+		})
+		body = string(encoded)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// requestQuery reads the query parameter from the URL or form body.
+func requestQuery(req *http.Request) (string, error) {
+	values := req.URL.Query()
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "", err
+		}
+		for k, v := range form {
+			values[k] = append(values[k], v...)
+		}
+	}
+	return values.Get("query"), nil
+}
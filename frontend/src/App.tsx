@@ -50,18 +50,20 @@ export default function App() {
 
 function HomePage() {
   const [now, setNow] = React.useState(new Date())
+  // Fields without a backend data source are omitted from the payload.
   const [summary, setSummary] = React.useState<null | {
-    totalEndpoints: number
-    avgLatencyMs: number
-    uptimeTodayPercent: number
-    scheduledApis: number
-    totalRunsToday: number
-    successRateToday: number
-    scheduledRunsToday: number
-    pipelineSuccess: number
-    serversActive: string
-    serverUptime: number
+    totalEndpoints?: number
+    avgLatencyMs?: number
+    uptimeTodayPercent?: number
+    scheduledApis?: number
+    totalRunsToday?: number
+    successRateToday?: number
+    scheduledRunsToday?: number
+    pipelineSuccess?: number
+    serversActive?: string
+    serverUptime?: number
     monitoringSince: string
+    unavailable: string[]
   }>(null)
 
   React.useEffect(() => {
@@ -145,11 +147,11 @@ function HomePage() {
         <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-2">Total endpoints</p>
-            <p className="text-2xl font-semibold">{summary?.totalEndpoints ?? 137}</p>
+            <p className="text-2xl font-semibold">{summary?.totalEndpoints ?? '—'}</p>
           </div>
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-2">Avg latency</p>
-            <p className="text-2xl font-semibold">{summary?.avgLatencyMs ?? 177} <span className="text-sm text-gray-500">ms</span></p>
+            <p className="text-2xl font-semibold">{summary?.avgLatencyMs ?? '—'} <span className="text-sm text-gray-500">ms</span></p>
           </div>
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-3">Uptime today</p>
@@ -157,10 +159,10 @@ function HomePage() {
               <div className="h-2 w-full bg-gray-100 rounded-full">
                 <div
                   className="h-2 bg-green-600 rounded-full"
-                  style={{ width: `${summary?.uptimeTodayPercent ?? 100}%` }}
+                  style={{ width: `${summary?.uptimeTodayPercent ?? 0}%` }}
                 />
               </div>
-              <span className="text-sm font-semibold">{summary?.uptimeTodayPercent ?? 100}%</span>
+              <span className="text-sm font-semibold">{summary?.uptimeTodayPercent ?? '—'}%</span>
             </div>
           </div>
         </div>
@@ -168,22 +170,22 @@ function HomePage() {
         <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-2">Scheduled APIs</p>
-            <p className="text-2xl font-semibold">{summary?.scheduledApis ?? 6}</p>
+            <p className="text-2xl font-semibold">{summary?.scheduledApis ?? '—'}</p>
           </div>
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-2">Total runs today</p>
-            <p className="text-2xl font-semibold">{summary?.totalRunsToday ?? 1227}</p>
+            <p className="text-2xl font-semibold">{summary?.totalRunsToday ?? '—'}</p>
           </div>
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-2">Success rate today</p>
-            <p className="text-2xl font-semibold">{summary?.successRateToday ?? 97}<span className="text-sm text-gray-500">%</span></p>
+            <p className="text-2xl font-semibold">{summary?.successRateToday ?? '—'}<span className="text-sm text-gray-500">%</span></p>
           </div>
         </div>
 
         <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-2">Scheduled runs today</p>
-            <p className="text-2xl font-semibold">{summary?.scheduledRunsToday ?? 4}</p>
+            <p className="text-2xl font-semibold">{summary?.scheduledRunsToday ?? '—'}</p>
           </div>
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-3">Pipeline success rate</p>
@@ -191,10 +193,10 @@ function HomePage() {
               <div className="h-2 w-full bg-gray-100 rounded-full">
                 <div
                   className="h-2 bg-blue-600 rounded-full"
-                  style={{ width: `${summary?.pipelineSuccess ?? 100}%` }}
+                  style={{ width: `${summary?.pipelineSuccess ?? 0}%` }}
                 />
               </div>
-              <span className="text-sm font-semibold">{summary?.pipelineSuccess ?? 100}%</span>
+              <span className="text-sm font-semibold">{summary?.pipelineSuccess ?? '—'}%</span>
             </div>
           </div>
         </div>
@@ -202,7 +204,7 @@ function HomePage() {
         <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-2">Servers active</p>
-            <p className="text-2xl font-semibold">{summary?.serversActive ?? '0/0'}</p>
+            <p className="text-2xl font-semibold">{summary?.serversActive ?? '—'}</p>
           </div>
           <div className="bg-white rounded-xl shadow p-5 border border-gray-100">
             <p className="text-xs uppercase tracking-widest text-gray-400 mb-3">Server uptime</p>
@@ -210,10 +212,10 @@ function HomePage() {
               <div className="h-2 w-full bg-gray-100 rounded-full">
                 <div
                   className="h-2 bg-green-600 rounded-full"
-                  style={{ width: `${summary?.serverUptime ?? 100}%` }}
+                  style={{ width: `${summary?.serverUptime ?? 0}%` }}
                 />
               </div>
-              <span className="text-sm font-semibold">{summary?.serverUptime ?? 100}%</span>
+              <span className="text-sm font-semibold">{summary?.serverUptime ?? '—'}%</span>
             </div>
           </div>
         </div>
@@ -18,7 +18,7 @@ import {
   AlertIcon,
 } from '@chakra-ui/react'
 
-import { getToken } from '../../auth/authStorage'
+import { authFetch, getToken } from '../../auth/authStorage'
 
 interface UserRow {
   id: string
@@ -44,9 +44,7 @@ export default function UserAdmin() {
       return
     }
 
-    const res = await fetch('/api/v1/admin/users', {
-      headers: { Authorization: `Bearer ${token}` },
-    })
+    const res = await authFetch('/api/v1/admin/users')
 
     if (!res.ok) {
       const data = await res.json()
@@ -71,12 +69,9 @@ export default function UserAdmin() {
       return
     }
 
-    const res = await fetch('/api/v1/admin/users', {
+    const res = await authFetch('/api/v1/admin/users', {
       method: 'POST',
-      headers: {
-        'Content-Type': 'application/json',
-        Authorization: `Bearer ${token}`,
-      },
+      headers: { 'Content-Type': 'application/json' },
       body: JSON.stringify({ fullName, email, password, role }),
     })
 
@@ -103,12 +98,9 @@ export default function UserAdmin() {
       return
     }
 
-    const res = await fetch(`/api/v1/admin/users/${userId}/role`, {
+    const res = await authFetch(`/api/v1/admin/users/${userId}/role`, {
       method: 'PUT',
-      headers: {
-        'Content-Type': 'application/json',
-        Authorization: `Bearer ${token}`,
-      },
+      headers: { 'Content-Type': 'application/json' },
       body: JSON.stringify({ role: nextRole }),
     })
 
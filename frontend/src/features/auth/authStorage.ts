@@ -5,24 +5,61 @@ export type AuthUser = {
   role: string
 }
 
-type AuthPayload = {
-  user: AuthUser
+type TokenPair = {
   token: string
+  expiresAt: string
+  refreshToken: string
+}
+
+type AuthPayload = TokenPair & {
+  user: AuthUser
 }
 
 const TOKEN_KEY = 'fbisdevoptics.token'
+const EXPIRES_AT_KEY = 'fbisdevoptics.tokenExpiresAt'
+const REFRESH_TOKEN_KEY = 'fbisdevoptics.refreshToken'
 const USER_KEY = 'fbisdevoptics.user'
 
+// Access tokens this close to expiry are refreshed before use rather than
+// sent and rejected.
+const REFRESH_MARGIN_MS = 30 * 1000
+
 export function saveAuth(payload: AuthPayload) {
-  localStorage.setItem(TOKEN_KEY, payload.token)
+  saveTokens(payload)
   localStorage.setItem(USER_KEY, JSON.stringify(payload.user))
 }
 
+function saveTokens(tokens: TokenPair) {
+  localStorage.setItem(TOKEN_KEY, tokens.token)
+  localStorage.setItem(EXPIRES_AT_KEY, tokens.expiresAt)
+  localStorage.setItem(REFRESH_TOKEN_KEY, tokens.refreshToken)
+}
+
 export function clearAuth() {
   localStorage.removeItem(TOKEN_KEY)
+  localStorage.removeItem(EXPIRES_AT_KEY)
+  localStorage.removeItem(REFRESH_TOKEN_KEY)
   localStorage.removeItem(USER_KEY)
 }
 
+// signOut revokes the session's refresh tokens on the server, then forgets
+// the local session even if the server could not be reached.
+export async function signOut() {
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY)
+  if (refreshToken) {
+    try {
+      await fetch('/api/v1/auth/logout', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refreshToken }),
+      })
+    } catch {
+      // The refresh token expires on its own.
+    }
+  }
+  clearAuth()
+}
+
 export function getToken(): string | null {
   return localStorage.getItem(TOKEN_KEY)
 }
@@ -31,3 +68,101 @@ export function getUser(): AuthUser | null {
   const raw = localStorage.getItem(USER_KEY)
   return raw ? (JSON.parse(raw) as AuthUser) : null
 }
+
+function expiresSoon(): boolean {
+  const expiresAt = Date.parse(localStorage.getItem(EXPIRES_AT_KEY) || '')
+  return Number.isNaN(expiresAt) || expiresAt - Date.now() < REFRESH_MARGIN_MS
+}
+
+let pendingRefresh: Promise<string | null> | null = null
+
+// refreshSession rotates the stored refresh token into a new pair and
+// returns the new access token, or null if the session is over. A refresh
+// token is single use and presenting it twice revokes the session, so
+// refreshes are serialised within the tab and, where the browser allows,
+// across tabs. staleToken is the access token that prompted the refresh:
+// if another tab has replaced it meanwhile, its pair is used instead.
+function refreshSession(staleToken: string | null): Promise<string | null> {
+  if (!pendingRefresh) {
+    const run = async () => {
+      const current = getToken()
+      if (current && current !== staleToken && !expiresSoon()) {
+        return current
+      }
+      return rotate()
+    }
+    const locked = navigator.locks
+      ? navigator.locks.request('fbisdevoptics.refresh', run)
+      : run()
+    pendingRefresh = locked.finally(() => {
+      pendingRefresh = null
+    })
+  }
+  return pendingRefresh
+}
+
+async function rotate(): Promise<string | null> {
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY)
+  if (!refreshToken) {
+    return null
+  }
+
+  let response: Response
+  try {
+    response = await fetch('/api/v1/auth/refresh', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ refreshToken }),
+    })
+  } catch {
+    // Offline: keep the session and let the caller's request fail.
+    return null
+  }
+
+  if (response.status === 401) {
+    // The token expired, was revoked, or was already used, which the
+    // server treats as theft and answers by ending the whole session.
+    clearAuth()
+    return null
+  }
+  if (!response.ok) {
+    return null
+  }
+
+  const tokens = (await response.json()) as TokenPair
+  saveTokens(tokens)
+  return tokens.token
+}
+
+// authFetch is fetch for authenticated API calls. It sends the access
+// token, refreshing it first when it is about to expire, and retries once
+// with a refreshed token if the server still answers 401. When the session
+// cannot be refreshed the user is signed out and sent to sign in.
+export async function authFetch(input: RequestInfo | URL, init: RequestInit = {}): Promise<Response> {
+  let token = getToken()
+  if (token && expiresSoon()) {
+    token = (await refreshSession(token)) ?? token
+  }
+
+  const send = (accessToken: string | null) => {
+    const headers = new Headers(init.headers)
+    if (accessToken) {
+      headers.set('Authorization', `Bearer ${accessToken}`)
+    }
+    return fetch(input, { ...init, headers })
+  }
+
+  const response = await send(token)
+  if (response.status !== 401 || !token) {
+    return response
+  }
+
+  const refreshed = await refreshSession(token)
+  if (!refreshed) {
+    if (!getToken()) {
+      window.location.assign('/auth/sign-in')
+    }
+    return response
+  }
+  return send(refreshed)
+}
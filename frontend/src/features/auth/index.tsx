@@ -3,8 +3,9 @@ import { Box, Button, Grid, GridItem, HStack, Text } from '@chakra-ui/react'
 
 import SignIn from './pages/SignIn'
 import SignUp from './pages/SignUp'
+import OIDCCallback from './pages/OIDCCallback'
 import AccessMatrix from './pages/AccessMatrix'
-import { clearAuth, getUser } from './authStorage'
+import { getUser, signOut } from './authStorage'
 
 export default function AuthModule() {
   const user = getUser()
@@ -23,7 +24,7 @@ export default function AuthModule() {
         {user && (
           <HStack spacing={3}>
             <Text color="gray.600">Signed in as {user.fullName} ({user.role})</Text>
-            <Button size="sm" variant="ghost" onClick={clearAuth}>Sign out</Button>
+            <Button size="sm" variant="ghost" onClick={signOut}>Sign out</Button>
           </HStack>
         )}
       </HStack>
@@ -33,6 +34,7 @@ export default function AuthModule() {
             <Route path="/" element={<SignIn />} />
             <Route path="/sign-in" element={<SignIn />} />
             <Route path="/sign-up" element={<SignUp />} />
+            <Route path="/oidc/callback" element={<OIDCCallback />} />
           </Routes>
         </GridItem>
         <GridItem>
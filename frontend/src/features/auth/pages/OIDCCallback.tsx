@@ -0,0 +1,79 @@
+import { Alert, AlertIcon, Box, Heading, Link, Spinner, Stack, Text } from '@chakra-ui/react'
+import { useEffect, useRef, useState } from 'react'
+import { Link as RouterLink, useSearchParams } from 'react-router-dom'
+
+import { saveAuth } from '../authStorage'
+
+// OIDCCallback is where the identity provider sends the browser back. It
+// hands the code and state to the backend, which completes the sign-in.
+export default function OIDCCallback() {
+  const [params] = useSearchParams()
+  const [error, setError] = useState('')
+  const [success, setSuccess] = useState('')
+  // A state completes one sign-in only, so the exchange must not run twice.
+  const started = useRef(false)
+
+  useEffect(() => {
+    if (started.current) return
+    started.current = true
+
+    const providerError = params.get('error')
+    if (providerError) {
+      setError(params.get('error_description') || providerError)
+      return
+    }
+
+    const complete = async () => {
+      try {
+        const response = await fetch('/api/v1/auth/oidc/callback', {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ code: params.get('code'), state: params.get('state') }),
+        })
+        const data = await response.json()
+        if (!response.ok) {
+          throw new Error(data.error || 'Unable to sign in')
+        }
+
+        saveAuth(data)
+        setSuccess(`Welcome, ${data.user.fullName}`)
+      } catch (err) {
+        setError(err instanceof Error ? err.message : 'Unable to sign in')
+      }
+    }
+    complete()
+  }, [params])
+
+  return (
+    <Box bg="white" p={10} rounded="2xl" shadow="xl" border="1px solid" borderColor="gray.100">
+      <Heading size="lg" mb={6} textAlign="center">Single sign-on</Heading>
+      <Stack spacing={5}>
+        {!error && !success && (
+          <Stack align="center">
+            <Spinner />
+            <Text color="gray.600">Completing sign-in…</Text>
+          </Stack>
+        )}
+        {error && (
+          <Alert status="error">
+            <AlertIcon />
+            {error}
+          </Alert>
+        )}
+        {success && (
+          <Alert status="success">
+            <AlertIcon />
+            {success}
+          </Alert>
+        )}
+        {error && (
+          <Text textAlign="center" color="gray.500">
+            <Link as={RouterLink} to="/auth/sign-in" color="blue.600" fontWeight="semibold">
+              Back to sign in
+            </Link>
+          </Text>
+        )}
+      </Stack>
+    </Box>
+  )
+}
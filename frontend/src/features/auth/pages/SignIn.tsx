@@ -27,6 +27,25 @@ export default function SignIn() {
   const [success, setSuccess] = useState('')
   const [showPassword, setShowPassword] = useState(false)
 
+  // handleSSO sends the browser to the identity provider, which returns it
+  // to the OIDC callback page.
+  const handleSSO = async () => {
+    setError('')
+    setSuccess('')
+
+    try {
+      const response = await fetch('/api/v1/auth/oidc/login')
+      const data = await response.json()
+      if (!response.ok) {
+        throw new Error(data.error || 'Single sign-on is unavailable')
+      }
+
+      window.location.assign(data.authorizationUrl)
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Single sign-on is unavailable')
+    }
+  }
+
   const handleSubmit = async (event: FormEvent) => {
     event.preventDefault()
     setError('')
@@ -118,6 +137,9 @@ export default function SignIn() {
           </InputGroup>
         </FormControl>
         <Button type="submit" colorScheme="blue" size="lg">Sign in</Button>
+        <Button variant="outline" colorScheme="blue" size="lg" onClick={handleSSO}>
+          Sign in with SSO
+        </Button>
         <Text textAlign="center" color="gray.500">
           Don&apos;t have an account?{' '}
           <Link as={RouterLink} to="/auth/sign-up" color="blue.600" fontWeight="semibold">
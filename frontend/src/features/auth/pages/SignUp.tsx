@@ -8,7 +8,6 @@ import {
   InputGroup,
   InputLeftElement,
   InputRightElement,
-  Select,
   Stack,
   Text,
   Alert,
@@ -26,7 +25,6 @@ export default function SignUp() {
   const [fullName, setFullName] = useState('')
   const [email, setEmail] = useState('')
   const [password, setPassword] = useState('')
-  const [role, setRole] = useState('viewer')
   const [error, setError] = useState('')
   const [success, setSuccess] = useState('')
   const [showPassword, setShowPassword] = useState(false)
@@ -40,7 +38,7 @@ export default function SignUp() {
       const response = await fetch('/api/v1/auth/signup', {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({ fullName, email, password, role }),
+        body: JSON.stringify({ fullName, email, password }),
       })
 
       const contentType = response.headers.get('content-type') || ''
@@ -134,15 +132,6 @@ export default function SignUp() {
             </InputRightElement>
           </InputGroup>
         </FormControl>
-        <FormControl>
-          <FormLabel>Role</FormLabel>
-          <Select value={role} onChange={(event) => setRole(event.target.value)}>
-            <option value="admin">Admin (full access)</option>
-            <option value="manager">Manager (manage teams)</option>
-            <option value="analyst">Analyst (view analytics)</option>
-            <option value="viewer">Viewer (read-only)</option>
-          </Select>
-        </FormControl>
         <Button type="submit" colorScheme="blue" size="lg">Create account</Button>
         <Divider />
         <Text textAlign="center" color="gray.500">
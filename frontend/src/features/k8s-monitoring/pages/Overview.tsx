@@ -1,16 +1,39 @@
 import { useEffect, useState } from 'react'
-import { Box, Heading, Text, VStack, Alert, AlertIcon } from '@chakra-ui/react'
+import { Box, Heading, Text, VStack, Alert, AlertIcon, Select } from '@chakra-ui/react'
 
-import { getToken, getUser } from '../../auth/authStorage'
+import { authFetch, getToken, getUser } from '../../auth/authStorage'
+
+interface ComponentHealth {
+  name: string
+  healthy: boolean
+  message?: string
+}
 
 interface ClusterHealth {
   clusterName: string
   status: string
   timestamp: string
+  nodes: { total: number; ready: number; notReady: number }
+  pods: {
+    total: number
+    running: number
+    pending: number
+    succeeded: number
+    failed: number
+    unknown: number
+  }
+  controlPlane: ComponentHealth[] | null
   signals: Record<string, string>
 }
 
+interface Cluster {
+  name: string
+  environment: string
+}
+
 export default function Overview() {
+  const [clusters, setClusters] = useState<Cluster[]>([])
+  const [cluster, setCluster] = useState('')
   const [health, setHealth] = useState<ClusterHealth | null>(null)
   const [error, setError] = useState('')
   const user = getUser()
@@ -22,11 +45,34 @@ export default function Overview() {
       return
     }
 
-    fetch('/api/v1/k8s/health/default', {
-      headers: {
-        Authorization: `Bearer ${token}`,
-      },
-    })
+    authFetch('/api/v1/k8s/clusters')
+      .then(async (res) => {
+        if (!res.ok) {
+          const body = await res.json()
+          throw new Error(body.error || 'Unable to load clusters')
+        }
+        return res.json()
+      })
+      .then((data: { clusters: Cluster[] }) => {
+        setClusters(data.clusters)
+        if (data.clusters.length === 0) {
+          setError('No clusters registered yet.')
+          return
+        }
+        setCluster(data.clusters[0].name)
+      })
+      .catch((err) => setError(err.message))
+  }, [])
+
+  useEffect(() => {
+    const token = getToken()
+    if (!token || !cluster) {
+      return
+    }
+
+    setHealth(null)
+    setError('')
+    authFetch(`/api/v1/k8s/health/${encodeURIComponent(cluster)}`)
       .then(async (res) => {
         if (!res.ok) {
           const body = await res.json()
@@ -36,7 +82,7 @@ export default function Overview() {
       })
       .then(setHealth)
       .catch((err) => setError(err.message))
-  }, [])
+  }, [cluster])
 
   return (
     <Box>
@@ -46,6 +92,16 @@ export default function Overview() {
           Role: {user.role}. Admins have full access.
         </Text>
       )}
+      {clusters.length > 0 && (
+        <Select value={cluster} onChange={(e) => setCluster(e.target.value)} mb={4} maxW="sm">
+          {clusters.map((c) => (
+            <option key={c.name} value={c.name}>
+              {c.name}
+              {c.environment ? ` (${c.environment})` : ''}
+            </option>
+          ))}
+        </Select>
+      )}
       {error && (
         <Alert status="warning" mb={4}>
           <AlertIcon />
@@ -57,7 +113,18 @@ export default function Overview() {
           <Text><strong>Cluster:</strong> {health.clusterName}</Text>
           <Text><strong>Status:</strong> {health.status}</Text>
           <Text><strong>Timestamp:</strong> {health.timestamp}</Text>
-          <Text><strong>Note:</strong> {health.signals?.note}</Text>
+          <Text>
+            <strong>Nodes:</strong> {health.nodes.ready}/{health.nodes.total} ready
+          </Text>
+          <Text>
+            <strong>Pods:</strong> {health.pods.running} running, {health.pods.pending} pending,{' '}
+            {health.pods.failed} failed
+          </Text>
+          {health.controlPlane?.map((component) => (
+            <Text key={component.name}>
+              <strong>{component.name}:</strong> {component.healthy ? 'healthy' : 'unhealthy'}
+            </Text>
+          ))}
         </VStack>
       ) : (
         !error && <Text>Waiting for health snapshot...</Text>